@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// bilinearAccumulator is a Nguyen-style accumulator of a set X of Fr elements under the
+// trapdoor s: acc = f(s) * g1 with f(X) = prod (X + x_i). The verifier only needs the
+// public key [s] * g2.
+type bilinearAccumulator struct {
+	Elements []fr.Element
+	Trapdoor fr.Element
+	Poly     []fr.Element // f, lowest degree first
+	Value    bls.G1Affine
+	PubKey   bls.G2Affine
+}
+
+// membershipWitness proves that Element is in the accumulated set: w = (f(s) / (s + y)) * g1
+type membershipWitness struct {
+	Element  fr.Element
+	Quotient []fr.Element // f(X) / (X + y)
+	Witness  bls.G1Affine
+	// Operand is [s + y] * g2, the G2 point the verifier builds from the public key
+	Operand bls.G2Affine
+}
+
+// newBilinearAccumulator accumulates elements under trapdoor s
+func newBilinearAccumulator(elements []fr.Element, s fr.Element) (bilinearAccumulator, error) {
+	a := bilinearAccumulator{Elements: elements, Trapdoor: s, Poly: []fr.Element{fr.One()}}
+	if len(elements) == 0 {
+		return a, fmt.Errorf("at least one element is required")
+	}
+	for i := range elements {
+		// f(X) *= (X + x_i)
+		next := make([]fr.Element, len(a.Poly)+1)
+		for k := range a.Poly {
+			var t fr.Element
+			t.Mul(&a.Poly[k], &elements[i])
+			next[k].Add(&next[k], &t)
+			next[k+1].Add(&next[k+1], &a.Poly[k])
+		}
+		a.Poly = next
+	}
+	fs := polyEval(a.Poly, s)
+	if fs.IsZero() {
+		// s = -x_i for some element; the accumulator would be the point at infinity
+		return a, fmt.Errorf("trapdoor is the negation of an element")
+	}
+	_, _, g1Gen, g2Gen := bls.Generators()
+	a.Value.ScalarMultiplication(&g1Gen, fs.BigInt(new(big.Int)))
+	a.PubKey.ScalarMultiplication(&g2Gen, s.BigInt(new(big.Int)))
+	return a, nil
+}
+
+// witness returns the membership witness of y, which must be one of the elements
+func (a bilinearAccumulator) witness(y fr.Element) (membershipWitness, error) {
+	w := membershipWitness{Element: y}
+	var negY fr.Element
+	negY.Neg(&y)
+	quotient, remainder := polyDivideLinear(a.Poly, negY)
+	if !remainder.IsZero() {
+		return w, fmt.Errorf("%s is not in the accumulated set", y.String())
+	}
+	w.Quotient = quotient
+	_, _, g1Gen, g2Gen := bls.Generators()
+	qs := polyEval(quotient, a.Trapdoor)
+	w.Witness.ScalarMultiplication(&g1Gen, qs.BigInt(new(big.Int)))
+	var sy fr.Element
+	sy.Add(&a.Trapdoor, &y)
+	w.Operand.ScalarMultiplication(&g2Gen, sy.BigInt(new(big.Int)))
+	return w, nil
+}
+
+// pairingCheckInput is the EIP-2537 pairing input of the membership check
+// e(w, [s]g2 + [y]g2) * e(-acc, g2) == 1
+func (a bilinearAccumulator) pairingCheckInput(w membershipWitness) []byte {
+	_, _, _, g2Gen := bls.Generators()
+	var negAcc bls.G1Affine
+	negAcc.Neg(&a.Value)
+	var out []byte
+	out = append(out, serialization.EncodeEthereumG1Point(w.Witness)...)
+	out = append(out, serialization.EncodeEthereumG2Point(w.Operand)...)
+	out = append(out, serialization.EncodeEthereumG1Point(negAcc)...)
+	out = append(out, serialization.EncodeEthereumG2Point(g2Gen)...)
+	return out
+}
+
+// operandMSMInput is the G2MSM input ([s]g2, 1), (g2, y) a contract uses to build the
+// pairing operand from the public key and the claimed element
+func (a bilinearAccumulator) operandMSMInput(y fr.Element) []byte {
+	_, _, _, g2Gen := bls.Generators()
+	var out []byte
+	out = append(out, serialization.EncodeEthereumG2Point(a.PubKey)...)
+	out = append(out, scalarTo32Bytes(big.NewInt(1))...)
+	out = append(out, serialization.EncodeEthereumG2Point(g2Gen)...)
+	out = append(out, scalarTo32Bytes(y.BigInt(new(big.Int)))...)
+	return out
+}
+
+// runAccumulatorMode accumulates a set of Fr elements, produces the membership witness of
+// one element and prints the pairing-check input that verifies it
+func runAccumulatorMode(args []string) error {
+	fs := newFlagSet("accumulator")
+	elementsStr := fs.String("elements", "", "Comma-separated set elements (decimal or 0x hex, reduced mod r)")
+	memberStr := fs.String("member", "", "Element to prove membership of (default: the first element)")
+	trapdoorStr := fs.String("trapdoor", "", "Trapdoor s (decimal or 0x hex; default: random, see --seed)")
+	seed := registerSeedFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *elementsStr == "" {
+		return usageErrorf("--elements is required")
+	}
+	elements, err := parseFrList(*elementsStr)
+	if err != nil {
+		return usageErrorf("--elements: %v", err)
+	}
+	if len(elements) == 0 {
+		return usageErrorf("--elements must list at least one element")
+	}
+	member := elements[0]
+	if *memberStr != "" {
+		if member, err = parseFrElement(*memberStr); err != nil {
+			return usageErrorf("--member: %v", err)
+		}
+	}
+	var s fr.Element
+	if *trapdoorStr != "" {
+		if s, err = parseFrElement(*trapdoorStr); err != nil {
+			return usageErrorf("--trapdoor: %v", err)
+		}
+	} else {
+		if err := applySeed(*seed); err != nil {
+			return usageError{err}
+		}
+		if s, err = randomFr(); err != nil {
+			return fmt.Errorf("failed to generate trapdoor: %v", err)
+		}
+	}
+	if s.IsZero() {
+		return usageErrorf("--trapdoor must not be zero")
+	}
+
+	acc, err := newBilinearAccumulator(elements, s)
+	if err != nil {
+		return err
+	}
+	w, err := acc.witness(member)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("=== Bilinear Accumulator (%d elements) ===\n", len(elements))
+	printFrList("Elements", elements)
+	printFrValue("Trapdoor s", s)
+	printFrList("f(X) = prod (X + x_i), coefficients", acc.Poly)
+	printG1Encodings("Accumulator f(s)*g1", acc.Value)
+	printG2Encodings("Public key s*g2", acc.PubKey)
+	fmt.Println()
+	fmt.Println("=== Membership Witness ===")
+	printFrValue("Member y", member)
+	printFrList("f(X) / (X + y), coefficients", w.Quotient)
+	printG1Encodings("Witness", w.Witness)
+	printG2Encodings("Operand (s+y)*g2", w.Operand)
+
+	operandInput := acc.operandMSMInput(member)
+	operandHex := hex.EncodeToString(operandInput)
+	operand, err := bls12381vec.G2MSM(operandHex)
+	if err != nil {
+		return fmt.Errorf("G2MSM of the operand input failed: %v", err)
+	}
+	if operand != hex.EncodeToString(serialization.EncodeEthereumG2Point(w.Operand)) {
+		return fmt.Errorf("G2MSM of the operand input %s differs from (s+y)*g2", operand)
+	}
+	fmt.Printf("Operand G2MSM input ([s]g2, 1), (g2, y) (%d bytes): %s\n", len(operandInput), operandHex)
+
+	input := acc.pairingCheckInput(w)
+	inputHex := hex.EncodeToString(input)
+	result, err := bls12381vec.Pairing(inputHex)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("Check: e(witness, (s+y)*g2) * e(-accumulator, g2) == 1")
+	fmt.Printf("Pairing check input (EIP-2537, 2 pairs, %d bytes): %s\n", len(input), inputHex)
+	fmt.Printf("Pairing check result: %s\n", result)
+
+	// The same witness must not verify a different element
+	var other fr.Element
+	other.Add(&member, new(fr.Element).SetOne())
+	wrong := w
+	var sy fr.Element
+	sy.Add(&s, &other)
+	_, _, _, g2Gen := bls.Generators()
+	wrong.Operand.ScalarMultiplication(&g2Gen, sy.BigInt(new(big.Int)))
+	wrongInput := acc.pairingCheckInput(wrong)
+	wrongResult, err := bls12381vec.Pairing(hex.EncodeToString(wrongInput))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pairing check input for y+1 with the same witness (%d bytes): %x\n", len(wrongInput), wrongInput)
+	fmt.Printf("Pairing check result (y+1): %s\n", wrongResult)
+
+	one := strings.Repeat("00", 31) + "01"
+	if result != one || wrongResult == one {
+		fmt.Printf("❌ Membership check returned %s, y+1 check returned %s\n", result, wrongResult)
+		return errChecksFailed
+	}
+	fmt.Println("✅ Witness verifies y and is rejected for y+1")
+
+	recordVerdictInput(inputHex)
+	recordVerdictResult(result)
+	reportInput("elements", frStrings(elements))
+	reportInput("member", member.String())
+	reportOutput("accumulator", hex.EncodeToString(serialization.EncodeEthereumG1Point(acc.Value)))
+	reportOutput("public_key", hex.EncodeToString(serialization.EncodeEthereumG2Point(acc.PubKey)))
+	reportOutput("witness", hex.EncodeToString(serialization.EncodeEthereumG1Point(w.Witness)))
+	reportOutput("operand_msm_input", operandHex)
+	reportOutput("pairing_input", inputHex)
+	reportOutput("pairing_input_wrong_member", hex.EncodeToString(wrongInput))
+	reportOutput("result", result)
+	return nil
+}
+
+// frStrings returns Fr values in decimal
+func frStrings(values []fr.Element) []string {
+	out := make([]string, len(values))
+	for i := range values {
+		out[i] = values[i].String()
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// BLS signature ciphersuites differ in which group holds public keys and signatures:
+//   - min-pk (minimal-pubkey-size, used by Ethereum and Neo): public keys in G1, signatures in G2
+//   - min-sig (minimal-signature-size): public keys in G2, signatures in G1
+var blsCiphersuites = []string{"min-pk", "min-sig"}
+
+// aggregateUsesG2 reports whether the points aggregated by mode are G2 points
+func aggregateUsesG2(mode, ciphersuite string) (bool, error) {
+	switch ciphersuite {
+	case "min-pk":
+		return mode == "agg-sigs", nil
+	case "min-sig":
+		return mode == "agg-pubkeys", nil
+	}
+	return false, fmt.Errorf("unknown ciphersuite '%s' (valid: %s)", ciphersuite, strings.Join(blsCiphersuites, ", "))
+}
+
+// aggregateG1 sums compressed G1 points; each point is strictly decoded and subgroup-checked
+func aggregateG1(hexes []string) (bls.G1Affine, int, error) {
+	var acc bls.G1Jac
+	var result bls.G1Affine
+	infinities := 0
+	for i, h := range hexes {
+		data, err := hex.DecodeString(strings.TrimPrefix(h, "0x"))
+		if err != nil {
+			return result, 0, fmt.Errorf("point %d: invalid hex: %v", i, err)
+		}
+		p, err := bls12381vec.DecodeCompressedG1Point(data)
+		if err != nil {
+			return result, 0, fmt.Errorf("point %d: %v", i, err)
+		}
+		if p.IsInfinity() {
+			infinities++
+		}
+		var pJac bls.G1Jac
+		pJac.FromAffine(&p)
+		acc.AddAssign(&pJac)
+	}
+	result.FromJacobian(&acc)
+	return result, infinities, nil
+}
+
+// aggregateG2 is the G2 analogue of aggregateG1
+func aggregateG2(hexes []string) (bls.G2Affine, int, error) {
+	var acc bls.G2Jac
+	var result bls.G2Affine
+	infinities := 0
+	for i, h := range hexes {
+		data, err := hex.DecodeString(strings.TrimPrefix(h, "0x"))
+		if err != nil {
+			return result, 0, fmt.Errorf("point %d: invalid hex: %v", i, err)
+		}
+		p, err := bls12381vec.DecodeCompressedG2Point(data)
+		if err != nil {
+			return result, 0, fmt.Errorf("point %d: %v", i, err)
+		}
+		if p.IsInfinity() {
+			infinities++
+		}
+		var pJac bls.G2Jac
+		pJac.FromAffine(&p)
+		acc.AddAssign(&pJac)
+	}
+	result.FromJacobian(&acc)
+	return result, infinities, nil
+}
+
+// runAggregateMode runs agg-pubkeys and agg-sigs: the aggregate is the plain sum of the
+// given points, printed in compressed, gnark uncompressed and Ethereum encodings
+func runAggregateMode(mode string, args []string) error {
+	fs := newFlagSet(mode)
+	pointList := fs.String("points", "", "Compressed points (hex), comma separated")
+	pointFile := fs.String("file", "", "File with compressed points, one or more per line (overrides --points)")
+	ciphersuite := fs.String("ciphersuite", "min-pk", "Ciphersuite: min-pk (pubkeys G1, sigs G2) or min-sig (pubkeys G2, sigs G1)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	useG2, err := aggregateUsesG2(mode, *ciphersuite)
+	if err != nil {
+		return err
+	}
+	hexes, err := readPointList(*pointList, *pointFile)
+	if err != nil {
+		return err
+	}
+	if len(hexes) == 0 {
+		return fmt.Errorf("no points given (use --points or --file)")
+	}
+
+	what := "public keys"
+	if mode == "agg-sigs" {
+		what = "signatures"
+	}
+	group := "G1"
+	if useG2 {
+		group = "G2"
+	}
+	fmt.Printf("=== Aggregate %s (%s, %s) ===\n", what, *ciphersuite, group)
+	fmt.Printf("Inputs: %d\n", len(hexes))
+
+	var compressed, uncompressed, ethereum []byte
+	var infinities int
+	var isInfinity bool
+	if useG2 {
+		agg, n, err := aggregateG2(hexes)
+		if err != nil {
+			return err
+		}
+		compressed = serialization.ConvertG2AffineToCompressed(agg)
+		uncompressed = agg.Marshal()
+		ethereum = serialization.EncodeEthereumG2Point(agg)
+		infinities, isInfinity = n, agg.IsInfinity()
+	} else {
+		agg, n, err := aggregateG1(hexes)
+		if err != nil {
+			return err
+		}
+		compressed = serialization.ConvertG1AffineToCompressed(agg)
+		uncompressed = agg.Marshal()
+		ethereum = serialization.EncodeEthereumG1Point(agg)
+		infinities, isInfinity = n, agg.IsInfinity()
+	}
+
+	if infinities > 0 {
+		fmt.Printf("⚠️  %d input(s) are the point at infinity\n", infinities)
+	}
+	if isInfinity {
+		fmt.Println("⚠️  The aggregate is the point at infinity (verifiers must reject it as a public key)")
+	}
+	fmt.Printf("Aggregate (compressed): %x\n", compressed)
+	fmt.Printf("Aggregate (gnark uncompressed): %x\n", uncompressed)
+	fmt.Printf("Aggregate (Ethereum): %x\n", ethereum)
+	return nil
+}
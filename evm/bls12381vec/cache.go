@@ -0,0 +1,120 @@
+package bls12381vec
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// pointCacheCapacity bounds the number of parsed points kept in memory
+const pointCacheCapacity = 4096
+
+// pointCacheEntry is one parsed point. The subgroup check, the most expensive part of
+// decoding, is computed once on insertion and reused for every later lookup.
+type pointCacheEntry struct {
+	key        string
+	g1         bls.G1Affine
+	g2         bls.G2Affine
+	inSubgroup bool
+	err        error // decoding error (length, padding, canonicity, curve, flags)
+}
+
+// pointCache is a concurrency-safe LRU cache of parsed affine points keyed by encoding
+// kind and input bytes. Campaign runs decode the same generators and repeated inputs many
+// times; the cache turns every repeat into a map lookup.
+type pointCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newPointCache(capacity int) *pointCache {
+	return &pointCache{capacity: capacity, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// parsedPoints is the process-wide cache used by the strict decoders below
+var parsedPoints = newPointCache(pointCacheCapacity)
+
+// lookup returns the entry for key, computing and inserting it with decode on a miss.
+// decode runs outside the lock so concurrent misses on different keys do not serialize.
+func (c *pointCache) lookup(key string, decode func() pointCacheEntry) pointCacheEntry {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		entry := *el.Value.(*pointCacheEntry)
+		c.mu.Unlock()
+		return entry
+	}
+	c.mu.Unlock()
+
+	entry := decode()
+	entry.key = key
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		// Another goroutine inserted it meanwhile
+		c.ll.MoveToFront(el)
+		return *el.Value.(*pointCacheEntry)
+	}
+	c.items[key] = c.ll.PushFront(&entry)
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*pointCacheEntry).key)
+	}
+	return entry
+}
+
+// DecodeEIP2537G1Point is serialization.DecodeEIP2537G1Point backed by parsedPoints
+func DecodeEIP2537G1Point(data []byte, subgroupCheck bool) (bls.G1Affine, error) {
+	entry := parsedPoints.lookup("eth-g1:"+string(data), func() pointCacheEntry {
+		p, err := serialization.DecodeEIP2537G1Point(data, false)
+		return pointCacheEntry{g1: p, inSubgroup: err == nil && p.IsInSubGroup(), err: err}
+	})
+	if entry.err != nil {
+		return bls.G1Affine{}, entry.err
+	}
+	if subgroupCheck && !entry.inSubgroup {
+		return bls.G1Affine{}, fmt.Errorf("G1: %w", serialization.ErrNotInSubgroup)
+	}
+	return entry.g1, nil
+}
+
+// DecodeEIP2537G2Point is serialization.DecodeEIP2537G2Point backed by parsedPoints
+func DecodeEIP2537G2Point(data []byte, subgroupCheck bool) (bls.G2Affine, error) {
+	entry := parsedPoints.lookup("eth-g2:"+string(data), func() pointCacheEntry {
+		p, err := serialization.DecodeEIP2537G2Point(data, false)
+		return pointCacheEntry{g2: p, inSubgroup: err == nil && p.IsInSubGroup(), err: err}
+	})
+	if entry.err != nil {
+		return bls.G2Affine{}, entry.err
+	}
+	if subgroupCheck && !entry.inSubgroup {
+		return bls.G2Affine{}, fmt.Errorf("G2: %w", serialization.ErrNotInSubgroup)
+	}
+	return entry.g2, nil
+}
+
+// DecodeCompressedG1Point is serialization.DecodeCompressedG1Point backed by parsedPoints
+func DecodeCompressedG1Point(data []byte) (bls.G1Affine, error) {
+	entry := parsedPoints.lookup("cmp-g1:"+string(data), func() pointCacheEntry {
+		p, err := serialization.DecodeCompressedG1Point(data)
+		return pointCacheEntry{g1: p, inSubgroup: err == nil, err: err}
+	})
+	return entry.g1, entry.err
+}
+
+// DecodeCompressedG2Point is serialization.DecodeCompressedG2Point backed by parsedPoints
+func DecodeCompressedG2Point(data []byte) (bls.G2Affine, error) {
+	entry := parsedPoints.lookup("cmp-g2:"+string(data), func() pointCacheEntry {
+		p, err := serialization.DecodeCompressedG2Point(data)
+		return pointCacheEntry{g2: p, inSubgroup: err == nil, err: err}
+	})
+	return entry.g2, entry.err
+}
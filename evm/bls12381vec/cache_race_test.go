@@ -0,0 +1,129 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// The package functions keep no state between calls except the point cache and the
+// atomic switches, so they must be safe to call from many goroutines. TestConcurrentCalls
+// runs every operation from parallel subtests and compares each result with the
+// sequential one; run it under the race detector so data races are reported too:
+//
+//	go test -race -run TestConcurrentCalls ./bls12381vec
+
+// raceOp is one operation with its expected result, computed sequentially before the
+// concurrent phase
+type raceOp struct {
+	name     string
+	run      func() (string, error)
+	expected string
+}
+
+// racePoints returns k*g1 and k*g2 for k = seed+1 .. seed+n, so every vector set
+// decodes different points and exercises the point cache under contention
+func racePoints(seed, n int) ([]bls.G1Affine, []bls.G2Affine) {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	g1 := make([]bls.G1Affine, n)
+	g2 := make([]bls.G2Affine, n)
+	for i := 0; i < n; i++ {
+		k := big.NewInt(int64(seed + i + 1))
+		g1[i].ScalarMultiplication(&g1Gen, k)
+		g2[i].ScalarMultiplication(&g2Gen, k)
+	}
+	return g1, g2
+}
+
+// raceOps builds one vector of every operation per vector set
+func raceOps(vectors int) []raceOp {
+	_, _, _, g2Gen := bls.Generators()
+	var ops []raceOp
+	for v := 0; v < vectors; v++ {
+		g1, g2 := racePoints(3*v, 3)
+		scalars := []*big.Int{big.NewInt(int64(v + 2)), big.NewInt(0xffff), new(big.Int).Lsh(big.NewInt(1), 255)}
+
+		var msm1, msm2 []byte
+		for i := range g1 {
+			msm1 = append(msm1, serialization.EncodeEthereumG1Point(g1[i])...)
+			msm1 = append(msm1, scalarBytes(scalars[i])...)
+			msm2 = append(msm2, serialization.EncodeEthereumG2Point(g2[i])...)
+			msm2 = append(msm2, scalarBytes(scalars[i])...)
+		}
+		add1 := hexCat(serialization.EncodeEthereumG1Point(g1[0]), serialization.EncodeEthereumG1Point(g1[1]))
+		add2 := hexCat(serialization.EncodeEthereumG2Point(g2[0]), serialization.EncodeEthereumG2Point(g2[1]))
+		mul1 := hexCat(serialization.EncodeEthereumG1Point(g1[2]), scalarBytes(scalars[0]))
+		mul2 := hexCat(serialization.EncodeEthereumG2Point(g2[2]), scalarBytes(scalars[0]))
+		// e(a*g1, b*g2) * e(-(a*b)*g1, g2) == 1 with a = 3v+1, b = 3v+2
+		ab1, _ := racePoints((3*v+1)*(3*v+2)-1, 1)
+		ab1[0].Neg(&ab1[0])
+		pairing := hexCat(serialization.EncodeEthereumG1Point(g1[0]), serialization.EncodeEthereumG2Point(g2[1]),
+			serialization.EncodeEthereumG1Point(ab1[0]), serialization.EncodeEthereumG2Point(g2Gen))
+		c1 := serialization.ConvertG1AffineToCompressed(g1[0])
+		c2 := serialization.ConvertG2AffineToCompressed(g2[0])
+
+		tag := fmt.Sprintf("[%d]", v)
+		ops = append(ops,
+			raceOp{name: "g1add" + tag, run: func() (string, error) { return G1Add(add1) }},
+			raceOp{name: "g2add" + tag, run: func() (string, error) { return G2Add(add2) }},
+			raceOp{name: "g1mul" + tag, run: func() (string, error) { return G1Mul(mul1) }},
+			raceOp{name: "g2mul" + tag, run: func() (string, error) { return G2Mul(mul2) }},
+			raceOp{name: "g1msm" + tag, run: func() (string, error) { return G1MSM(hex.EncodeToString(msm1)) }},
+			raceOp{name: "g2msm" + tag, run: func() (string, error) { return G2MSM(hex.EncodeToString(msm2)) }},
+			raceOp{name: "naive-msm" + tag, run: func() (string, error) { return NaiveMSM(hex.EncodeToString(msm1), false) }},
+			raceOp{name: "multiexp-ethereum" + tag, run: func() (string, error) { return MultiExpFromEthereumFormat(hex.EncodeToString(msm1), false) }},
+			raceOp{name: "multiexp-compressed" + tag, run: func() (string, error) { return MultiExpFromCompressed(hex.EncodeToString(c1), scalars, false) }},
+			raceOp{name: "pairing" + tag, run: func() (string, error) { return Pairing(pairing) }},
+			raceOp{name: "pairing-naive" + tag, run: func() (string, error) { return PairingNaive(pairing) }},
+			raceOp{name: "decode-g1" + tag, run: func() (string, error) {
+				p, err := DecodeCompressedG1Point(c1)
+				return hex.EncodeToString(serialization.EncodeEthereumG1Point(p)), err
+			}},
+			raceOp{name: "decode-g2" + tag, run: func() (string, error) {
+				p, err := DecodeCompressedG2Point(c2)
+				return hex.EncodeToString(serialization.EncodeEthereumG2Point(p)), err
+			}},
+		)
+	}
+	return ops
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	vectors, iterations := 4, 4
+	if testing.Short() {
+		vectors, iterations = 2, 1
+	}
+	ops := raceOps(vectors)
+	for i := range ops {
+		result, err := ops[i].run()
+		if err != nil {
+			t.Fatalf("%s failed sequentially: %v", ops[i].name, err)
+		}
+		ops[i].expected = result
+	}
+
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		t.Run(fmt.Sprintf("worker%d", w), func(t *testing.T) {
+			t.Parallel()
+			for it := 0; it < iterations; it++ {
+				for j := range ops {
+					// Each worker starts at a different operation so different
+					// operations overlap, not only copies of the same one
+					op := ops[(j+w*7+it)%len(ops)]
+					got, err := op.run()
+					if err != nil {
+						t.Errorf("%s: %v", op.name, err)
+					} else if got != op.expected {
+						t.Errorf("%s: got %s, expected %s", op.name, got, op.expected)
+					}
+				}
+			}
+		})
+	}
+}
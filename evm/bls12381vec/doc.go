@@ -0,0 +1,11 @@
+// Package bls12381vec computes BLS12-381 test-vector results: the EIP-2537 operations
+// (G1Add, G2Add, G1Mul, G2Mul, G1MSM, G2MSM, Pairing), Neo-style MultiExp over Ethereum or
+// compressed input, and the point encodings they use. It is the library behind the
+// pairing_gen CLI and can be called directly from Go test suites:
+//
+//	out, err := bls12381vec.G1MSM(inputHex) // EIP-2537 output, hex
+//	res, err := bls12381vec.MultiExpFromEthereumFormat(inputHex, false) // compressed, hex
+//
+// Inputs and outputs are hex strings, as on the command line. Parsed points are kept in a
+// process-wide LRU cache (see DecodeEIP2537G1Point), which is safe for concurrent use.
+package bls12381vec
@@ -0,0 +1,73 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// The point encodings live in package serialization; they are re-exported here so a test
+// suite needs a single import.
+
+// ParseEthereumG1Point parses a 128-byte EIP-2537 G1 point (see serialization.ParseEthereumG1PointFromBytes)
+func ParseEthereumG1Point(data []byte) (bls.G1Affine, error) {
+	return serialization.ParseEthereumG1PointFromBytes(data)
+}
+
+// ParseEthereumG2Point parses a 256-byte EIP-2537 G2 point (see serialization.ParseEthereumG2PointFromBytes)
+func ParseEthereumG2Point(data []byte) (bls.G2Affine, error) {
+	return serialization.ParseEthereumG2PointFromBytes(data)
+}
+
+// EncodeEthereumG1Point encodes a G1 point as 128 EIP-2537 bytes
+func EncodeEthereumG1Point(p bls.G1Affine) []byte {
+	return serialization.EncodeEthereumG1Point(p)
+}
+
+// EncodeEthereumG2Point encodes a G2 point as 256 EIP-2537 bytes
+func EncodeEthereumG2Point(p bls.G2Affine) []byte {
+	return serialization.EncodeEthereumG2Point(p)
+}
+
+// CompressG1 encodes a G1 point as 48 bytes, matching Neo's G1Affine.ToCompressed()
+func CompressG1(p bls.G1Affine) []byte {
+	return serialization.ConvertG1AffineToCompressed(p)
+}
+
+// CompressG2 encodes a G2 point as 96 bytes ([x.C1, x.C0]), matching Neo's G2Affine.ToCompressed()
+func CompressG2(p bls.G2Affine) []byte {
+	return serialization.ConvertG2AffineToCompressed(p)
+}
+
+// CompressedToUncompressedHex converts a compressed point hex string back to the
+// uncompressed hex form (96 bytes for G1, 192 bytes for G2) for display.
+func CompressedToUncompressedHex(compressedHex string, useG2 bool) (string, error) {
+	bytes, err := hex.DecodeString(strings.TrimSpace(compressedHex))
+	if err != nil {
+		return "", fmt.Errorf("invalid compressed hex: %w", err)
+	}
+
+	if useG2 {
+		if len(bytes) != 96 {
+			return "", fmt.Errorf("compressed G2 value must be 96 bytes, got %d", len(bytes))
+		}
+		var point bls.G2Affine
+		if _, err := point.SetBytes(bytes); err != nil {
+			return "", fmt.Errorf("failed to parse compressed G2: %w", err)
+		}
+		return hex.EncodeToString(point.Marshal()), nil
+	}
+
+	if len(bytes) != 48 {
+		return "", fmt.Errorf("compressed G1 value must be 48 bytes, got %d", len(bytes))
+	}
+	var point bls.G1Affine
+	if _, err := point.SetBytes(bytes); err != nil {
+		return "", fmt.Errorf("failed to parse compressed G1: %w", err)
+	}
+	return hex.EncodeToString(point.Marshal()), nil
+}
@@ -0,0 +1,89 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// EIP-2537 MSM pair sizes: point (128 / 256 bytes) + scalar (32 bytes)
+const (
+	G1MSMPairLength = 128 + 32
+	G2MSMPairLength = 256 + 32
+)
+
+// ReduceScalarModR reduces a 32-byte big-endian scalar modulo the group order r.
+// EIP-2537 does not require scalars to be canonical, so values >= r are accepted.
+func ReduceScalarModR(data []byte) *big.Int {
+	s := new(big.Int).SetBytes(data)
+	return s.Mod(s, fr.Modulus())
+}
+
+// G1MSM computes G1 multi-scalar multiplication with exact EIP-2537 (G1MSM, 0x0c) semantics
+// Input: k pairs of Ethereum format G1 point (128 bytes) + scalar (32 bytes), k >= 1
+// Output: Ethereum format G1 point (128 bytes)
+// Every point must be canonical, on the curve and in the subgroup; empty input is rejected.
+func G1MSM(inputHex string) (string, error) {
+	inputBytes, err := hex.DecodeString(strings.TrimSpace(inputHex))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+	if len(inputBytes) == 0 || len(inputBytes)%G1MSMPairLength != 0 {
+		return "", fmt.Errorf("G1MSM input length must be a non-zero multiple of %d bytes, got %d", G1MSMPairLength, len(inputBytes))
+	}
+
+	n := len(inputBytes) / G1MSMPairLength
+	points := make([]bls.G1Affine, n)
+	scalars := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		offset := i * G1MSMPairLength
+		points[i], err = DecodeEIP2537G1Point(inputBytes[offset:offset+128], true)
+		if err != nil {
+			return "", fmt.Errorf("invalid G1 point at pair %d: %v", i, err)
+		}
+		scalars[i] = ReduceScalarModR(inputBytes[offset+128 : offset+G1MSMPairLength])
+	}
+
+	result, err := MultiExpG1(points, scalars)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(serialization.EncodeEthereumG1Point(result)), nil
+}
+
+// G2MSM computes G2 multi-scalar multiplication with exact EIP-2537 (G2MSM, 0x0e) semantics
+// Input: k pairs of Ethereum format G2 point (256 bytes) + scalar (32 bytes), k >= 1
+// Output: Ethereum format G2 point (256 bytes)
+func G2MSM(inputHex string) (string, error) {
+	inputBytes, err := hex.DecodeString(strings.TrimSpace(inputHex))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+	if len(inputBytes) == 0 || len(inputBytes)%G2MSMPairLength != 0 {
+		return "", fmt.Errorf("G2MSM input length must be a non-zero multiple of %d bytes, got %d", G2MSMPairLength, len(inputBytes))
+	}
+
+	n := len(inputBytes) / G2MSMPairLength
+	points := make([]bls.G2Affine, n)
+	scalars := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		offset := i * G2MSMPairLength
+		points[i], err = DecodeEIP2537G2Point(inputBytes[offset:offset+256], true)
+		if err != nil {
+			return "", fmt.Errorf("invalid G2 point at pair %d: %v", i, err)
+		}
+		scalars[i] = ReduceScalarModR(inputBytes[offset+256 : offset+G2MSMPairLength])
+	}
+
+	result, err := MultiExpG2(points, scalars)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(serialization.EncodeEthereumG2Point(result)), nil
+}
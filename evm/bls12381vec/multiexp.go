@@ -0,0 +1,185 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// MultiExpFromEthereumFormat computes MultiExp result from Ethereum format (uncompressed) G1/G2 point and scalars
+// This function is convenient for using Neo's Ethereum test vectors directly
+// Parameters:
+//   - inputHex: Ethereum format input (for G1: 160 bytes = 128 bytes point + 32 bytes scalar per pair)
+//   - useG2: true for G2, false for G1
+//
+// Returns: Compressed result point in hex string
+func MultiExpFromEthereumFormat(inputHex string, useG2 bool) (string, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	inputBytes, err := hex.DecodeString(inputHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+
+	if useG2 {
+		// G2 format: 288 bytes per pair = 256 bytes point + 32 bytes scalar
+		if len(inputBytes)%288 != 0 {
+			return "", fmt.Errorf("G2 input length must be multiple of 288 bytes, got %d", len(inputBytes))
+		}
+
+		var points []bls.G2Affine
+		var scalars []*big.Int
+
+		for offset := 0; offset < len(inputBytes); offset += 288 {
+			pointBytes := inputBytes[offset : offset+256]
+			scalarBytes := inputBytes[offset+256 : offset+288]
+
+			// Parse G2 point from Ethereum format (256 bytes)
+			g2Point, err := serialization.ParseEthereumG2PointFromBytes(pointBytes)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse G2 point at offset %d: %v", offset, err)
+			}
+
+			scalar := ParseEthereumScalar(scalarBytes)
+			points = append(points, g2Point)
+			scalars = append(scalars, scalar)
+		}
+
+		// Compute MultiExp: point1 × scalar1 + point2 × scalar2 + ...
+		resultAffine, err := MultiExpG2(points, scalars)
+		if err != nil {
+			return "", err
+		}
+
+		resultCompressed := serialization.ConvertG2AffineToCompressed(resultAffine)
+		return hex.EncodeToString(resultCompressed), nil
+	} else {
+		// G1 format: 160 bytes per pair = 128 bytes point + 32 bytes scalar
+		if len(inputBytes)%160 != 0 {
+			return "", fmt.Errorf("G1 input length must be multiple of 160 bytes, got %d", len(inputBytes))
+		}
+
+		var points []bls.G1Affine
+		var scalars []*big.Int
+
+		for offset := 0; offset < len(inputBytes); offset += 160 {
+			pointBytes := inputBytes[offset : offset+128]
+			scalarBytes := inputBytes[offset+128 : offset+160]
+
+			// Parse G1 point from Ethereum format (128 bytes)
+			g1Point, err := serialization.ParseEthereumG1PointFromBytes(pointBytes)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse G1 point at offset %d: %v", offset, err)
+			}
+
+			scalar := ParseEthereumScalar(scalarBytes)
+			points = append(points, g1Point)
+			scalars = append(scalars, scalar)
+		}
+
+		// Compute MultiExp: point1 × scalar1 + point2 × scalar2 + ...
+		resultAffine, err := MultiExpG1(points, scalars)
+		if err != nil {
+			return "", err
+		}
+
+		resultCompressed := serialization.ConvertG1AffineToCompressed(resultAffine)
+		return hex.EncodeToString(resultCompressed), nil
+	}
+}
+
+// MultiExpFromCompressed computes MultiExp result from compressed G1/G2 point and scalars
+// This function uses gnark-crypto API directly, independent of C# implementation logic
+// Parameters:
+//   - pointHex: Compressed G1 (96 hex chars) or G2 (192 hex chars) point in hex string
+//   - scalars: Array of scalar values (BigInteger values)
+//   - useG2: true for G2, false for G1
+//
+// Returns: Compressed result point in hex string
+func MultiExpFromCompressed(pointHex string, scalars []*big.Int, useG2 bool) (string, error) {
+	// Parse hex string to bytes
+	pointHex = strings.TrimSpace(pointHex)
+	pointBytes, err := hex.DecodeString(pointHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse point hex: %v", err)
+	}
+
+	if useG2 {
+		// G2 MultiExp
+		if len(pointBytes) != 96 {
+			return "", fmt.Errorf("G2 point must be 96 bytes (compressed), got %d", len(pointBytes))
+		}
+
+		// Deserialize compressed G2 point
+		var g2Affine bls.G2Affine
+		if _, err := g2Affine.SetBytes(pointBytes); err != nil {
+			return "", fmt.Errorf("failed to deserialize G2 point: %v", err)
+		}
+
+		// Compute MultiExp: point × scalar₁ + point × scalar₂ + ... as a MultiExp over the
+		// repeated point rather than point × (scalar₁ + scalar₂ + ...), the same sum Neo computes
+		points := make([]bls.G2Affine, len(scalars))
+		for i := range points {
+			points[i] = g2Affine
+		}
+		resultG2, err := MultiExpG2(points, scalars)
+		if err != nil {
+			return "", err
+		}
+
+		// Serialize to compressed format
+		g2ResultUncompressed := resultG2.Marshal()
+		if len(g2ResultUncompressed) != 192 {
+			return "", fmt.Errorf("unexpected G2 uncompressed length: %d", len(g2ResultUncompressed))
+		}
+
+		// Use the helper function to ensure correct format
+		g2ResultCompressed := serialization.ConvertG2AffineToCompressed(resultG2)
+		return fmt.Sprintf("%x", g2ResultCompressed), nil
+	} else {
+		// G1 MultiExp
+		if len(pointBytes) != 48 {
+			return "", fmt.Errorf("G1 point must be 48 bytes (compressed), got %d", len(pointBytes))
+		}
+
+		// Deserialize compressed G1 point
+		var g1Affine bls.G1Affine
+		if _, err := g1Affine.SetBytes(pointBytes); err != nil {
+			return "", fmt.Errorf("failed to deserialize G1 point: %v", err)
+		}
+
+		// Compute MultiExp: point × scalar₁ + point × scalar₂ + ... as a MultiExp over the
+		// repeated point rather than point × (scalar₁ + scalar₂ + ...), the same sum Neo computes
+		points := make([]bls.G1Affine, len(scalars))
+		for i := range points {
+			points[i] = g1Affine
+		}
+		resultG1, err := MultiExpG1(points, scalars)
+		if err != nil {
+			return "", err
+		}
+
+		// Serialize to compressed format
+		g1ResultUncompressed := resultG1.Marshal()
+		if len(g1ResultUncompressed) != 96 {
+			return "", fmt.Errorf("unexpected G1 uncompressed length: %d", len(g1ResultUncompressed))
+		}
+
+		// Convert to compressed format (48 bytes)
+		g1ResultCompressed := make([]byte, 48)
+		copy(g1ResultCompressed, g1ResultUncompressed[:48]) // Extract x coordinate
+		g1ResultCompressed[0] |= 0x80                       // Set compression flag
+
+		// Set y coordinate sort flag using lexicographically largest check
+		yBytes := g1ResultUncompressed[48:96]
+		if serialization.IsLexicographicallyLargestFp(yBytes) {
+			g1ResultCompressed[0] |= 0x20
+		}
+
+		return fmt.Sprintf("%x", g1ResultCompressed), nil
+	}
+}
@@ -0,0 +1,328 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// ParseEthereumScalar parses a scalar from Ethereum format (32 bytes, big-endian)
+func ParseEthereumScalar(data []byte) *big.Int {
+	return new(big.Int).SetBytes(data)
+}
+
+// G1Add computes G1 point addition: p1 + p2
+// Input: two Ethereum format G1 points (128 bytes each = 256 bytes total)
+// Output: Ethereum format G1 point (128 bytes)
+func G1Add(inputHex string) (string, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	inputBytes, err := hex.DecodeString(inputHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+
+	if len(inputBytes) != 256 {
+		return "", fmt.Errorf("G1 add input must be 256 bytes (128 bytes per point), got %d", len(inputBytes))
+	}
+
+	// Parse two G1 points
+	p1, err := serialization.ParseEthereumG1PointFromBytes(inputBytes[0:128])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse first G1 point: %v", err)
+	}
+
+	p2, err := serialization.ParseEthereumG1PointFromBytes(inputBytes[128:256])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse second G1 point: %v", err)
+	}
+
+	// Compute addition: p1 + p2
+	var p1Jac bls.G1Jac
+	p1Jac.FromAffine(&p1)
+	var p2Jac bls.G1Jac
+	p2Jac.FromAffine(&p2)
+	p1Jac.AddAssign(&p2Jac)
+
+	var result bls.G1Affine
+	result.FromJacobian(&p1Jac)
+
+	// Encode result to Ethereum format
+	resultBytes := serialization.EncodeEthereumG1Point(result)
+	return hex.EncodeToString(resultBytes), nil
+}
+
+// G2Add computes G2 point addition: p1 + p2
+// Input: two Ethereum format G2 points (256 bytes each = 512 bytes total)
+// Output: Ethereum format G2 point (256 bytes)
+// This function follows gnark-crypto standard and is compatible with Bls12381MultiExpHelper.cs
+func G2Add(inputHex string) (string, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	inputBytes, err := hex.DecodeString(inputHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+
+	if len(inputBytes) != 512 {
+		return "", fmt.Errorf("G2 add input must be 512 bytes (256 bytes per point), got %d", len(inputBytes))
+	}
+
+	// Parse two G2 points from Ethereum format
+	// Create separate slices to avoid potential slice sharing issues
+	point1Data := make([]byte, 256)
+	copy(point1Data, inputBytes[0:256])
+	point2Data := make([]byte, 256)
+	copy(point2Data, inputBytes[256:512])
+
+	// Verify point2Data's x.C0 padding is zero before parsing
+	for i := 0; i < 16; i++ {
+		if point2Data[i] != 0 {
+			return "", fmt.Errorf("second point x.C0 padding byte[%d] is non-zero: 0x%02x. Input data may be corrupted. First point y.C0 data (bytes 144-160): %x", i, point2Data[i], inputBytes[144:160])
+		}
+	}
+
+	p1, err := serialization.ParseEthereumG2PointFromBytes(point1Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse first G2 point: %v", err)
+	}
+
+	p2, err := serialization.ParseEthereumG2PointFromBytes(point2Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse second G2 point: %v", err)
+	}
+
+	// Compute addition: p1 + p2 using gnark-crypto standard API
+	// Convert to Jacobian coordinates for efficient addition
+	var p1Jac bls.G2Jac
+	p1Jac.FromAffine(&p1)
+	var p2Jac bls.G2Jac
+	p2Jac.FromAffine(&p2)
+
+	// Perform addition: p1Jac = p1Jac + p2Jac
+	p1Jac.AddAssign(&p2Jac)
+
+	// Convert back to Affine coordinates
+	var result bls.G2Affine
+	result.FromJacobian(&p1Jac)
+
+	// Encode result to Ethereum format
+	resultBytes := serialization.EncodeEthereumG2Point(result)
+	return hex.EncodeToString(resultBytes), nil
+}
+
+// G1Mul computes G1 point multiplication: point * scalar
+// Input: Ethereum format G1 point (128 bytes) + scalar (32 bytes) = 160 bytes total
+// Output: Ethereum format G1 point (128 bytes)
+func G1Mul(inputHex string) (string, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	inputBytes, err := hex.DecodeString(inputHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+
+	if len(inputBytes) != 160 {
+		return "", fmt.Errorf("G1 mul input must be 160 bytes (128 bytes point + 32 bytes scalar), got %d", len(inputBytes))
+	}
+
+	// Parse G1 point and scalar
+	point, err := serialization.ParseEthereumG1PointFromBytes(inputBytes[0:128])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse G1 point: %v", err)
+	}
+
+	scalar := ParseEthereumScalar(inputBytes[128:160])
+
+	// Compute multiplication: point * scalar
+	var pointJac bls.G1Jac
+	pointJac.FromAffine(&point)
+	pointJac.ScalarMultiplication(&pointJac, scalar)
+
+	var result bls.G1Affine
+	result.FromJacobian(&pointJac)
+
+	// Encode result to Ethereum format
+	resultBytes := serialization.EncodeEthereumG1Point(result)
+	return hex.EncodeToString(resultBytes), nil
+}
+
+// G2Mul computes G2 point multiplication: point * scalar
+// Input: Ethereum format G2 point (256 bytes) + scalar (32 bytes) = 288 bytes total
+// Output: Ethereum format G2 point (256 bytes)
+func G2Mul(inputHex string) (string, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	inputBytes, err := hex.DecodeString(inputHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+
+	if len(inputBytes) != 288 {
+		return "", fmt.Errorf("G2 mul input must be 288 bytes (256 bytes point + 32 bytes scalar), got %d", len(inputBytes))
+	}
+
+	// Parse G2 point and scalar
+	point, err := serialization.ParseEthereumG2PointFromBytes(inputBytes[0:256])
+	if err != nil {
+		return "", fmt.Errorf("failed to parse G2 point: %v", err)
+	}
+
+	scalar := ParseEthereumScalar(inputBytes[256:288])
+
+	// Compute multiplication: point * scalar
+	var pointJac bls.G2Jac
+	pointJac.FromAffine(&point)
+	pointJac.ScalarMultiplication(&pointJac, scalar)
+
+	var result bls.G2Affine
+	result.FromJacobian(&pointJac)
+
+	// Encode result to Ethereum format
+	resultBytes := serialization.EncodeEthereumG2Point(result)
+	return hex.EncodeToString(resultBytes), nil
+}
+
+// Pairing computes BLS12-381 pairing: e(g1_1, g2_1) * e(g1_2, g2_2) * ...
+// Input: Ethereum format pairs, each pair is G1 (128 bytes) + G2 (256 bytes) = 384 bytes
+// Output: 32 bytes, last byte is 1 if pairing result is identity (unit element), 0 otherwise
+// This matches Neo's Bls12Pairing implementation. All pairs go through one multi-Miller
+// loop and a single final exponentiation; PairingNaive is the pair-by-pair reference.
+func Pairing(inputHex string) (string, error) {
+	g1Points, g2Points, err := parsePairingInput(inputHex)
+	if err != nil {
+		return "", err
+	}
+	if len(g1Points) == 0 {
+		// Empty input: return identity (unit element) = 1
+		return encodePairingResult(true), nil
+	}
+	ok, err := bls.PairingCheck(g1Points, g2Points)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute pairing: %v", err)
+	}
+	return encodePairingResult(ok), nil
+}
+
+// PairingNaive computes the same result as Pairing with one full pairing (Miller loop and
+// final exponentiation) per pair, multiplying the GT results, for differential comparison
+func PairingNaive(inputHex string) (string, error) {
+	g1Points, g2Points, err := parsePairingInput(inputHex)
+	if err != nil {
+		return "", err
+	}
+
+	// Compute pairing product
+	var accumulator bls.GT
+	accumulator.SetOne() // Start with identity element
+	for i := range g1Points {
+		// Compute pairing: e(g1, g2)
+		pairResult, err := bls.Pair([]bls.G1Affine{g1Points[i]}, []bls.G2Affine{g2Points[i]})
+		if err != nil {
+			return "", fmt.Errorf("failed to compute pairing at pair %d: %v", i, err)
+		}
+
+		// Multiply accumulator by pair result: accumulator = accumulator * pairResult
+		accumulator.Mul(&accumulator, &pairResult)
+	}
+
+	// Check if result is identity (unit element)
+	var identity bls.GT
+	identity.SetOne()
+	return encodePairingResult(accumulator.Equal(&identity)), nil
+}
+
+// PairingGT returns the pairing product e(g1_1, g2_1) * e(g1_2, g2_2) * ... itself, the
+// value Pairing reduces to a bool; empty input gives the identity. It is what Neo's
+// CryptoLib.Bls12381Pairing returns (a Gt, for one pair) instead of a check result.
+func PairingGT(inputHex string) (bls.GT, error) {
+	var z bls.GT
+	g1Points, g2Points, err := parsePairingInput(inputHex)
+	if err != nil {
+		return z, err
+	}
+	if len(g1Points) == 0 {
+		z.SetOne()
+		return z, nil
+	}
+	if z, err = bls.Pair(g1Points, g2Points); err != nil {
+		return z, fmt.Errorf("failed to compute pairing: %v", err)
+	}
+	return z, nil
+}
+
+// MillerLoop computes the product of the Miller loops of an Ethereum-format pairing input,
+// before the final exponentiation. The empty input gives 1, like PairingGT.
+func MillerLoop(inputHex string) (bls.GT, error) {
+	var z bls.GT
+	g1Points, g2Points, err := parsePairingInput(inputHex)
+	if err != nil {
+		return z, err
+	}
+	if len(g1Points) == 0 {
+		z.SetOne()
+		return z, nil
+	}
+	if z, err = bls.MillerLoop(g1Points, g2Points); err != nil {
+		return z, fmt.Errorf("failed to compute Miller loop: %v", err)
+	}
+	return z, nil
+}
+
+// parsePairingInput splits an Ethereum-format pairing input into its G1 and G2 points
+func parsePairingInput(inputHex string) ([]bls.G1Affine, []bls.G2Affine, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	inputBytes, err := hex.DecodeString(inputHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse input hex: %v", err)
+	}
+	// Create a copy to avoid any potential modifications by gnark-crypto
+	inputBytesCopy := make([]byte, len(inputBytes))
+	copy(inputBytesCopy, inputBytes)
+	inputBytes = inputBytesCopy
+
+	// Each pair is 384 bytes: 128 bytes G1 + 256 bytes G2
+	const pairLength = 128 + 256 // 384 bytes
+	if len(inputBytes)%pairLength != 0 {
+		return nil, nil, fmt.Errorf("pairing input must be multiple of %d bytes (each pair is %d bytes), got %d", pairLength, pairLength, len(inputBytes))
+	}
+
+	numPairs := len(inputBytes) / pairLength
+	g1Points := make([]bls.G1Affine, numPairs)
+	g2Points := make([]bls.G2Affine, numPairs)
+	for i := 0; i < numPairs; i++ {
+		offset := i * pairLength
+		g1Bytes := inputBytes[offset : offset+128]
+		g2Bytes := inputBytes[offset+128 : offset+pairLength]
+
+		// Create copies to avoid any potential modifications to inputBytes by gnark-crypto
+		g1BytesCopy := make([]byte, len(g1Bytes))
+		copy(g1BytesCopy, g1Bytes)
+		g2BytesCopy := make([]byte, len(g2Bytes))
+		copy(g2BytesCopy, g2Bytes)
+
+		// Parse G1 point from Ethereum format (using copy)
+		g1Points[i], err = serialization.ParseEthereumG1PointFromBytes(g1BytesCopy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse G1 point at pair %d: %v", i, err)
+		}
+
+		// Parse G2 point from Ethereum format (using copy)
+		g2Points[i], err = serialization.ParseEthereumG2PointFromBytes(g2BytesCopy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse G2 point at pair %d: %v", i, err)
+		}
+	}
+	return g1Points, g2Points, nil
+}
+
+// encodePairingResult encodes a pairing check as 32 bytes, last byte 1 if the product is
+// the identity and 0 otherwise
+func encodePairingResult(isIdentity bool) string {
+	result := make([]byte, 32)
+	if isIdentity {
+		result[31] = 1
+	}
+	return hex.EncodeToString(result)
+}
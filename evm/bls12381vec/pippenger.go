@@ -0,0 +1,167 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// crossCheckMSM makes every MultiExpG1/MultiExpG2 call also run the naive pair-by-pair
+// loop and fail if the two results differ. It is atomic so it can be toggled while other
+// goroutines compute MSMs.
+var crossCheckMSM atomic.Bool
+
+// SetCrossCheckMSM enables or disables the naive cross-check of every MultiExp
+func SetCrossCheckMSM(enabled bool) {
+	crossCheckMSM.Store(enabled)
+}
+
+// frScalars converts scalars for gnark's MultiExp, which works on Fr elements. Reducing
+// mod r only preserves the result for points in the r-torsion, so ok is false when a
+// scalar outside [0, r) belongs to a point that is not in the subgroup.
+func frScalars(scalars []*big.Int, inSubgroup func(i int) bool) (out []fr.Element, ok bool) {
+	out = make([]fr.Element, len(scalars))
+	for i, s := range scalars {
+		if (s.Sign() < 0 || s.Cmp(fr.Modulus()) >= 0) && !inSubgroup(i) {
+			return nil, false
+		}
+		out[i].SetBigInt(s)
+	}
+	return out, true
+}
+
+// MultiExpG1 computes scalars[0] * points[0] + ... with gnark's bucket-method MSM
+// (Pippenger). Inputs the bucket method cannot represent exactly (see frScalars) use the
+// naive loop.
+func MultiExpG1(points []bls.G1Affine, scalars []*big.Int) (bls.G1Affine, error) {
+	var result bls.G1Affine
+	if len(points) != len(scalars) {
+		return result, fmt.Errorf("MultiExp needs one scalar per point, got %d points and %d scalars", len(points), len(scalars))
+	}
+	if len(points) == 0 {
+		return result, nil
+	}
+	frs, ok := frScalars(scalars, func(i int) bool { return points[i].IsInSubGroup() })
+	if !ok {
+		return NaiveMultiExpG1(points, scalars), nil
+	}
+	if _, err := result.MultiExp(points, frs, ecc.MultiExpConfig{}); err != nil {
+		return result, fmt.Errorf("G1 MultiExp failed: %v", err)
+	}
+	if crossCheckMSM.Load() {
+		if naive := NaiveMultiExpG1(points, scalars); !naive.Equal(&result) {
+			return result, fmt.Errorf("G1 MSM cross-check failed: bucket method %x, naive loop %x",
+				serialization.ConvertG1AffineToCompressed(result), serialization.ConvertG1AffineToCompressed(naive))
+		}
+	}
+	return result, nil
+}
+
+// MultiExpG2 is MultiExpG1 for G2
+func MultiExpG2(points []bls.G2Affine, scalars []*big.Int) (bls.G2Affine, error) {
+	var result bls.G2Affine
+	if len(points) != len(scalars) {
+		return result, fmt.Errorf("MultiExp needs one scalar per point, got %d points and %d scalars", len(points), len(scalars))
+	}
+	if len(points) == 0 {
+		return result, nil
+	}
+	frs, ok := frScalars(scalars, func(i int) bool { return points[i].IsInSubGroup() })
+	if !ok {
+		return NaiveMultiExpG2(points, scalars), nil
+	}
+	if _, err := result.MultiExp(points, frs, ecc.MultiExpConfig{}); err != nil {
+		return result, fmt.Errorf("G2 MultiExp failed: %v", err)
+	}
+	if crossCheckMSM.Load() {
+		if naive := NaiveMultiExpG2(points, scalars); !naive.Equal(&result) {
+			return result, fmt.Errorf("G2 MSM cross-check failed: bucket method %x, naive loop %x",
+				serialization.ConvertG2AffineToCompressed(result), serialization.ConvertG2AffineToCompressed(naive))
+		}
+	}
+	return result, nil
+}
+
+// NaiveMultiExpG1 is the reference loop: one ScalarMultiplication per pair, then additions
+func NaiveMultiExpG1(points []bls.G1Affine, scalars []*big.Int) bls.G1Affine {
+	var acc bls.G1Jac
+	for i := range points {
+		var pJac, term bls.G1Jac
+		pJac.FromAffine(&points[i])
+		term.ScalarMultiplication(&pJac, scalars[i])
+		if i == 0 {
+			acc.Set(&term)
+		} else {
+			acc.AddAssign(&term)
+		}
+	}
+	var result bls.G1Affine
+	result.FromJacobian(&acc)
+	return result
+}
+
+// NaiveMultiExpG2 is NaiveMultiExpG1 for G2
+func NaiveMultiExpG2(points []bls.G2Affine, scalars []*big.Int) bls.G2Affine {
+	var acc bls.G2Jac
+	for i := range points {
+		var pJac, term bls.G2Jac
+		pJac.FromAffine(&points[i])
+		term.ScalarMultiplication(&pJac, scalars[i])
+		if i == 0 {
+			acc.Set(&term)
+		} else {
+			acc.AddAssign(&term)
+		}
+	}
+	var result bls.G2Affine
+	result.FromJacobian(&acc)
+	return result
+}
+
+// NaiveMSM computes an EIP-2537 MSM input with the naive loop, for cross-checks
+// Input: k pairs of Ethereum format point + 32-byte scalar, decoded strictly
+// Output: Ethereum format point
+func NaiveMSM(inputHex string, useG2 bool) (string, error) {
+	inputBytes, err := hex.DecodeString(strings.TrimSpace(inputHex))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+	pairLen := G1MSMPairLength
+	if useG2 {
+		pairLen = G2MSMPairLength
+	}
+	if len(inputBytes) == 0 || len(inputBytes)%pairLen != 0 {
+		return "", fmt.Errorf("MSM input length must be a non-zero multiple of %d bytes, got %d", pairLen, len(inputBytes))
+	}
+	var g1Points []bls.G1Affine
+	var g2Points []bls.G2Affine
+	var scalars []*big.Int
+	for offset := 0; offset < len(inputBytes); offset += pairLen {
+		if useG2 {
+			p, err := DecodeEIP2537G2Point(inputBytes[offset:offset+256], true)
+			if err != nil {
+				return "", fmt.Errorf("invalid G2 point at pair %d: %v", offset/pairLen, err)
+			}
+			g2Points = append(g2Points, p)
+		} else {
+			p, err := DecodeEIP2537G1Point(inputBytes[offset:offset+128], true)
+			if err != nil {
+				return "", fmt.Errorf("invalid G1 point at pair %d: %v", offset/pairLen, err)
+			}
+			g1Points = append(g1Points, p)
+		}
+		scalars = append(scalars, ReduceScalarModR(inputBytes[offset+pairLen-32:offset+pairLen]))
+	}
+	if useG2 {
+		return hex.EncodeToString(serialization.EncodeEthereumG2Point(NaiveMultiExpG2(g2Points, scalars))), nil
+	}
+	return hex.EncodeToString(serialization.EncodeEthereumG1Point(NaiveMultiExpG1(g1Points, scalars))), nil
+}
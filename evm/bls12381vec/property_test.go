@@ -0,0 +1,188 @@
+package bls12381vec
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Algebraic properties of the EIP-2537 operations over random inputs, driven by
+// testing/quick: addition commutes, MSM is linear in the scalars and agrees with repeated
+// addition, and the pairing is bilinear. Random points are generator multiples with
+// quick-generated 256-bit scalars.
+
+// quickScalar turns a quick-generated value into a scalar in [0, r-1]
+func quickScalar(limbs [4]uint64) *big.Int {
+	v := new(big.Int)
+	for _, l := range limbs {
+		v.Lsh(v, 64)
+		v.Or(v, new(big.Int).SetUint64(l))
+	}
+	return v.Mod(v, fr.Modulus())
+}
+
+func quickG1(limbs [4]uint64) []byte {
+	_, _, g1Gen, _ := bls.Generators()
+	var p bls.G1Affine
+	p.ScalarMultiplication(&g1Gen, quickScalar(limbs))
+	return serialization.EncodeEthereumG1Point(p)
+}
+
+func quickG2(limbs [4]uint64) []byte {
+	_, _, _, g2Gen := bls.Generators()
+	var p bls.G2Affine
+	p.ScalarMultiplication(&g2Gen, quickScalar(limbs))
+	return serialization.EncodeEthereumG2Point(p)
+}
+
+// hexCat hex-encodes the concatenation of parts, the input format of every operation
+func hexCat(parts ...[]byte) string {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return hex.EncodeToString(b)
+}
+
+func scalarBytes(s *big.Int) []byte {
+	out := make([]byte, 32)
+	s.FillBytes(out)
+	return out
+}
+
+func TestAddCommutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		add   func(string) (string, error)
+		point func([4]uint64) []byte
+	}{
+		{"G1", G1Add, quickG1},
+		{"G2", G2Add, quickG2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prop := func(a, b [4]uint64) bool {
+				p, q := tc.point(a), tc.point(b)
+				pq, err1 := tc.add(hexCat(p, q))
+				qp, err2 := tc.add(hexCat(q, p))
+				return err1 == nil && err2 == nil && pq == qp
+			}
+			if err := quick.Check(prop, &quick.Config{MaxCount: 50}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMSMLinearity(t *testing.T) {
+	tests := []struct {
+		name  string
+		msm   func(string) (string, error)
+		point func([4]uint64) []byte
+		useG2 bool
+	}{
+		{"G1", G1MSM, quickG1, false},
+		{"G2", G2MSM, quickG2, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+" msm(P,a) + msm(P,b) = msm(P,a+b)", func(t *testing.T) {
+			prop := func(k, a, b [4]uint64) bool {
+				sa, sb := quickScalar(a), quickScalar(b)
+				sum := new(big.Int).Add(sa, sb)
+				sum.Mod(sum, fr.Modulus())
+				p := tc.point(k)
+				two := hexCat(p, scalarBytes(sa), p, scalarBytes(sb))
+				one := hexCat(p, scalarBytes(sum))
+				gotTwo, err1 := tc.msm(two)
+				gotOne, err2 := tc.msm(one)
+				if err1 != nil || err2 != nil || gotTwo != gotOne {
+					return false
+				}
+				if tc.useG2 {
+					// The Neo path prints debug output for every G2 point; the EIP-2537 path is enough
+					return true
+				}
+				neoTwo, err1 := MultiExpFromEthereumFormat(two, false)
+				neoOne, err2 := MultiExpFromEthereumFormat(one, false)
+				return err1 == nil && err2 == nil && neoTwo == neoOne
+			}
+			if err := quick.Check(prop, &quick.Config{MaxCount: 25}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMSMMatchesRepeatedAdd(t *testing.T) {
+	tests := []struct {
+		name  string
+		msm   func(string) (string, error)
+		add   func(string) (string, error)
+		point func([4]uint64) []byte
+	}{
+		{"G1", G1MSM, G1Add, quickG1},
+		{"G2", G2MSM, G2Add, quickG2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Small scalars keep the repeated addition short
+			prop := func(k [4]uint64, n uint8) bool {
+				p := tc.point(k)
+				want := hex.EncodeToString(make([]byte, len(p))) // n = 0 gives infinity
+				for i := 0; i < int(n%32); i++ {
+					acc, err := hex.DecodeString(want)
+					if err != nil {
+						return false
+					}
+					if want, err = tc.add(hexCat(acc, p)); err != nil {
+						return false
+					}
+				}
+				got, err := tc.msm(hexCat(p, scalarBytes(big.NewInt(int64(n%32)))))
+				return err == nil && got == want
+			}
+			if err := quick.Check(prop, &quick.Config{MaxCount: 20}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestPairingBilinearity(t *testing.T) {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	// e(aP, bQ) = e(abP, Q) = e(P, abQ), checked as e(aP, bQ) * e(-abP, Q) = 1 and its G2 mirror
+	prop := func(a, b [4]uint64) bool {
+		sa, sb := quickScalar(a), quickScalar(b)
+		ab := new(big.Int).Mul(sa, sb)
+		ab.Mod(ab, fr.Modulus())
+		var aP, abP, abPNeg bls.G1Affine
+		aP.ScalarMultiplication(&g1Gen, sa)
+		abP.ScalarMultiplication(&g1Gen, ab)
+		abPNeg.Neg(&abP)
+		var bQ, abQ bls.G2Affine
+		bQ.ScalarMultiplication(&g2Gen, sb)
+		abQ.ScalarMultiplication(&g2Gen, ab)
+		var g1GenNeg bls.G1Affine
+		g1GenNeg.Neg(&g1Gen)
+		for _, input := range []string{
+			hexCat(serialization.EncodeEthereumG1Point(aP), serialization.EncodeEthereumG2Point(bQ),
+				serialization.EncodeEthereumG1Point(abPNeg), serialization.EncodeEthereumG2Point(g2Gen)),
+			hexCat(serialization.EncodeEthereumG1Point(aP), serialization.EncodeEthereumG2Point(bQ),
+				serialization.EncodeEthereumG1Point(g1GenNeg), serialization.EncodeEthereumG2Point(abQ)),
+		} {
+			if got, err := Pairing(input); err != nil || got != encodePairingResult(true) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(prop, &quick.Config{MaxCount: 10}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Aggregation in the IETF BLS signature draft: the aggregate signature is the sum of the
+// signatures. AggregateVerify checks it against n (public key, message) pairs with one
+// pairing per pair; the basic scheme requires distinct messages and message augmentation
+// makes them distinct by prefixing the public key. FastAggregateVerify, the
+// proof-of-possession scheme's check for a common message, sums the public keys and
+// needs a single pairing besides the signature's.
+
+// blsCoreAggregateVerify is CoreAggregateVerify (fast: FastAggregateVerify on msgs[0])
+// with KeyValidate on every public key
+func blsCoreAggregateVerify(pkBytes, msgs [][]byte, sigBytes []byte, ciphersuite, dst string, fast bool) error {
+	if len(pkBytes) == 0 {
+		return fmt.Errorf("no public keys")
+	}
+	if !fast && len(msgs) != len(pkBytes) {
+		return fmt.Errorf("%d public keys but %d messages", len(pkBytes), len(msgs))
+	}
+	label := func(i int) string {
+		if len(pkBytes) == 1 {
+			return "public key"
+		}
+		return fmt.Sprintf("public key %d", i)
+	}
+	prod, pkTerm, hTerm := "", "pk", "H(m)"
+	switch {
+	case fast:
+		pkTerm = "sum pk_i"
+	case len(pkBytes) > 1:
+		prod, pkTerm, hTerm = "prod ", "pk_i", "H(m_i)"
+	}
+	_, _, g1Gen, g2Gen := bls.Generators()
+	if ciphersuite == "min-sig" {
+		var pks []bls.G2Affine
+		var apk bls.G2Jac
+		for i, b := range pkBytes {
+			pk, err := serialization.DecodeCompressedG2Point(b)
+			if err != nil {
+				return fmt.Errorf("%s: %v", label(i), err)
+			}
+			if pk.IsInfinity() {
+				return fmt.Errorf("%s is the identity", label(i))
+			}
+			pks = append(pks, pk)
+			var pkJac bls.G2Jac
+			pkJac.FromAffine(&pk)
+			apk.AddAssign(&pkJac)
+		}
+		sig, err := serialization.DecodeCompressedG1Point(sigBytes)
+		if err != nil {
+			return fmt.Errorf("signature: %v", err)
+		}
+		if fast {
+			pks = []bls.G2Affine{*new(bls.G2Affine).FromJacobian(&apk)}
+			msgs = msgs[:1]
+		}
+		var g1s []bls.G1Affine
+		for _, msg := range msgs {
+			h, err := bls.HashToG1(msg, []byte(dst))
+			if err != nil {
+				return fmt.Errorf("hash to G1 failed: %v", err)
+			}
+			g1s = append(g1s, h)
+		}
+		var negG2 bls.G2Affine
+		negG2.Neg(&g2Gen)
+		ok, err := bls.PairingCheck(append(g1s, sig), append(pks, negG2))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("pairing check failed: %se(%s, %s) != e(sig, g2)", prod, hTerm, pkTerm)
+		}
+		return nil
+	}
+	var pks []bls.G1Affine
+	var apk bls.G1Jac
+	for i, b := range pkBytes {
+		pk, err := serialization.DecodeCompressedG1Point(b)
+		if err != nil {
+			return fmt.Errorf("%s: %v", label(i), err)
+		}
+		if pk.IsInfinity() {
+			return fmt.Errorf("%s is the identity", label(i))
+		}
+		pks = append(pks, pk)
+		var pkJac bls.G1Jac
+		pkJac.FromAffine(&pk)
+		apk.AddAssign(&pkJac)
+	}
+	sig, err := serialization.DecodeCompressedG2Point(sigBytes)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	if fast {
+		pks = []bls.G1Affine{*new(bls.G1Affine).FromJacobian(&apk)}
+		msgs = msgs[:1]
+	}
+	var g2s []bls.G2Affine
+	for _, msg := range msgs {
+		h, err := bls.HashToG2(msg, []byte(dst))
+		if err != nil {
+			return fmt.Errorf("hash to G2 failed: %v", err)
+		}
+		g2s = append(g2s, h)
+	}
+	var negG1 bls.G1Affine
+	negG1.Neg(&g1Gen)
+	ok, err := bls.PairingCheck(append(pks, negG1), append(g2s, sig))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("pairing check failed: %se(%s, %s) != e(g1, sig)", prod, pkTerm, hTerm)
+	}
+	return nil
+}
+
+// aggregateVerify runs the scheme's check of an aggregate signature: FastAggregateVerify
+// for a common message under proof of possession, AggregateVerify otherwise. It returns
+// the name of the algorithm and why the signature is invalid.
+func (f signatureSchemeFlags) aggregateVerify(pks, msgs [][]byte, sig []byte, dst string) (string, error) {
+	common := true
+	for _, m := range msgs[1:] {
+		common = common && bytes.Equal(m, msgs[0])
+	}
+	if *f.scheme == "pop" && common {
+		return "FastAggregateVerify", blsCoreAggregateVerify(pks, msgs, sig, *f.ciphersuite, dst, true)
+	}
+	if *f.scheme == "basic" {
+		seen := map[string]int{}
+		for i, m := range msgs {
+			if j, ok := seen[string(m)]; ok {
+				return "AggregateVerify", fmt.Errorf("messages %d and %d are equal (the basic scheme requires distinct messages)", j, i)
+			}
+			seen[string(m)] = i
+		}
+	}
+	signed := make([][]byte, len(msgs))
+	for i, m := range msgs {
+		signed[i] = f.signedMessage(pks[i], m)
+	}
+	return "AggregateVerify", blsCoreAggregateVerify(pks, signed, sig, *f.ciphersuite, dst, false)
+}
+
+// parseAggregateMessages returns one message per signer: the common --message /
+// --message-hex, or the comma-separated --messages-hex
+func parseAggregateMessages(message, messageHex, messagesHex string, n int) ([][]byte, error) {
+	if messagesHex == "" {
+		msg, err := parseMessageFlags(message, messageHex)
+		if err != nil {
+			return nil, err
+		}
+		msgs := make([][]byte, n)
+		for i := range msgs {
+			msgs[i] = msg
+		}
+		return msgs, nil
+	}
+	if message != "" || messageHex != "" {
+		return nil, usageErrorf("--messages-hex excludes --message and --message-hex")
+	}
+	parts := strings.Split(messagesHex, ",")
+	if len(parts) != n {
+		return nil, usageErrorf("--messages-hex has %d messages for %d signers", len(parts), n)
+	}
+	msgs := make([][]byte, n)
+	for i, p := range parts {
+		m, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(p), "0x"))
+		if err != nil {
+			return nil, usageErrorf("--messages-hex message %d: invalid hex: %v", i, err)
+		}
+		msgs[i] = m
+	}
+	return msgs, nil
+}
+
+func hexStrings(values [][]byte) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = hex.EncodeToString(v)
+	}
+	return out
+}
+
+// runAggregateSignMode signs one message per secret key, aggregates the signatures and
+// verifies the aggregate with the scheme's algorithm. The expected result is what the
+// verification returned, so duplicate messages under the basic scheme give a negative
+// vector.
+func runAggregateSignMode(args []string) error {
+	fs := newFlagSet("aggregate")
+	skList := fs.String("sks", "", "Secret keys (decimal or hex), comma separated")
+	message := fs.String("message", "", "Common message of every signer (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Common message of every signer (hex, overrides --message)")
+	messagesHex := fs.String("messages-hex", "", "One message per signer (hex), comma separated")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *skList == "" {
+		return usageErrorf("--sks is required")
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return err
+	}
+	var sks []*big.Int
+	for i, s := range strings.Split(*skList, ",") {
+		sk, err := parseSecretKey(strings.TrimSpace(s))
+		if err != nil {
+			return usageErrorf("--sks key %d: %v", i, err)
+		}
+		sks = append(sks, sk)
+	}
+	msgs, err := parseAggregateMessages(*message, *messageHex, *messagesHex, len(sks))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("=== Aggregate Signatures (%s, %s) ===\n", *scheme.ciphersuite, *scheme.scheme)
+	fmt.Printf("DST: %s\n", dst)
+	var pks, sigs [][]byte
+	skSum := new(big.Int)
+	for i, sk := range sks {
+		pk := blsPublicKey(sk, *scheme.ciphersuite)
+		s, err := blsSign(scheme.signedMessage(pk, msgs[i]), sk, *scheme.ciphersuite, dst)
+		if err != nil {
+			return err
+		}
+		sig := serialization.ConvertG2AffineToCompressed(s.SigG2)
+		if *scheme.ciphersuite == "min-sig" {
+			sig = serialization.ConvertG1AffineToCompressed(s.SigG1)
+		}
+		pks, sigs = append(pks, pk), append(sigs, sig)
+		skSum.Add(skSum, sk)
+		fmt.Printf("\nSigner %d:\n", i)
+		fmt.Printf("  Public key: %x\n", pk)
+		fmt.Printf("  Message (hex): %x\n", msgs[i])
+		fmt.Printf("  Signature: %x\n", sig)
+	}
+
+	var aggSig, aggSigEth []byte
+	if *scheme.ciphersuite == "min-sig" {
+		agg, _, err := aggregateG1(hexStrings(sigs))
+		if err != nil {
+			return err
+		}
+		aggSig, aggSigEth = serialization.ConvertG1AffineToCompressed(agg), serialization.EncodeEthereumG1Point(agg)
+	} else {
+		agg, _, err := aggregateG2(hexStrings(sigs))
+		if err != nil {
+			return err
+		}
+		aggSig, aggSigEth = serialization.ConvertG2AffineToCompressed(agg), serialization.EncodeEthereumG2Point(agg)
+	}
+	// the sum of the public keys is the public key of the sum of the secret keys
+	aggPk := blsPublicKey(skSum.Mod(skSum, fr.Modulus()), *scheme.ciphersuite)
+	algorithm, verr := scheme.aggregateVerify(pks, msgs, aggSig, dst)
+	recordVerdictResult(hex.EncodeToString(aggSig))
+
+	fmt.Println()
+	fmt.Printf("Aggregate signature (compressed): %x\n", aggSig)
+	fmt.Printf("Aggregate signature (Ethereum): %x\n", aggSigEth)
+	fmt.Printf("Aggregate public key (compressed): %x\n", aggPk)
+	fmt.Printf("Verification: %s\n", algorithm)
+	if verr != nil {
+		fmt.Printf("Expected result: false (%v)\n", verr)
+	} else {
+		fmt.Println("Expected result: true")
+	}
+	reportOutput("dst", dst)
+	reportOutput("public_keys", hexStrings(pks))
+	reportOutput("messages", hexStrings(msgs))
+	reportOutput("signatures", hexStrings(sigs))
+	reportOutput("aggregate_signature", hex.EncodeToString(aggSig))
+	reportOutput("aggregate_public_key", hex.EncodeToString(aggPk))
+	reportOutput("algorithm", algorithm)
+	reportOutput("expected", verr == nil)
+	return nil
+}
+
+// runAggregateVerifyMode checks an aggregate signature against public keys and their
+// messages; an invalid signature is a failure (exit code 1)
+func runAggregateVerifyMode(args []string) (int, error) {
+	fs := newFlagSet("aggregate-verify")
+	pkList := fs.String("pks", "", "Compressed public keys (hex), comma separated")
+	pkFile := fs.String("file", "", "File with compressed public keys, one or more per line (overrides --pks)")
+	sigHex := fs.String("signature", "", "Compressed aggregate signature")
+	message := fs.String("message", "", "Common message of every signer (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Common message of every signer (hex, overrides --message)")
+	messagesHex := fs.String("messages-hex", "", "One message per public key (hex), comma separated")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *sigHex == "" {
+		return 0, usageErrorf("--signature is required")
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return 0, err
+	}
+	hexes, err := readPointList(*pkList, *pkFile)
+	if err != nil {
+		return 0, err
+	}
+	if len(hexes) == 0 {
+		return 0, usageErrorf("no public keys given (use --pks or --file)")
+	}
+	var pks [][]byte
+	for i, h := range hexes {
+		pk, err := hex.DecodeString(strings.TrimPrefix(h, "0x"))
+		if err != nil {
+			return 0, usageErrorf("public key %d: invalid hex: %v", i, err)
+		}
+		pks = append(pks, pk)
+	}
+	sig, err := decodeHexFlag("signature", *sigHex)
+	if err != nil {
+		return 0, err
+	}
+	msgs, err := parseAggregateMessages(*message, *messageHex, *messagesHex, len(pks))
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictInput(hex.EncodeToString(bytes.Join(append(pks, sig), nil)))
+
+	fmt.Printf("=== Aggregate Verify (%s, %s) ===\n", *scheme.ciphersuite, *scheme.scheme)
+	fmt.Printf("DST: %s\n", dst)
+	fmt.Printf("Public keys: %d\n", len(pks))
+	algorithm, verr := scheme.aggregateVerify(pks, msgs, sig, dst)
+	fmt.Printf("Verification: %s\n", algorithm)
+	reportOutput("algorithm", algorithm)
+	reportOutput("valid", verr == nil)
+	if verr != nil {
+		fmt.Printf("❌ INVALID: %v\n", verr)
+		return 1, nil
+	}
+	fmt.Println("✅ VALID")
+	return 0, nil
+}
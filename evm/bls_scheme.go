@@ -0,0 +1,285 @@
+package main
+
+import (
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The IETF BLS signature draft (draft-irtf-cfrg-bls-signature-05): KeyGen, SkToPk, Sign
+// and Verify for the min-pk and min-sig ciphersuites, each with the three schemes. The
+// hashing and signing core is blsSign; the schemes differ in the DST and, for
+// message augmentation, in prefixing the message with the public key.
+
+// signatureSchemeTags are the DST suffixes of the three schemes
+var signatureSchemeTags = map[string]string{
+	"basic": "NUL",
+	"aug":   "AUG",
+	"pop":   "POP",
+}
+
+// signatureDST is the ciphersuite ID of a ciphersuite and scheme, e.g.
+// BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_ for basic min-pk
+func signatureDST(ciphersuite, scheme string) (string, error) {
+	tag, ok := signatureSchemeTags[scheme]
+	if !ok {
+		return "", fmt.Errorf("unknown scheme '%s' (valid: basic, aug, pop)", scheme)
+	}
+	switch ciphersuite {
+	case "min-pk":
+		return "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_" + tag + "_", nil
+	case "min-sig":
+		return "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_" + tag + "_", nil
+	}
+	return "", fmt.Errorf("unknown ciphersuite '%s' (valid: min-pk, min-sig)", ciphersuite)
+}
+
+// signatureSchemeFlags are the --ciphersuite, --scheme and --dst flags of keygen, sign and
+// verify
+type signatureSchemeFlags struct {
+	ciphersuite, scheme, dst *string
+}
+
+func addSignatureSchemeFlags(fs *flag.FlagSet) signatureSchemeFlags {
+	return signatureSchemeFlags{
+		ciphersuite: fs.String("ciphersuite", "min-pk", "Ciphersuite: min-pk (pubkey G1, signature G2) or min-sig (pubkey G2, signature G1)"),
+		scheme:      fs.String("scheme", "pop", "Scheme: basic (NUL), aug (message augmentation) or pop (proof of possession)"),
+		dst:         fs.String("dst", "", "Hash-to-curve DST (default: the ciphersuite ID of --ciphersuite and --scheme)"),
+	}
+}
+
+// resolve returns the DST, checking the ciphersuite and scheme
+func (f signatureSchemeFlags) resolve() (string, error) {
+	dst, err := signatureDST(*f.ciphersuite, *f.scheme)
+	if err != nil {
+		return "", usageError{err}
+	}
+	if *f.dst != "" {
+		return *f.dst, nil
+	}
+	return dst, nil
+}
+
+// signedMessage is what the scheme hashes: the message, prefixed with the compressed
+// public key for message augmentation
+func (f signatureSchemeFlags) signedMessage(pk, msg []byte) []byte {
+	if *f.scheme == "aug" {
+		return append(append([]byte(nil), pk...), msg...)
+	}
+	return msg
+}
+
+// blsKeyGen is KeyGen(IKM, key_info): HKDF-SHA256 mod r, rehashing the salt until the key
+// is non-zero
+func blsKeyGen(ikm, keyInfo []byte) (*big.Int, error) {
+	if len(ikm) < 32 {
+		return nil, fmt.Errorf("IKM must be at least 32 bytes, got %d", len(ikm))
+	}
+	const l = 48 // ceil((3 * ceil(log2(r))) / 16)
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	info := string(append(append([]byte(nil), keyInfo...), 0, l))
+	for {
+		digest := sha256.Sum256(salt)
+		salt = digest[:]
+		prk, err := hkdf.Extract(sha256.New, append(append([]byte(nil), ikm...), 0), salt)
+		if err != nil {
+			return nil, err
+		}
+		okm, err := hkdf.Expand(sha256.New, prk, info, l)
+		if err != nil {
+			return nil, err
+		}
+		sk := new(big.Int).Mod(new(big.Int).SetBytes(okm), fr.Modulus())
+		if sk.Sign() != 0 {
+			return sk, nil
+		}
+	}
+}
+
+// blsPublicKey is SkToPk, compressed
+func blsPublicKey(sk *big.Int, ciphersuite string) []byte {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	if ciphersuite == "min-sig" {
+		var pk bls.G2Affine
+		pk.ScalarMultiplication(&g2Gen, sk)
+		return serialization.ConvertG2AffineToCompressed(pk)
+	}
+	var pk bls.G1Affine
+	pk.ScalarMultiplication(&g1Gen, sk)
+	return serialization.ConvertG1AffineToCompressed(pk)
+}
+
+// blsVerify is CoreVerify with KeyValidate: both points must decode into their
+// subgroups, the public key must not be the identity and e(pk, H(m)) must equal
+// e(g, sig). The error says why a signature is invalid.
+func blsVerify(pkBytes, msg, sigBytes []byte, ciphersuite, dst string) error {
+	return blsCoreAggregateVerify([][]byte{pkBytes}, [][]byte{msg}, sigBytes, ciphersuite, dst, false)
+}
+
+// decodeHexFlag decodes a hex flag value, with or without 0x
+func decodeHexFlag(name, value string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(value), "0x"))
+	if err != nil {
+		return nil, usageErrorf("invalid --%s hex: %v", name, err)
+	}
+	return b, nil
+}
+
+// runKeygenMode derives a secret key from input keying material and prints the key pair
+func runKeygenMode(args []string) error {
+	fs := newFlagSet("keygen")
+	ikmHex := fs.String("ikm", "", "Input keying material, at least 32 bytes hex (default: 32 random bytes, printed)")
+	keyInfo := fs.String("key-info", "", "KeyGen key_info (UTF-8)")
+	keyInfoHex := fs.String("key-info-hex", "", "KeyGen key_info (hex, overrides --key-info)")
+	ciphersuite := fs.String("ciphersuite", "min-pk", "Ciphersuite of the public key: min-pk (G1) or min-sig (G2)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if _, err := signatureDST(*ciphersuite, "pop"); err != nil {
+		return usageError{err}
+	}
+	var ikm []byte
+	if *ikmHex == "" {
+		ikm = make([]byte, 32)
+		if _, err := rand.Read(ikm); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if ikm, err = decodeHexFlag("ikm", *ikmHex); err != nil {
+			return err
+		}
+	}
+	info := []byte(*keyInfo)
+	if *keyInfoHex != "" {
+		var err error
+		if info, err = decodeHexFlag("key-info-hex", *keyInfoHex); err != nil {
+			return err
+		}
+	}
+	sk, err := blsKeyGen(ikm, info)
+	if err != nil {
+		return usageError{err}
+	}
+	pk := blsPublicKey(sk, *ciphersuite)
+	recordVerdictResult(hex.EncodeToString(pk))
+
+	fmt.Printf("=== KeyGen (%s) ===\n", *ciphersuite)
+	fmt.Printf("IKM: %x\n", ikm)
+	fmt.Printf("key_info: %x\n", info)
+	fmt.Printf("Secret key: %s\n", sk.String())
+	fmt.Printf("Secret key (32 bytes): %x\n", scalarTo32Bytes(sk))
+	fmt.Printf("Public key (compressed, %d bytes): %x\n", len(pk), pk)
+	reportOutput("ikm", hex.EncodeToString(ikm))
+	reportOutput("secret_key", hex.EncodeToString(scalarTo32Bytes(sk)))
+	reportOutput("public_key", hex.EncodeToString(pk))
+	return nil
+}
+
+// runSignMode signs a message with the selected ciphersuite and scheme
+func runSignMode(args []string) error {
+	fs := newFlagSet("sign")
+	skStr := fs.String("sk", "", "Secret key (decimal or hex), in [1, r-1]")
+	message := fs.String("message", "", "Message to sign (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Message to sign (hex, overrides --message)")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *skStr == "" {
+		return usageErrorf("--sk is required")
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return err
+	}
+	msg, err := parseMessageFlags(*message, *messageHex)
+	if err != nil {
+		return err
+	}
+	sk, err := parseSecretKey(*skStr)
+	if err != nil {
+		return usageError{err}
+	}
+	pk := blsPublicKey(sk, *scheme.ciphersuite)
+	s, err := blsSign(scheme.signedMessage(pk, msg), sk, *scheme.ciphersuite, dst)
+	if err != nil {
+		return err
+	}
+	sig := serialization.ConvertG2AffineToCompressed(s.SigG2)
+	if *scheme.ciphersuite == "min-sig" {
+		sig = serialization.ConvertG1AffineToCompressed(s.SigG1)
+	}
+	recordVerdictResult(hex.EncodeToString(sig))
+
+	fmt.Printf("=== Sign (%s, %s) ===\n", *scheme.ciphersuite, *scheme.scheme)
+	fmt.Printf("Message (hex): %x\n", msg)
+	fmt.Printf("DST: %s\n", dst)
+	fmt.Printf("Public key (compressed, %d bytes): %x\n", len(pk), pk)
+	fmt.Printf("Signature (compressed, %d bytes): %x\n", len(sig), sig)
+	if *scheme.ciphersuite == "min-sig" {
+		fmt.Printf("Signature (Ethereum, 128 bytes): %x\n", serialization.EncodeEthereumG1Point(s.SigG1))
+	} else {
+		fmt.Printf("Signature (Ethereum, 256 bytes): %x\n", serialization.EncodeEthereumG2Point(s.SigG2))
+	}
+	reportOutput("dst", dst)
+	reportOutput("public_key", hex.EncodeToString(pk))
+	reportOutput("signature", hex.EncodeToString(sig))
+	return nil
+}
+
+// runVerifyMode checks a compressed signature against a compressed public key; an
+// invalid signature is a failure (exit code 1)
+func runVerifyMode(args []string) (int, error) {
+	fs := newFlagSet("verify")
+	pkHex := fs.String("pk", "", "Compressed public key (48 bytes min-pk, 96 bytes min-sig)")
+	sigHex := fs.String("signature", "", "Compressed signature (96 bytes min-pk, 48 bytes min-sig)")
+	message := fs.String("message", "", "Signed message (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Signed message (hex, overrides --message)")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *pkHex == "" || *sigHex == "" {
+		return 0, usageErrorf("--pk and --signature are required")
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return 0, err
+	}
+	msg, err := parseMessageFlags(*message, *messageHex)
+	if err != nil {
+		return 0, err
+	}
+	pk, err := decodeHexFlag("pk", *pkHex)
+	if err != nil {
+		return 0, err
+	}
+	sig, err := decodeHexFlag("signature", *sigHex)
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictInput(hex.EncodeToString(append(append([]byte(nil), pk...), sig...)))
+
+	fmt.Printf("=== Verify (%s, %s) ===\n", *scheme.ciphersuite, *scheme.scheme)
+	fmt.Printf("Message (hex): %x\n", msg)
+	fmt.Printf("DST: %s\n", dst)
+	verr := blsVerify(pk, scheme.signedMessage(pk, msg), sig, *scheme.ciphersuite, dst)
+	reportOutput("valid", verr == nil)
+	if verr != nil {
+		fmt.Printf("❌ INVALID: %v\n", verr)
+		return 1, nil
+	}
+	fmt.Println("✅ VALID")
+	return 0, nil
+}
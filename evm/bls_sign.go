@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Default hash-to-curve domain separation tags (proof-of-possession scheme, as used by
+// Ethereum consensus) for each ciphersuite
+var ciphersuiteDSTs = map[string]string{
+	"min-pk":  "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+	"min-sig": "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_",
+}
+
+// blsSignature holds a signed message in both ciphersuites; only the fields for the
+// chosen ciphersuite are set
+type blsSignature struct {
+	Ciphersuite string
+	// min-pk: public key in G1, hashed message and signature in G2
+	PubG1    bls.G1Affine
+	HashedG2 bls.G2Affine
+	SigG2    bls.G2Affine
+	// min-sig: public key in G2, hashed message and signature in G1
+	PubG2    bls.G2Affine
+	HashedG1 bls.G1Affine
+	SigG1    bls.G1Affine
+}
+
+// parseSecretKey parses a secret key (decimal or hex) and checks it is in [1, r-1]
+func parseSecretKey(s string) (*big.Int, error) {
+	sk, _, err := parseScalarAnyForm(s, "auto")
+	if err != nil {
+		return nil, err
+	}
+	if sk.Sign() <= 0 || sk.Cmp(fr.Modulus()) >= 0 {
+		return nil, fmt.Errorf("secret key must be in [1, r-1]")
+	}
+	return sk, nil
+}
+
+// blsSign hashes msg to the signature group and signs it with sk
+func blsSign(msg []byte, sk *big.Int, ciphersuite, dst string) (blsSignature, error) {
+	s := blsSignature{Ciphersuite: ciphersuite}
+	_, _, g1Gen, g2Gen := bls.Generators()
+	switch ciphersuite {
+	case "min-pk":
+		h, err := bls.HashToG2(msg, []byte(dst))
+		if err != nil {
+			return s, fmt.Errorf("hash to G2 failed: %v", err)
+		}
+		s.HashedG2 = h
+		s.PubG1.ScalarMultiplication(&g1Gen, sk)
+		s.SigG2.ScalarMultiplication(&h, sk)
+	case "min-sig":
+		h, err := bls.HashToG1(msg, []byte(dst))
+		if err != nil {
+			return s, fmt.Errorf("hash to G1 failed: %v", err)
+		}
+		s.HashedG1 = h
+		s.PubG2.ScalarMultiplication(&g2Gen, sk)
+		s.SigG1.ScalarMultiplication(&h, sk)
+	default:
+		return s, fmt.Errorf("unknown ciphersuite '%s' (valid: min-pk, min-sig)", ciphersuite)
+	}
+	return s, nil
+}
+
+// pairingCheckInput is the EIP-2537 pairing input a verifier builds for the signature:
+//   - min-pk: e(pk, H(m)) * e(-g1, sig) == 1
+//   - min-sig: e(H(m), pk) * e(sig, -g2) == 1
+func (s blsSignature) pairingCheckInput() []byte {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var out []byte
+	if s.Ciphersuite == "min-pk" {
+		var negG1 bls.G1Affine
+		negG1.Neg(&g1Gen)
+		out = append(out, serialization.EncodeEthereumG1Point(s.PubG1)...)
+		out = append(out, serialization.EncodeEthereumG2Point(s.HashedG2)...)
+		out = append(out, serialization.EncodeEthereumG1Point(negG1)...)
+		out = append(out, serialization.EncodeEthereumG2Point(s.SigG2)...)
+		return out
+	}
+	var negG2 bls.G2Affine
+	negG2.Neg(&g2Gen)
+	out = append(out, serialization.EncodeEthereumG1Point(s.HashedG1)...)
+	out = append(out, serialization.EncodeEthereumG2Point(s.PubG2)...)
+	out = append(out, serialization.EncodeEthereumG1Point(s.SigG1)...)
+	out = append(out, serialization.EncodeEthereumG2Point(negG2)...)
+	return out
+}
+
+// printG1Encodings prints a G1 point compressed (Neo) and in Ethereum format
+func printG1Encodings(label string, p bls.G1Affine) {
+	fmt.Printf("%s (compressed, 48 bytes): %x\n", label, serialization.ConvertG1AffineToCompressed(p))
+	fmt.Printf("%s (Ethereum, 128 bytes): %x\n", label, serialization.EncodeEthereumG1Point(p))
+}
+
+// printG2Encodings prints a G2 point compressed (Neo) and in Ethereum format
+func printG2Encodings(label string, p bls.G2Affine) {
+	fmt.Printf("%s (compressed, 96 bytes): %x\n", label, serialization.ConvertG2AffineToCompressed(p))
+	fmt.Printf("%s (Ethereum, 256 bytes): %x\n", label, serialization.EncodeEthereumG2Point(p))
+}
+
+// runHashAndSignMode emits everything a contract test needs for one signed message:
+// hashed point, signature, public key and the pairing-check input
+func runHashAndSignMode(args []string) error {
+	fs := newFlagSet("hash-and-sign")
+	message := fs.String("message", "", "Message to sign (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Message to sign (hex, overrides --message)")
+	skStr := fs.String("sk", "", "Secret key (decimal or hex), in [1, r-1]")
+	ciphersuite := fs.String("ciphersuite", "min-pk", "Ciphersuite: min-pk (pubkey G1, signature G2) or min-sig (pubkey G2, signature G1)")
+	dst := fs.String("dst", "", "Hash-to-curve domain separation tag (default: the ciphersuite's POP tag)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *skStr == "" {
+		return fmt.Errorf("--sk is required")
+	}
+	msg, err := parseMessageFlags(*message, *messageHex)
+	if err != nil {
+		return err
+	}
+	sk, err := parseSecretKey(*skStr)
+	if err != nil {
+		return err
+	}
+	if *dst == "" {
+		*dst = ciphersuiteDSTs[*ciphersuite]
+	}
+	sig, err := blsSign(msg, sk, *ciphersuite, *dst)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("=== Hash and Sign (%s) ===\n", *ciphersuite)
+	fmt.Printf("Message (hex): %x\n", msg)
+	fmt.Printf("DST: %s\n", *dst)
+	fmt.Printf("Secret key: %s\n", sk.String())
+	fmt.Println()
+	if *ciphersuite == "min-pk" {
+		printG2Encodings("Hashed message (G2)", sig.HashedG2)
+		printG2Encodings("Signature (G2)", sig.SigG2)
+		printG1Encodings("Public key (G1)", sig.PubG1)
+	} else {
+		printG1Encodings("Hashed message (G1)", sig.HashedG1)
+		printG1Encodings("Signature (G1)", sig.SigG1)
+		printG2Encodings("Public key (G2)", sig.PubG2)
+	}
+
+	input := sig.pairingCheckInput()
+	fmt.Println()
+	fmt.Printf("Pairing check input (EIP-2537, 2 pairs, %d bytes): %x\n", len(input), input)
+	result, err := bls12381vec.Pairing(hex.EncodeToString(input))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pairing check result: %s\n", result)
+	return nil
+}
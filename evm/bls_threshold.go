@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Threshold BLS: the secret key is the constant term of a random polynomial f of degree
+// t-1 over Fr and share i is f(i). Any t partial signatures sig_i = Sign(f(i), m)
+// combine into Sign(f(0), m) = sum lambda_i * sig_i, with the Lagrange coefficients
+// lambda_i = prod_{j != i} x_j / (x_j - x_i) of the signer set at 0. Under message
+// augmentation every signer prefixes the group public key, not its own.
+
+// lagrangeAtZero returns the Lagrange coefficients at 0 of distinct non-zero ids
+func lagrangeAtZero(ids []*big.Int) ([]*big.Int, error) {
+	r := fr.Modulus()
+	lambdas := make([]*big.Int, len(ids))
+	for i, xi := range ids {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, xj := range ids {
+			if i == j {
+				continue
+			}
+			diff := new(big.Int).Sub(xj, xi)
+			if diff.Mod(diff, r).Sign() == 0 {
+				return nil, fmt.Errorf("share ids %s and %s are equal mod r", xi, xj)
+			}
+			num.Mod(num.Mul(num, xj), r)
+			den.Mod(den.Mul(den, diff), r)
+		}
+		lambdas[i] = num.Mul(num, new(big.Int).ModInverse(den, r)).Mod(num, r)
+	}
+	return lambdas, nil
+}
+
+// evalPolynomial evaluates sum coeffs[k] x^k mod r
+func evalPolynomial(coeffs []*big.Int, x *big.Int) *big.Int {
+	r := fr.Modulus()
+	y := new(big.Int)
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		y.Mul(y, x).Add(y, coeffs[k]).Mod(y, r)
+	}
+	return y
+}
+
+// combineSignatures is sum lambda_i * sig_i over compressed signatures
+func combineSignatures(partials [][]byte, lambdas []*big.Int, ciphersuite string) ([]byte, error) {
+	sig, err := combinePoints(partials, lambdas, ciphersuite == "min-sig")
+	if err != nil {
+		return nil, fmt.Errorf("partial signature %v", err)
+	}
+	return sig, nil
+}
+
+// combinePoints is sum scalars[i] * points[i] over compressed G1 (g1) or G2 points
+func combinePoints(points [][]byte, scalars []*big.Int, g1 bool) ([]byte, error) {
+	if g1 {
+		var acc bls.G1Jac
+		for i, b := range points {
+			p, err := serialization.DecodeCompressedG1Point(b)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", i, err)
+			}
+			var term bls.G1Jac
+			term.FromAffine(&p)
+			term.ScalarMultiplication(&term, scalars[i])
+			acc.AddAssign(&term)
+		}
+		var sum bls.G1Affine
+		sum.FromJacobian(&acc)
+		return serialization.ConvertG1AffineToCompressed(sum), nil
+	}
+	var acc bls.G2Jac
+	for i, b := range points {
+		p, err := serialization.DecodeCompressedG2Point(b)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		var term bls.G2Jac
+		term.FromAffine(&p)
+		term.ScalarMultiplication(&term, scalars[i])
+		acc.AddAssign(&term)
+	}
+	var sum bls.G2Affine
+	sum.FromJacobian(&acc)
+	return serialization.ConvertG2AffineToCompressed(sum), nil
+}
+
+// parseShareIDs parses signer ids, which must be distinct, non-zero and below r
+func parseShareIDs(list string) ([]*big.Int, error) {
+	var ids []*big.Int
+	for i, s := range splitList(list) {
+		id, _, err := parseScalarAnyForm(s, "auto")
+		if err != nil {
+			return nil, fmt.Errorf("id %d: %v", i, err)
+		}
+		if id.Sign() <= 0 || id.Cmp(fr.Modulus()) >= 0 {
+			return nil, fmt.Errorf("id %d (%s) must be in [1, r-1]", i, s)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// thresholdShareJSON is one share in the --format json output
+type thresholdShareJSON struct {
+	ID               string `json:"id"`
+	SecretKey        string `json:"secret_key"`
+	PublicKey        string `json:"public_key"`
+	PartialSignature string `json:"partial_signature"`
+}
+
+// runThresholdSplitMode splits a secret key into n shares with threshold t, signs the
+// message with every share and combines the first t partial signatures back into the
+// group signature
+func runThresholdSplitMode(args []string) (int, error) {
+	fs := newFlagSet("threshold-split")
+	skStr := fs.String("sk", "", "Group secret key (decimal or hex, default: random)")
+	t := fs.Int("t", 2, "Threshold: shares needed to sign")
+	n := fs.Int("n", 3, "Number of shares")
+	coeffList := fs.String("coefficients", "", "Polynomial coefficients a_1..a_{t-1} (comma separated, default: random)")
+	message := fs.String("message", "", "Message to sign (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Message to sign (hex, overrides --message)")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *t < 1 || *n < *t {
+		return 0, usageErrorf("need 1 <= t <= n (got t=%d, n=%d)", *t, *n)
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return 0, err
+	}
+	msg, err := parseMessageFlags(*message, *messageHex)
+	if err != nil {
+		return 0, err
+	}
+	randomScalar := func() (*big.Int, error) {
+		for {
+			k, err := rand.Int(rand.Reader, fr.Modulus())
+			if err != nil || k.Sign() != 0 {
+				return k, err
+			}
+		}
+	}
+	var sk *big.Int
+	if *skStr != "" {
+		if sk, err = parseSecretKey(*skStr); err != nil {
+			return 0, usageError{err}
+		}
+	} else if sk, err = randomScalar(); err != nil {
+		return 0, err
+	}
+	coeffs := []*big.Int{sk}
+	if *coeffList != "" {
+		for i, s := range splitList(*coeffList) {
+			a, _, err := parseScalarAnyForm(s, "auto")
+			if err != nil {
+				return 0, usageErrorf("--coefficients %d: %v", i, err)
+			}
+			coeffs = append(coeffs, a.Mod(a, fr.Modulus()))
+		}
+		if len(coeffs) != *t {
+			return 0, usageErrorf("--coefficients needs t-1 = %d values, got %d", *t-1, len(coeffs)-1)
+		}
+	}
+	for len(coeffs) < *t {
+		a, err := randomScalar()
+		if err != nil {
+			return 0, err
+		}
+		coeffs = append(coeffs, a)
+	}
+
+	groupPk := blsPublicKey(sk, *scheme.ciphersuite)
+	signed := scheme.signedMessage(groupPk, msg)
+	signBytes := func(k *big.Int) ([]byte, error) {
+		s, err := blsSign(signed, k, *scheme.ciphersuite, dst)
+		if err != nil {
+			return nil, err
+		}
+		if *scheme.ciphersuite == "min-sig" {
+			return serialization.ConvertG1AffineToCompressed(s.SigG1), nil
+		}
+		return serialization.ConvertG2AffineToCompressed(s.SigG2), nil
+	}
+	groupSig, err := signBytes(sk)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("=== Threshold Split (%d of %d, %s, %s) ===\n", *t, *n, *scheme.ciphersuite, *scheme.scheme)
+	fmt.Printf("DST: %s\n", dst)
+	fmt.Printf("Message (hex): %x\n", msg)
+	for k, a := range coeffs {
+		fmt.Printf("a_%d: %s\n", k, a.String())
+	}
+	fmt.Printf("Group public key: %x\n", groupPk)
+	fmt.Printf("Group signature: %x\n", groupSig)
+
+	var shares []thresholdShareJSON
+	var ids []*big.Int
+	var partials [][]byte
+	for i := 1; i <= *n; i++ {
+		id := big.NewInt(int64(i))
+		share := evalPolynomial(coeffs, id)
+		partial, err := signBytes(share)
+		if err != nil {
+			return 0, err
+		}
+		pk := blsPublicKey(share, *scheme.ciphersuite)
+		shares = append(shares, thresholdShareJSON{ID: id.String(), SecretKey: hex.EncodeToString(scalarTo32Bytes(share)), PublicKey: hex.EncodeToString(pk), PartialSignature: hex.EncodeToString(partial)})
+		ids, partials = append(ids, id), append(partials, partial)
+		fmt.Printf("\nShare %d:\n", i)
+		fmt.Printf("  Secret key: %s\n", share.String())
+		fmt.Printf("  Public key: %x\n", pk)
+		fmt.Printf("  Partial signature: %x\n", partial)
+	}
+
+	// the first t signers reconstruct the group signature
+	lambdas, err := lagrangeAtZero(ids[:*t])
+	if err != nil {
+		return 0, err
+	}
+	combined, err := combineSignatures(partials[:*t], lambdas, *scheme.ciphersuite)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Printf("\nCombining shares 1..%d:\n", *t)
+	for i, l := range lambdas {
+		fmt.Printf("  lambda_%s: %s\n", ids[i], l.String())
+	}
+	fmt.Printf("  Combined signature: %x\n", combined)
+	recordVerdictResult(hex.EncodeToString(combined))
+	reportOutput("dst", dst)
+	reportOutput("coefficients", scalarStrings(coeffs))
+	reportOutput("group_public_key", hex.EncodeToString(groupPk))
+	reportOutput("group_signature", hex.EncodeToString(groupSig))
+	reportOutput("shares", shares)
+	reportOutput("lagrange_coefficients", scalarStrings(lambdas))
+	reportOutput("combined_signature", hex.EncodeToString(combined))
+	if !bytes.Equal(combined, groupSig) {
+		fmt.Println("❌ The combined signature differs from the group signature")
+		return 1, nil
+	}
+	fmt.Println("✅ The combined signature equals the group signature")
+	return 0, nil
+}
+
+// runThresholdCombineMode combines partial signatures of the given signer ids and, with
+// --pk, verifies the result against the group public key
+func runThresholdCombineMode(args []string) (int, error) {
+	fs := newFlagSet("threshold-combine")
+	idList := fs.String("ids", "", "Signer ids (share indices), comma separated")
+	partialList := fs.String("partials", "", "Compressed partial signatures in the order of --ids, comma separated")
+	pkHex := fs.String("pk", "", "Group public key: verify the combined signature against it")
+	message := fs.String("message", "", "Signed message (UTF-8), with --pk")
+	messageHex := fs.String("message-hex", "", "Signed message (hex, overrides --message), with --pk")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	ids, err := parseShareIDs(*idList)
+	if err != nil {
+		return 0, usageError{err}
+	}
+	hexes := splitList(*partialList)
+	if len(ids) == 0 || len(ids) != len(hexes) {
+		return 0, usageErrorf("--ids and --partials need the same, non-zero number of values (got %d and %d)", len(ids), len(hexes))
+	}
+	if _, err := signatureDST(*scheme.ciphersuite, *scheme.scheme); err != nil {
+		return 0, usageError{err}
+	}
+	partials := make([][]byte, len(hexes))
+	for i, h := range hexes {
+		if partials[i], err = hex.DecodeString(strings.TrimPrefix(h, "0x")); err != nil {
+			return 0, usageErrorf("partial signature %d: invalid hex: %v", i, err)
+		}
+	}
+	lambdas, err := lagrangeAtZero(ids)
+	if err != nil {
+		return 0, usageError{err}
+	}
+	combined, err := combineSignatures(partials, lambdas, *scheme.ciphersuite)
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictResult(hex.EncodeToString(combined))
+
+	fmt.Printf("=== Threshold Combine (%d signers, %s) ===\n", len(ids), *scheme.ciphersuite)
+	for i, l := range lambdas {
+		fmt.Printf("lambda_%s: %s\n", ids[i], l.String())
+	}
+	fmt.Printf("Combined signature: %x\n", combined)
+	reportOutput("lagrange_coefficients", scalarStrings(lambdas))
+	reportOutput("combined_signature", hex.EncodeToString(combined))
+	if *pkHex == "" {
+		return 0, nil
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return 0, err
+	}
+	msg, err := parseMessageFlags(*message, *messageHex)
+	if err != nil {
+		return 0, err
+	}
+	pk, err := decodeHexFlag("pk", *pkHex)
+	if err != nil {
+		return 0, err
+	}
+	verr := blsVerify(pk, scheme.signedMessage(pk, msg), combined, *scheme.ciphersuite, dst)
+	reportOutput("valid", verr == nil)
+	if verr != nil {
+		fmt.Printf("❌ INVALID against the group public key: %v\n", verr)
+		return 1, nil
+	}
+	fmt.Println("✅ VALID against the group public key")
+	return 0, nil
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// A BLS VRF: the proof of input alpha is the signature Sign(sk, alpha), which is unique
+// for a key and an input, and the output is SHA-256 of the compressed proof. This is the
+// construction of randomness beacons such as drand, whose default tag is the basic
+// signature DST. Verification checks the signature and recomputes the output.
+
+// vrfOutput is the VRF output of a proof
+func vrfOutput(proof []byte) []byte {
+	h := sha256.Sum256(proof)
+	return h[:]
+}
+
+// blsVrfVerify checks a proof of alpha under pk and that output is its hash
+func blsVrfVerify(pk, alpha, proof, output []byte, ciphersuite, dst string) error {
+	if err := blsVerify(pk, alpha, proof, ciphersuite, dst); err != nil {
+		return err
+	}
+	if !bytes.Equal(output, vrfOutput(proof)) {
+		return fmt.Errorf("output is not SHA-256 of the proof")
+	}
+	return nil
+}
+
+// vrfVector is one VRF proof and the expected verification result
+type vrfVector struct {
+	Name     string `json:"name"`
+	PubKey   string `json:"pubkey"`
+	Input    string `json:"input"`
+	Proof    string `json:"proof"`
+	Output   string `json:"output"`
+	Expected bool   `json:"expected"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// vrfVectors builds the valid proof of alpha and the proofs a verifier must refuse: a
+// proof by another key or of another input, a mismatched output, the negated and
+// identity proofs and a truncated proof
+func vrfVectors(sk, wrongSk *big.Int, alpha []byte, ciphersuite, dst string) ([]vrfVector, error) {
+	minSig := ciphersuite == "min-sig"
+	prove := func(k *big.Int, input []byte) ([]byte, error) {
+		s, err := blsSign(input, k, ciphersuite, dst)
+		if err != nil {
+			return nil, err
+		}
+		if minSig {
+			return serialization.ConvertG1AffineToCompressed(s.SigG1), nil
+		}
+		return serialization.ConvertG2AffineToCompressed(s.SigG2), nil
+	}
+	proof, err := prove(sk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	wrongKey, err := prove(wrongSk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	otherInput, err := prove(sk, append(append([]byte(nil), alpha...), 0x00))
+	if err != nil {
+		return nil, err
+	}
+	size := bls.SizeOfG2AffineCompressed
+	if minSig {
+		size = bls.SizeOfG1AffineCompressed
+	}
+	negated := append([]byte(nil), proof...)
+	negated[0] ^= 0x20
+	identity := make([]byte, size)
+	identity[0] = 0xc0
+	flipped := vrfOutput(proof)
+	flipped[len(flipped)-1] ^= 0x01
+
+	pk := hex.EncodeToString(blsPublicKey(sk, ciphersuite))
+	in := hex.EncodeToString(alpha)
+	vector := func(name string, p, output []byte) vrfVector {
+		if output == nil {
+			output = vrfOutput(p)
+		}
+		return vrfVector{Name: name, PubKey: pk, Input: in, Proof: hex.EncodeToString(p), Output: hex.EncodeToString(output)}
+	}
+	vectors := []vrfVector{
+		vector("valid", proof, nil),
+		vector("wrong-key", wrongKey, nil),
+		vector("other-input", otherInput, nil),
+		vector("wrong-output", proof, flipped),
+		vector("negated", negated, nil),
+		vector("identity-proof", identity, nil),
+		vector("truncated", proof[:size-1], nil),
+	}
+	vectors[0].Expected = true
+	return vectors, nil
+}
+
+// vrfDST is the default VRF tag: the basic signature DST of the ciphersuite
+func vrfDST(ciphersuite, dst string) (string, error) {
+	def, err := signatureDST(ciphersuite, "basic")
+	if err != nil {
+		return "", err
+	}
+	if dst != "" {
+		return dst, nil
+	}
+	return def, nil
+}
+
+// runVrfMode proves with --sk, printing the proof, the output and the negative vectors,
+// each run through verification; or verifies --proof (and --output) with --pk. A
+// vector whose result differs from its intent, or an invalid proof, is a failure.
+func runVrfMode(args []string) (int, error) {
+	fs := newFlagSet("vrf")
+	skStr := fs.String("sk", "", "Prove with this secret key (decimal or hex)")
+	wrongSkStr := fs.String("wrong-sk", "", "Secret key of the wrong-key proof (default: sk + 1)")
+	pkHex := fs.String("pk", "", "Verify with this compressed public key")
+	proofHex := fs.String("proof", "", "Proof to verify (compressed signature)")
+	outputHex := fs.String("output", "", "Expected output to verify (default: only the proof is checked)")
+	input := fs.String("input", "", "VRF input alpha (UTF-8)")
+	inputHex := fs.String("input-hex", "", "VRF input alpha (hex, overrides --input)")
+	negative := fs.Bool("negative", true, "With --sk, also print the proofs a verifier must refuse")
+	ciphersuite := fs.String("ciphersuite", "min-pk", "Ciphersuite: min-pk (pubkey G1, proof G2) or min-sig (pubkey G2, proof G1)")
+	dstFlag := fs.String("dst", "", "Proof DST (default: the basic signature DST, BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if (*skStr == "") == (*pkHex == "") {
+		return 0, usageErrorf("exactly one of --sk (prove) and --pk (verify) is required")
+	}
+	dst, err := vrfDST(*ciphersuite, *dstFlag)
+	if err != nil {
+		return 0, usageError{err}
+	}
+	alpha, err := parseMessageFlags(*input, *inputHex)
+	if err != nil {
+		return 0, err
+	}
+
+	if *pkHex != "" {
+		if *proofHex == "" {
+			return 0, usageErrorf("--proof is required with --pk")
+		}
+		pk, err := decodeHexFlag("pk", *pkHex)
+		if err != nil {
+			return 0, err
+		}
+		proof, err := decodeHexFlag("proof", *proofHex)
+		if err != nil {
+			return 0, err
+		}
+		output := vrfOutput(proof)
+		if *outputHex != "" {
+			if output, err = decodeHexFlag("output", *outputHex); err != nil {
+				return 0, err
+			}
+		}
+		recordVerdictInput(hex.EncodeToString(append(append([]byte(nil), pk...), proof...)))
+		fmt.Printf("=== BLS VRF Verify (%s) ===\n", *ciphersuite)
+		fmt.Printf("DST: %s\n", dst)
+		fmt.Printf("Input (hex): %x\n", alpha)
+		verr := blsVrfVerify(pk, alpha, proof, output, *ciphersuite, dst)
+		reportOutput("valid", verr == nil)
+		if verr != nil {
+			fmt.Printf("❌ INVALID: %v\n", verr)
+			return 1, nil
+		}
+		fmt.Printf("Output: %x\n", output)
+		reportOutput("output", hex.EncodeToString(output))
+		fmt.Println("✅ VALID")
+		return 0, nil
+	}
+
+	sk, err := parseSecretKey(*skStr)
+	if err != nil {
+		return 0, usageError{err}
+	}
+	wrongSk := new(big.Int).Add(sk, big.NewInt(1))
+	if wrongSk.Cmp(fr.Modulus()) == 0 {
+		wrongSk.SetInt64(1)
+	}
+	if *wrongSkStr != "" {
+		if wrongSk, err = parseSecretKey(*wrongSkStr); err != nil {
+			return 0, usageErrorf("--wrong-sk: %v", err)
+		}
+		if wrongSk.Cmp(sk) == 0 {
+			return 0, usageErrorf("--wrong-sk must differ from --sk")
+		}
+	}
+	vectors, err := vrfVectors(sk, wrongSk, alpha, *ciphersuite, dst)
+	if err != nil {
+		return 0, err
+	}
+	if !*negative {
+		vectors = vectors[:1]
+	}
+	recordVerdictResult(vectors[0].Output)
+
+	fmt.Printf("=== BLS VRF Prove (%s) ===\n", *ciphersuite)
+	fmt.Printf("DST: %s\n", dst)
+	fmt.Printf("Public key: %s\n", vectors[0].PubKey)
+	fmt.Printf("Input (hex): %s\n", vectors[0].Input)
+	fmt.Printf("Proof: %s\n", vectors[0].Proof)
+	fmt.Printf("Output: %s\n", vectors[0].Output)
+	fmt.Println()
+	failed := 0
+	for i := range vectors {
+		v := &vectors[i]
+		pk, _ := hex.DecodeString(v.PubKey)
+		proof, _ := hex.DecodeString(v.Proof)
+		output, _ := hex.DecodeString(v.Output)
+		verr := blsVrfVerify(pk, alpha, proof, output, *ciphersuite, dst)
+		if verr != nil {
+			v.Reason = verr.Error()
+		}
+		if (verr == nil) != v.Expected {
+			fmt.Printf("❌ %-15s verification returned %v, expected %v\n", v.Name, verr == nil, v.Expected)
+			failed++
+			continue
+		}
+		result := "true"
+		if !v.Expected {
+			result = "false (" + v.Reason + ")"
+		}
+		fmt.Printf("✅ %-15s expected %s\n", v.Name, result)
+		if i > 0 {
+			fmt.Printf("   proof  %s\n   output %s\n", v.Proof, v.Output)
+		}
+	}
+	reportOutput("dst", dst)
+	reportOutput("vectors", vectors)
+	return failed, nil
+}
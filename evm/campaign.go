@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// campaignConfig describes a generation campaign: a list of presets and random batches,
+// optionally emitted for one or more target languages. Example:
+//
+//	{
+//	  "name": "overnight",
+//	  "emit": "all",
+//	  "emit_dir": "fixtures/overnight",
+//	  "template": "class",
+//	  "timing": true,
+//	  "slow_threshold": "250ms",
+//	  "seed": "0x5eed",
+//	  "entries": [
+//	    {"preset": "neo-edge"},
+//	    {"random": {"count": 500, "max_scalars": 128, "use_g2": true}}
+//	  ]
+//	}
+type campaignConfig struct {
+	Name    string `json:"name"`
+	Emit    string `json:"emit"`
+	EmitDir string `json:"emit_dir"`
+	// Template is the --template of the csharp emitter: snippet, class or a template file
+	Template string `json:"template"`
+	// Timing annotates every fixture with its generation time; fixtures slower than
+	// SlowThreshold (a Go duration, default 100ms) are listed as performance test candidates
+	Timing        bool   `json:"timing"`
+	SlowThreshold string `json:"slow_threshold"`
+	// Seed (hex) makes random entries reproducible; it is required for --shard, where each
+	// shard derives its own sub-seed
+	Seed    string          `json:"seed"`
+	Entries []campaignEntry `json:"entries"`
+
+	path string // the config file, for reproduction commands
+}
+
+// campaignEntry is either a named preset or a batch of random MultiExp fixtures
+type campaignEntry struct {
+	Preset string          `json:"preset,omitempty"`
+	Random *campaignRandom `json:"random,omitempty"`
+}
+
+type campaignRandom struct {
+	Count       int    `json:"count"`
+	MaxScalars  int    `json:"max_scalars"`
+	UseG2       bool   `json:"use_g2"`
+	ScalarRange string `json:"scalar_range,omitempty"` // a --scalar-range value, csharp-int by default
+
+	scalars scalarRange // ScalarRange, parsed when the config is loaded
+}
+
+// Approximate single-core cost per MultiExp pair (point generation, scalar multiplication
+// and encoding). Only used for dry-run estimates.
+const (
+	estimatedG1PairCost = 600 * time.Microsecond
+	estimatedG2PairCost = 1500 * time.Microsecond
+)
+
+// loadCampaignConfig reads and validates a campaign config file
+func loadCampaignConfig(path string) (campaignConfig, error) {
+	cfg := campaignConfig{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid campaign config %s: %v", path, err)
+	}
+	if cfg.Name == "" {
+		cfg.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if cfg.EmitDir == "" {
+		cfg.EmitDir = "fixtures"
+	}
+	if cfg.Emit != "" {
+		if _, err := parseEmitTargets(cfg.Emit); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.Template != "" {
+		if err := setCSharpTemplate(cfg.Template); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.SlowThreshold != "" {
+		if _, err := time.ParseDuration(cfg.SlowThreshold); err != nil {
+			return cfg, fmt.Errorf("invalid slow_threshold '%s': %v", cfg.SlowThreshold, err)
+		}
+	}
+	if cfg.Seed != "" {
+		if _, err := parseSeed(cfg.Seed); err != nil {
+			return cfg, err
+		}
+	}
+	if len(cfg.Entries) == 0 {
+		return cfg, fmt.Errorf("campaign %s has no entries", cfg.Name)
+	}
+	for i := range cfg.Entries {
+		e := &cfg.Entries[i]
+		switch {
+		case e.Preset != "" && e.Random != nil:
+			return cfg, fmt.Errorf("entry %d: set either preset or random, not both", i)
+		case e.Preset != "":
+			if _, ok := fixturePresets[e.Preset]; !ok {
+				return cfg, fmt.Errorf("entry %d: unknown preset '%s' (valid: %s)", i, e.Preset, strings.Join(fixturePresetNames(), ", "))
+			}
+		case e.Random != nil:
+			if e.Random.Count < 1 {
+				return cfg, fmt.Errorf("entry %d: random count must be at least 1", i)
+			}
+			if e.Random.MaxScalars == 0 {
+				e.Random.MaxScalars = 128
+			}
+			if e.Random.MaxScalars < 1 {
+				return cfg, fmt.Errorf("entry %d: max_scalars must be at least 1", i)
+			}
+			sr, err := parseScalarRange(e.Random.ScalarRange)
+			if err != nil {
+				return cfg, fmt.Errorf("entry %d: scalar_range: %v", i, err)
+			}
+			e.Random.scalars = sr
+		default:
+			return cfg, fmt.Errorf("entry %d: set preset or random", i)
+		}
+	}
+	return cfg, nil
+}
+
+// campaignEstimate summarizes what one entry (or the whole campaign) would generate.
+// Random fixtures pick their pair count in [1, max_scalars], so sizes are ranges.
+type campaignEstimate struct {
+	Fixtures           int
+	MinPairs, MaxPairs int
+	MinBytes, MaxBytes int
+	MaxTime            time.Duration
+}
+
+func (e *campaignEstimate) add(o campaignEstimate) {
+	e.Fixtures += o.Fixtures
+	e.MinPairs += o.MinPairs
+	e.MaxPairs += o.MaxPairs
+	e.MinBytes += o.MinBytes
+	e.MaxBytes += o.MaxBytes
+	e.MaxTime += o.MaxTime
+}
+
+// fixtureBytes is the raw size of one fixture: compressed points, Ethereum input and
+// both encodings of the expected result
+func fixtureBytes(pairs int, useG2 bool) int {
+	if useG2 {
+		return pairs*(96+288) + 96 + 256
+	}
+	return pairs*(48+160) + 48 + 128
+}
+
+func pairCost(useG2 bool) time.Duration {
+	if useG2 {
+		return estimatedG2PairCost
+	}
+	return estimatedG1PairCost
+}
+
+// estimateCampaignEntry computes the estimate for one entry without any cryptography
+func estimateCampaignEntry(e campaignEntry) campaignEstimate {
+	var est campaignEstimate
+	if e.Preset != "" {
+		for _, c := range fixturePresets[e.Preset].Cases {
+			pairs := len(c.PointMultiples)
+			size := fixtureBytes(pairs, c.UseG2)
+			est.add(campaignEstimate{Fixtures: 1, MinPairs: pairs, MaxPairs: pairs, MinBytes: size, MaxBytes: size,
+				MaxTime: time.Duration(pairs) * pairCost(c.UseG2)})
+		}
+		return est
+	}
+	r := e.Random
+	return campaignEstimate{
+		Fixtures: r.Count,
+		MinPairs: r.Count,
+		MaxPairs: r.Count * r.MaxScalars,
+		MinBytes: r.Count * fixtureBytes(1, r.UseG2),
+		MaxBytes: r.Count * fixtureBytes(r.MaxScalars, r.UseG2),
+		MaxTime:  time.Duration(r.Count*r.MaxScalars) * pairCost(r.UseG2),
+	}
+}
+
+func describeCampaignEntry(e campaignEntry) string {
+	if e.Preset != "" {
+		return "preset " + e.Preset
+	}
+	group := "G1"
+	if e.Random.UseG2 {
+		group = "G2"
+	}
+	return fmt.Sprintf("random %s x%d (max_scalars %d)", group, e.Random.Count, e.Random.MaxScalars)
+}
+
+// formatRange prints "n" or "min-max"
+func formatRange(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("%d", min)
+	}
+	return fmt.Sprintf("%d-%d", min, max)
+}
+
+// printCampaignPlan reports exactly what the campaign would generate
+func printCampaignPlan(cfg campaignConfig, shard campaignShard) {
+	var targets []string
+	if cfg.Emit != "" {
+		targets, _ = parseEmitTargets(cfg.Emit)
+	}
+
+	fmt.Printf("=== Campaign Dry Run: %s ===\n", cfg.Name)
+	if len(targets) > 0 {
+		fmt.Printf("Emit targets: %s (into %s)\n", strings.Join(targets, ", "), cfg.EmitDir)
+	} else {
+		fmt.Println("Emit targets: none (stdout only)")
+	}
+	fmt.Println()
+
+	var total campaignEstimate
+	for i, e := range cfg.Entries {
+		est := estimateCampaignEntry(e)
+		total.add(est)
+		fmt.Printf("[%d] %s\n", i, describeCampaignEntry(e))
+		fmt.Printf("    Fixtures: %d, pairs: %s, fixture bytes: %s\n", est.Fixtures,
+			formatRange(est.MinPairs, est.MaxPairs), formatRange(est.MinBytes, est.MaxBytes))
+		if len(targets) > 0 {
+			fmt.Printf("    Files: %d\n", est.Fixtures*len(targets))
+		}
+		fmt.Printf("    Estimated time: up to %v\n", est.MaxTime.Round(time.Millisecond))
+	}
+	fmt.Println()
+	fmt.Println("Total:")
+	fmt.Printf("  Fixtures: %d\n", total.Fixtures)
+	fmt.Printf("  Pairs: %s\n", formatRange(total.MinPairs, total.MaxPairs))
+	fmt.Printf("  Fixture bytes: %s (emitted files are roughly 2x as hex)\n", formatRange(total.MinBytes, total.MaxBytes))
+	if len(targets) > 0 {
+		fmt.Printf("  Files: %d\n", total.Fixtures*len(targets))
+	}
+	fmt.Printf("  Estimated time: up to %v (approximate, single core)\n", total.MaxTime.Round(time.Millisecond))
+	if shard.sharded() {
+		mine := 0
+		for k := 0; k < total.Fixtures; k++ {
+			if shard.includes(k) {
+				mine++
+			}
+		}
+		fmt.Printf("Shard %s: %d of %d fixtures (fixture indices k with k %% %d == %d)\n",
+			shard, mine, total.Fixtures, shard.Count, shard.Index)
+	}
+}
+
+// runCampaign generates every entry of the campaign, or only the fixtures of one shard, or
+// only global fixture index only (-1 for all). With a seed, random fixture k is generated
+// from deriveSeed(shard sub-seed, k), so a shard always reproduces the same bytes and every
+// fixture can be regenerated on its own. Seeded and sharded runs write a manifest.
+func runCampaign(cfg campaignConfig, shard campaignShard, only int) error {
+	timing := &timingOptions{Enabled: cfg.Timing, SlowThreshold: defaultSlowThreshold}
+	if cfg.SlowThreshold != "" {
+		timing.SlowThreshold, _ = time.ParseDuration(cfg.SlowThreshold) // validated on load
+	}
+	var shardSeed []byte
+	if cfg.Seed != "" {
+		seed, _ := parseSeed(cfg.Seed) // validated on load
+		shardSeed = deriveSeed(seed, "shard "+shard.String())
+	}
+	manifest := campaignManifest{Campaign: cfg.Name, Seed: cfg.Seed, Shard: shard.Index, Shards: 1}
+	if shard.sharded() {
+		manifest.Shards = shard.Count
+		fmt.Printf("=== Campaign %s: shard %s ===\n", cfg.Name, shard)
+	}
+	selected := func(k int) bool { return shard.includes(k) && (only < 0 || k == only) }
+
+	k := 0 // global fixture index across entries
+	for i, e := range cfg.Entries {
+		if n := estimateCampaignEntry(e).Fixtures; only >= 0 && (only < k || only >= k+n) {
+			k += n // skip entries without the selected fixture
+			continue
+		}
+		fmt.Printf("=== Campaign %s: entry %d (%s) ===\n", cfg.Name, i, describeCampaignEntry(e))
+		if e.Preset != "" {
+			if !shard.sharded() && shardSeed == nil && only < 0 {
+				if err := generatePreset(e.Preset, cfg.Emit, cfg.EmitDir, timing); err != nil {
+					return err
+				}
+				k += len(fixturePresets[e.Preset].Cases)
+				continue
+			}
+			for _, c := range fixturePresets[e.Preset].Cases {
+				if selected(k) {
+					reproduce := campaignReproduce(cfg, shard, k)
+					f, dir, err := generatePresetCase(e.Preset, c, cfg.Emit, cfg.EmitDir, timing, reproduce)
+					if err != nil {
+						return err
+					}
+					manifest.Fixtures = append(manifest.Fixtures, newManifestEntry(k, i, f, dir, reproduce))
+				}
+				k++
+			}
+			continue
+		}
+		for j := 0; j < e.Random.Count; j, k = j+1, k+1 {
+			if !selected(k) {
+				continue
+			}
+			name := fmt.Sprintf("random-%d-%04d", i, j)
+			start := time.Now()
+			var f multiExpFixture
+			reproduce := ""
+			if shardSeed != nil {
+				var err error
+				rng := newSeededReader(deriveSeed(shardSeed, fmt.Sprintf("fixture %d", k)))
+				if f, err = buildSeededRandomFixture(name, rng, e.Random.MaxScalars, e.Random.UseG2, e.Random.scalars); err != nil {
+					return err
+				}
+				printMultiExpFixture(f)
+				reproduce = campaignReproduce(cfg, shard, k)
+				recordReproduce(f.Name, reproduce)
+			} else {
+				var err error
+				if f, err = runRandomMode(name, e.Random.MaxScalars, e.Random.UseG2, e.Random.scalars, nil); err != nil {
+					return err
+				}
+			}
+			timing.report(fmt.Sprintf("%s, %d pairs", name, len(f.Points)), time.Since(start))
+			dir := ""
+			if cfg.Emit != "" {
+				dir = filepath.Join(cfg.EmitDir, name)
+				if err := emitFixtures(f, cfg.Emit, dir); err != nil {
+					return err
+				}
+			}
+			manifest.Fixtures = append(manifest.Fixtures, newManifestEntry(k, i, f, dir, reproduce))
+		}
+	}
+	timing.printSummary()
+	if only < 0 && (shard.sharded() || shardSeed != nil) {
+		return writeCampaignManifest(manifestPath(cfg.EmitDir, shard), manifest)
+	}
+	return nil
+}
+
+// campaignReproduce is the command that regenerates only fixture k of a campaign run
+func campaignReproduce(cfg campaignConfig, shard campaignShard, k int) string {
+	args := []string{"campaign", "--config", cfg.path}
+	if cfg.Seed != "" {
+		args = append(args, "--seed", cfg.Seed)
+	}
+	if shard.sharded() {
+		args = append(args, "--shard", shard.String())
+	}
+	return reproduceCommand(append(args, "--fixture", strconv.Itoa(k))...)
+}
+
+// runCampaignMode runs the campaign mode
+func runCampaignMode(args []string) error {
+	fs := newFlagSet("campaign")
+	configPath := fs.String("config", "", "Campaign config file (JSON)")
+	dryRun := fs.Bool("dry-run", false, "Report what would be generated without doing the cryptography")
+	shardStr := fs.String("shard", "", "Generate only shard i of n (i/n, 0-based); requires a seed")
+	seed := fs.String("seed", "", "Seed (hex) for random entries, overrides the config's seed")
+	merge := fs.Bool("merge", false, "Merge the shard manifests in emit_dir into manifest.json")
+	only := fs.Int("fixture", -1, "Generate only global fixture index k (as printed by the Reproduce line; random entries need a seed)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *configPath == "" {
+		return fmt.Errorf("--config is required")
+	}
+	cfg, err := loadCampaignConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *seed != "" {
+		if _, err := parseSeed(*seed); err != nil {
+			return err
+		}
+		cfg.Seed = *seed
+	}
+	if *merge {
+		return mergeShardManifests(cfg)
+	}
+	var shard campaignShard
+	if *shardStr != "" {
+		if shard, err = parseShard(*shardStr); err != nil {
+			return err
+		}
+		if shard.sharded() && cfg.Seed == "" {
+			return fmt.Errorf("--shard requires a seed (config \"seed\" or --seed) so every shard is reproducible")
+		}
+	}
+	if *only >= 0 {
+		if err := checkCampaignFixture(cfg, shard, *only); err != nil {
+			return err
+		}
+	}
+	if *dryRun {
+		printCampaignPlan(cfg, shard)
+		return nil
+	}
+	return runCampaign(cfg, shard, *only)
+}
+
+// checkCampaignFixture validates --fixture: the index must exist, belong to the shard and,
+// for a random entry, be reproducible from a seed
+func checkCampaignFixture(cfg campaignConfig, shard campaignShard, k int) error {
+	start := 0
+	for i, e := range cfg.Entries {
+		n := estimateCampaignEntry(e).Fixtures
+		if k < start+n {
+			if !shard.includes(k) {
+				return fmt.Errorf("fixture %d is not in shard %s", k, shard)
+			}
+			if e.Random != nil && cfg.Seed == "" {
+				return fmt.Errorf("fixture %d belongs to random entry %d; --fixture needs a seed (config \"seed\" or --seed)", k, i)
+			}
+			return nil
+		}
+		start += n
+	}
+	return fmt.Errorf("campaign %s has %d fixtures, --fixture %d is out of range", cfg.Name, start, k)
+}
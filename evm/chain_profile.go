@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"evm/bls12381vec"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// scalarPolicy defines how a chain treats 32-byte scalars >= r
+type scalarPolicy string
+
+const (
+	// scalarReduce accepts any 32-byte scalar and reduces it mod r (EIP-2537)
+	scalarReduce scalarPolicy = "reduce"
+	// scalarCanonical rejects scalars >= r (Neo's Scalar type only holds canonical values)
+	scalarCanonical scalarPolicy = "canonical"
+)
+
+// gasModel names the cost schedule of a chain
+type gasModel string
+
+const (
+	// gasEIP2537 is the EIP-2537 precompile gas schedule (Prague/Pectra)
+	gasEIP2537 gasModel = "eip2537"
+	// gasNeoCryptoLib is the CPU fee of the CryptoLib calls the operation is built from
+	gasNeoCryptoLib gasModel = "neo-cryptolib"
+)
+
+// chainProfile bundles every behavior that differs between target chains, so --chain
+// configures them together instead of one flag each
+type chainProfile struct {
+	Description string
+	// InputFormat is the default --input-format of g1msm, g2msm and pairing
+	InputFormat string
+	Scalars     scalarPolicy
+	// EmptyInput is the emptyInputProfiles entry used as default --profile
+	EmptyInput string
+	// AddSubgroupCheck requires g1add/g2add operands to be in the subgroup; EIP-2537
+	// only checks them on the curve, Neo deserializes (and subgroup-checks) every point
+	AddSubgroupCheck bool
+	Gas              gasModel
+}
+
+// chainProfiles lists the known --chain values. Neo X is an EVM chain and follows the
+// Ethereum precompile rules.
+var chainProfiles = map[string]chainProfile{
+	"neo-n3":      {Description: "Neo N3 CryptoLib", InputFormat: "compressed", Scalars: scalarCanonical, EmptyInput: "neo", AddSubgroupCheck: true, Gas: gasNeoCryptoLib},
+	"neox":        {Description: "Neo X EVM sidechain, EIP-2537 precompiles", InputFormat: "ethereum", Scalars: scalarReduce, EmptyInput: "eip2537", AddSubgroupCheck: false, Gas: gasEIP2537},
+	"eth-mainnet": {Description: "Ethereum mainnet, EIP-2537 precompiles", InputFormat: "ethereum", Scalars: scalarReduce, EmptyInput: "eip2537", AddSubgroupCheck: false, Gas: gasEIP2537},
+}
+
+func chainProfileNames() []string {
+	names := make([]string, 0, len(chainProfiles))
+	for name := range chainProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registerChainFlag adds --chain to an operation mode
+func registerChainFlag(fs *flag.FlagSet) *string {
+	return fs.String("chain", "", "Chain profile: "+strings.Join(chainProfileNames(), ", ")+" (sets --profile, --input-format, scalar and subgroup rules and the gas model)")
+}
+
+// applyChain looks up --chain and fills in --profile and --input-format unless they were
+// given explicitly. It returns nil when no chain was selected.
+func applyChain(fs *flag.FlagSet, name string, profile, inputFormat *string) (*chainProfile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	c, ok := chainProfiles[name]
+	if !ok {
+		return nil, usageErrorf("unknown chain '%s' (valid: %s)", name, strings.Join(chainProfileNames(), ", "))
+	}
+	if !isFlagSet(fs, "profile") {
+		*profile = c.EmptyInput
+	}
+	if inputFormat != nil && !isFlagSet(fs, "input-format") {
+		*inputFormat = c.InputFormat
+	}
+	return &c, nil
+}
+
+// checkInput applies the chain rules the shared EIP-2537 implementation does not: subgroup
+// checks on add operands and the scalar policy. input is the Ethereum-format input.
+func (c *chainProfile) checkInput(op, inputHex string) error {
+	input, err := hex.DecodeString(strings.TrimSpace(inputHex))
+	if err != nil {
+		// Reported by the operation itself
+		return nil
+	}
+	switch op {
+	case "g1add", "g2add":
+		pointLen := 128
+		if op == "g2add" {
+			pointLen = 256
+		}
+		if !c.AddSubgroupCheck || len(input) != 2*pointLen {
+			return nil
+		}
+		for i := 0; i < 2; i++ {
+			point := input[i*pointLen : (i+1)*pointLen]
+			if op == "g1add" {
+				_, err = bls12381vec.DecodeEIP2537G1Point(point, true)
+			} else {
+				_, err = bls12381vec.DecodeEIP2537G2Point(point, true)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: point %d: %v (the chain subgroup-checks add operands)", op, i, err)
+			}
+		}
+	case "g1mul", "g2mul", "g1msm", "g2msm":
+		if c.Scalars != scalarCanonical {
+			return nil
+		}
+		pairLen := bls12381vec.G1MSMPairLength
+		if op == "g2mul" || op == "g2msm" {
+			pairLen = bls12381vec.G2MSMPairLength
+		}
+		if len(input)%pairLen != 0 {
+			return nil
+		}
+		for i := 0; i*pairLen < len(input); i++ {
+			s := new(big.Int).SetBytes(input[(i+1)*pairLen-32 : (i+1)*pairLen])
+			if s.Cmp(fr.Modulus()) >= 0 {
+				return fmt.Errorf("%s: scalar of pair %d is >= r (the chain requires canonical scalars)", op, i)
+			}
+		}
+	}
+	return nil
+}
+
+// EIP-2537 MSM discounts per 1000 for k = 1..128 pairs; larger inputs use the last entry
+var (
+	eip2537G1MSMDiscount = []uint64{
+		1000, 949, 848, 797, 764, 750, 738, 728, 719, 712, 705, 698, 692, 687, 682, 677,
+		673, 669, 665, 661, 658, 654, 651, 648, 645, 642, 640, 637, 635, 632, 630, 627,
+		625, 623, 621, 619, 617, 615, 613, 611, 609, 608, 606, 604, 603, 601, 599, 598,
+		596, 595, 593, 592, 591, 589, 588, 586, 585, 584, 582, 581, 580, 579, 577, 576,
+		575, 574, 573, 572, 570, 569, 568, 567, 566, 565, 564, 563, 562, 561, 560, 559,
+		558, 557, 556, 555, 554, 553, 552, 551, 550, 549, 548, 547, 547, 546, 545, 544,
+		543, 542, 541, 540, 540, 539, 538, 537, 536, 536, 535, 534, 533, 532, 532, 531,
+		530, 529, 528, 528, 527, 526, 525, 525, 524, 523, 522, 522, 521, 520, 520, 519,
+	}
+	eip2537G2MSMDiscount = []uint64{
+		1000, 1000, 923, 884, 855, 832, 812, 796, 782, 770, 759, 749, 740, 732, 724, 717,
+		711, 704, 699, 693, 688, 683, 679, 674, 670, 666, 663, 659, 655, 652, 649, 646,
+		643, 640, 637, 634, 632, 629, 627, 624, 622, 620, 618, 615, 613, 611, 609, 607,
+		606, 604, 602, 600, 598, 597, 595, 593, 592, 590, 589, 587, 586, 584, 583, 582,
+		580, 579, 578, 576, 575, 574, 573, 571, 570, 569, 568, 567, 566, 565, 563, 562,
+		561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 552, 551, 550, 549, 548, 547,
+		546, 545, 545, 544, 543, 542, 541, 541, 540, 539, 538, 537, 537, 536, 535, 535,
+		534, 533, 532, 532, 531, 530, 530, 529, 528, 528, 527, 526, 526, 525, 524, 524,
+	}
+)
+
+// Neo CryptoLib CPU fees; the datoshi cost is the fee times the ExecFeeFactor policy
+// value (30 by default)
+const (
+	neoFeeBls12381Add     = 1 << 19
+	neoFeeBls12381Mul     = 1 << 21
+	neoFeeBls12381Pairing = 1 << 23
+)
+
+// gasCost returns the cost of op on k pairs (k = 1 for add and mul) and its unit
+func (m gasModel) gasCost(op string, k int) (uint64, string) {
+	n := uint64(k)
+	switch m {
+	case gasEIP2537:
+		msm := func(base uint64, discount []uint64) uint64 {
+			if n == 0 {
+				return 0
+			}
+			return n * base * discount[min(k, len(discount))-1] / 1000
+		}
+		switch op {
+		case "g1add":
+			return 375, "gas"
+		case "g2add":
+			return 600, "gas"
+		case "g1mul", "g1msm":
+			// EIP-2537 has no MUL precompile; a multiplication is a one-pair MSM
+			return msm(12000, eip2537G1MSMDiscount), "gas"
+		case "g2mul", "g2msm":
+			return msm(22500, eip2537G2MSMDiscount), "gas"
+		case "pairing":
+			return 32600*n + 37700, "gas"
+		case "map-fp-to-g1":
+			return 5500, "gas"
+		case "map-fp2-to-g2":
+			return 23800, "gas"
+		}
+	case gasNeoCryptoLib:
+		// One Bls12381Mul per pair and Bls12381Add to combine; pairings are multiplied
+		// in GT with Bls12381Add as well
+		switch op {
+		case "g1add", "g2add":
+			return neoFeeBls12381Add, "CPU fee units"
+		case "g1mul", "g2mul", "g1msm", "g2msm":
+			if n == 0 {
+				return 0, "CPU fee units"
+			}
+			return n*neoFeeBls12381Mul + (n-1)*neoFeeBls12381Add, "CPU fee units"
+		case "pairing":
+			if n == 0 {
+				return 0, "CPU fee units"
+			}
+			return n*neoFeeBls12381Pairing + (n-1)*neoFeeBls12381Add, "CPU fee units"
+		}
+	}
+	return 0, ""
+}
+
+// reportGas prints the chain's cost of op on an Ethereum-format input
+func (c *chainProfile) reportGas(name, op, inputHex string) {
+	k := 1
+	if size := pairSizeForOp(op); size > 0 {
+		k = len(strings.TrimSpace(inputHex)) / 2 / size
+	}
+	cost, unit := c.Gas.gasCost(op, k)
+	fmt.Printf("Chain: %s (%s)\n", name, c.Description)
+	fmt.Printf("Gas (%s): %d %s\n", c.Gas, cost, unit)
+	reportOutput("gas", cost)
+}
+
+// runChainProfilesMode prints the rules of one or all chain profiles
+func runChainProfilesMode(args []string) error {
+	fs := newFlagSet("chain-profiles")
+	chain := fs.String("chain", "", "Chain to print ("+strings.Join(chainProfileNames(), ", ")+"; default: all)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	names := chainProfileNames()
+	if *chain != "" {
+		if _, ok := chainProfiles[*chain]; !ok {
+			return usageErrorf("unknown chain '%s' (valid: %s)", *chain, strings.Join(names, ", "))
+		}
+		names = []string{*chain}
+	}
+	for _, name := range names {
+		c := chainProfiles[name]
+		rules := emptyInputProfiles[c.EmptyInput]
+		fmt.Printf("=== Chain: %s (%s) ===\n", name, c.Description)
+		fmt.Printf("Input format: %s\n", c.InputFormat)
+		fmt.Printf("Scalars: %s\n", c.Scalars)
+		fmt.Printf("Empty input: profile %s (MSM %s, pairing %s)\n", c.EmptyInput, rules.MSMEmpty, rules.PairingEmpty)
+		fmt.Printf("Subgroup check on add operands: %v (MSM and pairing always check)\n", c.AddSubgroupCheck)
+		fmt.Printf("Gas model: %s\n", c.Gas)
+		for _, op := range []string{"g1add", "g2add", "g1msm", "g2msm", "pairing"} {
+			one, unit := c.Gas.gasCost(op, 1)
+			line := fmt.Sprintf("  %-8s %d %s", op, one, unit)
+			if pairSizeForOp(op) > 0 {
+				many, _ := c.Gas.gasCost(op, 128)
+				line += fmt.Sprintf(" (k=1), %d (k=128)", many)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"evm/bls12381vec"
+	"evm/logging"
+	"evm/serialization"
+)
+
+// cliCommand is one subcommand of the tool. Every mode parses its flags with a flag set
+// from newFlagSet, so "<command> --help" and "help <command>" print the same flag list
+// and flag errors are reported the same way everywhere.
+type cliCommand struct {
+	name     string
+	synopsis string // arguments after the command name
+	summary  string
+	run      func(args []string) error
+}
+
+// cliCommands lists every command in the order of the usage text
+func cliCommands() []cliCommand {
+	precompile := func(mode string) func([]string) error {
+		return func(args []string) error { return runPrecompileCommand(mode, args) }
+	}
+	aggregate := func(mode string) func([]string) error {
+		return func(args []string) error { return runAggregateMode(mode, args) }
+	}
+	hashToCurve := func(mode string) func([]string) error {
+		return func(args []string) error { return runHashToCurveMode(mode, args) }
+	}
+	pointOp := func(mode string) func([]string) error {
+		return func(args []string) error { return runPointOpMode(mode, args) }
+	}
+	poly := func(mode string) func([]string) error {
+		return func(args []string) error { return runPolyMode(mode, args) }
+	}
+	return []cliCommand{
+		{"random", "[max_scalars] [--use-g2] [--count N] [--seed <hex> [--fixture i]] [--emit <targets>] [--emit-dir <dir>] [--out-dir <dir>] [--gt-format <formats>] [--scalar-range <range>]", "Random MultiExp fixture with up to max_scalars scalars (default 128); also the default with no command", runRandomCommand},
+		{"manual", "--g1 <hex> | --g2 <hex> --use-g2 --scalars \"<s1,s2,...>\"", "MultiExp of one compressed point with a list of scalars", runManualCommand},
+		{"ethereum", "--input <hex> [--use-g2] [--verbose]", "MultiExp of an Ethereum-format (uncompressed) input, for Neo test vectors", runEthereumCommand},
+		{"g1add", "--input <hex> [--chain <c>]", "G1 addition, 256-byte Ethereum-format input", precompile("g1add")},
+		{"g2add", "--input <hex> [--chain <c>]", "G2 addition, 512-byte Ethereum-format input", precompile("g2add")},
+		{"g1mul", "--input <hex> [--chain <c>] [--trace]", "G1 scalar multiplication, 160-byte Ethereum-format input", precompile("g1mul")},
+		{"g2mul", "--input <hex> [--chain <c>] [--trace]", "G2 scalar multiplication, 288-byte Ethereum-format input", precompile("g2mul")},
+		{"g1msm", "--input <hex> [--input-format ethereum|compressed|auto] [--profile <p>] [--empty error|identity] [--chain <c>]", "G1 MSM with exact EIP-2537 semantics (k * 160 bytes)", precompile("g1msm")},
+		{"g2msm", "--input <hex> [--input-format ethereum|compressed|auto] [--profile <p>] [--empty error|identity] [--chain <c>]", "G2 MSM with exact EIP-2537 semantics (k * 288 bytes)", precompile("g2msm")},
+		{"g1neg", "--point <hex> [--format <f>] [--no-subgroup-check]", "G1 negation -P of a compressed or Ethereum-format point", pointOp("g1neg")},
+		{"g2neg", "--point <hex> [--format <f>] [--no-subgroup-check]", "G2 negation -P of a compressed or Ethereum-format point", pointOp("g2neg")},
+		{"g1double", "--point <hex> [--format <f>] [--no-subgroup-check]", "G1 doubling 2P of a compressed or Ethereum-format point", pointOp("g1double")},
+		{"g2double", "--point <hex> [--format <f>] [--no-subgroup-check]", "G2 doubling 2P of a compressed or Ethereum-format point", pointOp("g2double")},
+		{"g1sub", "--a <hex> --b <hex> [--format <f>] [--no-subgroup-check]", "G1 subtraction A - B of compressed or Ethereum-format points", pointOp("g1sub")},
+		{"g2sub", "--a <hex> --b <hex> [--format <f>] [--no-subgroup-check]", "G2 subtraction A - B of compressed or Ethereum-format points", pointOp("g2sub")},
+		{"g2add-random", "[--seed <hex>]", "Random G2 addition test", runG2AddRandomCommand},
+		{"edge-cases", "[--op add,mul,msm,pairing|all] [--group g1|g2|both] [--chain <c>] [--verbose] [--out <file.json>]", "Infinity, zero and r-boundary scalar and single-pair MSM cases with expected results, run through every implementation", func(args []string) error { return checkFailures(runEdgeCasesMode(args)) }},
+		{"empty-input-vectors", "[--profile eip2537|neo|gnark]", "Empty and zero-pair input vectors per chain profile", runEmptyInputVectors},
+		{"gas", "--op <op> (--input <hex> | --pairs k | --table)", "EIP-2537 gas of an input, with the MSM discount applied for its pair count", runGasMode},
+		{"chain-profiles", "[--chain neo-n3|neox|eth-mainnet]", "Format, scalar, empty-input, subgroup and gas rules of each --chain profile", runChainProfilesMode},
+		{"validation-order", "[--profile eip2537|neo] [--out <file.json>]", "Inputs with several defects and the first error each profile must report", runValidationOrderMode},
+		{"gt-equal", "--a <hex> --b <hex> [--format auto|gnark|neo] [--gt-format <formats>]", "Compare two serialized GT elements", runGTEqualMode},
+		{"gt-equal-vectors", "[--format neo|gnark|both] [--gt-format <formats>]", "Equal/unequal GT pairs from different pairing computations", runGTEqualVectors},
+		{"preset", "<name>|list [--case <case>] [--emit <targets>] [--emit-dir <dir>] [--timing]", "Named deterministic fixture presets (also: --preset <name>)", runPresetCommand},
+		{"weighted", "--weights <file.csv> [--column <name>] [--count N] [--use-g2] [--emit <targets>]", "MultiExp fixture with scalars from a weight CSV (e.g. validator stakes)", runWeightedMode},
+		{"import-csharp", "--file <snippet.cs> [--group g1|g2] [--expected <hex>] [--emit <targets>]", "Re-check C# arrays pasted from Bls12381MultiExpHelper.cs", func(args []string) error { return checkFailures(runImportCSharpMode(args)) }},
+		{"neo-alias-args", "--op <operation> --input <hex> [--input-format ethereum|compressed|auto]", "Neo Ethereum alias method argument and expected result", runNeoAliasArgsMode},
+		{"corrupt-nearby", "--point <hex> [--all-bits] [--out <file.csv|file.json>]", "Nearby corruption variants of a valid point, labeled accept/reject", runCorruptMode},
+		{"invalid-vectors", "[--group g1|g2|both] [--out <file.csv|file.json>]", "Malformed inputs for every rejection rule, with the expected error codes", func(args []string) error { return checkFailures(runInvalidVectorsMode(args)) }},
+		{"validate-file", "--file <dump.txt>|- [--format auto|g1c|g2c|g1u|g2u|g1e|g2e] [--invalid-only] [--out <file.csv>]", "Per-line validation verdicts and statistics for a file of point encodings", func(args []string) error { return checkFailures(runValidateFileMode(args)) }},
+		{"verify-errors", "--fixtures <file.json> (--results <file.csv> [--impl gnark|geth|go] [--map <rules.json>] | --self)", "Map an implementation's concrete errors onto the taxonomy and compare with the fixture codes", func(args []string) error { return checkFailures(runVerifyErrorsMode(args)) }},
+		{"neo-compare", "--response <file.json> | --rpc <url> --script <base64> (--expected <value> | --expect-fault)", "Compare a Neo invocation result with the expectation", runNeoCompareMode},
+		{"neo-script", "--op add|mul|pairing|multiexp <inputs> [--id N] [--body-out <file.json>]", "Base64 NeoVM script and invokescript request calling CryptoLib, with the expected result", runNeoScriptMode},
+		{"neo-verify", "--rpc-url <url> (--vectors <file.json> | --op add|mul|pairing|multiexp <inputs>)", "Run vectors on a Neo N3 node with invokescript and check them against the local results", func(args []string) error { return checkFailures(runNeoVerifyMode(args)) }},
+		{"verify-file", "--file <file.json|dir>[,...] [--op <op>] [--verbose]", "Recompute every case of ethereum/tests precompile files or MultiExp fixture JSON and report mismatches", func(args []string) error { return checkFailures(runVerifyFileMode(args)) }},
+		{"import-eth-tests", "--file <file.json|dir>[,...] [--op <op>] [--map <rules.json>] [--gas=false] [--out <dir>]", "Run the official EIP-2537 vectors (ethereum/tests, go-ethereum, EIP assets) with per-case error-category matching", func(args []string) error { return checkFailures(runImportEthTestsMode(args)) }},
+		{"eth-verify", "--rpc-url <url> (--op <op> --input <hex> | --vectors <file.json>) [--addresses final|draft]", "Run EIP-2537 inputs with eth_call on a node and diff the results against the local ones", func(args []string) error { return checkFailures(runEthVerifyMode(args)) }},
+		{"hash-and-sign", "--message <text> | --message-hex <hex> --sk <key> [--ciphersuite min-pk|min-sig] [--dst <tag>]", "Hashed point, signature, public key and pairing-check input for one message", runHashAndSignMode},
+		{"keygen", "[--ikm <hex>] [--key-info <text> | --key-info-hex <hex>] [--ciphersuite min-pk|min-sig]", "IETF BLS KeyGen (HKDF-SHA256) secret key and public key from input keying material", runKeygenMode},
+		{"sign", "--sk <key> --message <text> | --message-hex <hex> [--ciphersuite min-pk|min-sig] [--scheme basic|aug|pop] [--dst <tag>]", "IETF BLS Sign with the basic, message-augmentation or proof-of-possession scheme", runSignMode},
+		{"verify", "--pk <hex> --signature <hex> --message <text> | --message-hex <hex> [--ciphersuite min-pk|min-sig] [--scheme basic|aug|pop] [--dst <tag>]", "IETF BLS Verify with KeyValidate; exits 1 on an invalid signature", func(args []string) error { return checkFailures(runVerifyMode(args)) }},
+		{"aggregate", "--sks <k1,k2,...> (--message <text> | --message-hex <hex> | --messages-hex <h1,h2,...>) [--ciphersuite min-pk|min-sig] [--scheme basic|aug|pop] [--dst <tag>]", "Sign per key, aggregate the signatures and record the expected verification result", runAggregateSignMode},
+		{"aggregate-verify", "--pks <hex,...> | --file <file> --signature <hex> (--message <text> | --message-hex <hex> | --messages-hex <h1,h2,...>) [--ciphersuite ...] [--scheme ...] [--dst <tag>]", "AggregateVerify, or FastAggregateVerify for a common message under pop; exits 1 on an invalid signature", func(args []string) error { return checkFailures(runAggregateVerifyMode(args)) }},
+		{"pop-prove", "--sk <key> [--wrong-sk <key>] [--negative=false] [--ciphersuite min-pk|min-sig] [--dst <tag>]", "Proof of possession of a key, with wrong-key and malformed proofs a registration must refuse", func(args []string) error { return checkFailures(runPopProveMode(args)) }},
+		{"pop-verify", "--pk <hex> --proof <hex> [--ciphersuite min-pk|min-sig] [--dst <tag>]", "PopVerify with KeyValidate; exits 1 on an invalid proof", func(args []string) error { return checkFailures(runPopVerifyMode(args)) }},
+		{"derive", "--seed <hex> | --sk <key> [--path m/12381/3600/0/0/0] [--lamport] [--ciphersuite min-pk|min-sig]", "EIP-2333 key tree along an EIP-2334 path, with the Lamport values of every step", runDeriveMode},
+		{"keystore-create", "--sk <key> | --seed <hex> [--path <path>] --password <text> | --password-hex <hex> [--kdf scrypt|pbkdf2] [--cost N] [--salt <hex>] [--iv <hex>] [--uuid <id>] [--out <file>]", "EIP-2335 keystore of a secret key (scrypt or pbkdf2)", runKeystoreCreateMode},
+		{"keystore-unlock", "--file <keystore.json> --password <text> | --password-hex <hex>", "Decrypt an EIP-2335 keystore and check its checksum and public key; exits 1 on a wrong password", func(args []string) error { return checkFailures(runKeystoreUnlockMode(args)) }},
+		{"eth-spec-gen", "[--out <dir>] [--handlers <h1,h2,...>]", "Consensus-spec BLS test cases (sign, verify, aggregate, ...) in the data.yaml layout", runEthSpecGenMode},
+		{"eth-spec-run", "--dir <tests/general/phase0/bls>[,...] [--handlers <h1,h2,...>] [--verbose]", "Replay consensus-spec BLS test cases against the local implementation", func(args []string) error { return checkFailures(runEthSpecRunMode(args)) }},
+		{"threshold-split", "[--sk <key>] -t <t> -n <n> [--coefficients <a1,...>] --message <text> | --message-hex <hex> [--ciphersuite ...] [--scheme ...]", "Shamir-split a secret key, sign with every share and recombine t partial signatures", func(args []string) error { return checkFailures(runThresholdSplitMode(args)) }},
+		{"threshold-combine", "--ids <i1,i2,...> --partials <hex,...> [--pk <hex> --message <text>] [--ciphersuite ...] [--scheme ...]", "Combine partial signatures with the Lagrange coefficients at 0 and verify the result", func(args []string) error { return checkFailures(runThresholdCombineMode(args)) }},
+		{"dkg-sim", "[-t <t>] [-n <n>] [--seed <hex>] [--bad-shares <i:j,...>] [--refuse <i,...>] [--message <text>] [--out <file>]", "Simulate a Pedersen (joint Feldman) DKG and dump its transcript as test vectors", func(args []string) error { return checkFailures(runDkgSimMode(args)) }},
+		{"vrf", "--sk <key> | --pk <hex> --proof <hex> [--output <hex>] --input <text> | --input-hex <hex> [--ciphersuite ...] [--dst <tag>]", "BLS VRF proofs (signature of the input, output SHA-256 of the proof) with negative cases", func(args []string) error { return checkFailures(runVrfMode(args)) }},
+		{"kzg", "--setup <trusted_setup.json|.txt> | --insecure-seed <hex> [--blob <file> | --blob-seed <hex>] [--z <value> | --z-domain <i>] [--diff-geth] [--out <file>]", "EIP-4844 KZG commitment, proof and point evaluation precompile (0x0a) vectors", func(args []string) error { return checkFailures(runKZGMode(args)) }},
+		{"groth16", "[--x <value>] [--out <file>]", "gnark Groth16 verifying key, proof and public input vectors for the circuit x^3 + x + 5 == y", func(args []string) error { return checkFailures(runGroth16Mode(args)) }},
+		{"pedersen", "[--values <v1,...>] [--blinding <r>] [--add-values <v1,...>] [--add-blinding <r>] [--seed <hex>] [--dst <tag>]", "Pedersen commitments over G1 with opening and homomorphic-addition vectors", func(args []string) error { return checkFailures(runPedersenMode(args)) }},
+		{"dleq", "[--secret <k>] [--nonce <w>] [--groups g1|g1g2] [--h-message <text>] [--seed <hex>] [--dst <tag>]", "Chaum-Pedersen discrete-log-equality proofs with valid and invalid vectors", func(args []string) error { return checkFailures(runDleqMode(args)) }},
+		{"hash-g1", "--message <text> | --message-hex <hex> [--dst <tag>] [--trace]", "RFC 9380 hash to G1 (BLS12381G1_XMD:SHA-256_SSWU_RO_) in every encoding", hashToCurve("hash-g1")},
+		{"hash-g2", "--message <text> | --message-hex <hex> [--dst <tag>] [--trace]", "RFC 9380 hash to G2 (BLS12381G2_XMD:SHA-256_SSWU_RO_) in every encoding", hashToCurve("hash-g2")},
+		{"agg-pubkeys", "--points <hex,...> | --file <file> [--ciphersuite min-pk|min-sig]", "Sum of compressed public keys", aggregate("agg-pubkeys")},
+		{"agg-sigs", "--points <hex,...> | --file <file> [--ciphersuite min-pk|min-sig]", "Sum of compressed signatures", aggregate("agg-sigs")},
+		{"campaign", "--config <campaign.json> [--dry-run] [--shard <i>/<n>] [--seed <hex>] [--fixture k] [--merge]", "Generation campaigns from a JSON config of presets and random batches", runCampaignMode},
+		{"scalar-report", "--scalar <value> [--format auto|dec|hex|le-hex]", "Every representation of a scalar (decimal, BE/LE bytes, mod r, C# BigInteger)", runScalarReportMode},
+		{"scalar", "--op reduce|neg|inv --value <v> [--format auto|dec|hex|le-hex] | --op random [--count N] [--seed <hex>]", "Fr reduction, negation, inverse and random elements in 32-byte BE/LE and C# BigInteger form", runScalarMode},
+		{"convert", "--point <hex> [--from auto|compressed|uncompressed|ethereum] [--to <formats>|all] [--group auto|g1|g2] [--no-subgroup-check]", "Re-encode a G1/G2 point between the compressed, uncompressed and Ethereum formats", runConvertMode},
+		{"well-known", "[--name <entry>] [--group g1|g2|all] [--to <formats>|all] [--list]", "Canonical generators, identities and standard test points in every encoding", runWellKnownMode},
+		{"eip2537-suite", "[--out <dir>] [--op <ops>|all] [--dry-run]", "Named valid, edge and error vectors for all nine EIP-2537 operations in the ethereum/tests JSON layout", func(args []string) error { return checkFailures(runEip2537SuiteMode(args)) }},
+		{"diff-geth", "[--op <ops>|all] [--mutations N] [--seed <n>] | --op <op> --input <hex>", "Differential test of the EIP-2537 operations against go-ethereum's precompiles", func(args []string) error { return checkFailures(runDiffGethMode(args)) }},
+		{"diff-blst", "[--op serialization,add,mul,msm,pairing,hash-to-curve|all] [--count N] [--seed <n>]", "Differential test of every operation against blst (build with -tags blst)", func(args []string) error { return checkFailures(runDiffBlstMode(args)) }},
+		{"subgroup-check", "--point <hex> [--format auto|g1c|g2c|g1u|g2u|g1e|g2e]", "Prime-order subgroup membership (two tests) and the order of a curve point", func(args []string) error { return checkFailures(runSubgroupCheckMode(args)) }},
+		{"subgroup-vectors", "[--group g1|g2|both] [--seed <hex>] [--out <file.json>]", "On-curve points outside the subgroup for negative tests of subgroup validation", func(args []string) error { return checkFailures(runSubgroupVectorsMode(args)) }},
+		{"g2-torsion", "[--order all|<m>,small,p448,r*<m>] [--seed <hex>] [--out <file.json>]", "G2 points of exact small or large torsion order, on the curve but outside the subgroup", func(args []string) error { return checkFailures(runG2TorsionMode(args)) }},
+		{"sample-curve-point", "[--group g1|g2|both] [--count N] [--clear] [--seed <hex>]", "Random curve points, usually outside the subgroup, and their cofactor-cleared counterparts", func(args []string) error { return checkFailures(runSampleCurvePointMode(args)) }},
+		{"decode", "--point <hex> [--format auto|g1c|g2c|g1u|g2u|g1e|g2e]", "Flags, coordinates, curve/subgroup/infinity status of any point encoding", runDecodeMode},
+		{"g2-coords", "--point <hex>", "G2 coordinates with gnark and Ethereum orderings", runG2CoordsMode},
+		{"accumulator", "--elements <x1,x2,...> [--member <y>] [--trapdoor <s> | --seed <hex>]", "Bilinear accumulator, membership witness and the pairing-check input that verifies it", runAccumulatorMode},
+		{"build-pairing-input", "--g1 <hex,...> --g2 <hex,...> | --g1-file <file> --g2-file <file> [--quiet]", "Pairing input (384 bytes per pair) from compressed points", runBuildPairingInput},
+		{"field", "--a <hex> [--b <hex>] [--op add|sub|mul|inv|sqrt|legendre|sgn0|largest|all] [--field auto|fp|fp2] [--order c1c0|c0c1]", "Fp and Fp2 arithmetic, square roots, sgn0 and the sort flag on hex elements", runFieldMode},
+		{"poly-eval", "--coeffs <c0,c1,...> --z <value>", "Evaluate a polynomial over Fr", poly("poly-eval")},
+		{"poly-interpolate", "--xs <x0,x1,...> --ys <y0,y1,...>", "Lagrange interpolation over Fr", poly("poly-interpolate")},
+		{"poly-divide", "--coeffs <c0,c1,...> --z <value>", "Divide a polynomial by (X - z) over Fr", poly("poly-divide")},
+		{"pairing", "--input <hex> [--input-format ethereum|compressed|auto] [--profile <p>] [--empty error|identity] [--chain <c>] [--naive]", "Pairing check (384 bytes per pair); result byte 1 if the product is the identity", runPairingCommand},
+		{"pairing-gt", "--input <hex> [--input-format ethereum|compressed|auto] [--gt-format <formats>] [--gt-order tower|reverse]", "The 576-byte pairing product (GT element) instead of the identity indicator, as Neo's Bls12381Pairing returns", runPairingGTMode},
+		{"miller-loop", "--input <hex> [--input-format ethereum|compressed|auto] [--gt-format <formats>]", "The Miller loop of a pairing input (Fp12, before the final exponentiation), per pair and checked against the pairing", runMillerLoopMode},
+		{"final-exp", "--a <hex> [--format neo|gnark] [--gt-format <formats>]", "Final exponentiation of an Fp12 element, with its easy part", runFinalExpMode},
+		{"gtmul", "--a <hex> --b <hex> [--format auto|gnark|neo] [--gt-format <formats>]", "Product of two GT elements (Neo: Bls12381Add on Gt)", runGTMulMode},
+		{"gtexp", "--a <hex> --scalar <k> [--scalar-format auto|dec|hex|le-hex] [--format auto|gnark|neo] [--gt-format <formats>]", "GT element raised to a scalar (Neo: Bls12381Mul on Gt)", runGTExpMode},
+		{"gtinv", "--a <hex> [--format auto|gnark|neo] [--gt-format <formats>]", "Inverse of a GT element", runGTInvMode},
+		{"pairing-random", "[--seed <hex>] [--gt-format <formats>] [--gt-order tower|reverse]", "Random pairing scenarios, including e(g1, g2) * e(-g1, g2) = 1", runPairingRandomCommand},
+		{"ethereum-test", "", "Verify the Ethereum MultiExp test vectors", runEthereumTestCommand},
+		{"compression-check", "[--count N]", "Manual compression flags vs gnark Bytes() for edge cases and random points", func(args []string) error { return checkFailures(runCompressionCheckMode(args)) }},
+		{"fuzz-serialization", "[--duration 30s] [--oracle gnark|<command>] [--kinds g1c,g2c,g1e,g2e] [--corpus <file>] [--failures <file.jsonl>] [--workers N] [--oracle-workers N] [--queue N]", "Deserializers vs an oracle", func(args []string) error { return checkFailures(runFuzzSerializationMode(args)) }},
+		{"replay-divergences", "--file <failures.jsonl> [--oracle gnark|<command>|none]", "Re-run recorded fuzz divergences as a regression suite", func(args []string) error { return checkFailures(runReplayDivergencesMode(args)) }},
+		{"help", "[command]", "Print the usage text, or the flags of one command", runHelpCommand},
+	}
+}
+
+// findCommand returns the command with the given name
+func findCommand(name string) (cliCommand, bool) {
+	for _, c := range cliCommands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return cliCommand{}, false
+}
+
+// resolveCommand maps a command line to a command name and its arguments. Besides
+// "<command> [flags]" it accepts the legacy forms "[max_scalars] [--use-g2]" (random)
+// and "--preset <name>" (preset); anything else starting with "-" is not a command.
+func resolveCommand(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "random", nil
+	}
+	first := args[0]
+	switch {
+	case first == "-h" || first == "-help" || first == "--help":
+		return "help", args[1:]
+	case first == "--preset" || strings.HasPrefix(first, "--preset="):
+		return "preset", args
+	case first == "--use-g2":
+		return "random", args
+	}
+	if _, err := strconv.Atoi(first); err == nil {
+		return "random", args
+	}
+	return first, args[1:]
+}
+
+// parseGlobalFlags strips the global flags, which go before the command, and returns the
+// remaining arguments. The flags are listed under "Global flags" in the usage text.
+func parseGlobalFlags(args []string) ([]string, error) {
+	parsingModeSet, levelSet := false, false
+	for len(args) > 0 {
+		var value string
+		switch {
+		case args[0] == "--msm-cross-check" || args[0] == "-msm-cross-check":
+			bls12381vec.SetCrossCheckMSM(true)
+			args = args[1:]
+			continue
+		case args[0] == "--strict" || args[0] == "-strict" || args[0] == "--lenient" || args[0] == "-lenient":
+			lenient := strings.TrimLeft(args[0], "-") == "lenient"
+			if parsingModeSet && lenient != serialization.LenientG2Parsing() {
+				return nil, fmt.Errorf("--strict and --lenient are mutually exclusive")
+			}
+			parsingModeSet = true
+			serialization.SetLenientG2Parsing(lenient)
+			args = args[1:]
+			continue
+		case args[0] == "--quiet" || args[0] == "-quiet" || args[0] == "--verbose" || args[0] == "-verbose" || args[0] == "--debug" || args[0] == "-debug":
+			l := logging.Debug
+			switch strings.TrimLeft(args[0], "-") {
+			case "quiet":
+				l = logging.Quiet
+			case "verbose":
+				l = logging.Verbose
+			}
+			if levelSet && l != logging.CurrentLevel() {
+				return nil, fmt.Errorf("--quiet, --verbose and --debug are mutually exclusive")
+			}
+			levelSet = true
+			logging.SetLevel(l)
+			args = args[1:]
+			continue
+		case args[0] == "--hex-style" || args[0] == "-hex-style" || strings.HasPrefix(args[0], "--hex-style=") || strings.HasPrefix(args[0], "-hex-style="):
+			if i := strings.Index(args[0], "="); i >= 0 {
+				value, args = args[0][i+1:], args[1:]
+			} else if len(args) < 2 {
+				return nil, fmt.Errorf("--hex-style needs a value (e.g. upper,0x,space)")
+			} else {
+				value, args = args[1], args[2:]
+			}
+			style, err := parseHexStyle(value)
+			if err != nil {
+				return nil, err
+			}
+			outputHexStyle = style
+			continue
+		case args[0] == "--sink" || args[0] == "-sink" || strings.HasPrefix(args[0], "--sink=") || strings.HasPrefix(args[0], "-sink="):
+			if i := strings.Index(args[0], "="); i >= 0 {
+				value, args = args[0][i+1:], args[1:]
+			} else if len(args) < 2 {
+				return nil, fmt.Errorf("--sink needs a value (dir, stdout, file:<path.jsonl> or an http(s):// URL)")
+			} else {
+				value, args = args[1], args[2:]
+			}
+			sink, err := parseSink(value)
+			if err != nil {
+				return nil, err
+			}
+			outputSink = sink
+			continue
+		case args[0] == "--format" || args[0] == "-format":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("--format needs a value (text or json)")
+			}
+			value, args = args[1], args[2:]
+		case strings.HasPrefix(args[0], "--format=") || strings.HasPrefix(args[0], "-format="):
+			value, args = args[0][strings.Index(args[0], "=")+1:], args[1:]
+		default:
+			return args, nil
+		}
+		switch value {
+		case "text":
+			report = nil
+		case "json":
+			report = &jsonReport{}
+		default:
+			return nil, fmt.Errorf("invalid --format '%s' (valid: text, json)", value)
+		}
+	}
+	return args, nil
+}
+
+// runCLI runs the command named by args and returns the process exit code
+func runCLI(args []string) int {
+	name, rest := resolveCommand(args)
+	cmd, ok := findCommand(name)
+	if !ok {
+		msg := fmt.Sprintf("unknown command '%s'", name)
+		if s := suggestCommand(name); s != "" {
+			msg += fmt.Sprintf(" (did you mean '%s'?)", s)
+		}
+		reportError(errors.New(msg))
+		fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
+		fmt.Fprintf(os.Stderr, "Run 'go run . help' for the list of commands.\n")
+		return 2
+	}
+
+	if report != nil {
+		report.Args = rest
+	}
+	logging.Verbosef("Running %s %s", cmd.name, strings.Join(rest, " "))
+	start := time.Now()
+	err := cmd.run(rest)
+	logging.Verbosef("%s finished in %v", cmd.name, time.Since(start).Round(time.Millisecond))
+	if cerr := outputSink.close(); cerr != nil && err == nil {
+		err = fmt.Errorf("--sink: %v", cerr)
+	}
+	reportFlags(cliFlags)
+	if err != nil && !errors.Is(err, flag.ErrHelp) && !errors.Is(err, errChecksFailed) {
+		reportError(err)
+	}
+	var usage usageError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		printCommandHelp(cmd, cliFlags)
+		return 0
+	case errors.Is(err, errChecksFailed):
+		// The command already printed what failed
+		return 1
+	case errors.As(err, &usage):
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", cmd.name, usage.error)
+		fmt.Fprintf(os.Stderr, "Run 'go run . %s --help' for usage.\n", cmd.name)
+		return 2
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+}
+
+// cliFlags is the flag set of the running command, for its --help output
+var cliFlags *flag.FlagSet
+
+// newFlagSet returns the flag set a command parses its arguments with. The flag package
+// prints nothing itself: errors and --help are reported by runCLI.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	cliFlags = fs
+	return fs
+}
+
+// parseFlags parses args and rejects positional arguments, which flag.Parse would
+// otherwise leave behind silently (together with every flag after the first one).
+// A hex --input is then read from --input-file or stdin when asked to.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected argument '%s'", fs.Arg(0))
+	}
+	return resolveInputFile(fs)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// usageError is an error in the command line rather than in the input values; runCLI
+// follows it with a pointer to the command's --help
+type usageError struct{ error }
+
+func (e usageError) Unwrap() error { return e.error }
+
+func usageErrorf(format string, a ...any) error {
+	return usageError{fmt.Errorf(format, a...)}
+}
+
+// errChecksFailed is returned by self-checks after they printed their failures
+var errChecksFailed = errors.New("checks failed")
+
+// checkFailures adapts a mode returning a failure count to a command error
+func checkFailures(failed int, err error) error {
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errChecksFailed
+	}
+	return nil
+}
+
+// printCommandHelp prints the synopsis, summary and flags of one command
+func printCommandHelp(cmd cliCommand, fs *flag.FlagSet) {
+	fmt.Fprintf(os.Stderr, "Usage: go run . %s %s\n\n", cmd.name, cmd.synopsis)
+	fmt.Fprintf(os.Stderr, "%s\n", cmd.summary)
+	hasFlags := false
+	if fs != nil {
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	}
+	if hasFlags {
+		fmt.Fprintf(os.Stderr, "\nFlags:\n")
+		fs.SetOutput(os.Stderr)
+		fs.PrintDefaults()
+	}
+}
+
+// runHelpCommand prints the full usage text, or the help of one command by running it
+// with --help so the flag list comes from its own flag set
+func runHelpCommand(args []string) error {
+	if len(args) == 0 {
+		printUsage()
+		return nil
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok || cmd.name == "help" {
+		return fmt.Errorf("unknown command '%s' (run 'go run . help' for the list of commands)", args[0])
+	}
+	cliFlags = nil
+	if err := cmd.run([]string{"--help"}); !errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	printCommandHelp(cmd, cliFlags)
+	return nil
+}
+
+// runPresetCommand accepts "preset <name> [flags]" as well as the legacy "--preset <name>"
+func runPresetCommand(args []string) error {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = append([]string{"--preset", args[0]}, args[1:]...)
+	}
+	return runPresetMode(args)
+}
+
+func runG2AddRandomCommand(args []string) error {
+	fs := newFlagSet("g2add-random")
+	seed := registerSeedFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := applySeed(*seed); err != nil {
+		return err
+	}
+	runG2AddRandomMode()
+	return nil
+}
+
+func runEthereumTestCommand(args []string) error {
+	if err := parseFlags(newFlagSet("ethereum-test"), args); err != nil {
+		return err
+	}
+	runEthereumVectorTest()
+	return nil
+}
+
+// suggestCommand returns the command closest to a mistyped name, if any is close
+func suggestCommand(name string) string {
+	best, bestDist := "", 3
+	for _, c := range cliCommands() {
+		if d := editDistance(name, c.name); d < bestDist {
+			best, bestDist = c.name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The tool builds compressed encodings by hand (Marshal() + flag bits) so the flag logic
+// mirrors Neo's ToCompressed(). gnark's Bytes() derives the same encoding independently;
+// any disagreement means one of the flag conventions is wrong and the fixture must not
+// be written.
+
+// compressionFlags names the three flag bits of the first byte
+var compressionFlags = []struct {
+	mask byte
+	name string
+}{
+	{0x80, "compression"},
+	{0x40, "infinity"},
+	{0x20, "sort"},
+}
+
+// describeCompressionDiff lists the flag bits and x bytes on which two compressed
+// encodings of the same point differ
+func describeCompressionDiff(manual, gnark []byte) string {
+	var diffs []string
+	for _, f := range compressionFlags {
+		m, g := manual[0]&f.mask != 0, gnark[0]&f.mask != 0
+		if m != g {
+			diffs = append(diffs, fmt.Sprintf("%s flag manual=%v gnark=%v", f.name, m, g))
+		}
+	}
+	mx, gx := append([]byte{manual[0] & 0x1F}, manual[1:]...), append([]byte{gnark[0] & 0x1F}, gnark[1:]...)
+	if !bytes.Equal(mx, gx) {
+		for i := range mx {
+			if mx[i] != gx[i] {
+				diffs = append(diffs, fmt.Sprintf("x bytes differ from byte %d", i))
+				break
+			}
+		}
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// checkG1Compression returns the manual compressed encoding of p, or an error when it
+// differs from gnark's Bytes()
+func checkG1Compression(p bls.G1Affine) ([]byte, error) {
+	manual := serialization.ConvertG1AffineToCompressed(p)
+	gnark := p.Bytes()
+	if !bytes.Equal(manual, gnark[:]) {
+		return manual, fmt.Errorf("G1 compression mismatch (%s): manual %x, gnark %x", describeCompressionDiff(manual, gnark[:]), manual, gnark[:])
+	}
+	return manual, nil
+}
+
+// checkG2Compression returns the manual compressed encoding of p, or an error when it
+// differs from gnark's Bytes()
+func checkG2Compression(p bls.G2Affine) ([]byte, error) {
+	manual := serialization.ConvertG2AffineToCompressed(p)
+	gnark := p.Bytes()
+	if !bytes.Equal(manual, gnark[:]) {
+		return manual, fmt.Errorf("G2 compression mismatch (%s): manual %x, gnark %x", describeCompressionDiff(manual, gnark[:]), manual, gnark[:])
+	}
+	return manual, nil
+}
+
+// compressionCheckPoints returns the edge cases (infinity, ±generator, both sort-flag
+// values) followed by count random points
+func compressionCheckPoints(count int) ([]string, []bls.G1Affine, []bls.G2Affine, error) {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var g1Neg bls.G1Affine
+	var g2Neg bls.G2Affine
+	g1Neg.Neg(&g1Gen)
+	g2Neg.Neg(&g2Gen)
+	labels := []string{"infinity", "generator", "-generator"}
+	g1 := []bls.G1Affine{{}, g1Gen, g1Neg}
+	g2 := []bls.G2Affine{{}, g2Gen, g2Neg}
+	for i := 0; i < count; i++ {
+		k, err := randomScalar()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var p bls.G1Affine
+		var q bls.G2Affine
+		p.ScalarMultiplication(&g1Gen, k)
+		q.ScalarMultiplication(&g2Gen, k)
+		labels = append(labels, fmt.Sprintf("random[%d]", i))
+		g1 = append(g1, p)
+		g2 = append(g2, q)
+	}
+	return labels, g1, g2, nil
+}
+
+// randomScalar draws a uniform scalar in [0, r-1]
+func randomScalar() (*big.Int, error) {
+	var s fr.Element
+	if _, err := s.SetRandom(); err != nil {
+		return nil, fmt.Errorf("failed to generate random scalar: %v", err)
+	}
+	return s.BigInt(new(big.Int)), nil
+}
+
+// runCompressionCheckMode compares the manual and gnark compressed encodings for the
+// edge cases and count random points in both groups. Returns the number of mismatches.
+func runCompressionCheckMode(args []string) (int, error) {
+	fs := newFlagSet("compression-check")
+	count := fs.Int("count", 100, "Random points per group (in addition to the edge cases)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *count < 0 {
+		return 0, fmt.Errorf("--count must be non-negative")
+	}
+	labels, g1, g2, err := compressionCheckPoints(*count)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Println("=== Compression Cross-Check (manual flags vs gnark Bytes()) ===")
+	mismatches := 0
+	for i, label := range labels {
+		if _, err := checkG1Compression(g1[i]); err != nil {
+			mismatches++
+			fmt.Printf("❌ G1 %s: %v\n", label, err)
+		}
+		if _, err := checkG2Compression(g2[i]); err != nil {
+			mismatches++
+			fmt.Printf("❌ G2 %s: %v\n", label, err)
+		}
+	}
+	fmt.Printf("\nChecked %d G1 and %d G2 points (%d edge cases + %d random per group)\n", len(g1), len(g2), len(labels)-*count, *count)
+	if mismatches > 0 {
+		fmt.Printf("%d mismatch(es) FOUND\n", mismatches)
+	} else {
+		fmt.Println("✅ All compressed encodings match gnark")
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// pointFormats are the encodings convert reads and writes. Compressed is the Neo/ZCash
+// format, uncompressed is gnark's Marshal() (the ZCash uncompressed layout) and ethereum
+// is EIP-2537 with 64-byte padded coordinates.
+var pointFormats = []string{"compressed", "uncompressed", "ethereum"}
+
+// pointFormatKinds maps a format and group onto the validate-file kind names
+var pointFormatKinds = map[string][2]string{
+	"compressed":   {"g1c", "g2c"},
+	"uncompressed": {"g1u", "g2u"},
+	"ethereum":     {"g1e", "g2e"},
+}
+
+// decodePointKind decodes one encoding of either group. Without the subgroup check any
+// point on the curve is accepted, so out-of-subgroup test points can be converted too.
+func decodePointKind(kind string, data []byte, subgroupCheck bool) (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+	if info, ok := pointKindInfo[kind]; !ok || len(data) != info.Length {
+		return g1, g2, fmt.Errorf("%w: %s needs %d bytes, got %d", serialization.ErrInvalidLength, kind, pointKindInfo[kind].Length, len(data))
+	}
+	switch kind {
+	case "g1c":
+		if subgroupCheck {
+			g1, err = serialization.DecodeCompressedG1Point(data)
+		} else {
+			err = bls.NewDecoder(bytes.NewReader(data), bls.NoSubgroupChecks()).Decode(&g1)
+		}
+	case "g2c":
+		if subgroupCheck {
+			g2, err = serialization.DecodeCompressedG2Point(data)
+		} else {
+			err = bls.NewDecoder(bytes.NewReader(data), bls.NoSubgroupChecks()).Decode(&g2)
+		}
+	case "g1u", "g2u":
+		eth, infinity, uerr := uncompressedToEIP2537(data, kind == "g2u")
+		if uerr != nil || infinity {
+			return g1, g2, uerr
+		}
+		if kind == "g1u" {
+			g1, err = serialization.DecodeEIP2537G1Point(eth, subgroupCheck)
+		} else {
+			g2, err = serialization.DecodeEIP2537G2Point(eth, subgroupCheck)
+		}
+	case "g1e":
+		g1, err = serialization.DecodeEIP2537G1Point(data, subgroupCheck)
+	case "g2e":
+		g2, err = serialization.DecodeEIP2537G2Point(data, subgroupCheck)
+	}
+	return g1, g2, err
+}
+
+// encodePointFormat writes a point in one of pointFormats
+func encodePointFormat(format string, useG2 bool, g1 bls.G1Affine, g2 bls.G2Affine) []byte {
+	switch {
+	case format == "compressed" && useG2:
+		return serialization.ConvertG2AffineToCompressed(g2)
+	case format == "compressed":
+		return serialization.ConvertG1AffineToCompressed(g1)
+	case format == "uncompressed" && useG2:
+		return g2.Marshal()
+	case format == "uncompressed":
+		return g1.Marshal()
+	case useG2:
+		return serialization.EncodeEthereumG2Point(g2)
+	}
+	return serialization.EncodeEthereumG1Point(g1)
+}
+
+// runConvertMode re-encodes a G1 or G2 point between the compressed, uncompressed and
+// Ethereum formats
+func runConvertMode(args []string) error {
+	fs := newFlagSet("convert")
+	pointHex := fs.String("point", "", "Point hex in the --from format")
+	from := fs.String("from", "auto", "Input format: auto (by length and flags), "+strings.Join(pointFormats, ", "))
+	to := fs.String("to", "all", "Output formats: "+strings.Join(pointFormats, ", ")+" or all (comma-separated)")
+	group := fs.String("group", "auto", "Group of the point: auto (by length), g1 or g2")
+	noSubgroupCheck := fs.Bool("no-subgroup-check", false, "Accept points on the curve outside the prime-order subgroup")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *pointHex == "" {
+		return usageErrorf("--point is required")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*pointHex), "0x"))
+	if err != nil {
+		return usageErrorf("--point: invalid hex: %v", err)
+	}
+	if *group != "auto" && *group != "g1" && *group != "g2" {
+		return usageErrorf("invalid --group '%s' (valid: auto, g1, g2)", *group)
+	}
+	targets, err := parsePointFormats(*to)
+	if err != nil {
+		return usageError{err}
+	}
+
+	kind, err := convertInputKind(data, *from, *group)
+	if err != nil {
+		return err
+	}
+	useG2 := kind[1] == '2'
+	g1, g2, err := decodePointKind(kind, data, !*noSubgroupCheck)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", pointKindInfo[kind].Description, err)
+	}
+
+	fmt.Println("=== Convert ===")
+	fmt.Printf("Input: %s\n", pointKindInfo[kind].Description)
+	if useG2 {
+		fmt.Printf("Infinity: %v, in subgroup: %v\n", g2.IsInfinity(), g2.IsInSubGroup())
+	} else {
+		fmt.Printf("Infinity: %v, in subgroup: %v\n", g1.IsInfinity(), g1.IsInSubGroup())
+	}
+	var last string
+	for _, t := range targets {
+		out := hex.EncodeToString(encodePointFormat(t, useG2, g1, g2))
+		fmt.Printf("%s (%d bytes): %s\n", t, len(out)/2, out)
+		reportOutput(t, out)
+		last = out
+	}
+
+	recordVerdictInput(hex.EncodeToString(data))
+	if len(targets) == 1 {
+		recordVerdictResult(last)
+	}
+	reportInput("point", hex.EncodeToString(data))
+	reportOutput("group", groupPrefix(useG2))
+	return nil
+}
+
+// convertInputKind resolves --from and --group into the kind of the input encoding
+func convertInputKind(data []byte, from, group string) (string, error) {
+	if from == "auto" {
+		kind, ok := detectPointKind(data)
+		if group != "auto" && len(data) == 96 {
+			// 96 bytes is compressed G2 or uncompressed G1; --group decides
+			kind, ok = map[string]string{"g1": "g1u", "g2": "g2c"}[group], true
+		}
+		if !ok {
+			return "", fmt.Errorf("%d bytes matches no encoding (48, 96, 128, 192, 256)", len(data))
+		}
+		if group != "auto" && kind[:2] != group {
+			return "", fmt.Errorf("%d bytes is a %s encoding, not %s", len(data), strings.ToUpper(kind[:2]), strings.ToUpper(group))
+		}
+		return kind, nil
+	}
+	kinds, ok := pointFormatKinds[from]
+	if !ok {
+		return "", usageErrorf("invalid --from '%s' (valid: auto, %s)", from, strings.Join(pointFormats, ", "))
+	}
+	switch {
+	case group == "g1":
+		return kinds[0], nil
+	case group == "g2":
+		return kinds[1], nil
+	case len(data) == pointKindInfo[kinds[0]].Length:
+		return kinds[0], nil
+	case len(data) == pointKindInfo[kinds[1]].Length:
+		return kinds[1], nil
+	}
+	return "", fmt.Errorf("%s G1 is %d bytes and G2 is %d bytes, got %d", from,
+		pointKindInfo[kinds[0]].Length, pointKindInfo[kinds[1]].Length, len(data))
+}
+
+// parsePointFormats parses a comma-separated --to ("all" expands to every format)
+func parsePointFormats(value string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range strings.Split(value, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		names := []string{f}
+		if f == "all" {
+			names = pointFormats
+		} else if _, ok := pointFormatKinds[f]; !ok {
+			return nil, fmt.Errorf("invalid --to '%s' (valid: %s, all)", f, strings.Join(pointFormats, ", "))
+		}
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+			}
+		}
+	}
+	return out, nil
+}
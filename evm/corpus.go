@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// With --out-dir, random mode writes a corpus instead of printing the vectors: one JSON
+// file per vector and an index.json listing them. Files hold no timestamps or paths
+// outside the corpus, so a seeded corpus regenerates byte-for-byte and can be versioned
+// and diffed in git. A vector file is a single jsonFixture document, so verify-file reads
+// it as is.
+
+// corpusVector is one <out-dir>/<name>.json file
+type corpusVector struct {
+	jsonFixture
+	Metadata corpusMetadata `json:"metadata"`
+}
+
+// corpusMetadata records how a vector was generated
+type corpusMetadata struct {
+	Generator   string `json:"generator"`
+	Index       int    `json:"index"` // 1-based in a batch, 0 for a single vector
+	MaxScalars  int    `json:"max_scalars"`
+	ScalarRange string `json:"scalar_range"`
+	Pairs       int    `json:"pairs"`
+	Seed        string `json:"seed,omitempty"` // unseeded vectors cannot be regenerated
+}
+
+// corpusIndex is <out-dir>/index.json
+type corpusIndex struct {
+	Generator string             `json:"generator"`
+	Seed      string             `json:"seed,omitempty"`
+	Count     int                `json:"count"`
+	Vectors   []corpusIndexEntry `json:"vectors"`
+}
+
+type corpusIndexEntry struct {
+	Index     int    `json:"index"`
+	Name      string `json:"name"`
+	File      string `json:"file"` // relative to the corpus directory
+	Group     string `json:"group"`
+	Pairs     int    `json:"pairs"`
+	Expected  string `json:"expected"`
+	Reproduce string `json:"reproduce,omitempty"`
+}
+
+// writeCorpusFile writes one corpus file through the --sink
+func writeCorpusFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := outputSink.write(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// writeRandomCorpus generates count random vectors into dir, one file each, followed by
+// index.json. Vectors are generated exactly as without --out-dir (same names, same seeded
+// stream, same Reproduce commands); only their output goes to the files.
+func writeRandomCorpus(dir string, count, maxScalars int, useG2 bool, scalarRange scalarRange, seed string, gt *gtFormatOptions, emit, emitDir string) error {
+	index := corpusIndex{Generator: "random", Seed: seed, Count: count, Vectors: []corpusIndexEntry{}}
+	fmt.Printf("=== Writing Corpus: %s (%d vectors) ===\n", dir, count)
+	for i := 1; i <= count; i++ {
+		// A single vector keeps the name, index and emit directory it has without --out-dir
+		name, n := fmt.Sprintf("random-%04d", i), i
+		fixtureEmitDir := filepath.Join(emitDir, name)
+		if count == 1 {
+			name, n, fixtureEmitDir = "random", 0, emitDir
+		}
+		var f multiExpFixture
+		err := quietly(func() (err error) {
+			f, err = runRandomMode(name, maxScalars, useG2, scalarRange, gt)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		reproduce := ""
+		if seed != "" {
+			reproduce = randomReproduce(maxScalars, useG2, scalarRange, seed, n)
+		}
+		jf := newJSONFixture(f)
+		jf.Reproduce = reproduce
+		file := name + ".json"
+		vector := corpusVector{jsonFixture: jf, Metadata: corpusMetadata{Generator: "random", Index: n, MaxScalars: maxScalars,
+			ScalarRange: scalarRange.flagValue(), Pairs: len(f.Points), Seed: seed}}
+		path := filepath.Join(dir, file)
+		if err := writeCorpusFile(path, vector); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s: %s, %3d pairs, expected %x\n", outputSink.describe(path), f.groupName(), len(f.Points), f.Expected)
+		recordQuietResult(f.Name, f.groupName(), strconv.Itoa(len(f.Points)), hex.EncodeToString(f.Expected))
+		index.Vectors = append(index.Vectors, corpusIndexEntry{Index: n, Name: f.Name, File: file, Group: f.groupName(),
+			Pairs: len(f.Points), Expected: hex.EncodeToString(f.Expected), Reproduce: reproduce})
+		if emit != "" {
+			if err := emitFixtures(f, emit, fixtureEmitDir); err != nil {
+				return err
+			}
+		}
+	}
+	path := filepath.Join(dir, "index.json")
+	if err := writeCorpusFile(path, index); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote index: %s (%d vectors)\n", outputSink.describe(path), len(index.Vectors))
+	reportOutput("corpus_index", path)
+	reportOutput("vectors", len(index.Vectors))
+	return nil
+}
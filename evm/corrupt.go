@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// Nearby corruption: small, targeted edits of a valid point (x+1, the other y root,
+// single-bit flips) instead of random mutations. Every variant is labeled with the verdict
+// of the strict decoder for its encoding, so the labels are what a conforming
+// implementation must return.
+
+// pointLayout describes where the 48-byte field elements of an encoding live
+type pointLayout struct {
+	Group      string
+	Encoding   string // "compressed" or "ethereum"
+	Fields     []string
+	Offsets    []int // byte offset of each 48-byte field element
+	YFields    []int // indices into Fields of the y coordinate (ethereum only)
+	Compressed bool
+}
+
+var pointLayouts = map[int]pointLayout{
+	48:  {Group: "G1", Encoding: "compressed", Fields: []string{"x"}, Offsets: []int{0}, Compressed: true},
+	96:  {Group: "G2", Encoding: "compressed", Fields: []string{"x.C1", "x.C0"}, Offsets: []int{0, 48}, Compressed: true},
+	128: {Group: "G1", Encoding: "ethereum", Fields: []string{"x", "y"}, Offsets: []int{16, 80}, YFields: []int{1}},
+	256: {Group: "G2", Encoding: "ethereum", Fields: []string{"x.C0", "x.C1", "y.C0", "y.C1"},
+		Offsets: []int{16, 80, 144, 208}, YFields: []int{2, 3}},
+}
+
+// corruptionBitPositions are the bits flipped by default: limb and byte boundaries and
+// the top bits of the 381-bit value. --all-bits flips every bit.
+var corruptionBitPositions = []int{0, 1, 7, 8, 31, 32, 63, 64, 127, 128, 191, 192, 255, 256, 319, 320, 378, 379, 380}
+
+const flagMask = 0xe0 // compression, infinity and sort flags in the first byte
+
+type corruptionVariant struct {
+	Name   string
+	Data   []byte
+	Accept bool
+	Code   errorCode // taxonomy code of the rejection
+	Reason string
+}
+
+// strictVerdict decodes data with the strict decoder for its layout
+func strictVerdict(layout pointLayout, data []byte) (bool, errorCode, string) {
+	var err error
+	switch {
+	case layout.Compressed && layout.Group == "G1":
+		_, err = serialization.DecodeCompressedG1Point(data)
+	case layout.Compressed:
+		_, err = serialization.DecodeCompressedG2Point(data)
+	case layout.Group == "G1":
+		_, err = serialization.DecodeEIP2537G1Point(data, true)
+	default:
+		_, err = serialization.DecodeEIP2537G2Point(data, true)
+	}
+	if err != nil {
+		code, _ := classifyError(err)
+		return false, code, err.Error()
+	}
+	return true, "", ""
+}
+
+// op is the fixture operation name of the layout's decoder
+func (l pointLayout) op() string {
+	if l.Compressed {
+		return "deserialize-" + strings.ToLower(l.Group)
+	}
+	return "eip2537-" + strings.ToLower(l.Group)
+}
+
+// fieldValue reads the 381-bit value of field element i (flags masked off for compressed x)
+func (l pointLayout) fieldValue(data []byte, i int) *big.Int {
+	b := append([]byte(nil), data[l.Offsets[i]:l.Offsets[i]+48]...)
+	if l.Compressed && i == 0 {
+		b[0] &^= flagMask
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// withFieldValue returns a copy of data with field element i replaced by v, keeping flags.
+// Values that do not fit in 48 bytes (minus the flag bits) are truncated to the low bits.
+func (l pointLayout) withFieldValue(data []byte, i int, v *big.Int) []byte {
+	out := append([]byte(nil), data...)
+	b := make([]byte, 48)
+	new(big.Int).And(v, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 384), big.NewInt(1))).FillBytes(b)
+	off := l.Offsets[i]
+	if l.Compressed && i == 0 {
+		b[0] = b[0]&^flagMask | data[0]&flagMask
+	}
+	copy(out[off:off+48], b)
+	return out
+}
+
+// nearbyCorruptions builds every variant of a valid point encoding
+func nearbyCorruptions(layout pointLayout, data []byte, allBits bool) []corruptionVariant {
+	var variants []corruptionVariant
+	add := func(name string, v []byte) {
+		accept, code, reason := strictVerdict(layout, v)
+		variants = append(variants, corruptionVariant{Name: name, Data: v, Accept: accept, Code: code, Reason: reason})
+	}
+	one := big.NewInt(1)
+
+	// x incremented by 1 (the real part for G2), y and flags unchanged
+	xi := 0
+	if layout.Group == "G2" && layout.Compressed {
+		xi = 1 // x.C0 is the second field element of a compressed G2 point
+	}
+	add(layout.Fields[xi]+"+1", layout.withFieldValue(data, xi, new(big.Int).Add(layout.fieldValue(data, xi), one)))
+
+	// The other y root
+	if layout.Compressed {
+		flipped := append([]byte(nil), data...)
+		flipped[0] ^= 0x20
+		add("sort flag flipped (other y root)", flipped)
+	} else {
+		// y -> -y without touching anything else; -P is a valid point, so this must be accepted
+		negated := data
+		p := fp.Modulus()
+		for _, yi := range layout.YFields {
+			y := layout.fieldValue(data, yi)
+			if y.Sign() != 0 {
+				y = new(big.Int).Sub(p, y)
+			}
+			negated = layout.withFieldValue(negated, yi, y)
+		}
+		add("y negated (other root)", negated)
+	}
+
+	// Single flag bit flips (compressed only)
+	if layout.Compressed {
+		for _, f := range []struct {
+			name string
+			bit  byte
+		}{{"compression flag", 0x80}, {"infinity flag", 0x40}} {
+			v := append([]byte(nil), data...)
+			v[0] ^= f.bit
+			add(f.name+" flipped", v)
+		}
+	}
+
+	// Single-bit flips in each coordinate
+	bits := corruptionBitPositions
+	if allBits {
+		bits = make([]int, 381)
+		for i := range bits {
+			bits[i] = i
+		}
+	}
+	for i, field := range layout.Fields {
+		for _, b := range bits {
+			v := new(big.Int).Xor(layout.fieldValue(data, i), new(big.Int).Lsh(one, uint(b)))
+			add(fmt.Sprintf("%s bit %d flipped", field, b), layout.withFieldValue(data, i, v))
+		}
+		if !layout.Compressed {
+			// Lowest bit of the 16-byte zero padding in front of the field element
+			v := append([]byte(nil), data...)
+			v[layout.Offsets[i]-1] ^= 0x01
+			add(field+" padding bit flipped", v)
+		}
+	}
+	return variants
+}
+
+// runCorruptMode runs the corrupt-nearby mode
+func runCorruptMode(args []string) error {
+	fs := newFlagSet("corrupt-nearby")
+	pointHex := fs.String("point", "", "Valid point: compressed G1 (48 bytes) / G2 (96 bytes) or Ethereum G1 (128 bytes) / G2 (256 bytes)")
+	allBits := fs.Bool("all-bits", false, "Flip every bit of each coordinate (default: boundary bits only)")
+	outPath := fs.String("out", "", "Also write the variants: <file>.json as a self-describing fixture file, otherwise CSV (variant,expected,hex,reason,error_code)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *pointHex == "" {
+		return fmt.Errorf("--point is required")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*pointHex), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid point hex: %v", err)
+	}
+	layout, ok := pointLayouts[len(data)]
+	if !ok {
+		return fmt.Errorf("unsupported point length %d bytes (expected 48, 96, 128 or 256)", len(data))
+	}
+	if accept, _, reason := strictVerdict(layout, data); !accept {
+		return fmt.Errorf("base point is not valid: %s", reason)
+	}
+
+	variants := nearbyCorruptions(layout, data, *allBits)
+	accepted := 0
+	fmt.Printf("=== Nearby Corruption Variants (%s, %s) ===\n", layout.Group, layout.Encoding)
+	fmt.Printf("Base point: %x\n", data)
+	for _, v := range variants {
+		verdict := "reject"
+		if v.Accept {
+			verdict = "accept"
+			accepted++
+		}
+		fmt.Printf("%-34s %-6s %x", v.Name, verdict, v.Data)
+		if v.Reason != "" {
+			fmt.Printf("  # %s: %s", v.Code, v.Reason)
+			recordQuietResult(v.Name, verdict, string(v.Code))
+		} else {
+			recordQuietResult(v.Name, verdict)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Variants: %d (%d accept, %d reject)\n", len(variants), accepted, len(variants)-accepted)
+
+	if strings.HasSuffix(*outPath, ".json") {
+		fixtures := make([]negativeFixture, len(variants))
+		for i, v := range variants {
+			fixtures[i] = negativeFixture{Name: v.Name, Op: layout.op(), Input: hex.EncodeToString(v.Data), Expected: "accept"}
+			if !v.Accept {
+				fixtures[i].Expected, fixtures[i].ErrorCode, fixtures[i].Reason = "reject", v.Code, v.Reason
+			}
+		}
+		source := fmt.Sprintf("corrupt-nearby %s %s %x", layout.Group, layout.Encoding, data)
+		if err := writeNegativeFixtures(*outPath, source, fixtures); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", *outPath)
+	} else if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w := csv.NewWriter(f)
+		w.Write([]string{"variant", "expected", "hex", "reason", "error_code"})
+		for _, v := range variants {
+			verdict := "reject"
+			if v.Accept {
+				verdict = "accept"
+			}
+			w.Write([]string{v.Name, verdict, hex.EncodeToString(v.Data), v.Reason, string(v.Code)})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to write %s: %v", *outPath, err)
+		}
+		fmt.Printf("Wrote %s\n", *outPath)
+	}
+	return nil
+}
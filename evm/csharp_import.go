@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+
+	"evm/bls12381vec"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// csharpArrays is what the importer recovers from a C# snippet: the arrays this tool
+// prints for Bls12381MultiExpHelper.cs (or the whole helper file), plus the constants
+// written by the csharp fixture emitter when present. Empty fields were not found.
+type csharpArrays struct {
+	UseG2            *bool
+	Scalars          []*big.Int
+	G1Points         []string
+	G2Points         []string
+	G1Hex            string // legacy single-point fallback
+	G2Hex            string
+	EthereumInput    string
+	Expected         string
+	ExpectedEthereum string
+}
+
+var (
+	csharpScalarsRe    = regexp.MustCompile(`(?s)BigInteger\[\]\s*SCALARS\s*=\s*new\s*BigInteger\[\]\s*\{(.*?)\}`)
+	csharpUseG2Re      = regexp.MustCompile(`bool\s+USE_G2\s*=\s*(true|false)\s*;`)
+	csharpStringRe     = regexp.MustCompile(`"([0-9a-fA-Fx]*)"`)
+	csharpCommentRe    = regexp.MustCompile(`//[^\n]*`)
+	csharpBigParseRe   = regexp.MustCompile(`^BigInteger\.Parse\(\s*"([0-9]+)"\s*\)$`)
+	csharpStringArrRes = map[string]*regexp.Regexp{
+		"G1_POINTS": regexp.MustCompile(`(?s)string\[\]\s*G1_POINTS\s*=\s*(?:new\s*string\[\]\s*\{(.*?)\}|Array\.Empty<string>\(\))`),
+		"G2_POINTS": regexp.MustCompile(`(?s)string\[\]\s*G2_POINTS\s*=\s*(?:new\s*string\[\]\s*\{(.*?)\}|Array\.Empty<string>\(\))`),
+	}
+)
+
+// csharpStringConst finds `string NAME = "hex";` (static readonly or const)
+func csharpStringConst(text, name string) string {
+	re := regexp.MustCompile(`string\s+` + name + `\s*=\s*"([0-9a-fA-Fx]*)"\s*;`)
+	if m := re.FindStringSubmatch(text); m != nil {
+		return strings.TrimPrefix(m[1], "0x")
+	}
+	return ""
+}
+
+// parseCSharpArrays extracts the MultiExp configuration from C# source text
+func parseCSharpArrays(text string) (csharpArrays, error) {
+	var a csharpArrays
+	m := csharpScalarsRe.FindStringSubmatch(text)
+	if m == nil {
+		return a, fmt.Errorf("no SCALARS array found")
+	}
+	for i, item := range strings.Split(csharpCommentRe.ReplaceAllString(m[1], ""), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if p := csharpBigParseRe.FindStringSubmatch(item); p != nil {
+			item = p[1]
+		}
+		s, ok := new(big.Int).SetString(item, 10)
+		if !ok || s.Sign() < 0 {
+			return a, fmt.Errorf("SCALARS[%d]: invalid value '%s'", i, item)
+		}
+		a.Scalars = append(a.Scalars, s)
+	}
+	if len(a.Scalars) == 0 {
+		return a, fmt.Errorf("SCALARS array is empty")
+	}
+
+	if m := csharpUseG2Re.FindStringSubmatch(text); m != nil {
+		v := m[1] == "true"
+		a.UseG2 = &v
+	}
+	for name, re := range csharpStringArrRes {
+		m := re.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		var points []string
+		for _, s := range csharpStringRe.FindAllStringSubmatch(csharpCommentRe.ReplaceAllString(m[1], ""), -1) {
+			points = append(points, strings.TrimPrefix(s[1], "0x"))
+		}
+		if name == "G1_POINTS" {
+			a.G1Points = points
+		} else {
+			a.G2Points = points
+		}
+	}
+	a.G1Hex = csharpStringConst(text, "G1_HEX")
+	a.G2Hex = csharpStringConst(text, "G2_HEX")
+	a.EthereumInput = csharpStringConst(text, "ETHEREUM_INPUT")
+	a.Expected = csharpStringConst(text, "EXPECTED_RESULT")
+	a.ExpectedEthereum = csharpStringConst(text, "EXPECTED_RESULT_ETHEREUM")
+	return a, nil
+}
+
+// fixture rebuilds the MultiExp fixture the C# helper computes: scalar i is paired with
+// point i modulo the number of points, and an empty points array falls back to the
+// legacy single point (G1_HEX / G2_HEX)
+func (a csharpArrays) fixture(name string, useG2 bool) (multiExpFixture, error) {
+	points, fallback, label := a.G1Points, a.G1Hex, "G1"
+	if useG2 {
+		points, fallback, label = a.G2Points, a.G2Hex, "G2"
+	}
+	if len(points) == 0 {
+		if fallback == "" {
+			return multiExpFixture{}, fmt.Errorf("no %s_POINTS entries and no %s_HEX fallback found", label, label)
+		}
+		points = []string{fallback}
+	}
+
+	g1Points := make([]bls.G1Affine, len(a.Scalars))
+	g2Points := make([]bls.G2Affine, len(a.Scalars))
+	for i := range a.Scalars {
+		idx := i % len(points)
+		data, err := hex.DecodeString(points[idx])
+		if err != nil {
+			return multiExpFixture{}, fmt.Errorf("%s point %d: invalid hex: %v", label, idx, err)
+		}
+		if useG2 {
+			g2Points[i], err = bls12381vec.DecodeCompressedG2Point(data)
+		} else {
+			g1Points[i], err = bls12381vec.DecodeCompressedG1Point(data)
+		}
+		if err != nil {
+			return multiExpFixture{}, fmt.Errorf("%s point %d: %v", label, idx, err)
+		}
+	}
+	return newMultiExpFixture(name, g1Points, g2Points, a.Scalars, useG2)
+}
+
+// runImportCSharpMode recomputes the expected results of pasted C# arrays and reports
+// whether the checked-in values still hold. Returns the number of stale values.
+func runImportCSharpMode(args []string) (int, error) {
+	fs := newFlagSet("import-csharp")
+	file := fs.String("file", "", "C# snippet or Bls12381MultiExpHelper.cs to import (- for stdin)")
+	name := fs.String("name", "imported", "Fixture name for the report and --emit")
+	group := fs.String("group", "", "Point group g1 or g2 (default: USE_G2 from the snippet, else G1)")
+	expected := fs.String("expected", "", "Checked-in compressed expected result, if not in the snippet as EXPECTED_RESULT")
+	emit := fs.String("emit", "", "Re-emit the recomputed fixture for targets: csharp, go, go-bytes, rust, solidity, python, neo-alias, neo-debugger, gotest or all (comma-separated)")
+	registerCSharpTemplateFlag(fs)
+	emitDir := fs.String("emit-dir", "fixtures", "Output directory for --emit fixtures")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *file == "" {
+		return 0, fmt.Errorf("--file is required")
+	}
+	if *emit != "" {
+		if _, err := parseEmitTargets(*emit); err != nil {
+			return 0, err
+		}
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return 0, err
+	}
+	a, err := parseCSharpArrays(string(data))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", *file, err)
+	}
+	if *expected != "" {
+		a.Expected = strings.TrimPrefix(strings.TrimSpace(*expected), "0x")
+	}
+
+	useG2 := a.UseG2 != nil && *a.UseG2
+	switch *group {
+	case "":
+	case "g1":
+		useG2 = false
+	case "g2":
+		useG2 = true
+	default:
+		return 0, fmt.Errorf("invalid --group '%s' (valid: g1, g2)", *group)
+	}
+	f, err := a.fixture(*name, useG2)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Println("=== Imported C# Arrays ===")
+	fmt.Printf("Scalars: %d, G1_POINTS: %d, G2_POINTS: %d\n", len(a.Scalars), len(a.G1Points), len(a.G2Points))
+	printMultiExpFixture(f)
+
+	fmt.Println("\n=== Checked-In Values ===")
+	stale, checked := 0, 0
+	for _, c := range []struct{ label, have, want string }{
+		{"ETHEREUM_INPUT", a.EthereumInput, hex.EncodeToString(f.EthereumInput)},
+		{"EXPECTED_RESULT", a.Expected, hex.EncodeToString(f.Expected)},
+		{"EXPECTED_RESULT_ETHEREUM", a.ExpectedEthereum, hex.EncodeToString(f.ExpectedEthereum)},
+	} {
+		if c.have == "" {
+			continue
+		}
+		checked++
+		if strings.EqualFold(c.have, c.want) {
+			fmt.Printf("✅ %s still correct\n", c.label)
+		} else {
+			stale++
+			fmt.Printf("❌ %s is stale\n   checked in: %s\n   recomputed: %s\n", c.label, c.have, c.want)
+		}
+	}
+	if checked == 0 {
+		fmt.Println("⚠️  No expected values in the snippet (pass --expected to check one); recomputed values printed above")
+	}
+
+	if *emit != "" {
+		fmt.Println("\n=== Emitting Fixtures ===")
+		if err := emitFixtures(f, *emit, *emitDir); err != nil {
+			return stale, err
+		}
+	}
+	return stale, nil
+}
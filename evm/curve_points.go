@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math/big"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// g1CurveRHS returns x^3 + 4, the right-hand side of the G1 curve equation y^2 = x^3 + 4
+func g1CurveRHS(x *fp.Element) fp.Element {
+	var rhs, four fp.Element
+	four.SetUint64(4)
+	rhs.Square(x)
+	rhs.Mul(&rhs, x)
+	rhs.Add(&rhs, &four)
+	return rhs
+}
+
+// g2CurveRHS returns x^3 + 4(1+u), the right-hand side of the G2 (twist) curve equation
+func g2CurveRHS(x *bls.E2) bls.E2 {
+	var rhs, b bls.E2
+	b.A0.SetUint64(4)
+	b.A1.SetUint64(4)
+	rhs.Square(x)
+	rhs.Mul(&rhs, x)
+	rhs.Add(&rhs, &b)
+	return rhs
+}
+
+// g1PointOnCurveNotInSubgroup returns the first point (x, y), x = start, start+1, ..., that lies
+// on the G1 curve but outside the prime-order subgroup. The cofactor of G1 is large, so almost
+// every curve point qualifies.
+func g1PointOnCurveNotInSubgroup(start uint64) bls.G1Affine {
+	var p bls.G1Affine
+	for x := start; ; x++ {
+		p.X.SetUint64(x)
+		rhs := g1CurveRHS(&p.X)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		p.Y.Sqrt(&rhs)
+		if p.IsOnCurve() && !p.IsInSubGroup() {
+			return p
+		}
+	}
+}
+
+// g2PointOnCurveNotInSubgroup is the G2 analogue of g1PointOnCurveNotInSubgroup, trying
+// x = start + 0*u, (start+1) + 0*u, ...
+func g2PointOnCurveNotInSubgroup(start uint64) bls.G2Affine {
+	var p bls.G2Affine
+	for x := start; ; x++ {
+		p.X.A0.SetUint64(x)
+		p.X.A1.SetZero()
+		rhs := g2CurveRHS(&p.X)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		p.Y.Sqrt(&rhs)
+		if p.IsOnCurve() && !p.IsInSubGroup() {
+			return p
+		}
+	}
+}
+
+// g1PointNotOnCurve returns a copy of p with y incremented by one. y and y+1 can only both
+// satisfy the curve equation if y = -1/2, so the result is off the curve for practical inputs.
+func g1PointNotOnCurve(p bls.G1Affine) bls.G1Affine {
+	var one fp.Element
+	one.SetOne()
+	p.Y.Add(&p.Y, &one)
+	return p
+}
+
+// g2PointNotOnCurve is the G2 analogue of g1PointNotOnCurve (y.C0 incremented by one)
+func g2PointNotOnCurve(p bls.G2Affine) bls.G2Affine {
+	var one fp.Element
+	one.SetOne()
+	p.Y.A0.Add(&p.Y.A0, &one)
+	return p
+}
+
+// g1MulAnyOrder computes [k]p by double-and-add. gnark's ScalarMultiplication uses the GLV
+// endomorphism, which only gives the right result inside the prime-order subgroup.
+func g1MulAnyOrder(p *bls.G1Affine, k *big.Int) bls.G1Affine {
+	var acc, base bls.G1Jac
+	acc.X.SetOne()
+	acc.Y.SetOne() // (1, 1, 0) is infinity
+	base.FromAffine(p)
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		acc.DoubleAssign()
+		if k.Bit(i) == 1 {
+			acc.AddAssign(&base)
+		}
+	}
+	var out bls.G1Affine
+	out.FromJacobian(&acc)
+	return out
+}
+
+// g2MulAnyOrder is the G2 analogue of g1MulAnyOrder
+func g2MulAnyOrder(p *bls.G2Affine, k *big.Int) bls.G2Affine {
+	var acc, base bls.G2Jac
+	acc.X.SetOne()
+	acc.Y.SetOne()
+	base.FromAffine(p)
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		acc.DoubleAssign()
+		if k.Bit(i) == 1 {
+			acc.AddAssign(&base)
+		}
+	}
+	var out bls.G2Affine
+	out.FromJacobian(&acc)
+	return out
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// pointKindInfo describes the encodings of validateKinds for decode
+var pointKindInfo = map[string]struct {
+	Length      int
+	Description string
+}{
+	"g1c": {48, "G1 compressed (48 bytes)"},
+	"g2c": {96, "G2 compressed (96 bytes, x.C1 | x.C0)"},
+	"g1u": {96, "G1 gnark uncompressed (96 bytes, x | y)"},
+	"g2u": {192, "G2 gnark uncompressed (192 bytes, x.C1 | x.C0 | y.C1 | y.C0)"},
+	"g1e": {128, "G1 Ethereum EIP-2537 (128 bytes, x | y, each 64-byte padded)"},
+	"g2e": {256, "G2 Ethereum EIP-2537 (256 bytes, x.C0 | x.C1 | y.C0 | y.C1, each 64-byte padded)"},
+}
+
+// rawPointFields holds the 48-byte coordinate fields of an encoding as they appear, with the
+// flag bits cleared, before any validation. G1 uses index 0 only; y is nil for compressed points.
+type rawPointFields struct {
+	g2      bool
+	flags   byte // top three bits of the first byte; compressed and gnark uncompressed only
+	hasFlag bool
+	x, y    [2][]byte // [C0, C1]
+	padding []string  // names of EIP-2537 fields with non-zero padding
+}
+
+// splitPointFields cuts an encoding into its coordinate fields without checking them
+func splitPointFields(kind string, data []byte) rawPointFields {
+	f := rawPointFields{g2: kind[1] == '2'}
+	field := func(i int) []byte { return append([]byte{}, data[i*48:(i+1)*48]...) }
+	switch kind {
+	case "g1c", "g2c", "g1u", "g2u":
+		f.hasFlag = true
+		f.flags = data[0] & 0xe0
+		data = append([]byte{data[0] &^ 0xe0}, data[1:]...)
+		switch kind {
+		case "g1c":
+			f.x[0] = field(0)
+		case "g2c":
+			f.x[1], f.x[0] = field(0), field(1)
+		case "g1u":
+			f.x[0], f.y[0] = field(0), field(1)
+		case "g2u":
+			f.x[1], f.x[0], f.y[1], f.y[0] = field(0), field(1), field(2), field(3)
+		}
+	case "g1e", "g2e":
+		names := []string{"x", "y"}
+		if f.g2 {
+			names = []string{"x.C0", "x.C1", "y.C0", "y.C1"}
+		}
+		fields := make([][]byte, len(names))
+		for i := range names {
+			word := data[i*64 : (i+1)*64]
+			if !bytes.Equal(word[:16], make([]byte, 16)) {
+				f.padding = append(f.padding, names[i])
+			}
+			fields[i] = append([]byte{}, word[16:]...)
+		}
+		if f.g2 {
+			f.x[0], f.x[1], f.y[0], f.y[1] = fields[0], fields[1], fields[2], fields[3]
+		} else {
+			f.x[0], f.y[0] = fields[0], fields[1]
+		}
+	}
+	return f
+}
+
+// decodedFp reduces a 48-byte field and reports whether it was already < p
+func decodedFp(b []byte) (fp.Element, bool) {
+	var e fp.Element
+	canonical := e.SetBytesCanonical(b) == nil
+	if !canonical {
+		e.SetBytes(b)
+	}
+	return e, canonical
+}
+
+// runDecodeMode prints everything about a point encoding without rejecting it: flags,
+// coordinates, canonicity, curve and subgroup membership, and the strict decoder's verdict
+func runDecodeMode(args []string) error {
+	fs := newFlagSet("decode")
+	pointHex := fs.String("point", "", "Point hex: 48/96-byte compressed, 96/192-byte gnark uncompressed or 128/256-byte Ethereum")
+	format := fs.String("format", "auto", "Encoding: auto (by length and flags), "+strings.Join(validateKinds, ", "))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *pointHex == "" {
+		return usageErrorf("--point is required")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*pointHex), "0x"))
+	if err != nil {
+		return usageErrorf("--point: invalid hex: %v", err)
+	}
+	kind := *format
+	if kind == "auto" {
+		var ok bool
+		if kind, ok = detectPointKind(data); !ok {
+			return fmt.Errorf("%d bytes matches no encoding (48, 96, 128, 192, 256)", len(data))
+		}
+	}
+	info, ok := pointKindInfo[kind]
+	if !ok {
+		return usageErrorf("invalid --format '%s' (valid: auto, %s)", kind, strings.Join(validateKinds, ", "))
+	}
+	if len(data) != info.Length {
+		return fmt.Errorf("%s needs %d bytes, got %d", kind, info.Length, len(data))
+	}
+	f := splitPointFields(kind, data)
+
+	fmt.Println("=== Decode ===")
+	fmt.Printf("Encoding: %s\n", info.Description)
+	if f.hasFlag {
+		fmt.Printf("Flags: 0x%02x (compression %v, infinity %v, sort %v)\n", f.flags,
+			f.flags&0x80 != 0, f.flags&0x40 != 0, f.flags&0x20 != 0)
+	}
+	if kind[2] == 'e' {
+		padding := "all zero"
+		if len(f.padding) > 0 {
+			padding = "non-zero in " + strings.Join(f.padding, ", ")
+		}
+		fmt.Printf("Padding (16 bytes per field): %s\n", padding)
+	}
+
+	// Coordinates as given, reduced mod p when non-canonical so the curve checks still run
+	var x, y bls.E2
+	canonical := true
+	names := []string{"x"}
+	if f.g2 {
+		names = []string{"x.C0", "x.C1"}
+	}
+	coords := []*fp.Element{&x.A0, &x.A1}
+	fmt.Println()
+	fmt.Println("Coordinates:")
+	for i, name := range names {
+		e, ok := decodedFp(f.x[i])
+		*coords[i] = e
+		canonical = canonical && ok
+		printDecodedCoordinate(name, f.x[i], ok)
+		recordQuietCoordinate(name, e)
+	}
+
+	compressed := f.y[0] == nil
+	infinity := f.flags&0x40 != 0
+	if !f.hasFlag {
+		infinity = bytes.Equal(data, make([]byte, len(data)))
+	}
+	yFound := true
+	if compressed {
+		if !infinity {
+			y, yFound = recoverY(f.g2, x, f.flags&0x20 != 0)
+		}
+		if !yFound {
+			fmt.Println("  y     none: x^3 + b is not a square (x is not on the curve)")
+		} else if !infinity {
+			fmt.Println("  y recovered from x and the sort flag:")
+		}
+	}
+	if !compressed || (yFound && !infinity) {
+		names = []string{"y"}
+		if f.g2 {
+			names = []string{"y.C0", "y.C1"}
+		}
+		coords = []*fp.Element{&y.A0, &y.A1}
+		for i, name := range names {
+			if compressed {
+				printFpCoordinate(name, *coords[i])
+				recordQuietCoordinate(name, *coords[i])
+				continue
+			}
+			e, ok := decodedFp(f.y[i])
+			*coords[i] = e
+			canonical = canonical && ok
+			printDecodedCoordinate(name, f.y[i], ok)
+			recordQuietCoordinate(name, e)
+		}
+	}
+
+	var onCurve, inSubgroup bool
+	var compressedOut, ethereumOut []byte
+	if f.g2 {
+		p := bls.G2Affine{X: x, Y: y}
+		onCurve = yFound && p.IsOnCurve()
+		inSubgroup = onCurve && p.IsInSubGroup()
+		compressedOut, ethereumOut = serialization.ConvertG2AffineToCompressed(p), serialization.EncodeEthereumG2Point(p)
+	} else {
+		p := bls.G1Affine{X: x.A0, Y: y.A0}
+		onCurve = yFound && p.IsOnCurve()
+		inSubgroup = onCurve && p.IsInSubGroup()
+		compressedOut, ethereumOut = serialization.ConvertG1AffineToCompressed(p), serialization.EncodeEthereumG1Point(p)
+	}
+	if infinity {
+		// (0, 0) is how gnark and EIP-2537 represent infinity; it is not on the curve equation
+		onCurve, inSubgroup = true, true
+	}
+
+	fmt.Println()
+	fmt.Printf("Infinity: %v\n", infinity)
+	fmt.Printf("Canonical coordinates (< p): %v\n", canonical)
+	fmt.Printf("On curve: %v\n", onCurve)
+	fmt.Printf("In subgroup: %v\n", inSubgroup)
+	verdict := "valid"
+	if _, err := validatePoint(kind, data); err != nil {
+		verdict = fmt.Sprintf("%s (%v)", validationCode(err), err)
+	}
+	fmt.Printf("Strict decoder: %s\n", verdict)
+	if onCurve {
+		fmt.Printf("Compressed: %x\n", compressedOut)
+		fmt.Printf("Ethereum: %x\n", ethereumOut)
+	}
+
+	recordVerdictInput(hex.EncodeToString(data))
+	reportInput("point", hex.EncodeToString(data))
+	reportOutput("kind", kind)
+	reportOutput("infinity", infinity)
+	reportOutput("canonical", canonical)
+	reportOutput("on_curve", onCurve)
+	reportOutput("in_subgroup", inSubgroup)
+	reportOutput("strict_decoder", verdict)
+	return nil
+}
+
+// recordQuietCoordinate adds a decoded coordinate to the --quiet result lines
+func recordQuietCoordinate(name string, e fp.Element) {
+	b := e.Bytes()
+	recordQuietResult(name, hex.EncodeToString(b[:]))
+}
+
+// printDecodedCoordinate prints a coordinate field as given, noting when it is >= p
+func printDecodedCoordinate(label string, raw []byte, canonical bool) {
+	if canonical {
+		var e fp.Element
+		e.SetBytes(raw)
+		printFpCoordinate(label, e)
+		return
+	}
+	fmt.Printf("  %-5s hex: %x (>= p, reduced below)\n", label, raw)
+	e, _ := decodedFp(raw)
+	printFpCoordinate(label, e)
+}
+
+// recoverY returns the y with x^3 + b = y^2 that the sort flag selects, and false if there is none
+func recoverY(g2 bool, x bls.E2, largest bool) (bls.E2, bool) {
+	var y bls.E2
+	if g2 {
+		rhs := g2CurveRHS(&x)
+		if rhs.Legendre() != 1 && !rhs.IsZero() {
+			return y, false
+		}
+		y.Sqrt(&rhs)
+		c1, c0 := y.A1.Bytes(), y.A0.Bytes()
+		if serialization.IsLexicographicallyLargestFp2(append(c1[:], c0[:]...)) != largest {
+			y.Neg(&y)
+		}
+		return y, true
+	}
+	rhs := g1CurveRHS(&x.A0)
+	if rhs.Legendre() != 1 && !rhs.IsZero() {
+		return y, false
+	}
+	y.A0.Sqrt(&rhs)
+	b := y.A0.Bytes()
+	if serialization.IsLexicographicallyLargestFp(b[:]) != largest {
+		y.A0.Neg(&y.A0)
+	}
+	return y, true
+}
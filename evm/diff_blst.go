@@ -0,0 +1,379 @@
+//go:build blst
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+	"time"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+// Differential testing against blst, the library of most consensus clients. The bindings
+// need cgo, so they are only linked with -tags blst; the default build keeps a stub. Every
+// operation runs through gnark-crypto (with this tool's serialization) and blst on the
+// same inputs, and the results are compared as compressed points, or for the pairing as
+// blst's big-endian Fp12 encoding.
+
+// blstDiffOps are the operation groups of diff-blst, in run order
+var blstDiffOps = []string{"serialization", "add", "mul", "msm", "pairing", "hash-to-curve"}
+
+// blstDiff collects the disagreements of one run
+type blstDiff struct {
+	op      string
+	checked int
+	failed  int
+}
+
+func (d *blstDiff) check(name string, detail string) {
+	d.checked++
+	if detail != "" {
+		fmt.Printf("❌ %s %s: %s\n", d.op, name, detail)
+		d.failed++
+	}
+}
+
+// compareEncodings describes a mismatch of two encodings, "" when they are equal
+func compareEncodings(gnark, blstOut []byte) string {
+	if bytes.Equal(gnark, blstOut) {
+		return ""
+	}
+	return fmt.Sprintf("gnark %x, blst %x", gnark, blstOut)
+}
+
+// Conversions into blst go through the compressed encoding, which both sides read
+
+func toBlstP1(p bls.G1Affine) *blst.P1Affine {
+	return new(blst.P1Affine).Uncompress(serialization.ConvertG1AffineToCompressed(p))
+}
+
+func toBlstP2(p bls.G2Affine) *blst.P2Affine {
+	return new(blst.P2Affine).Uncompress(serialization.ConvertG2AffineToCompressed(p))
+}
+
+// blstScalar is the 32-byte little-endian scalar encoding blst's Mult takes
+func blstScalar(k *big.Int) []byte {
+	return reverseBytes(scalarTo32Bytes(k))
+}
+
+// gtBlstBytes encodes a gnark GT element like blst_bendian_from_fp12: the 48-byte
+// big-endian coefficients with C0/C1 interleaved per B index
+func gtBlstBytes(z *bls.GT) []byte {
+	c := [2][3][2]*[48]byte{}
+	for j, e6 := range []*bls.E6{&z.C0, &z.C1} {
+		for i, e2 := range []*bls.E2{&e6.B0, &e6.B1, &e6.B2} {
+			a0, a1 := e2.A0.Bytes(), e2.A1.Bytes()
+			c[j][i] = [2]*[48]byte{&a0, &a1}
+		}
+	}
+	var out []byte
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			out = append(out, c[j][i][0][:]...)
+			out = append(out, c[j][i][1][:]...)
+		}
+	}
+	return out
+}
+
+// blstDiffScalars are the edge scalars every mul run covers, then --count random ones
+func blstDiffScalars(rng *rand.Rand, count int) []*big.Int {
+	r := fr.Modulus()
+	out := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2),
+		new(big.Int).Sub(r, big.NewInt(1)), new(big.Int).Set(r), new(big.Int).Add(r, big.NewInt(1)),
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+	}
+	for i := 0; i < count; i++ {
+		out = append(out, new(big.Int).Rand(rng, r))
+	}
+	return out
+}
+
+func randomG1(rng *rand.Rand) bls.G1Affine {
+	_, _, g1, _ := bls.Generators()
+	var p bls.G1Affine
+	p.ScalarMultiplication(&g1, new(big.Int).Rand(rng, fr.Modulus()))
+	return p
+}
+
+func randomG2(rng *rand.Rand) bls.G2Affine {
+	_, _, _, g2 := bls.Generators()
+	var p bls.G2Affine
+	p.ScalarMultiplication(&g2, new(big.Int).Rand(rng, fr.Modulus()))
+	return p
+}
+
+// diffBlstSerialization decodes valid points and the compressed invalid-vectors with both
+// libraries: gnark through DecodeCompressedG1/G2Point, blst through Uncompress plus the
+// subgroup check. Both must agree on acceptance and re-encode accepted points identically.
+func diffBlstSerialization(d *blstDiff, rng *rand.Rand, count int) {
+	type input struct {
+		name string
+		data []byte
+		g2   bool
+	}
+	var inputs []input
+	for _, g2 := range []bool{false, true} {
+		for _, v := range invalidVectorsForGroup(g2) {
+			if v.Op == "deserialize-g1" || v.Op == "deserialize-g2" {
+				inputs = append(inputs, input{v.Name, v.Data, g2})
+			}
+		}
+	}
+	for i := 0; i < count; i++ {
+		inputs = append(inputs,
+			input{fmt.Sprintf("g1_random_%d", i), serialization.ConvertG1AffineToCompressed(randomG1(rng)), false},
+			input{fmt.Sprintf("g2_random_%d", i), serialization.ConvertG2AffineToCompressed(randomG2(rng)), true})
+	}
+
+	for _, in := range inputs {
+		var gnarkOut, blstOut []byte
+		var gnarkErr error
+		if in.g2 {
+			var p bls.G2Affine
+			if p, gnarkErr = serialization.DecodeCompressedG2Point(in.data); gnarkErr == nil {
+				gnarkOut = serialization.ConvertG2AffineToCompressed(p)
+			}
+			if q := new(blst.P2Affine).Uncompress(in.data); q != nil && q.InG2() {
+				blstOut = q.Compress()
+			}
+		} else {
+			var p bls.G1Affine
+			if p, gnarkErr = serialization.DecodeCompressedG1Point(in.data); gnarkErr == nil {
+				gnarkOut = serialization.ConvertG1AffineToCompressed(p)
+			}
+			if q := new(blst.P1Affine).Uncompress(in.data); q != nil && q.InG1() {
+				blstOut = q.Compress()
+			}
+		}
+		switch {
+		case gnarkErr != nil && blstOut != nil:
+			d.check(in.name, fmt.Sprintf("gnark rejects (%v), blst accepts", gnarkErr))
+		case gnarkErr == nil && blstOut == nil:
+			d.check(in.name, "gnark accepts, blst rejects")
+		default:
+			d.check(in.name, compareEncodings(gnarkOut, blstOut))
+		}
+	}
+}
+
+func diffBlstAdd(d *blstDiff, rng *rand.Rand, count int) {
+	_, _, g1, g2 := bls.Generators()
+	var inf1 bls.G1Affine
+	var inf2 bls.G2Affine
+	var neg1 bls.G1Affine
+	var neg2 bls.G2Affine
+	neg1.Neg(&g1)
+	neg2.Neg(&g2)
+	type pair1 struct {
+		name string
+		a, b bls.G1Affine
+	}
+	type pair2 struct {
+		name string
+		a, b bls.G2Affine
+	}
+	cases1 := []pair1{{"g+g", g1, g1}, {"g+0", g1, inf1}, {"0+0", inf1, inf1}, {"g+-g", g1, neg1}}
+	cases2 := []pair2{{"g+g", g2, g2}, {"g+0", g2, inf2}, {"0+0", inf2, inf2}, {"g+-g", g2, neg2}}
+	for i := 0; i < count; i++ {
+		cases1 = append(cases1, pair1{fmt.Sprintf("random_%d", i), randomG1(rng), randomG1(rng)})
+		cases2 = append(cases2, pair2{fmt.Sprintf("random_%d", i), randomG2(rng), randomG2(rng)})
+	}
+	for _, c := range cases1 {
+		var sum bls.G1Affine
+		sum.Add(&c.a, &c.b)
+		var acc blst.P1
+		acc.FromAffine(toBlstP1(c.a))
+		d.check("g1_"+c.name, compareEncodings(serialization.ConvertG1AffineToCompressed(sum), acc.Add(toBlstP1(c.b)).Compress()))
+	}
+	for _, c := range cases2 {
+		var sum bls.G2Affine
+		sum.Add(&c.a, &c.b)
+		var acc blst.P2
+		acc.FromAffine(toBlstP2(c.a))
+		d.check("g2_"+c.name, compareEncodings(serialization.ConvertG2AffineToCompressed(sum), acc.Add(toBlstP2(c.b)).Compress()))
+	}
+}
+
+func diffBlstMul(d *blstDiff, rng *rand.Rand, count int) {
+	p1, p2 := randomG1(rng), randomG2(rng)
+	for i, k := range blstDiffScalars(rng, count) {
+		name := fmt.Sprintf("k[%d]=%s", i, k.Text(16))
+		var q1 bls.G1Affine
+		q1.ScalarMultiplication(&p1, k)
+		var b1 blst.P1
+		b1.FromAffine(toBlstP1(p1))
+		d.check("g1_"+name, compareEncodings(serialization.ConvertG1AffineToCompressed(q1), b1.Mult(blstScalar(k), 256).Compress()))
+
+		var q2 bls.G2Affine
+		q2.ScalarMultiplication(&p2, k)
+		var b2 blst.P2
+		b2.FromAffine(toBlstP2(p2))
+		d.check("g2_"+name, compareEncodings(serialization.ConvertG2AffineToCompressed(q2), b2.Mult(blstScalar(k), 256).Compress()))
+	}
+}
+
+func diffBlstMSM(d *blstDiff, rng *rand.Rand, count int) {
+	sizes := []int{1, 2, 3, 8, 32, 128}
+	for i := 0; i < count; i++ {
+		sizes = append(sizes, 1+rng.Intn(256))
+	}
+	for _, n := range sizes {
+		scalars := make([]*big.Int, n)
+		var packed []byte
+		g1Points := make([]bls.G1Affine, n)
+		g2Points := make([]bls.G2Affine, n)
+		b1 := make(blst.P1Affines, n)
+		b2 := make(blst.P2Affines, n)
+		for j := 0; j < n; j++ {
+			scalars[j] = new(big.Int).Rand(rng, fr.Modulus())
+			packed = append(packed, blstScalar(scalars[j])...)
+			g1Points[j], g2Points[j] = randomG1(rng), randomG2(rng)
+			b1[j], b2[j] = *toBlstP1(g1Points[j]), *toBlstP2(g2Points[j])
+		}
+		name := fmt.Sprintf("%d_pairs", n)
+		if r, err := bls12381vec.MultiExpG1(g1Points, scalars); err != nil {
+			d.check("g1_"+name, err.Error())
+		} else {
+			d.check("g1_"+name, compareEncodings(serialization.ConvertG1AffineToCompressed(r), b1.Mult(packed, 256).Compress()))
+		}
+		if r, err := bls12381vec.MultiExpG2(g2Points, scalars); err != nil {
+			d.check("g2_"+name, err.Error())
+		} else {
+			d.check("g2_"+name, compareEncodings(serialization.ConvertG2AffineToCompressed(r), b2.Mult(packed, 256).Compress()))
+		}
+	}
+}
+
+func diffBlstPairing(d *blstDiff, rng *rand.Rand, count int) {
+	_, _, g1, g2 := bls.Generators()
+	var inf1 bls.G1Affine
+	type pairingCase struct {
+		name string
+		p    []bls.G1Affine
+		q    []bls.G2Affine
+	}
+	cases := []pairingCase{
+		{"e(g1,g2)", []bls.G1Affine{g1}, []bls.G2Affine{g2}},
+		{"e(0,g2)", []bls.G1Affine{inf1}, []bls.G2Affine{g2}},
+	}
+	for i := 0; i < count; i++ {
+		n := 1 + i%4
+		c := pairingCase{name: fmt.Sprintf("random_%d_pairs_%d", i, n)}
+		for j := 0; j < n; j++ {
+			c.p, c.q = append(c.p, randomG1(rng)), append(c.q, randomG2(rng))
+		}
+		cases = append(cases, c)
+	}
+	for _, c := range cases {
+		z, err := bls.Pair(c.p, c.q)
+		if err != nil {
+			d.check(c.name, err.Error())
+			continue
+		}
+		ps := make([]blst.P1Affine, len(c.p))
+		qs := make([]blst.P2Affine, len(c.q))
+		for j := range c.p {
+			ps[j], qs[j] = *toBlstP1(c.p[j]), *toBlstP2(c.q[j])
+		}
+		gt := blst.Fp12MillerLoopN(qs, ps)
+		gt.FinalExp()
+		d.check(c.name, compareEncodings(gtBlstBytes(&z), gt.ToBendian()))
+	}
+}
+
+func diffBlstHashToCurve(d *blstDiff, rng *rand.Rand, count int) {
+	messages := [][]byte{{}, []byte("abc"), []byte("abcdef0123456789"), bytes.Repeat([]byte("a"), 512)}
+	for i := 0; i < count; i++ {
+		m := make([]byte, rng.Intn(200))
+		rng.Read(m)
+		messages = append(messages, m)
+	}
+	dst1, dst2 := []byte(hashToCurveDSTs["hash-g1"]), []byte(hashToCurveDSTs["hash-g2"])
+	for i, m := range messages {
+		name := fmt.Sprintf("msg[%d] (%d bytes)", i, len(m))
+		if p, err := bls.HashToG1(m, dst1); err != nil {
+			d.check("g1_"+name, err.Error())
+		} else {
+			d.check("g1_"+name, compareEncodings(serialization.ConvertG1AffineToCompressed(p), blst.HashToG1(m, dst1).Compress()))
+		}
+		if p, err := bls.HashToG2(m, dst2); err != nil {
+			d.check("g2_"+name, err.Error())
+		} else {
+			d.check("g2_"+name, compareEncodings(serialization.ConvertG2AffineToCompressed(p), blst.HashToG2(m, dst2).Compress()))
+		}
+	}
+}
+
+// runDiffBlstMode runs the selected operation groups through gnark-crypto and blst
+func runDiffBlstMode(args []string) (int, error) {
+	fs := newFlagSet("diff-blst")
+	ops := fs.String("op", "all", "Operations (comma-separated): "+strings.Join(blstDiffOps, ", ")+" or all")
+	count := fs.Int("count", 16, "Random inputs per operation on top of the fixed edge cases")
+	seed := fs.Int64("seed", 0, "Seed for the random inputs (default: time-based, printed)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *count < 0 {
+		return 0, usageErrorf("--count must not be negative")
+	}
+	selected := blstDiffOps
+	if *ops != "all" {
+		selected = nil
+		for _, op := range strings.Split(*ops, ",") {
+			op = strings.TrimSpace(op)
+			known := false
+			for _, name := range blstDiffOps {
+				known = known || name == op
+			}
+			if !known {
+				return 0, usageErrorf("unknown --op '%s' (valid: %s, all)", op, strings.Join(blstDiffOps, ", "))
+			}
+			selected = append(selected, op)
+		}
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	runs := map[string]func(*blstDiff, *rand.Rand, int){
+		"serialization": diffBlstSerialization,
+		"add":           diffBlstAdd,
+		"mul":           diffBlstMul,
+		"msm":           diffBlstMSM,
+		"pairing":       diffBlstPairing,
+		"hash-to-curve": diffBlstHashToCurve,
+	}
+
+	fmt.Println("=== Differential Test: gnark-crypto vs blst ===")
+	fmt.Printf("Random inputs: %d per operation, seed: %d\n", *count, *seed)
+	failed, total := 0, 0
+	for _, op := range selected {
+		d := &blstDiff{op: op}
+		runs[op](d, rng, *count)
+		if d.failed == 0 {
+			fmt.Printf("✅ %-14s %d inputs agree\n", op, d.checked)
+		}
+		failed += d.failed
+		total += d.checked
+	}
+	fmt.Printf("Inputs: %d, disagreements: %d\n", total, failed)
+	if failed == 0 {
+		fmt.Println("✅ gnark-crypto and blst agree on every input")
+	} else {
+		fmt.Printf("Reproduce with --seed %d --count %d\n", *seed, *count)
+	}
+	reportOutput("inputs", total)
+	reportOutput("disagreements", failed)
+	return failed, nil
+}
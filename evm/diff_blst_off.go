@@ -0,0 +1,10 @@
+//go:build !blst
+
+package main
+
+import "fmt"
+
+// runDiffBlstMode needs the cgo blst bindings, which only -tags blst links
+func runDiffBlstMode(args []string) (int, error) {
+	return 0, fmt.Errorf("diff-blst needs the blst backend: rebuild with 'go build -tags blst' (requires cgo and a C compiler)")
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// A simulated Pedersen DKG (joint Feldman VSS): every party i deals a random polynomial
+// f_i of degree t-1, broadcasts the commitments C_ik = a_ik * G of its coefficients and
+// sends f_i(j) to party j, who checks f_i(j) * G = sum_k j^k C_ik. A party complains
+// against a dealer whose share fails the check; the dealer answers with the share in
+// public and is disqualified if that share fails too. Over the qualified dealers QUAL,
+// party j holds x_j = sum f_i(j) and the group key is sum C_i0. G is the public key
+// group of the ciphersuite.
+
+// dkgShare is one dealt share and the result of the recipient's check
+type dkgShare struct {
+	To    int    `json:"to"`
+	Share string `json:"share"`
+	Valid bool   `json:"valid"`
+}
+
+// dkgDealer is the dealing of one party
+type dkgDealer struct {
+	Index        int        `json:"index"`
+	Coefficients []string   `json:"coefficients"`
+	Commitments  []string   `json:"commitments"`
+	Shares       []dkgShare `json:"shares"`
+}
+
+// dkgComplaint is a complaint and the dealer's public answer
+type dkgComplaint struct {
+	Complainer int    `json:"complainer"`
+	Dealer     int    `json:"dealer"`
+	Revealed   string `json:"revealed_share"`
+	Resolved   bool   `json:"resolved"`
+}
+
+// dkgParty is the final key share of one party
+type dkgParty struct {
+	Index     int    `json:"index"`
+	SecretKey string `json:"secret_key"`
+	PublicKey string `json:"public_key"`
+}
+
+// dkgTranscript is the full, reproducible record of one run
+type dkgTranscript struct {
+	Ciphersuite    string         `json:"ciphersuite"`
+	Threshold      int            `json:"threshold"`
+	Parties        int            `json:"parties"`
+	Seed           string         `json:"seed"`
+	Dealers        []dkgDealer    `json:"dealers"`
+	Complaints     []dkgComplaint `json:"complaints"`
+	Qualified      []int          `json:"qualified"`
+	KeyShares      []dkgParty     `json:"key_shares"`
+	GroupPublicKey string         `json:"group_public_key"`
+	DST            string         `json:"dst,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	Signers        []int          `json:"signers,omitempty"`
+	Signature      string         `json:"signature,omitempty"`
+}
+
+// dkgCoefficient derives coefficient k of party i from the seed, so a transcript is
+// reproducible from its seed alone
+func dkgCoefficient(seed []byte, party, k int) (*big.Int, error) {
+	okm, err := hkdf.Key(sha256.New, seed, nil, fmt.Sprintf("dkg party %d coefficient %d", party, k), 48)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(okm), fr.Modulus()), nil
+}
+
+// feldmanCheck reports whether share * G = sum_k x^k C_k
+func feldmanCheck(commitments [][]byte, x int, share *big.Int, ciphersuite string) (bool, error) {
+	powers := make([]*big.Int, len(commitments))
+	xk := big.NewInt(1)
+	for k := range powers {
+		powers[k] = new(big.Int).Set(xk)
+		xk.Mod(xk.Mul(xk, big.NewInt(int64(x))), fr.Modulus())
+	}
+	expected, err := combinePoints(commitments, powers, ciphersuite != "min-sig")
+	if err != nil {
+		return false, fmt.Errorf("commitment %v", err)
+	}
+	return bytes.Equal(expected, blsPublicKey(share, ciphersuite)), nil
+}
+
+// parsePartyPairs parses dealer:recipient pairs such as 1:2,3:1
+func parsePartyPairs(list string, n int) (map[[2]int]bool, error) {
+	pairs := map[[2]int]bool{}
+	for _, s := range splitList(list) {
+		a, b, ok := strings.Cut(s, ":")
+		i, erri := strconv.Atoi(a)
+		j, errj := strconv.Atoi(b)
+		if !ok || erri != nil || errj != nil || i < 1 || i > n || j < 1 || j > n || i == j {
+			return nil, fmt.Errorf("'%s' is not a dealer:recipient pair of distinct parties in [1, %d]", s, n)
+		}
+		pairs[[2]int{i, j}] = true
+	}
+	return pairs, nil
+}
+
+// parsePartyList parses a list of party indices in [1, n]
+func parsePartyList(list string, n int) (map[int]bool, error) {
+	parties := map[int]bool{}
+	for _, s := range splitList(list) {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 || i > n {
+			return nil, fmt.Errorf("'%s' is not a party in [1, %d]", s, n)
+		}
+		parties[i] = true
+	}
+	return parties, nil
+}
+
+// runDkgSimMode runs the DKG among n parties, with the corrupted shares and refusals
+// given on the command line, and prints or writes the transcript. The key shares are
+// checked against the commitments, and the group key against the Lagrange interpolation
+// of t party public keys; with a message, t parties also sign and the combined
+// signature is verified. A failed check is a failure.
+func runDkgSimMode(args []string) (int, error) {
+	fs := newFlagSet("dkg-sim")
+	t := fs.Int("t", 2, "Threshold: shares needed to sign")
+	n := fs.Int("n", 3, "Number of parties")
+	seedHex := fs.String("seed", "", "Seed of all polynomial coefficients (hex, default: 32 random bytes)")
+	badShares := fs.String("bad-shares", "", "dealer:recipient pairs whose share is corrupted, e.g. 1:2,3:1")
+	refuse := fs.String("refuse", "", "Dealers that answer a complaint with the corrupted share again (disqualified)")
+	message := fs.String("message", "", "Message parties 1..t sign with their key shares (UTF-8)")
+	messageHex := fs.String("message-hex", "", "Message to sign (hex, overrides --message)")
+	out := fs.String("out", "", "Write the transcript JSON to this file (through --sink) instead of stdout")
+	scheme := addSignatureSchemeFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *t < 1 || *n < *t {
+		return 0, usageErrorf("need 1 <= t <= n (got t=%d, n=%d)", *t, *n)
+	}
+	dst, err := scheme.resolve()
+	if err != nil {
+		return 0, err
+	}
+	bad, err := parsePartyPairs(*badShares, *n)
+	if err != nil {
+		return 0, usageErrorf("--bad-shares: %v", err)
+	}
+	refusing, err := parsePartyList(*refuse, *n)
+	if err != nil {
+		return 0, usageErrorf("--refuse: %v", err)
+	}
+	seed, err := randomOrHex("seed", *seedHex, 32)
+	if err != nil {
+		return 0, err
+	}
+	cs := *scheme.ciphersuite
+	r := fr.Modulus()
+	tr := dkgTranscript{Ciphersuite: cs, Threshold: *t, Parties: *n, Seed: hex.EncodeToString(seed), Complaints: []dkgComplaint{}}
+	failed := 0
+
+	// dealing: coefficients, commitments and the shares as sent
+	coeffs := make([][]*big.Int, *n+1)
+	commitments := make([][][]byte, *n+1)
+	for i := 1; i <= *n; i++ {
+		d := dkgDealer{Index: i}
+		for k := 0; k < *t; k++ {
+			a, err := dkgCoefficient(seed, i, k)
+			if err != nil {
+				return 0, err
+			}
+			c := blsPublicKey(a, cs)
+			coeffs[i], commitments[i] = append(coeffs[i], a), append(commitments[i], c)
+			d.Coefficients, d.Commitments = append(d.Coefficients, a.String()), append(d.Commitments, hex.EncodeToString(c))
+		}
+		tr.Dealers = append(tr.Dealers, d)
+	}
+	received := make([][]*big.Int, *n+1) // received[j][i] is the share of dealer i held by j
+	for j := 1; j <= *n; j++ {
+		received[j] = make([]*big.Int, *n+1)
+	}
+	disqualified := map[int]bool{}
+	for i := 1; i <= *n; i++ {
+		d := &tr.Dealers[i-1]
+		for j := 1; j <= *n; j++ {
+			share := evalPolynomial(coeffs[i], big.NewInt(int64(j)))
+			sent := share
+			if bad[[2]int{i, j}] {
+				sent = new(big.Int).Add(share, big.NewInt(1))
+				sent.Mod(sent, r)
+			}
+			valid, err := feldmanCheck(commitments[i], j, sent, cs)
+			if err != nil {
+				return 0, err
+			}
+			d.Shares = append(d.Shares, dkgShare{To: j, Share: sent.String(), Valid: valid})
+			received[j][i] = sent
+			if valid {
+				continue
+			}
+			// complaint: the dealer reveals the share, honestly unless it refuses
+			revealed := share
+			if refusing[i] {
+				revealed = sent
+			}
+			resolved, err := feldmanCheck(commitments[i], j, revealed, cs)
+			if err != nil {
+				return 0, err
+			}
+			tr.Complaints = append(tr.Complaints, dkgComplaint{Complainer: j, Dealer: i, Revealed: revealed.String(), Resolved: resolved})
+			if resolved {
+				received[j][i] = revealed
+			} else {
+				disqualified[i] = true
+			}
+		}
+	}
+	for i := 1; i <= *n; i++ {
+		if !disqualified[i] {
+			tr.Qualified = append(tr.Qualified, i)
+		}
+	}
+	if len(tr.Qualified) == 0 {
+		return 0, usageErrorf("every dealer is disqualified")
+	}
+
+	// key shares, and the public keys both from the shares and from the commitments
+	var groupTerms [][]byte
+	var ones []*big.Int
+	for _, i := range tr.Qualified {
+		groupTerms, ones = append(groupTerms, commitments[i][0]), append(ones, big.NewInt(1))
+	}
+	groupPk, err := combinePoints(groupTerms, ones, cs != "min-sig")
+	if err != nil {
+		return 0, err
+	}
+	tr.GroupPublicKey = hex.EncodeToString(groupPk)
+	keyShares := make([]*big.Int, *n+1)
+	for j := 1; j <= *n; j++ {
+		x := new(big.Int)
+		for _, i := range tr.Qualified {
+			x.Add(x, received[j][i])
+		}
+		keyShares[j] = x.Mod(x, r)
+		tr.KeyShares = append(tr.KeyShares, dkgParty{Index: j, SecretKey: hex.EncodeToString(scalarTo32Bytes(x)), PublicKey: hex.EncodeToString(blsPublicKey(x, cs))})
+	}
+
+	fmt.Printf("=== DKG Simulation (%d of %d, %s) ===\n", *t, *n, cs)
+	fmt.Printf("Seed: %s\n", tr.Seed)
+	for _, d := range tr.Dealers {
+		fmt.Printf("\nDealer %d:\n", d.Index)
+		for k, c := range d.Commitments {
+			fmt.Printf("  C_%d: %s\n", k, c)
+		}
+		for _, s := range d.Shares {
+			mark := "✅"
+			if !s.Valid {
+				mark = "❌"
+			}
+			fmt.Printf("  %s share for %d: %s\n", mark, s.To, s.Share)
+		}
+	}
+	fmt.Printf("\nComplaints: %d\n", len(tr.Complaints))
+	for _, c := range tr.Complaints {
+		outcome := "resolved by the revealed share"
+		if !c.Resolved {
+			outcome = "dealer disqualified"
+		}
+		fmt.Printf("  party %d against dealer %d: %s\n", c.Complainer, c.Dealer, outcome)
+	}
+	fmt.Printf("Qualified dealers: %v\n", tr.Qualified)
+	fmt.Printf("Group public key: %s\n", tr.GroupPublicKey)
+	for _, p := range tr.KeyShares {
+		fmt.Printf("  party %d: public key %s\n", p.Index, p.PublicKey)
+	}
+	fmt.Println()
+
+	// every key share matches the qualified commitments
+	for j := 1; j <= *n; j++ {
+		var terms [][]byte
+		var powers []*big.Int
+		for _, i := range tr.Qualified {
+			xk := big.NewInt(1)
+			for _, c := range commitments[i] {
+				terms, powers = append(terms, c), append(powers, new(big.Int).Set(xk))
+				xk.Mod(xk.Mul(xk, big.NewInt(int64(j))), r)
+			}
+		}
+		expected, err := combinePoints(terms, powers, cs != "min-sig")
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.Equal(expected, blsPublicKey(keyShares[j], cs)) {
+			fmt.Printf("❌ The key share of party %d does not match the commitments\n", j)
+			failed++
+		}
+	}
+	if failed == 0 {
+		fmt.Println("✅ Every key share matches the qualified commitments")
+	}
+
+	// t party public keys interpolate to the group public key
+	signers := make([]int, *t)
+	ids := make([]*big.Int, *t)
+	for k := range signers {
+		signers[k], ids[k] = k+1, big.NewInt(int64(k+1))
+	}
+	lambdas, err := lagrangeAtZero(ids)
+	if err != nil {
+		return 0, err
+	}
+	var pks [][]byte
+	for _, j := range signers {
+		pks = append(pks, blsPublicKey(keyShares[j], cs))
+	}
+	interpolated, err := combinePoints(pks, lambdas, cs != "min-sig")
+	if err != nil {
+		return 0, err
+	}
+	if bytes.Equal(interpolated, groupPk) {
+		fmt.Printf("✅ The public keys of parties %v interpolate to the group public key\n", signers)
+	} else {
+		fmt.Printf("❌ The public keys of parties %v do not interpolate to the group public key\n", signers)
+		failed++
+	}
+
+	if *message != "" || *messageHex != "" {
+		msg, err := parseMessageFlags(*message, *messageHex)
+		if err != nil {
+			return 0, err
+		}
+		signed := scheme.signedMessage(groupPk, msg)
+		var partials [][]byte
+		for _, j := range signers {
+			s, err := blsSign(signed, keyShares[j], cs, dst)
+			if err != nil {
+				return 0, err
+			}
+			if cs == "min-sig" {
+				partials = append(partials, serialization.ConvertG1AffineToCompressed(s.SigG1))
+			} else {
+				partials = append(partials, serialization.ConvertG2AffineToCompressed(s.SigG2))
+			}
+		}
+		sig, err := combineSignatures(partials, lambdas, cs)
+		if err != nil {
+			return 0, err
+		}
+		tr.DST, tr.Message, tr.Signers, tr.Signature = dst, hex.EncodeToString(msg), signers, hex.EncodeToString(sig)
+		fmt.Printf("Signature of parties %v: %s\n", signers, tr.Signature)
+		if verr := blsVerify(groupPk, signed, sig, cs, dst); verr != nil {
+			fmt.Printf("❌ The combined signature is invalid under the group public key: %v\n", verr)
+			failed++
+		} else {
+			fmt.Println("✅ The combined signature verifies under the group public key")
+		}
+	}
+	recordVerdictInput(tr.Seed)
+	recordVerdictResult(tr.GroupPublicKey)
+	reportOutput("transcript", tr)
+
+	data, err := json.MarshalIndent(tr, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if *out == "" {
+		fmt.Println()
+		fmt.Println(string(data))
+		return failed, nil
+	}
+	if err := outputSink.write(*out, append(data, '\n')); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %v", *out, err)
+	}
+	fmt.Printf("Wrote %s\n", outputSink.describe(*out))
+	return failed, nil
+}
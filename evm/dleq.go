@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Chaum-Pedersen proofs of discrete-log equality: the prover knows k with X = k G and
+// Y = k H. It commits to a nonce w with A = w G and B = w H, takes the challenge
+// c = hash_to_field(G | H | X | Y | A | B) and answers s = w - c k. The verifier
+// recomputes A = s G + c X and B = s H + c Y and checks the challenge. G is the G1
+// generator; H is a hash-to-curve point in G1, or in G2 for a cross-group proof, since
+// both groups have order r. Points enter the transcript compressed.
+
+const (
+	dleqChallengeDST   = "DLEQ_BLS12381_XMD:SHA-256_CHALLENGE_"
+	dleqGeneratorG1DST = "DLEQ_BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	dleqGeneratorG2DST = "DLEQ_BLS12381G2_XMD:SHA-256_SSWU_RO_"
+)
+
+// dleqStatement is the public part of a proof: the bases and X = k G, Y = k H,
+// compressed; HInG1 tells the group of H and Y
+type dleqStatement struct {
+	G, H, X, Y []byte
+	HInG1      bool
+}
+
+// dleqProof is the challenge and the response, with the nonce commitments
+type dleqProof struct {
+	C, S fr.Element
+	A, B []byte
+}
+
+// dleqBase hashes a message to the base H, compressed
+func dleqBase(msg string, g1 bool) ([]byte, error) {
+	if g1 {
+		h, err := bls.HashToG1([]byte(msg), []byte(dleqGeneratorG1DST))
+		return serialization.ConvertG1AffineToCompressed(h), err
+	}
+	h, err := bls.HashToG2([]byte(msg), []byte(dleqGeneratorG2DST))
+	return serialization.ConvertG2AffineToCompressed(h), err
+}
+
+// mulPoint is k times a compressed point
+func mulPoint(p []byte, k fr.Element, g1 bool) ([]byte, error) {
+	return combinePoints([][]byte{p}, []*big.Int{k.BigInt(new(big.Int))}, g1)
+}
+
+// dleqChallenge is hash_to_field over the transcript
+func dleqChallenge(st dleqStatement, a, b []byte, dst string) (fr.Element, error) {
+	c, err := fr.Hash(bytes.Join([][]byte{st.G, st.H, st.X, st.Y, a, b}, nil), []byte(dst), 1)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	return c[0], nil
+}
+
+// dleqProve proves log_G X = log_H Y = k with nonce w
+func dleqProve(st dleqStatement, k, w fr.Element, dst string) (dleqProof, error) {
+	var p dleqProof
+	var err error
+	if p.A, err = mulPoint(st.G, w, true); err != nil {
+		return p, err
+	}
+	if p.B, err = mulPoint(st.H, w, st.HInG1); err != nil {
+		return p, err
+	}
+	if p.C, err = dleqChallenge(st, p.A, p.B, dst); err != nil {
+		return p, err
+	}
+	var ck fr.Element
+	ck.Mul(&p.C, &k)
+	p.S.Sub(&w, &ck)
+	return p, nil
+}
+
+// dleqRecompute is A = s G + c X and B = s H + c Y
+func dleqRecompute(st dleqStatement, c, s fr.Element) ([]byte, []byte, error) {
+	sc := []*big.Int{s.BigInt(new(big.Int)), c.BigInt(new(big.Int))}
+	a, err := combinePoints([][]byte{st.G, st.X}, sc, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := combinePoints([][]byte{st.H, st.Y}, sc, st.HInG1)
+	return a, b, err
+}
+
+// dleqVerify checks a (c, s) proof
+func dleqVerify(st dleqStatement, c, s fr.Element, dst string) error {
+	a, b, err := dleqRecompute(st, c, s)
+	if err != nil {
+		return err
+	}
+	expected, err := dleqChallenge(st, a, b, dst)
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(&c) {
+		return fmt.Errorf("challenge mismatch: hash of the recomputed commitments is %s", expected.String())
+	}
+	return nil
+}
+
+// ethEncodeCompressed re-encodes a compressed point in the EIP-2537 layout
+func ethEncodeCompressed(b []byte, g1 bool) ([]byte, error) {
+	if g1 {
+		p, err := serialization.DecodeCompressedG1Point(b)
+		return serialization.EncodeEthereumG1Point(p), err
+	}
+	p, err := serialization.DecodeCompressedG2Point(b)
+	return serialization.EncodeEthereumG2Point(p), err
+}
+
+// dleqMSMInput is the EIP-2537 MSM input (P, s), (Q, c) a contract uses to recompute a
+// nonce commitment
+func dleqMSMInput(p, q []byte, s, c fr.Element, g1 bool) ([]byte, error) {
+	var out []byte
+	for _, pair := range []struct {
+		point []byte
+		k     fr.Element
+	}{{p, s}, {q, c}} {
+		enc, err := ethEncodeCompressed(pair.point, g1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(append(out, enc...), frBytes(pair.k)...)
+	}
+	return out, nil
+}
+
+// dleqVector is one statement and proof with the expected verification result
+type dleqVector struct {
+	Name   string `json:"name"`
+	G      string `json:"g"`
+	H      string `json:"h"`
+	X      string `json:"x"`
+	Y      string `json:"y"`
+	C      string `json:"c"`
+	S      string `json:"s"`
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// runDleqMode proves that X = k G and Y = k H share their discrete logarithm and prints
+// the proof with the vectors a verifier must refuse, each run through verification;
+// an unexpected result is a failure
+func runDleqMode(args []string) (int, error) {
+	fs := newFlagSet("dleq")
+	secretStr := fs.String("secret", "", "Secret k (default: derived from --seed)")
+	nonceStr := fs.String("nonce", "", "Nonce w (default: derived from --seed and k)")
+	groups := fs.String("groups", "g1", "Groups of G and H: g1 (both G1) or g1g2 (H in G2)")
+	hMessage := fs.String("h-message", "H", "Hash-to-curve message of the base H")
+	seedHex := fs.String("seed", "", "Seed of the defaults (hex, default: 32 random bytes)")
+	dst := fs.String("dst", dleqChallengeDST, "Challenge DST (hash_to_field, expand_message_xmd SHA-256)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *groups != "g1" && *groups != "g1g2" {
+		return 0, usageErrorf("unknown --groups '%s' (valid: g1, g1g2)", *groups)
+	}
+	seed, err := randomOrHex("seed", *seedHex, 32)
+	if err != nil {
+		return 0, err
+	}
+	scalarFlag := func(name, value, label string) (fr.Element, error) {
+		if value != "" {
+			e, err := parseFrElement(value)
+			if err != nil {
+				return e, usageErrorf("--%s: %v", name, err)
+			}
+			return e, nil
+		}
+		return seededFieldElement(seed, label)
+	}
+	k, err := scalarFlag("secret", *secretStr, "dleq secret")
+	if err != nil {
+		return 0, err
+	}
+	w, err := scalarFlag("nonce", *nonceStr, "dleq nonce "+k.String())
+	if err != nil {
+		return 0, err
+	}
+	if k.IsZero() || w.IsZero() {
+		return 0, usageErrorf("the secret and the nonce must be non-zero")
+	}
+
+	_, _, g1Gen, _ := bls.Generators()
+	st := dleqStatement{G: serialization.ConvertG1AffineToCompressed(g1Gen), HInG1: *groups == "g1"}
+	if st.H, err = dleqBase(*hMessage, st.HInG1); err != nil {
+		return 0, err
+	}
+	if st.X, err = mulPoint(st.G, k, true); err != nil {
+		return 0, err
+	}
+	if st.Y, err = mulPoint(st.H, k, st.HInG1); err != nil {
+		return 0, err
+	}
+	proof, err := dleqProve(st, k, w, *dst)
+	if err != nil {
+		return 0, err
+	}
+
+	// the statement Y = (k + 1) H is false; so are the proofs with a changed c or s, and
+	// the proof checked against another base H
+	var one, k1, c1, s1 fr.Element
+	one.SetOne()
+	k1.Add(&k, &one)
+	c1.Add(&proof.C, &one)
+	s1.Add(&proof.S, &one)
+	wrongY := st
+	if wrongY.Y, err = mulPoint(st.H, k1, st.HInG1); err != nil {
+		return 0, err
+	}
+	wrongProof, err := dleqProve(wrongY, k, w, *dst)
+	if err != nil {
+		return 0, err
+	}
+	otherH := st
+	if otherH.H, err = dleqBase(*hMessage+"'", st.HInG1); err != nil {
+		return 0, err
+	}
+	cases := []struct {
+		name  string
+		st    dleqStatement
+		c, s  fr.Element
+		valid bool
+	}{
+		{"valid", st, proof.C, proof.S, true},
+		{"unequal-logs", wrongY, wrongProof.C, wrongProof.S, false},
+		{"tampered-c", st, c1, proof.S, false},
+		{"tampered-s", st, proof.C, s1, false},
+		{"other-base", otherH, proof.C, proof.S, false},
+	}
+
+	fmt.Printf("=== DLEQ Proof (Chaum-Pedersen, %s) ===\n", *groups)
+	fmt.Printf("Challenge DST: %s\n", *dst)
+	fmt.Printf("G: %x\nH: %x\nX = kG: %x\nY = kH: %x\n", st.G, st.H, st.X, st.Y)
+	fmt.Printf("A = wG: %x\nB = wH: %x\n", proof.A, proof.B)
+	printFrValue("c", proof.C)
+	printFrValue("s", proof.S)
+	msmA, err := dleqMSMInput(st.G, st.X, proof.S, proof.C, true)
+	if err != nil {
+		return 0, err
+	}
+	msmB, err := dleqMSMInput(st.H, st.Y, proof.S, proof.C, st.HInG1)
+	if err != nil {
+		return 0, err
+	}
+	bOp := "G2MSM (0x0e)"
+	if st.HInG1 {
+		bOp = "G1MSM (0x0c)"
+	}
+	fmt.Printf("A = sG + cX, G1MSM (0x0c) input: %x\n", msmA)
+	fmt.Printf("B = sH + cY, %s input: %x\n", bOp, msmB)
+	fmt.Println()
+
+	failed := 0
+	for _, m := range []struct {
+		name   string
+		input  []byte
+		commit []byte
+		g1     bool
+	}{{"msm-a", msmA, proof.A, true}, {"msm-b", msmB, proof.B, st.HInG1}} {
+		expected, err := ethEncodeCompressed(m.commit, m.g1)
+		if err != nil {
+			return 0, err
+		}
+		out, err := eip2537MSM(!m.g1)(m.input)
+		if err != nil || !bytes.Equal(out, expected) {
+			fmt.Printf("❌ %-13s the MSM input gives %x, %v\n", m.name, out, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %-13s the MSM input gives the nonce commitment\n", m.name)
+	}
+	var vectors []dleqVector
+	for _, tc := range cases {
+		v := dleqVector{Name: tc.name, G: hex.EncodeToString(tc.st.G), H: hex.EncodeToString(tc.st.H), X: hex.EncodeToString(tc.st.X), Y: hex.EncodeToString(tc.st.Y), C: hex.EncodeToString(frBytes(tc.c)), S: hex.EncodeToString(frBytes(tc.s)), Valid: tc.valid}
+		verr := dleqVerify(tc.st, tc.c, tc.s, *dst)
+		if verr != nil {
+			v.Reason = verr.Error()
+		}
+		vectors = append(vectors, v)
+		if (verr == nil) != tc.valid {
+			fmt.Printf("❌ %-13s verification returned %v, expected %v\n", tc.name, verr == nil, tc.valid)
+			failed++
+			continue
+		}
+		result := "true"
+		if !tc.valid {
+			result = "false (" + v.Reason + ")"
+		}
+		fmt.Printf("✅ %-13s expected %s\n", tc.name, result)
+	}
+	recordVerdictResult(vectors[0].C + vectors[0].S)
+	reportOutput("challenge_dst", *dst)
+	reportOutput("a", hex.EncodeToString(proof.A))
+	reportOutput("b", hex.EncodeToString(proof.B))
+	reportOutput("msm_a_input", hex.EncodeToString(msmA))
+	reportOutput("msm_b_input", hex.EncodeToString(msmB))
+	reportOutput("vectors", vectors)
+	return failed, nil
+}
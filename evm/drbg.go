@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// seededReader is a deterministic byte stream: SHA-256(seed || counter) blocks. It is not a
+// cryptographic DRBG for key material, only a reproducible source for test vectors.
+type seededReader struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+func newSeededReader(seed []byte) *seededReader {
+	return &seededReader{seed: append([]byte(nil), seed...)}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var ctr [8]byte
+			binary.BigEndian.PutUint64(ctr[:], r.counter)
+			r.counter++
+			block := sha256.Sum256(append(append([]byte(nil), r.seed...), ctr[:]...))
+			r.buf = block[:]
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// vectorRand is the randomness source of random, pairing-random and g2add-random:
+// crypto/rand, or a seededReader when --seed is given
+var vectorRand io.Reader = rand.Reader
+
+// randomFr draws a uniform element of Fr from vectorRand
+func randomFr() (fr.Element, error) {
+	k, err := uniformBelow(vectorRand, fr.Modulus())
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var e fr.Element
+	e.SetBigInt(k)
+	return e, nil
+}
+
+// registerSeedFlag adds --seed to a random generation mode
+func registerSeedFlag(fs *flag.FlagSet) *string {
+	return fs.String("seed", "", "Hex seed for a reproducible run: all points and scalars are derived from it (default: crypto/rand)")
+}
+
+// applySeed switches vectorRand to a seeded stream when a seed was given
+func applySeed(s string) error {
+	if s == "" {
+		return nil
+	}
+	seed, err := parseSeed(s)
+	if err != nil {
+		return err
+	}
+	vectorRand = newSeededReader(seed)
+	fmt.Printf("Seed: %x (deterministic run)\n", seed)
+	return nil
+}
+
+// deriveSeed derives an independent sub-seed for label (e.g. a shard or fixture index)
+func deriveSeed(seed []byte, label string) []byte {
+	h := sha256.New()
+	h.Write(seed)
+	h.Write([]byte{0})
+	h.Write([]byte(label))
+	return h.Sum(nil)
+}
+
+// parseSeed parses a hex seed (0x prefix optional)
+func parseSeed(s string) ([]byte, error) {
+	seed, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed '%s': %v", s, err)
+	}
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("seed must not be empty")
+	}
+	return seed, nil
+}
+
+// uniformBelow draws a value in [0, bound) from rng. 64 bytes are reduced, so the bias is
+// negligible for every bound used here.
+func uniformBelow(rng io.Reader, bound *big.Int) (*big.Int, error) {
+	var buf [64]byte
+	if _, err := io.ReadFull(rng, buf[:]); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(buf[:]), bound), nil
+}
+
+// buildSeededRandomFixture is the deterministic counterpart of runRandomMode: 1 to
+// maxScalars pairs of random points and scalars drawn from scalarRange
+func buildSeededRandomFixture(name string, rng io.Reader, maxScalars int, useG2 bool, scalarRange scalarRange) (multiExpFixture, error) {
+	n, err := uniformBelow(rng, big.NewInt(int64(maxScalars)))
+	if err != nil {
+		return multiExpFixture{}, err
+	}
+	count := int(n.Int64()) + 1
+
+	_, _, g1Gen, g2Gen := bls.Generators()
+	rMinus1 := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	g1Points := make([]bls.G1Affine, count)
+	g2Points := make([]bls.G2Affine, count)
+	scalars := make([]*big.Int, count)
+	for i := 0; i < count; i++ {
+		k, err := uniformBelow(rng, rMinus1)
+		if err != nil {
+			return multiExpFixture{}, err
+		}
+		k.Add(k, big.NewInt(1))
+		if useG2 {
+			g2Points[i].ScalarMultiplication(&g2Gen, k)
+		} else {
+			g1Points[i].ScalarMultiplication(&g1Gen, k)
+		}
+		s, err := scalarRange.draw(i, func(bound *big.Int) (*big.Int, error) { return uniformBelow(rng, bound) })
+		if err != nil {
+			return multiExpFixture{}, err
+		}
+		scalars[i] = s
+	}
+	return newMultiExpFixture(name, g1Points, g2Points, scalars, useG2)
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"evm/bls12381vec"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The edge-case suite crosses the operands implementations most often get wrong: the point
+// at infinity, the scalars 0, 1, r - 1, r, r + 1 and 2^256 - 1, and MSMs of a single pair.
+// Every expected result is computed by double-and-add on k mod r (or from bilinearity for
+// the pairing), independently of the implementations it is compared with. Empty inputs
+// depend on the profile and are covered by empty-input-vectors.
+
+var edgeCaseOps = []string{"add", "mul", "msm", "pairing"}
+
+// edgeCase is one input with its expected output and a short name for that output
+type edgeCase struct {
+	name    string
+	op      string // g1add, g2add, g1mul, g2mul, g1msm, g2msm or pairing
+	input   []byte
+	want    curvePoint // the expected point of add, mul and msm
+	pairing bool       // the expected pairing check result
+	label   string     // the expected result as printed: O, G, -G, 2G, true, ...
+	aboveR  bool       // some scalar is >= r, rejected by chains with canonical scalars
+}
+
+// edgeCaseVector is one case as written by --out
+type edgeCaseVector struct {
+	Name     string `json:"name"`
+	Op       string `json:"op"`
+	Input    string `json:"input"`
+	Expected string `json:"expected,omitempty"`
+	// ExpectedCompressed is the MSM result in the compressed encoding of Neo's MultiExp
+	ExpectedCompressed string `json:"expected_compressed,omitempty"`
+	ExpectedError      string `json:"expected_error,omitempty"`
+	Result             string `json:"result"`
+}
+
+// edgeCaseImpl is one implementation of an operation; compressed outputs are compared
+// with the compressed expectation
+type edgeCaseImpl struct {
+	name       string
+	run        func(inputHex string) (string, error)
+	compressed bool
+}
+
+func edgeCaseImpls(op string) []edgeCaseImpl {
+	multiExp := func(g2 bool) func(string) (string, error) {
+		return func(s string) (string, error) { return bls12381vec.MultiExpFromEthereumFormat(s, g2) }
+	}
+	switch op {
+	case "g1add":
+		return []edgeCaseImpl{{"G1Add", bls12381vec.G1Add, false}}
+	case "g2add":
+		return []edgeCaseImpl{{"G2Add", bls12381vec.G2Add, false}}
+	case "g1mul":
+		return []edgeCaseImpl{{"G1Mul", bls12381vec.G1Mul, false}}
+	case "g2mul":
+		return []edgeCaseImpl{{"G2Mul", bls12381vec.G2Mul, false}}
+	case "g1msm":
+		return []edgeCaseImpl{{"G1MSM", bls12381vec.G1MSM, false}, {"MultiExp", multiExp(false), true}}
+	case "g2msm":
+		return []edgeCaseImpl{{"G2MSM", bls12381vec.G2MSM, false}, {"MultiExp", multiExp(true), true}}
+	}
+	return []edgeCaseImpl{{"Pairing", bls12381vec.Pairing, false}, {"PairingNaive", bls12381vec.PairingNaive, false}}
+}
+
+// edgePointCases builds the add, mul and msm cases of one group
+func edgePointCases(g2 bool, ops map[string]bool) []edgeCase {
+	prefix := groupPrefix(g2)
+	g := suiteGenerator(g2)
+	inf := newCurvePoint(g2)
+	r := fr.Modulus()
+	one := big.NewInt(1)
+	rMinus1 := new(big.Int).Sub(r, one)
+	rPlus1 := new(big.Int).Add(r, one)
+	max := new(big.Int).Sub(new(big.Int).Lsh(one, 256), one)
+	negG := g.mul(rMinus1)
+	s := suiteScalar("edge-cases/" + prefix + "/s")
+
+	var cases []edgeCase
+	if ops["add"] {
+		add := func(name string, a, b, want curvePoint, label string) {
+			cases = append(cases, edgeCase{name: name, op: prefix + "add", input: concatBytes(a.ethereum(), b.ethereum()), want: want, label: label})
+		}
+		add("O+O", inf, inf, inf, "O")
+		add("O+G", inf, g, g, "G")
+		add("G+O", g, inf, g, "G")
+		add("G+(-G)", g, negG, inf, "O")
+		add("(-G)+G", negG, g, inf, "O")
+		add("G+G", g, g, g.mul(big.NewInt(2)), "2G")
+	}
+
+	// each scalar with its multiple of G
+	scalars := []struct {
+		name, label string
+		k           *big.Int
+	}{
+		{"0", "O", big.NewInt(0)},
+		{"1", "G", one},
+		{"(r-1)", "-G", rMinus1},
+		{"r", "O", r},
+		{"(r+1)", "G", rPlus1},
+		{"(2^256-1)", "[(2^256-1) mod r]G", max},
+	}
+	for _, op := range []string{"mul", "msm"} {
+		if !ops[op] {
+			continue
+		}
+		for _, p := range []struct {
+			name string
+			c    curvePoint
+		}{{"G", g}, {"O", inf}} {
+			for _, sc := range scalars {
+				label := sc.label
+				if p.c.isInfinity() {
+					label = "O"
+				}
+				cases = append(cases, edgeCase{
+					name:   fmt.Sprintf("%s*%s", p.name, sc.name),
+					op:     prefix + op,
+					input:  concatBytes(p.c.ethereum(), scalarTo32Bytes(sc.k)),
+					want:   p.c.mul(new(big.Int).Mod(sc.k, r)),
+					label:  label,
+					aboveR: sc.k.Cmp(r) >= 0,
+				})
+			}
+		}
+	}
+	if ops["msm"] {
+		msm := func(name string, want curvePoint, label string, aboveR bool, pairs ...[]byte) {
+			cases = append(cases, edgeCase{name: name, op: prefix + "msm", input: concatBytes(pairs...), want: want, label: label, aboveR: aboveR})
+		}
+		pair := func(p curvePoint, k *big.Int) []byte { return concatBytes(p.ethereum(), scalarTo32Bytes(k)) }
+		msm("G*1+G*(r-1)", inf, "O", false, pair(g, one), pair(g, rMinus1))
+		msm("G*s+(-G)*s", inf, "O", false, pair(g, s), pair(negG, s))
+		msm("G*r+G*1", g, "G", true, pair(g, r), pair(g, one))
+		msm("O*s+G*1", g, "G", false, pair(inf, s), pair(g, one))
+		msm("O*0+O*0", inf, "O", false, pair(inf, big.NewInt(0)), pair(inf, big.NewInt(0)))
+	}
+	return cases
+}
+
+// edgePairingCases builds the pairing cases: infinity on either side, cancelling pairs and
+// scalars r - 1 hidden in the points
+func edgePairingCases() []edgeCase {
+	g1, g2 := suiteGenerator(false), suiteGenerator(true)
+	inf1, inf2 := newCurvePoint(false), newCurvePoint(true)
+	rMinus1 := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	pairs := func(points ...curvePoint) []byte {
+		var out []byte
+		for _, p := range points {
+			out = append(out, p.ethereum()...)
+		}
+		return out
+	}
+	c := func(name string, ok bool, points ...curvePoint) edgeCase {
+		return edgeCase{name: name, op: "pairing", input: pairs(points...), pairing: ok, label: fmt.Sprint(ok)}
+	}
+	return []edgeCase{
+		c("e(O,G2)", true, inf1, g2),
+		c("e(G1,O)", true, g1, inf2),
+		c("e(O,O)", true, inf1, inf2),
+		c("e(G1,G2)", false, g1, g2),
+		c("e(G1,G2)*e(O,G2)", false, g1, g2, inf1, g2),
+		c("e(G1,G2)*e(G1,O)", false, g1, g2, g1, inf2),
+		c("e(G1,G2)*e(-G1,G2)", true, g1, g2, g1.mul(rMinus1), g2),
+		c("e(G1,G2)*e(G1,-G2)", true, g1, g2, g1, g2.mul(rMinus1)),
+		c("e(G1,G2)*e(G1,G2)", false, g1, g2, g1, g2),
+		c("e(O,G2)*e(G1,O)", true, inf1, g2, g1, inf2),
+	}
+}
+
+// runEdgeCasesMode runs every edge case through the implementations of its operation and
+// compares their outputs, or their rejection under a canonical-scalar --chain, with the
+// expected result
+func runEdgeCasesMode(args []string) (int, error) {
+	fs := newFlagSet("edge-cases")
+	opsFlag := fs.String("op", "all", "Operations (comma-separated): "+strings.Join(edgeCaseOps, ", ")+" or all")
+	group := fs.String("group", "both", "Group of add, mul and msm: g1, g2 or both")
+	chainName := registerChainFlag(fs)
+	outPath := fs.String("out", "", "Also write the cases with their expected results as JSON")
+	verbose := fs.Bool("verbose", false, "Print the input and expected output of every case")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	ops := map[string]bool{}
+	for _, o := range strings.Split(*opsFlag, ",") {
+		o = strings.TrimSpace(o)
+		if o == "all" {
+			for _, name := range edgeCaseOps {
+				ops[name] = true
+			}
+			continue
+		}
+		valid := false
+		for _, name := range edgeCaseOps {
+			valid = valid || name == o
+		}
+		if !valid {
+			return 0, usageErrorf("invalid --op '%s' (valid: %s, all)", o, strings.Join(edgeCaseOps, ", "))
+		}
+		ops[o] = true
+	}
+	groups := map[string][]bool{"g1": {false}, "g2": {true}, "both": {false, true}}[*group]
+	if groups == nil {
+		return 0, usageErrorf("invalid --group '%s' (valid: g1, g2, both)", *group)
+	}
+	var chain *chainProfile
+	if *chainName != "" {
+		c, ok := chainProfiles[*chainName]
+		if !ok {
+			return 0, usageErrorf("unknown chain '%s' (valid: %s)", *chainName, strings.Join(chainProfileNames(), ", "))
+		}
+		chain = &c
+	}
+
+	var cases []edgeCase
+	for _, g2 := range groups {
+		cases = append(cases, edgePointCases(g2, ops)...)
+	}
+	if ops["pairing"] {
+		cases = append(cases, edgePairingCases()...)
+	}
+
+	fmt.Println("=== Edge-Case Suite ===")
+	if chain != nil {
+		fmt.Printf("Chain: %s (%s, scalars: %s)\n", *chainName, chain.Description, chain.Scalars)
+	}
+	failed := 0
+	var vectors []edgeCaseVector
+	current := ""
+	for _, c := range cases {
+		if c.op != current {
+			current = c.op
+			fmt.Printf("\n--- %s ---\n", c.op)
+		}
+		inputHex := hex.EncodeToString(c.input)
+		v := edgeCaseVector{Name: c.op + "_" + c.name, Op: c.op, Input: inputHex, Result: c.label}
+		var want, wantCompressed string
+		if c.op == "pairing" {
+			want = hex.EncodeToString(eip2537PairingOutput(c.pairing))
+		} else {
+			want, wantCompressed = hex.EncodeToString(c.want.ethereum()), hex.EncodeToString(c.want.compressed())
+		}
+		reject := chain != nil && chain.Scalars == scalarCanonical && c.aboveR
+		if reject {
+			v.Result, v.ExpectedError = "reject", "scalar >= r"
+		} else {
+			v.Expected = want
+			if strings.HasSuffix(c.op, "msm") {
+				v.ExpectedCompressed = wantCompressed
+			}
+		}
+		vectors = append(vectors, v)
+		if reject {
+			recordQuietResult(v.Name, v.Result)
+		} else {
+			recordQuietResult(v.Name, v.Expected)
+		}
+
+		var verdicts []string
+		for _, impl := range edgeCaseImpls(c.op) {
+			var err error
+			if chain != nil {
+				err = chain.checkInput(c.op, inputHex)
+			}
+			got := ""
+			if err == nil {
+				got, err = impl.run(inputHex)
+			}
+			expected := want
+			if impl.compressed {
+				expected = wantCompressed
+			}
+			switch {
+			case reject && err != nil:
+				verdicts = append(verdicts, "✅ "+impl.name)
+			case reject:
+				verdicts = append(verdicts, fmt.Sprintf("❌ %s accepted: %s", impl.name, got))
+				failed++
+			case err != nil:
+				verdicts = append(verdicts, fmt.Sprintf("❌ %s rejected: %v", impl.name, err))
+				failed++
+			case got != expected:
+				verdicts = append(verdicts, fmt.Sprintf("❌ %s got %s", impl.name, got))
+				failed++
+			default:
+				verdicts = append(verdicts, "✅ "+impl.name)
+			}
+		}
+		fmt.Printf("%-24s -> %-20s %s\n", c.name, v.Result, strings.Join(verdicts, ", "))
+		if *verbose {
+			fmt.Printf("  input:    %s\n", inputHex)
+			if !reject {
+				fmt.Printf("  expected: %s\n", want)
+			}
+		}
+	}
+	fmt.Println()
+	if failed == 0 {
+		fmt.Printf("✅ %d edge cases: every implementation returned the expected result\n", len(cases))
+	} else {
+		fmt.Printf("❌ %d mismatches in %d edge cases\n", failed, len(cases))
+	}
+	reportOutput("cases", len(cases))
+	reportOutput("mismatches", failed)
+	if *outPath != "" {
+		data, err := json.MarshalIndent(vectors, "", "  ")
+		if err != nil {
+			return failed, err
+		}
+		if err := os.WriteFile(*outPath, append(data, '\n'), 0o644); err != nil {
+			return failed, fmt.Errorf("failed to write %s: %v", *outPath, err)
+		}
+		fmt.Printf("Wrote %d cases to %s\n", len(vectors), *outPath)
+	}
+	return failed, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// EIP-2333 derives a tree of BLS secret keys from a seed: the master key is
+// HKDF_mod_r(seed), the KeyGen of the BLS signature draft, and a child key is HKDF_mod_r
+// of the compressed Lamport public key of its parent and index. EIP-2334 names the keys
+// with paths m/12381/3600/<account>/0 (withdrawal) and m/12381/3600/<account>/0/0
+// (signing).
+
+// parseKeyPath parses an EIP-2334 path such as m/12381/3600/0/0/0 into its indices
+func parseKeyPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("path '%s' must start with m", path)
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for i, p := range parts[1:] {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("path '%s': level %d ('%s') is not an index in [0, 2^32)", path, i+1, p)
+		}
+		indices = append(indices, uint32(n))
+	}
+	return indices, nil
+}
+
+// ikmToLamportSK splits HKDF(salt, IKM) into 255 32-byte Lamport secret keys
+func ikmToLamportSK(ikm, salt []byte) ([][]byte, error) {
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	okm, err := hkdf.Expand(sha256.New, prk, "", 32*255)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make([][]byte, 255)
+	for i := range chunks {
+		chunks[i] = okm[32*i : 32*(i+1)]
+	}
+	return chunks, nil
+}
+
+// lamportStep holds the intermediate values of one derive_child_SK
+type lamportStep struct {
+	Lamport0     [][]byte // IKM_to_lamport_SK(I2OSP(parent_SK, 32), I2OSP(index, 4))
+	Lamport1     [][]byte // the same for the flipped bits of the parent key
+	CompressedPK []byte   // SHA256 of the 510 hashed Lamport keys
+}
+
+// parentSKToLamportPK is parent_SK_to_lamport_PK
+func parentSKToLamportPK(parent *big.Int, index uint32) (lamportStep, error) {
+	var step lamportStep
+	salt := binary.BigEndian.AppendUint32(nil, index)
+	ikm := scalarTo32Bytes(parent)
+	notIKM := make([]byte, len(ikm))
+	for i, b := range ikm {
+		notIKM[i] = ^b
+	}
+	var err error
+	if step.Lamport0, err = ikmToLamportSK(ikm, salt); err != nil {
+		return step, err
+	}
+	if step.Lamport1, err = ikmToLamportSK(notIKM, salt); err != nil {
+		return step, err
+	}
+	h := sha256.New()
+	for _, chunk := range append(append([][]byte(nil), step.Lamport0...), step.Lamport1...) {
+		digest := sha256.Sum256(chunk)
+		h.Write(digest[:])
+	}
+	step.CompressedPK = h.Sum(nil)
+	return step, nil
+}
+
+// deriveChildSK is derive_child_SK; it also returns the Lamport values
+func deriveChildSK(parent *big.Int, index uint32) (*big.Int, lamportStep, error) {
+	step, err := parentSKToLamportPK(parent, index)
+	if err != nil {
+		return nil, step, err
+	}
+	sk, err := blsKeyGen(step.CompressedPK, nil)
+	return sk, step, err
+}
+
+// deriveKeyJSON is one key of the path in the --format json output
+type deriveKeyJSON struct {
+	Path              string   `json:"path"`
+	Index             *uint32  `json:"index,omitempty"`
+	SecretKey         string   `json:"secret_key"`
+	PublicKey         string   `json:"public_key"`
+	CompressedLamport string   `json:"compressed_lamport_pk,omitempty"`
+	Lamport0          []string `json:"lamport_0,omitempty"`
+	Lamport1          []string `json:"lamport_1,omitempty"`
+}
+
+// runDeriveMode walks an EIP-2334 path from the master key of a seed (or from --sk) and
+// prints every key on the way with the Lamport values of its derivation
+func runDeriveMode(args []string) error {
+	fs := newFlagSet("derive")
+	seedHex := fs.String("seed", "", "Seed (hex), at least 32 bytes")
+	skStr := fs.String("sk", "", "Start from this parent secret key instead of the master key of --seed")
+	path := fs.String("path", "m", "EIP-2334 path, e.g. m/12381/3600/0/0/0")
+	lamport := fs.Bool("lamport", false, "Print the 2 x 255 Lamport secret keys of every step")
+	ciphersuite := fs.String("ciphersuite", "min-pk", "Ciphersuite of the public keys: min-pk (G1) or min-sig (G2)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if (*seedHex == "") == (*skStr == "") {
+		return usageErrorf("exactly one of --seed and --sk is required")
+	}
+	if _, err := signatureDST(*ciphersuite, "pop"); err != nil {
+		return usageError{err}
+	}
+	indices, err := parseKeyPath(*path)
+	if err != nil {
+		return usageError{err}
+	}
+	var sk *big.Int
+	if *seedHex != "" {
+		seed, err := decodeHexFlag("seed", *seedHex)
+		if err != nil {
+			return err
+		}
+		if sk, err = blsKeyGen(seed, nil); err != nil {
+			return usageErrorf("--seed: %v", err)
+		}
+		recordVerdictInput(*seedHex)
+	} else if sk, err = parseSecretKey(*skStr); err != nil {
+		return usageError{err}
+	}
+
+	fmt.Printf("=== EIP-2333 Key Derivation (%s) ===\n", *ciphersuite)
+	printKey := func(k deriveKeyJSON, sk *big.Int) {
+		fmt.Printf("\n%s\n", k.Path)
+		if k.CompressedLamport != "" {
+			fmt.Printf("  Compressed Lamport PK: %s\n", k.CompressedLamport)
+		}
+		fmt.Printf("  Secret key: %s\n", sk.String())
+		fmt.Printf("  Secret key (32 bytes): %s\n", k.SecretKey)
+		fmt.Printf("  Public key: %s\n", k.PublicKey)
+	}
+	label := "m"
+	if *skStr != "" {
+		label = "parent"
+	}
+	root := deriveKeyJSON{Path: label, SecretKey: hex.EncodeToString(scalarTo32Bytes(sk)), PublicKey: hex.EncodeToString(blsPublicKey(sk, *ciphersuite))}
+	printKey(root, sk)
+	keys := []deriveKeyJSON{root}
+	for _, index := range indices {
+		child, step, err := deriveChildSK(sk, index)
+		if err != nil {
+			return err
+		}
+		label += fmt.Sprintf("/%d", index)
+		k := deriveKeyJSON{
+			Path:              label,
+			Index:             &index,
+			SecretKey:         hex.EncodeToString(scalarTo32Bytes(child)),
+			PublicKey:         hex.EncodeToString(blsPublicKey(child, *ciphersuite)),
+			CompressedLamport: hex.EncodeToString(step.CompressedPK),
+		}
+		if *lamport {
+			k.Lamport0, k.Lamport1 = hexStrings(step.Lamport0), hexStrings(step.Lamport1)
+		}
+		printKey(k, child)
+		if *lamport {
+			for i := range step.Lamport0 {
+				fmt.Printf("  lamport_0[%d]: %s  lamport_1[%d]: %s\n", i, k.Lamport0[i], i, k.Lamport1[i])
+			}
+		}
+		keys = append(keys, k)
+		sk = child
+	}
+	last := keys[len(keys)-1]
+	recordVerdictResult(last.SecretKey)
+	reportOutput("keys", keys)
+	reportOutput("secret_key", last.SecretKey)
+	reportOutput("public_key", last.PublicKey)
+	return nil
+}
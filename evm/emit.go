@@ -0,0 +1,510 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// multiExpFixture holds one generated MultiExp vector in every representation the
+// downstream test suites consume. All emitters render from the same fixture, so the
+// C#, Go, Rust, Solidity and Python suites can never drift apart.
+type multiExpFixture struct {
+	Name    string
+	UseG2   bool
+	Points  [][]byte   // compressed points (48 bytes G1, 96 bytes G2)
+	Scalars []*big.Int // one scalar per point
+	// EthereumInput is the EIP-2537 MSM input: (128/256-byte point + 32-byte scalar) per pair
+	EthereumInput []byte
+	// Expected is the compressed MultiExp result (Neo format)
+	Expected []byte
+	// ExpectedEthereum is the MultiExp result in Ethereum format (128/256 bytes)
+	ExpectedEthereum []byte
+}
+
+func (f multiExpFixture) groupName() string {
+	if f.UseG2 {
+		return "G2"
+	}
+	return "G1"
+}
+
+// fixtureEmitter renders a fixture as source code for one target language
+type fixtureEmitter struct {
+	FileName string
+	Render   func(f multiExpFixture) string
+}
+
+// fixtureEmitters lists every supported --emit target. "all" selects all of them.
+var fixtureEmitters = map[string]fixtureEmitter{
+	"csharp": {FileName: "Bls12381Fixtures.cs", Render: renderCSharpFixture},
+	"go":     {FileName: "bls12381_fixtures.go", Render: renderGoFixture},
+	// []byte literals with identifiers derived from the fixture name, for Go projects
+	// that vendor several fixtures into one package
+	"go-bytes": {FileName: "bls12381_fixtures_bytes.go", Render: renderGoBytesFixture},
+	"rust":     {FileName: "bls12381_fixtures.rs", Render: renderRustFixture},
+	"solidity": {FileName: "Bls12381Fixtures.sol", Render: renderSolidityFixture},
+	"python":   {FileName: "bls12381_fixtures.py", Render: renderPythonFixture},
+	// Single base64 byte-array argument of Neo's Ethereum alias methods
+	"neo-alias": {FileName: "neo_alias_args.json", Render: renderNeoAliasFixture},
+	// Literals for the Neo debugger's watch/evaluate expressions
+	"neo-debugger": {FileName: "neo_debugger_expressions.txt", Render: renderNeoDebuggerFixture},
+	// Golden test of this package, to be copied next to pairing_gen.go
+	"gotest": {FileName: "bls12381_golden_test.go", Render: renderGoTestFixture},
+}
+
+// fixtureEmitterOrder keeps "all" output deterministic
+var fixtureEmitterOrder = []string{"csharp", "go", "go-bytes", "rust", "solidity", "python", "neo-alias", "neo-debugger"}
+
+// optInFixtureEmitters are left out of "all": a package main test file next to the
+// package fixtures file of the go targets would break the build of the emit directory
+var optInFixtureEmitters = []string{"gotest"}
+
+// parseEmitTargets parses a comma-separated --emit value ("all" expands to every target)
+func parseEmitTargets(emit string) ([]string, error) {
+	var targets []string
+	seen := map[string]bool{}
+	for _, t := range strings.Split(emit, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if t == "all" {
+			for _, name := range fixtureEmitterOrder {
+				if !seen[name] {
+					seen[name] = true
+					targets = append(targets, name)
+				}
+			}
+			continue
+		}
+		if _, ok := fixtureEmitters[t]; !ok {
+			return nil, fmt.Errorf("unknown emit target '%s' (valid: %s, all)", t, strings.Join(append(fixtureEmitterOrder, optInFixtureEmitters...), ", "))
+		}
+		if !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no emit targets given")
+	}
+	return targets, nil
+}
+
+// emitFixtures writes the fixture for every requested target into outDir, through the
+// --sink (by default the directory itself)
+func emitFixtures(f multiExpFixture, emit string, outDir string) error {
+	targets, err := parseEmitTargets(emit)
+	if err != nil {
+		return err
+	}
+	for _, name := range targets {
+		emitter := fixtureEmitters[name]
+		path := filepath.Join(outDir, emitter.FileName)
+		if err := outputSink.write(path, []byte(emitter.Render(f))); err != nil {
+			return fmt.Errorf("failed to write %s fixture: %v", name, err)
+		}
+		fmt.Printf("Wrote %s fixture: %s\n", name, outputSink.describe(path))
+	}
+	return nil
+}
+
+// newMultiExpFixture builds a fixture from affine points and scalars, computing the
+// expected result with gnark-crypto's MultiExp, and adds it to the --format json document
+// and the --quiet result lines
+func newMultiExpFixture(name string, g1Points []bls.G1Affine, g2Points []bls.G2Affine, scalars []*big.Int, useG2 bool) (multiExpFixture, error) {
+	f, err := computeMultiExpFixture(name, g1Points, g2Points, scalars, useG2)
+	if err != nil {
+		return f, err
+	}
+	reportFixture(f)
+	recordQuietResult(f.Name, f.groupName(), strconv.Itoa(len(f.Points)), hex.EncodeToString(f.Expected))
+	return f, nil
+}
+
+// computeMultiExpFixture is newMultiExpFixture without the report, for recomputing
+// fixtures read from files. A compression divergence from gnark fails the fixture
+// instead of producing a wrong one.
+func computeMultiExpFixture(name string, g1Points []bls.G1Affine, g2Points []bls.G2Affine, scalars []*big.Int, useG2 bool) (multiExpFixture, error) {
+	f := multiExpFixture{Name: name, UseG2: useG2, Scalars: scalars}
+	if useG2 {
+		for i, p := range g2Points {
+			c, err := checkG2Compression(p)
+			if err != nil {
+				return f, fmt.Errorf("fixture %s point %d: %v", name, i, err)
+			}
+			f.Points = append(f.Points, c)
+			f.EthereumInput = append(f.EthereumInput, serialization.EncodeEthereumG2Point(p)...)
+			f.EthereumInput = append(f.EthereumInput, scalarTo32Bytes(scalars[i])...)
+		}
+		result, err := bls12381vec.MultiExpG2(g2Points, scalars[:len(g2Points)])
+		if err != nil {
+			return f, fmt.Errorf("fixture %s: %v", name, err)
+		}
+		if f.Expected, err = checkG2Compression(result); err != nil {
+			return f, fmt.Errorf("fixture %s result: %v", name, err)
+		}
+		f.ExpectedEthereum = serialization.EncodeEthereumG2Point(result)
+	} else {
+		for i, p := range g1Points {
+			c, err := checkG1Compression(p)
+			if err != nil {
+				return f, fmt.Errorf("fixture %s point %d: %v", name, i, err)
+			}
+			f.Points = append(f.Points, c)
+			f.EthereumInput = append(f.EthereumInput, serialization.EncodeEthereumG1Point(p)...)
+			f.EthereumInput = append(f.EthereumInput, scalarTo32Bytes(scalars[i])...)
+		}
+		result, err := bls12381vec.MultiExpG1(g1Points, scalars[:len(g1Points)])
+		if err != nil {
+			return f, fmt.Errorf("fixture %s: %v", name, err)
+		}
+		if f.Expected, err = checkG1Compression(result); err != nil {
+			return f, fmt.Errorf("fixture %s result: %v", name, err)
+		}
+		f.ExpectedEthereum = serialization.EncodeEthereumG1Point(result)
+	}
+	return f, nil
+}
+
+// scalarTo32Bytes encodes a non-negative scalar as 32 bytes big-endian (Ethereum format)
+func scalarTo32Bytes(s *big.Int) []byte {
+	out := make([]byte, 32)
+	s.FillBytes(out)
+	return out
+}
+
+func renderGoFixture(f multiExpFixture) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by pairing_gen.go - fixture %s (%s MultiExp). DO NOT EDIT.\n\n", f.Name, f.groupName())
+	b.WriteString("package fixtures\n\n")
+	fmt.Fprintf(&b, "const UseG2 = %v\n\n", f.UseG2)
+	b.WriteString("var Points = []string{\n")
+	for _, p := range f.Points {
+		fmt.Fprintf(&b, "\t\"%s\",\n", hex.EncodeToString(p))
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("var Scalars = []string{\n")
+	for _, s := range f.Scalars {
+		fmt.Fprintf(&b, "\t\"%s\",\n", s.String())
+	}
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "const EthereumInput = \"%s\"\n\n", hex.EncodeToString(f.EthereumInput))
+	fmt.Fprintf(&b, "const Expected = \"%s\"\n\n", hex.EncodeToString(f.Expected))
+	fmt.Fprintf(&b, "const ExpectedEthereum = \"%s\"\n", hex.EncodeToString(f.ExpectedEthereum))
+	return b.String()
+}
+
+// goIdentifier turns a fixture name such as "g1_duplicate_pair" or "random-0001" into an
+// exported Go identifier prefix ("G1DuplicatePair", "Random0001")
+func goIdentifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		b.WriteRune(r)
+		upper = false
+	}
+	id := b.String()
+	if id == "" || id[0] >= '0' && id[0] <= '9' {
+		id = "Fixture" + id
+	}
+	return id
+}
+
+// writeGoBytes writes data as the elements of a []byte literal, 16 bytes per line
+func writeGoBytes(b *strings.Builder, data []byte, indent string) {
+	for i := 0; i < len(data); i += 16 {
+		b.WriteString(indent)
+		for j, c := range data[i:min(i+16, len(data))] {
+			if j > 0 {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(b, "0x%02x,", c)
+		}
+		b.WriteString("\n")
+	}
+}
+
+func renderGoBytesFixture(f multiExpFixture) string {
+	id := goIdentifier(f.Name)
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by pairing_gen.go - fixture %s (%s MultiExp). DO NOT EDIT.\n\n", f.Name, f.groupName())
+	b.WriteString("package fixtures\n\n")
+	fmt.Fprintf(&b, "// %sUseG2 reports whether the points are G2 points\n", id)
+	fmt.Fprintf(&b, "const %sUseG2 = %v\n\n", id, f.UseG2)
+	fmt.Fprintf(&b, "// %sPoints are the compressed points (%d bytes each)\n", id, len(f.Points[0]))
+	fmt.Fprintf(&b, "var %sPoints = [][]byte{\n", id)
+	for _, p := range f.Points {
+		b.WriteString("\t{\n")
+		writeGoBytes(&b, p, "\t\t")
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "// %sScalars are the scalars as 32-byte big-endian values\n", id)
+	fmt.Fprintf(&b, "var %sScalars = [][]byte{\n", id)
+	for _, s := range f.Scalars {
+		fmt.Fprintf(&b, "\t{ // %s\n", s.String())
+		writeGoBytes(&b, scalarTo32Bytes(s), "\t\t")
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n\n")
+	for _, c := range []struct {
+		name, doc string
+		data      []byte
+	}{
+		{"EthereumInput", "is the EIP-2537 MSM input", f.EthereumInput},
+		{"Expected", "is the compressed MultiExp result", f.Expected},
+		{"ExpectedEthereum", "is the MultiExp result in Ethereum format", f.ExpectedEthereum},
+	} {
+		fmt.Fprintf(&b, "// %s%s %s (%d bytes)\n", id, c.name, c.doc, len(c.data))
+		fmt.Fprintf(&b, "var %s%s = []byte{\n", id, c.name)
+		writeGoBytes(&b, c.data, "\t")
+		b.WriteString("}\n")
+		if c.name != "ExpectedEthereum" {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderGoTestFixture renders a golden test that recomputes the fixture through this
+// package's own paths: compressed point decoding, newMultiExpFixture (compression, Ethereum
+// encoding, MultiExp) and the EIP-2537 MSM precompile.
+func renderGoTestFixture(f multiExpFixture) string {
+	id := goIdentifier(f.Name)
+	g1, g2 := "points", "nil"
+	if f.UseG2 {
+		g1, g2 = "nil", "points"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by pairing_gen.go - fixture %s (%s MultiExp). DO NOT EDIT.\n\n", f.Name, f.groupName())
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n\t\"encoding/hex\"\n\t\"fmt\"\n\t\"math/big\"\n\t\"testing\"\n\n")
+	b.WriteString("\tbls \"github.com/consensys/gnark-crypto/ecc/bls12-381\"\n)\n\n")
+	fmt.Fprintf(&b, "// TestGolden%s recomputes fixture %s with the generator and the EIP-2537 MSM\n", id, f.Name)
+	fmt.Fprintf(&b, "func TestGolden%s(t *testing.T) {\n", id)
+	b.WriteString("\tpointHexes := []string{\n")
+	for _, p := range f.Points {
+		fmt.Fprintf(&b, "\t\t\"%x\",\n", p)
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("\tscalars := []string{\n")
+	for _, s := range f.Scalars {
+		fmt.Fprintf(&b, "\t\t\"%s\",\n", s.String())
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("\tconst (\n")
+	fmt.Fprintf(&b, "\t\tethereumInput    = \"%x\"\n", f.EthereumInput)
+	fmt.Fprintf(&b, "\t\texpected         = \"%x\"\n", f.Expected)
+	fmt.Fprintf(&b, "\t\texpectedEthereum = \"%x\"\n", f.ExpectedEthereum)
+	b.WriteString("\t)\n\n")
+	fmt.Fprintf(&b, "\tvar points []bls.%sAffine\n", f.groupName())
+	b.WriteString("\tks := make([]*big.Int, len(scalars))\n")
+	b.WriteString("\tfor i := range pointHexes {\n")
+	b.WriteString("\t\tdata, err := hex.DecodeString(pointHexes[i])\n")
+	b.WriteString("\t\tif err != nil {\n\t\t\tt.Fatalf(\"point %d: %v\", i, err)\n\t\t}\n")
+	fmt.Fprintf(&b, "\t\tvar p bls.%sAffine\n", f.groupName())
+	b.WriteString("\t\tif _, err := p.SetBytes(data); err != nil {\n\t\t\tt.Fatalf(\"point %d: %v\", i, err)\n\t\t}\n")
+	b.WriteString("\t\tpoints = append(points, p)\n")
+	b.WriteString("\t\tk, ok := new(big.Int).SetString(scalars[i], 10)\n")
+	b.WriteString("\t\tif !ok {\n\t\t\tt.Fatalf(\"scalar %d: invalid decimal %s\", i, scalars[i])\n\t\t}\n")
+	b.WriteString("\t\tks[i] = k\n")
+	b.WriteString("\t}\n")
+	fmt.Fprintf(&b, "\tf, err := newMultiExpFixture(%q, %s, %s, ks, %v)\n", f.Name, g1, g2, f.UseG2)
+	b.WriteString("\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	b.WriteString("\tinput, err := hex.DecodeString(ethereumInput)\n")
+	b.WriteString("\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	fmt.Fprintf(&b, "\tmsm, err := eip2537MSM(%v)(input)\n", f.UseG2)
+	b.WriteString("\tif err != nil {\n\t\tt.Fatalf(\"EIP-2537 MSM rejected the input: %v\", err)\n\t}\n\n")
+	b.WriteString("\ttype goldenCase struct {\n\t\tname string\n\t\tgot  []byte\n\t\twant string\n\t}\n")
+	b.WriteString("\ttests := []goldenCase{\n")
+	b.WriteString("\t\t{\"Ethereum input\", f.EthereumInput, ethereumInput},\n")
+	b.WriteString("\t\t{\"MultiExp\", f.Expected, expected},\n")
+	b.WriteString("\t\t{\"MultiExp Ethereum\", f.ExpectedEthereum, expectedEthereum},\n")
+	b.WriteString("\t\t{\"EIP-2537 MSM\", msm, expectedEthereum},\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\tfor i, p := range f.Points {\n")
+	b.WriteString("\t\ttests = append(tests, goldenCase{fmt.Sprintf(\"point %d compression\", i), p, pointHexes[i]})\n")
+	b.WriteString("\t}\n")
+	b.WriteString("\tfor _, tt := range tests {\n")
+	b.WriteString("\t\tt.Run(tt.name, func(t *testing.T) {\n")
+	b.WriteString("\t\t\tif got := hex.EncodeToString(tt.got); got != tt.want {\n")
+	b.WriteString("\t\t\t\tt.Errorf(\"got  %s\\nwant %s\", got, tt.want)\n")
+	b.WriteString("\t\t\t}\n")
+	b.WriteString("\t\t})\n")
+	b.WriteString("\t}\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderRustFixture(f multiExpFixture) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by pairing_gen.go - fixture %s (%s MultiExp)\n\n", f.Name, f.groupName())
+	fmt.Fprintf(&b, "pub const USE_G2: bool = %v;\n\n", f.UseG2)
+	fmt.Fprintf(&b, "pub const POINTS: [&str; %d] = [\n", len(f.Points))
+	for _, p := range f.Points {
+		fmt.Fprintf(&b, "    \"%s\",\n", hex.EncodeToString(p))
+	}
+	b.WriteString("];\n\n")
+	fmt.Fprintf(&b, "pub const SCALARS: [&str; %d] = [\n", len(f.Scalars))
+	for _, s := range f.Scalars {
+		fmt.Fprintf(&b, "    \"%s\",\n", s.String())
+	}
+	b.WriteString("];\n\n")
+	// bls12_381::Scalar::from_bytes takes canonical little-endian bytes
+	b.WriteString("// SCALARS mod r, 32 bytes little-endian\n")
+	fmt.Fprintf(&b, "pub const SCALARS_LE: [&str; %d] = [\n", len(f.Scalars))
+	for _, s := range f.Scalars {
+		fmt.Fprintf(&b, "    \"%s\",\n", hex.EncodeToString(reverseBytes(scalarTo32Bytes(new(big.Int).Mod(s, fr.Modulus())))))
+	}
+	b.WriteString("];\n\n")
+	fmt.Fprintf(&b, "pub const ETHEREUM_INPUT: &str = \"%s\";\n", hex.EncodeToString(f.EthereumInput))
+	fmt.Fprintf(&b, "pub const EXPECTED: &str = \"%s\";\n", hex.EncodeToString(f.Expected))
+	fmt.Fprintf(&b, "pub const EXPECTED_ETHEREUM: &str = \"%s\";\n", hex.EncodeToString(f.ExpectedEthereum))
+
+	// The zkcrypto bls12_381 crate uses the same ZCash compressed encoding as Neo
+	// (arkworks' flag bits differ), so its from_compressed/to_compressed round-trip the fixture
+	g, size := f.groupName(), len(f.Expected)
+	b.WriteString("\n// Needs the bls12_381 crate as a dev-dependency\n")
+	b.WriteString("#[cfg(test)]\nmod tests {\n")
+	b.WriteString("    use super::*;\n")
+	fmt.Fprintf(&b, "    use bls12_381::{%sAffine, %sProjective, Scalar};\n\n", g, g)
+	b.WriteString("    fn unhex<const N: usize>(s: &str) -> [u8; N] {\n")
+	b.WriteString("        let digits = s.as_bytes();\n")
+	b.WriteString("        assert_eq!(digits.len(), 2 * N, \"hex length\");\n")
+	b.WriteString("        let mut out = [0u8; N];\n")
+	b.WriteString("        for (i, pair) in digits.chunks(2).enumerate() {\n")
+	b.WriteString("            out[i] = u8::from_str_radix(std::str::from_utf8(pair).unwrap(), 16).unwrap();\n")
+	b.WriteString("        }\n")
+	b.WriteString("        out\n")
+	b.WriteString("    }\n\n")
+	b.WriteString("    #[test]\n")
+	b.WriteString("    fn multi_exp_matches_expected() {\n")
+	fmt.Fprintf(&b, "        let mut acc = %sProjective::identity();\n", g)
+	b.WriteString("        for (p, s) in POINTS.iter().zip(SCALARS_LE.iter()) {\n")
+	fmt.Fprintf(&b, "            let point = %sAffine::from_compressed(&unhex::<%d>(p)).unwrap();\n", g, size)
+	b.WriteString("            let scalar = Scalar::from_bytes(&unhex::<32>(s)).unwrap();\n")
+	b.WriteString("            acc += point * scalar;\n")
+	b.WriteString("        }\n")
+	fmt.Fprintf(&b, "        assert_eq!(%sAffine::from(acc).to_compressed(), unhex::<%d>(EXPECTED));\n", g, size)
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderSolidityFixture(f multiExpFixture) string {
+	var b strings.Builder
+	b.WriteString("// SPDX-License-Identifier: MIT\n")
+	fmt.Fprintf(&b, "// Generated by pairing_gen.go - fixture %s (%s MultiExp)\n", f.Name, f.groupName())
+	b.WriteString("pragma solidity ^0.8.0;\n\n")
+	b.WriteString("import {Test} from \"forge-std/Test.sol\";\n\n")
+	b.WriteString("library Bls12381Fixtures {\n")
+	// EIP-2537 precompile addresses: G1MSM = 0x0c, G2MSM = 0x0e
+	precompile := "0x0c"
+	if f.UseG2 {
+		precompile = "0x0e"
+	}
+	gas, _ := gasEIP2537.gasCost(strings.ToLower(f.groupName())+"msm", len(f.Points))
+	fmt.Fprintf(&b, "    address internal constant MSM_PRECOMPILE = address(%s);\n", precompile)
+	fmt.Fprintf(&b, "    uint256 internal constant GAS = %d;\n", gas)
+	fmt.Fprintf(&b, "    bytes internal constant INPUT = hex\"%s\";\n", hex.EncodeToString(f.EthereumInput))
+	fmt.Fprintf(&b, "    bytes internal constant EXPECTED = hex\"%s\";\n", hex.EncodeToString(f.ExpectedEthereum))
+	b.WriteString("}\n\n")
+
+	// Foundry test; a precompile that runs out of gas consumes the gas it was given and fails
+	// the call, so GAS - 1 must fail where GAS succeeds
+	name := goIdentifier(f.Name)
+	fmt.Fprintf(&b, "contract %sBls12381Test is Test {\n", name)
+	fmt.Fprintf(&b, "    function test_%s_MSM() public view {\n", name)
+	b.WriteString("        (bool ok, bytes memory out) = Bls12381Fixtures.MSM_PRECOMPILE.staticcall(Bls12381Fixtures.INPUT);\n")
+	b.WriteString("        assertTrue(ok, \"MSM precompile call failed\");\n")
+	b.WriteString("        assertEq(out, Bls12381Fixtures.EXPECTED);\n")
+	b.WriteString("    }\n\n")
+	fmt.Fprintf(&b, "    function test_%s_Gas() public view {\n", name)
+	b.WriteString("        (bool ok,) = Bls12381Fixtures.MSM_PRECOMPILE.staticcall{gas: Bls12381Fixtures.GAS}(Bls12381Fixtures.INPUT);\n")
+	b.WriteString("        assertTrue(ok, \"MSM precompile failed with GAS\");\n")
+	b.WriteString("        (ok,) = Bls12381Fixtures.MSM_PRECOMPILE.staticcall{gas: Bls12381Fixtures.GAS - 1}(Bls12381Fixtures.INPUT);\n")
+	b.WriteString("        assertFalse(ok, \"MSM precompile succeeded with GAS - 1\");\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderPythonFixture(f multiExpFixture) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by pairing_gen.go - fixture %s (%s MultiExp)\n\n", f.Name, f.groupName())
+	// py_ecc is only needed by the test, so importing the constants stays dependency-free
+	b.WriteString("# test_multi_exp needs py_ecc; run with pytest or as a script\n\n")
+	useG2 := "False"
+	if f.UseG2 {
+		useG2 = "True"
+	}
+	fmt.Fprintf(&b, "USE_G2 = %s\n\n", useG2)
+	b.WriteString("POINTS = [\n")
+	for _, p := range f.Points {
+		fmt.Fprintf(&b, "    \"%s\",\n", hex.EncodeToString(p))
+	}
+	b.WriteString("]\n\n")
+	b.WriteString("SCALARS = [\n")
+	for _, s := range f.Scalars {
+		fmt.Fprintf(&b, "    %s,\n", s.String())
+	}
+	b.WriteString("]\n\n")
+	fmt.Fprintf(&b, "ETHEREUM_INPUT = bytes.fromhex(\"%s\")\n", hex.EncodeToString(f.EthereumInput))
+	fmt.Fprintf(&b, "EXPECTED = bytes.fromhex(\"%s\")\n", hex.EncodeToString(f.Expected))
+	fmt.Fprintf(&b, "EXPECTED_ETHEREUM = bytes.fromhex(\"%s\")\n", hex.EncodeToString(f.ExpectedEthereum))
+	b.WriteString(pythonMultiExpTest)
+	return b.String()
+}
+
+// pythonMultiExpTest recomputes EXPECTED with py_ecc. A compressed G2 point is the pair
+// (x.c1 with the flags, x.c0) there, in the byte order of the 96-byte encoding.
+const pythonMultiExpTest = `
+
+def _decompress(data):
+    from py_ecc.bls.point_compression import decompress_G1, decompress_G2
+
+    if USE_G2:
+        return decompress_G2((int.from_bytes(data[:48], "big"), int.from_bytes(data[48:], "big")))
+    return decompress_G1(int.from_bytes(data, "big"))
+
+
+def _compress(point):
+    from py_ecc.bls.point_compression import compress_G1, compress_G2
+
+    if USE_G2:
+        z1, z2 = compress_G2(point)
+        return z1.to_bytes(48, "big") + z2.to_bytes(48, "big")
+    return compress_G1(point).to_bytes(48, "big")
+
+
+def test_multi_exp():
+    from py_ecc.optimized_bls12_381 import Z1, Z2, add, curve_order, multiply
+
+    acc = Z2 if USE_G2 else Z1
+    for point, scalar in zip(POINTS, SCALARS):
+        acc = add(acc, multiply(_decompress(bytes.fromhex(point)), scalar % curve_order))
+    assert _compress(acc) == EXPECTED
+
+
+if __name__ == "__main__":
+    test_multi_exp()
+    print("MultiExp matches EXPECTED")
+`
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// emptyInputPolicy defines what an operation returns for an empty input
+type emptyInputPolicy string
+
+const (
+	// emptyInputError rejects empty input (EIP-2537: "invalid input length")
+	emptyInputError emptyInputPolicy = "error"
+	// emptyInputIdentity returns the identity: infinity for MSM, true (0x..01) for pairing
+	emptyInputIdentity emptyInputPolicy = "identity"
+)
+
+// emptyInputRules bundles the empty-input behavior of one chain profile
+type emptyInputRules struct {
+	Description  string
+	MSMEmpty     emptyInputPolicy
+	PairingEmpty emptyInputPolicy
+}
+
+// emptyInputProfiles lists the known chain profiles
+//   - eip2537: the precompiles reject every empty input
+//   - neo: CryptoLib faults on an empty MultiExp pair list, while Bls12Pairing returns true
+//   - gnark: mathematical convention, the empty sum/product is the identity
+var emptyInputProfiles = map[string]emptyInputRules{
+	"eip2537": {Description: "EIP-2537 precompiles", MSMEmpty: emptyInputError, PairingEmpty: emptyInputError},
+	"neo":     {Description: "Neo N3 CryptoLib", MSMEmpty: emptyInputError, PairingEmpty: emptyInputIdentity},
+	"gnark":   {Description: "gnark-crypto / mathematical identity", MSMEmpty: emptyInputIdentity, PairingEmpty: emptyInputIdentity},
+}
+
+func emptyInputProfileNames() []string {
+	names := make([]string, 0, len(emptyInputProfiles))
+	for name := range emptyInputProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseEmptyInputPolicy(s string) (emptyInputPolicy, error) {
+	switch emptyInputPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case emptyInputError:
+		return emptyInputError, nil
+	case emptyInputIdentity:
+		return emptyInputIdentity, nil
+	}
+	return "", fmt.Errorf("invalid empty-input policy '%s' (valid: error, identity)", s)
+}
+
+// resolveEmptyInputPolicy picks the policy for an operation from --profile, letting --empty override it
+func resolveEmptyInputPolicy(profile string, override string, isPairing bool) (emptyInputPolicy, error) {
+	if override != "" {
+		return parseEmptyInputPolicy(override)
+	}
+	rules, ok := emptyInputProfiles[profile]
+	if !ok {
+		return "", fmt.Errorf("unknown profile '%s' (valid: %s)", profile, strings.Join(emptyInputProfileNames(), ", "))
+	}
+	if isPairing {
+		return rules.PairingEmpty, nil
+	}
+	return rules.MSMEmpty, nil
+}
+
+// emptyInputResult returns the result of an operation on empty input under the given policy
+// op is one of "g1msm", "g2msm", "pairing"
+func emptyInputResult(op string, policy emptyInputPolicy) (string, error) {
+	if policy == emptyInputError {
+		return "", fmt.Errorf("%s: empty input is invalid (invalid input length)", op)
+	}
+	switch op {
+	case "g1msm":
+		return hex.EncodeToString(make([]byte, 128)), nil
+	case "g2msm":
+		return hex.EncodeToString(make([]byte, 256)), nil
+	case "pairing":
+		result := make([]byte, 32)
+		result[31] = 1
+		return hex.EncodeToString(result), nil
+	}
+	return "", fmt.Errorf("operation %s has no empty-input semantics", op)
+}
+
+// isFlagSet reports whether a flag was given explicitly (so --input "" can mean empty input)
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// runEmptyInputVectors prints the empty-input and zero-pair vectors for one or all profiles
+func runEmptyInputVectors(args []string) error {
+	fs := newFlagSet("empty-input-vectors")
+	profile := fs.String("profile", "", "Profile to print (eip2537, neo, gnark; default: all)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	profiles := emptyInputProfileNames()
+	if *profile != "" {
+		if _, ok := emptyInputProfiles[*profile]; !ok {
+			return fmt.Errorf("unknown profile '%s' (valid: %s)", *profile, strings.Join(profiles, ", "))
+		}
+		profiles = []string{*profile}
+	}
+
+	// Zero-pair inputs: every pair is well-formed but contributes nothing.
+	// These are valid in every profile and must produce the identity.
+	_, _, g1Gen, g2Gen := bls.Generators()
+	zeroScalar := make([]byte, 32)
+	g1InfinityZero := hex.EncodeToString(append(make([]byte, 128), zeroScalar...))
+	g1GenZero := hex.EncodeToString(append(serialization.EncodeEthereumG1Point(g1Gen), zeroScalar...))
+	g2InfinityZero := hex.EncodeToString(append(make([]byte, 256), zeroScalar...))
+	g2GenZero := hex.EncodeToString(append(serialization.EncodeEthereumG2Point(g2Gen), zeroScalar...))
+	pairingInfinity := hex.EncodeToString(make([]byte, 384))
+	pairingG1Infinity := hex.EncodeToString(append(make([]byte, 128), serialization.EncodeEthereumG2Point(g2Gen)...))
+
+	zeroPairCases := []struct{ name, op, input string }{
+		{"g1msm_infinity_zero_scalar", "g1msm", g1InfinityZero},
+		{"g1msm_generator_zero_scalar", "g1msm", g1GenZero},
+		{"g2msm_infinity_zero_scalar", "g2msm", g2InfinityZero},
+		{"g2msm_generator_zero_scalar", "g2msm", g2GenZero},
+		{"pairing_both_infinity", "pairing", pairingInfinity},
+		{"pairing_g1_infinity", "pairing", pairingG1Infinity},
+	}
+
+	for _, name := range profiles {
+		rules := emptyInputProfiles[name]
+		fmt.Printf("=== Profile: %s (%s) ===\n", name, rules.Description)
+		fmt.Printf("MSM empty input: %s\n", rules.MSMEmpty)
+		fmt.Printf("Pairing empty input: %s\n", rules.PairingEmpty)
+		fmt.Println()
+
+		fmt.Println("Empty input vectors:")
+		for _, op := range []string{"g1msm", "g2msm", "pairing"} {
+			policy := rules.MSMEmpty
+			if op == "pairing" {
+				policy = rules.PairingEmpty
+			}
+			result, err := emptyInputResult(op, policy)
+			if err != nil {
+				result = "ERROR"
+			}
+			fmt.Printf("  %s_empty: input=\"\" expected=%s\n", op, result)
+			recordQuietResult(name, op+"_empty", result)
+		}
+		fmt.Println()
+
+		fmt.Println("Zero-pair vectors:")
+		for _, c := range zeroPairCases {
+			var result string
+			var err error
+			switch c.op {
+			case "g1msm":
+				result, err = bls12381vec.G1MSM(c.input)
+			case "g2msm":
+				result, err = bls12381vec.G2MSM(c.input)
+			case "pairing":
+				result, err = bls12381vec.Pairing(c.input)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %v", c.name, err)
+			}
+			fmt.Printf("  %s: input=%s expected=%s\n", c.name, c.input, result)
+			recordQuietResult(name, c.name, result)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"evm/serialization"
+)
+
+// errorCode classifies why an encoded input is rejected. The same codes are used by
+// every negative vector so downstream implementations can be compared on the class of
+// error rather than on library-specific messages.
+type errorCode string
+
+const (
+	errBadLength     errorCode = "BAD_LENGTH"      // input or point has the wrong number of bytes
+	errBadPadding    errorCode = "BAD_PADDING"     // non-zero bytes in the 16-byte Ethereum padding
+	errNonCanonical  errorCode = "NON_CANONICAL"   // field element >= p
+	errBadFlags      errorCode = "BAD_FLAGS"       // invalid compression / infinity / sort flag combination
+	errNotOnCurve    errorCode = "NOT_ON_CURVE"    // coordinates do not satisfy the curve equation
+	errNotInSubgroup errorCode = "NOT_IN_SUBGROUP" // on the curve but outside the prime-order subgroup
+)
+
+// errorTaxonomy lists every code in reporting order with its meaning; fixture files embed
+// it so they can be read without this tool
+var errorTaxonomy = []struct {
+	Code        errorCode `json:"code"`
+	Description string    `json:"description"`
+}{
+	{errBadLength, "input or point has the wrong number of bytes"},
+	{errBadPadding, "non-zero bytes in the 16-byte Ethereum padding"},
+	{errNonCanonical, "field element >= p"},
+	{errBadFlags, "invalid compression / infinity / sort flag combination"},
+	{errNotOnCurve, "coordinates do not satisfy the curve equation"},
+	{errNotInSubgroup, "on the curve but outside the prime-order subgroup"},
+}
+
+// parseErrorCode accepts a taxonomy code in any letter case
+func parseErrorCode(s string) (errorCode, bool) {
+	for _, t := range errorTaxonomy {
+		if strings.EqualFold(string(t.Code), strings.TrimSpace(s)) {
+			return t.Code, true
+		}
+	}
+	return "", false
+}
+
+// classifyError maps an error from the serialization decoders onto the taxonomy.
+// ok is false when the error does not belong to any category.
+func classifyError(err error) (code errorCode, ok bool) {
+	switch {
+	case err == nil:
+		return "", false
+	case errors.Is(err, serialization.ErrInvalidLength):
+		return errBadLength, true
+	case errors.Is(err, serialization.ErrNonZeroPadding):
+		return errBadPadding, true
+	case errors.Is(err, serialization.ErrNonCanonical):
+		return errNonCanonical, true
+	case errors.Is(err, serialization.ErrBadFlags):
+		return errBadFlags, true
+	case errors.Is(err, serialization.ErrNotOnCurve):
+		return errNotOnCurve, true
+	case errors.Is(err, serialization.ErrNotInSubgroup):
+		return errNotInSubgroup, true
+	}
+	return "", false
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"evm/serialization"
+)
+
+// negativeFixtureFormat identifies the self-describing fixture file layout
+const negativeFixtureFormat = "bls12381-error-fixtures/v1"
+
+// negativeFixture is one input with the verdict a conforming implementation must return.
+// Rejected inputs name the taxonomy code; the concrete message is informational only.
+type negativeFixture struct {
+	Name      string    `json:"name"`
+	Op        string    `json:"op"` // deserialize-g1/g2, eip2537-g1/g2, g1msm, g2msm, pairing
+	Profile   string    `json:"profile,omitempty"`
+	Input     string    `json:"input"`
+	Expected  string    `json:"expected"` // "accept" or "reject"
+	ErrorCode errorCode `json:"error_code,omitempty"`
+	Pair      *int      `json:"pair,omitempty"` // pair index of the error, for multi-pair inputs
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// negativeFixtureFile carries the taxonomy next to the fixtures, so a consumer in another
+// language can interpret the codes without this tool
+type negativeFixtureFile struct {
+	Format   string            `json:"format"`
+	Source   string            `json:"source"`
+	Taxonomy any               `json:"taxonomy"`
+	Fixtures []negativeFixture `json:"fixtures"`
+}
+
+// recordQuietFixtures adds one --quiet result line per fixture: its name, operation,
+// profile if any and expected verdict, followed by the error code of a rejection
+func recordQuietFixtures(fixtures []negativeFixture) {
+	for _, fx := range fixtures {
+		fields := []string{fx.Name, fx.Op}
+		if fx.Profile != "" {
+			fields = append(fields, fx.Profile)
+		}
+		fields = append(fields, fx.Expected)
+		if fx.ErrorCode != "" {
+			fields = append(fields, string(fx.ErrorCode))
+		}
+		recordQuietResult(fields...)
+	}
+}
+
+// writeNegativeFixtures writes fixtures as a self-describing JSON file
+func writeNegativeFixtures(path, source string, fixtures []negativeFixture) error {
+	data, err := json.MarshalIndent(negativeFixtureFile{
+		Format:   negativeFixtureFormat,
+		Source:   source,
+		Taxonomy: errorTaxonomy,
+		Fixtures: fixtures,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	reportOutput("fixture_file", path)
+	return nil
+}
+
+// readNegativeFixtures loads a fixture file and checks its format tag and codes
+func readNegativeFixtures(path string) (negativeFixtureFile, error) {
+	var f negativeFixtureFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("invalid fixture file %s: %v", path, err)
+	}
+	if f.Format != negativeFixtureFormat {
+		return f, fmt.Errorf("%s: unsupported format '%s' (want %s)", path, f.Format, negativeFixtureFormat)
+	}
+	for _, fx := range f.Fixtures {
+		if fx.Expected == "reject" {
+			if _, ok := parseErrorCode(string(fx.ErrorCode)); !ok {
+				return f, fmt.Errorf("%s: fixture %s has unknown error code '%s'", path, fx.Name, fx.ErrorCode)
+			}
+		}
+	}
+	return f, nil
+}
+
+// errorMapRule maps a concrete error message onto the taxonomy: the first rule whose
+// pattern occurs in the message (case-insensitive) wins
+type errorMapRule struct {
+	Pattern string    `json:"pattern"`
+	Code    errorCode `json:"code"`
+}
+
+// builtinErrorMaps are the messages of implementations whose errors carry a category.
+// Neo throws a bare FormatException for most defects, so its harness has to log a
+// category itself and pass the mapping with --map.
+var builtinErrorMaps = map[string][]errorMapRule{
+	// gnark-crypto SetBytes / fp.Element decoding
+	"gnark": {
+		{"subgroup check failed", errNotInSubgroup},
+		{"square root doesn't exist", errNotOnCurve},
+		{"not on curve", errNotOnCurve},
+		{"invalid fp.element encoding", errNonCanonical},
+		{"invalid infinity point encoding", errBadFlags},
+		{"invalid point encoding", errBadFlags},
+		{"short buffer", errBadLength},
+		{"unexpected eof", errBadLength},
+	},
+	// go-ethereum BLS12-381 precompiles (EIP-2537)
+	"geth": {
+		{"invalid input length", errBadLength},
+		{"invalid field element top bytes", errBadPadding},
+		{"invalid fp.element encoding", errNonCanonical},
+		{"must be less than modulus", errNonCanonical},
+		{"not on correct subgroup", errNotInSubgroup},
+		{"not on curve", errNotOnCurve},
+	},
+	// this tool's serialization package
+	"go": {
+		{serialization.ErrInvalidLength.Error(), errBadLength},
+		{serialization.ErrNonZeroPadding.Error(), errBadPadding},
+		{serialization.ErrNonCanonical.Error(), errNonCanonical},
+		{serialization.ErrBadFlags.Error(), errBadFlags},
+		{serialization.ErrNotOnCurve.Error(), errNotOnCurve},
+		{serialization.ErrNotInSubgroup.Error(), errNotInSubgroup},
+	},
+}
+
+func builtinErrorMapNames() []string {
+	names := make([]string, 0, len(builtinErrorMaps))
+	for name := range builtinErrorMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadErrorMap reads user rules (a JSON array of {pattern, code}); they are tried before
+// the built-in rules of --impl
+func loadErrorMap(path string) ([]errorMapRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []errorMapRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid error map %s: %v", path, err)
+	}
+	for i, r := range rules {
+		code, ok := parseErrorCode(string(r.Code))
+		if !ok || r.Pattern == "" {
+			return nil, fmt.Errorf("%s: rule %d needs a pattern and a taxonomy code, got %q -> %q", path, i, r.Pattern, r.Code)
+		}
+		rules[i].Code = code
+	}
+	return rules, nil
+}
+
+// mapError returns the taxonomy code for a concrete message (false if no rule matches)
+func mapError(rules []errorMapRule, message string) (errorCode, bool) {
+	lower := strings.ToLower(message)
+	if code, ok := parseErrorCode(message); ok {
+		// The implementation already reports taxonomy codes
+		return code, true
+	}
+	for _, r := range rules {
+		if strings.Contains(lower, strings.ToLower(r.Pattern)) {
+			return r.Code, true
+		}
+	}
+	return "", false
+}
+
+// readErrorResults reads an implementation's results as CSV rows "name,error"; an empty
+// error means the input was accepted. A header row "name,error" is skipped.
+func readErrorResults(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	results := map[string]string{}
+	for line := 1; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if line == 1 && len(rec) >= 1 && strings.EqualFold(rec[0], "name") {
+			continue
+		}
+		if len(rec) == 0 || rec[0] == "" {
+			continue
+		}
+		msg := ""
+		if len(rec) > 1 {
+			msg = strings.Join(rec[1:], ",")
+		}
+		results[rec[0]] = strings.TrimSpace(msg)
+	}
+	return results, nil
+}
+
+// decodeFixtureWithGo evaluates a fixture with this tool's strict decoders, for --self
+func decodeFixtureWithGo(fx negativeFixture) (string, error) {
+	data, err := hex.DecodeString(fx.Input)
+	if err != nil {
+		return "", err
+	}
+	switch fx.Op {
+	case "deserialize-g1":
+		_, err = serialization.DecodeCompressedG1Point(data)
+	case "deserialize-g2":
+		_, err = serialization.DecodeCompressedG2Point(data)
+	case "eip2537-g1":
+		_, err = serialization.DecodeEIP2537G1Point(data, true)
+	case "eip2537-g2":
+		_, err = serialization.DecodeEIP2537G2Point(data, true)
+	default:
+		profile := fx.Profile
+		if profile == "" {
+			profile = "eip2537"
+		}
+		code, _, applicable := firstValidationError(profile, fx.Op, data)
+		if !applicable {
+			return "", fmt.Errorf("operation %s is not defined for profile %s", fx.Op, profile)
+		}
+		return string(code), nil
+	}
+	if err != nil {
+		return err.Error(), nil
+	}
+	return "", nil
+}
+
+// runVerifyErrorsMode compares an implementation's concrete errors with the fixture codes
+func runVerifyErrorsMode(args []string) (int, error) {
+	fs := newFlagSet("verify-errors")
+	fixturesPath := fs.String("fixtures", "", "Fixture file written by corrupt-nearby or validation-order --out <file.json>")
+	resultsPath := fs.String("results", "", "Implementation results as CSV rows name,error (empty error = accepted)")
+	impl := fs.String("impl", "", "Built-in message map: "+strings.Join(builtinErrorMapNames(), ", "))
+	mapPath := fs.String("map", "", "JSON array of {\"pattern\": ..., \"code\": ...} rules, tried before --impl")
+	profile := fs.String("profile", "", "Only check fixtures of this profile")
+	self := fs.Bool("self", false, "Decode the fixtures with this tool instead of reading --results")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *fixturesPath == "" {
+		return 0, usageErrorf("--fixtures is required")
+	}
+	if *self == (*resultsPath != "") {
+		return 0, usageErrorf("give exactly one of --results and --self")
+	}
+
+	var rules []errorMapRule
+	if *mapPath != "" {
+		user, err := loadErrorMap(*mapPath)
+		if err != nil {
+			return 0, err
+		}
+		rules = append(rules, user...)
+	}
+	if *self && *impl == "" {
+		*impl = "go"
+	}
+	if *impl != "" {
+		builtin, ok := builtinErrorMaps[*impl]
+		if !ok {
+			return 0, usageErrorf("unknown --impl '%s' (valid: %s)", *impl, strings.Join(builtinErrorMapNames(), ", "))
+		}
+		rules = append(rules, builtin...)
+	}
+
+	file, err := readNegativeFixtures(*fixturesPath)
+	if err != nil {
+		return 0, err
+	}
+	var results map[string]string
+	if !*self {
+		if results, err = readErrorResults(*resultsPath); err != nil {
+			return 0, err
+		}
+	}
+
+	fmt.Printf("=== Verify Errors (%s, %d fixtures) ===\n", file.Source, len(file.Fixtures))
+	var passed, failed, missing, unmapped int
+	for _, fx := range file.Fixtures {
+		if *profile != "" && fx.Profile != *profile {
+			continue
+		}
+		key := fx.Name
+		if fx.Profile != "" {
+			key = fx.Name + "@" + fx.Profile
+		}
+		var got string
+		if *self {
+			if got, err = decodeFixtureWithGo(fx); err != nil {
+				return 0, fmt.Errorf("%s: %v", key, err)
+			}
+		} else {
+			var ok bool
+			if got, ok = results[key]; !ok {
+				if got, ok = results[fx.Name]; !ok {
+					fmt.Printf("⚠️  %s: no result\n", key)
+					missing++
+					continue
+				}
+			}
+		}
+
+		want := "accept"
+		if fx.Expected == "reject" {
+			want = string(fx.ErrorCode)
+		}
+		verdict := "accept"
+		if got != "" {
+			code, ok := mapError(rules, got)
+			if !ok {
+				fmt.Printf("⚠️  %s: unmapped error %q (expected %s)\n", key, got, want)
+				unmapped++
+				continue
+			}
+			verdict = string(code)
+		}
+		if verdict == want {
+			passed++
+			continue
+		}
+		failed++
+		fmt.Printf("❌ %s: expected %s, got %s", key, want, verdict)
+		if got != "" {
+			fmt.Printf(" (%q)", got)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Passed: %d, failed: %d, unmapped: %d, missing: %d\n", passed, failed, unmapped, missing)
+	fmt.Printf("Taxonomy: %s\n", taxonomyCodes())
+	if failed == 0 && unmapped == 0 && missing == 0 {
+		fmt.Println("✅ Every error maps onto the expected taxonomy code")
+	}
+	return failed + unmapped + missing, nil
+}
+
+// taxonomyCodes lists the codes for the summary line
+func taxonomyCodes() string {
+	codes := make([]string, len(errorTaxonomy))
+	for i, t := range errorTaxonomy {
+		codes[i] = string(t.Code)
+	}
+	return strings.Join(codes, ", ")
+}
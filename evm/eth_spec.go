@@ -0,0 +1,546 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"gopkg.in/yaml.v3"
+)
+
+// The consensus-spec BLS tests (tests/general/phase0/bls in ethereum/consensus-spec-tests)
+// are one data.yaml per case, at <handler>/bls/<case>/data.yaml, with an input and the
+// expected output: a 0x-prefixed hex value, a bool, or null when the operation must
+// fail. They use the min-pk ciphersuite with the proof-of-possession scheme, as the
+// beacon chain does. eth-spec-run replays them against the local implementation and
+// eth-spec-gen writes new cases in the same layout.
+
+type specSignInput struct {
+	Privkey string `yaml:"privkey"`
+	Message string `yaml:"message"`
+}
+
+type specVerifyInput struct {
+	Pubkey    string `yaml:"pubkey"`
+	Message   string `yaml:"message"`
+	Signature string `yaml:"signature"`
+}
+
+type specFastAggregateVerifyInput struct {
+	Pubkeys   []string `yaml:"pubkeys"`
+	Message   string   `yaml:"message"`
+	Signature string   `yaml:"signature"`
+}
+
+type specAggregateVerifyInput struct {
+	Pubkeys   []string `yaml:"pubkeys"`
+	Messages  []string `yaml:"messages"`
+	Signature string   `yaml:"signature"`
+}
+
+// specCase is a data.yaml document; Output is a hex string, a bool or nil
+type specCase struct {
+	Input  any `yaml:"input"`
+	Output any `yaml:"output"`
+}
+
+// specDST is the signature DST of the beacon chain
+const specDST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// specHex encodes a value as the tests do
+func specHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func specBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// specBytesList decodes a list of hex values; ok is false when one is not hex
+func specBytesList(values []string) ([][]byte, bool) {
+	out := make([][]byte, len(values))
+	for i, v := range values {
+		b, err := specBytes(v)
+		if err != nil {
+			return nil, false
+		}
+		out[i] = b
+	}
+	return out, true
+}
+
+// specG2Infinity is the compressed G2 identity, the "infinity signature" of the tests
+func specG2Infinity() []byte {
+	b := make([]byte, bls.SizeOfG2AffineCompressed)
+	b[0] = 0xc0
+	return b
+}
+
+func specSign(in specSignInput) any {
+	skBytes, err := specBytes(in.Privkey)
+	msg, merr := specBytes(in.Message)
+	if err != nil || merr != nil || len(skBytes) != 32 {
+		return nil
+	}
+	sk := new(big.Int).SetBytes(skBytes)
+	if sk.Sign() == 0 || sk.Cmp(fr.Modulus()) >= 0 {
+		return nil
+	}
+	s, err := blsSign(msg, sk, "min-pk", specDST)
+	if err != nil {
+		return nil
+	}
+	return specHex(serialization.ConvertG2AffineToCompressed(s.SigG2))
+}
+
+func specVerify(in specVerifyInput) any {
+	pk, err1 := specBytes(in.Pubkey)
+	msg, err2 := specBytes(in.Message)
+	sig, err3 := specBytes(in.Signature)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return false
+	}
+	return blsVerify(pk, msg, sig, "min-pk", specDST) == nil
+}
+
+func specAggregate(in []string) any {
+	sigs, ok := specBytesList(in)
+	if !ok || len(sigs) == 0 {
+		return nil
+	}
+	var acc bls.G2Jac
+	for _, b := range sigs {
+		p, err := serialization.DecodeCompressedG2Point(b)
+		if err != nil {
+			return nil
+		}
+		var pJac bls.G2Jac
+		pJac.FromAffine(&p)
+		acc.AddAssign(&pJac)
+	}
+	var agg bls.G2Affine
+	agg.FromJacobian(&acc)
+	return specHex(serialization.ConvertG2AffineToCompressed(agg))
+}
+
+func specFastAggregateVerify(in specFastAggregateVerifyInput) any {
+	pks, ok := specBytesList(in.Pubkeys)
+	msg, err1 := specBytes(in.Message)
+	sig, err2 := specBytes(in.Signature)
+	if !ok || err1 != nil || err2 != nil {
+		return false
+	}
+	return blsCoreAggregateVerify(pks, [][]byte{msg}, sig, "min-pk", specDST, true) == nil
+}
+
+func specAggregateVerify(in specAggregateVerifyInput) any {
+	pks, ok1 := specBytesList(in.Pubkeys)
+	msgs, ok2 := specBytesList(in.Messages)
+	sig, err := specBytes(in.Signature)
+	if !ok1 || !ok2 || err != nil {
+		return false
+	}
+	return blsCoreAggregateVerify(pks, msgs, sig, "min-pk", specDST, false) == nil
+}
+
+// specEthAggregatePubkeys is eth_aggregate_pubkeys: every key must pass KeyValidate
+func specEthAggregatePubkeys(in []string) any {
+	pks, ok := specBytesList(in)
+	if !ok || len(pks) == 0 {
+		return nil
+	}
+	var acc bls.G1Jac
+	for _, b := range pks {
+		p, err := serialization.DecodeCompressedG1Point(b)
+		if err != nil || p.IsInfinity() {
+			return nil
+		}
+		var pJac bls.G1Jac
+		pJac.FromAffine(&p)
+		acc.AddAssign(&pJac)
+	}
+	var agg bls.G1Affine
+	agg.FromJacobian(&acc)
+	return specHex(serialization.ConvertG1AffineToCompressed(agg))
+}
+
+// specEthFastAggregateVerify is eth_fast_aggregate_verify: no public keys with the
+// infinity signature is valid (an empty sync committee), anything else is
+// FastAggregateVerify
+func specEthFastAggregateVerify(in specFastAggregateVerifyInput) any {
+	if len(in.Pubkeys) == 0 {
+		sig, err := specBytes(in.Signature)
+		return err == nil && bytes.Equal(sig, specG2Infinity())
+	}
+	return specFastAggregateVerify(in)
+}
+
+// specHandlers decode a case input and compute the local output
+var specHandlers = map[string]func(input *yaml.Node) (any, error){
+	"sign":                      specRun(specSign),
+	"verify":                    specRun(specVerify),
+	"aggregate":                 specRun(specAggregate),
+	"fast_aggregate_verify":     specRun(specFastAggregateVerify),
+	"aggregate_verify":          specRun(specAggregateVerify),
+	"eth_aggregate_pubkeys":     specRun(specEthAggregatePubkeys),
+	"eth_fast_aggregate_verify": specRun(specEthFastAggregateVerify),
+}
+
+func specRun[T any](f func(T) any) func(*yaml.Node) (any, error) {
+	return func(input *yaml.Node) (any, error) {
+		var in T
+		if err := input.Decode(&in); err != nil {
+			return nil, err
+		}
+		return f(in), nil
+	}
+}
+
+func specHandlerNames() []string {
+	names := make([]string, 0, len(specHandlers))
+	for name := range specHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// specOutputsEqual compares an expected and a local output
+func specOutputsEqual(want, got any) bool {
+	switch w := want.(type) {
+	case nil:
+		return got == nil
+	case bool:
+		g, ok := got.(bool)
+		return ok && g == w
+	case string:
+		g, ok := got.(string)
+		return ok && strings.EqualFold(w, g)
+	}
+	return false
+}
+
+func specOutputString(v any) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
+
+// specHandlerOfCase finds the handler of a data.yaml at <handler>/bls/<case>/data.yaml
+func specHandlerOfCase(path string) string {
+	dir := filepath.Dir(filepath.Dir(filepath.Dir(path)))
+	return filepath.Base(dir)
+}
+
+// collectSpecCases finds the data.yaml files below the given files or directories
+func collectSpecCases(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && d.Name() == "data.yaml" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// runEthSpecRunMode replays consensus-spec BLS cases against the local implementation
+func runEthSpecRunMode(args []string) (int, error) {
+	fs := newFlagSet("eth-spec-run")
+	dir := fs.String("dir", "", "Directories (e.g. tests/general/phase0/bls) or data.yaml files, comma-separated")
+	handlers := fs.String("handlers", "", "Only these handlers (comma-separated, default all): "+strings.Join(specHandlerNames(), ", "))
+	verbose := fs.Bool("verbose", false, "Also list the cases that pass")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *dir == "" {
+		return 0, usageErrorf("--dir is required")
+	}
+	only := map[string]bool{}
+	for _, h := range splitList(*handlers) {
+		if specHandlers[h] == nil {
+			return 0, usageErrorf("unknown handler '%s' (valid: %s)", h, strings.Join(specHandlerNames(), ", "))
+		}
+		only[h] = true
+	}
+	files, err := collectSpecCases(splitList(*dir))
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictInput(*dir)
+	reportInput("dir", *dir)
+
+	fmt.Println("=== Consensus-Spec BLS Tests ===")
+	type handlerSummary struct{ cases, failed int }
+	summaries := map[string]*handlerSummary{}
+	skipped := map[string]int{}
+	failed := 0
+	for _, path := range files {
+		handler := specHandlerOfCase(path)
+		run := specHandlers[handler]
+		if run == nil || len(only) > 0 && !only[handler] {
+			skipped[handler]++
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return failed, err
+		}
+		var doc struct {
+			Input  yaml.Node `yaml:"input"`
+			Output any       `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return failed, fmt.Errorf("%s: %v", path, err)
+		}
+		got, err := run(&doc.Input)
+		if err != nil {
+			return failed, fmt.Errorf("%s: invalid %s input: %v", path, handler, err)
+		}
+		s := summaries[handler]
+		if s == nil {
+			s = &handlerSummary{}
+			summaries[handler] = s
+		}
+		s.cases++
+		name := handler + "/" + filepath.Base(filepath.Dir(path))
+		if !specOutputsEqual(doc.Output, got) {
+			fmt.Printf("❌ %s: expected %s, locally %s\n", name, specOutputString(doc.Output), specOutputString(got))
+			s.failed++
+			failed++
+		} else if *verbose {
+			fmt.Printf("✅ %s: %s\n", name, specOutputString(got))
+		}
+	}
+	if len(summaries) == 0 {
+		return 0, fmt.Errorf("no consensus-spec BLS cases found in %s", *dir)
+	}
+
+	fmt.Println("\n=== Summary ===")
+	total := 0
+	for _, h := range specHandlerNames() {
+		s := summaries[h]
+		if s == nil {
+			continue
+		}
+		mark := "✅"
+		if s.failed > 0 {
+			mark = "❌"
+		}
+		fmt.Printf("%s %-26s %4d cases, %d failed\n", mark, h, s.cases, s.failed)
+		total += s.cases
+	}
+	for h, n := range skipped {
+		fmt.Printf("⚠️  %s: %d cases skipped\n", h, n)
+	}
+	fmt.Printf("Cases: %d, passed: %d, failed: %d\n", total, total-failed, failed)
+	if failed == 0 {
+		fmt.Println("✅ The local implementation passes every case")
+	}
+	reportOutput("cases", total)
+	reportOutput("failed", failed)
+	return failed, nil
+}
+
+// specPrivkeys and specMessages are the keys and messages of the official generator,
+// so generated sign and verify cases line up with the published ones
+var (
+	specPrivkeys = []string{
+		"0x263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+		"0x47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138",
+		"0x328388aff0d4a5b7dc9205abd374e7e98f3cd9f3418edb4eafda5fb16473d216",
+	}
+	specMessages = []string{
+		"0x" + strings.Repeat("00", 32),
+		"0x" + strings.Repeat("56", 32),
+		"0x" + strings.Repeat("ab", 32),
+	}
+)
+
+// specGeneratedCase is one generated case before its output is computed
+type specGeneratedCase struct {
+	handler, name string
+	input         any
+}
+
+// specCases builds valid and invalid cases of every handler
+func specCases() []specGeneratedCase {
+	var pks []string
+	for _, k := range specPrivkeys {
+		sk, _ := parseSecretKey(k)
+		pks = append(pks, specHex(blsPublicKey(sk, "min-pk")))
+	}
+	sign := func(sk, msg string) string { return specSign(specSignInput{sk, msg}).(string) }
+	// the tests tamper with a signature by replacing its last four bytes
+	tamper := func(sig string) string { return sig[:len(sig)-8] + "ffffffff" }
+	infSig := specHex(specG2Infinity())
+	infPk := specHex(append([]byte{0xc0}, make([]byte, bls.SizeOfG1AffineCompressed-1)...))
+	short := func(h string) string { return h[2:10] }
+
+	var cases []specGeneratedCase
+	add := func(handler, name string, input any) {
+		cases = append(cases, specGeneratedCase{handler, name, input})
+	}
+	for i, sk := range specPrivkeys {
+		for _, msg := range specMessages {
+			name := fmt.Sprintf("case_%s_%s", short(pks[i]), short(msg))
+			add("sign", "sign_"+name, specSignInput{sk, msg})
+			sig := sign(sk, msg)
+			add("verify", "verify_valid_"+name, specVerifyInput{pks[i], msg, sig})
+			add("verify", "verify_wrong_pubkey_"+name, specVerifyInput{pks[(i+1)%len(pks)], msg, sig})
+			add("verify", "verify_tampered_signature_"+name, specVerifyInput{pks[i], msg, tamper(sig)})
+		}
+	}
+	add("sign", "sign_case_zero_privkey", specSignInput{"0x" + strings.Repeat("00", 32), specMessages[0]})
+	add("verify", "verify_infinity_pubkey_and_infinity_signature", specVerifyInput{infPk, specMessages[0], infSig})
+
+	for _, msg := range specMessages {
+		var sigs []string
+		for _, sk := range specPrivkeys {
+			sigs = append(sigs, sign(sk, msg))
+		}
+		agg := specAggregate(sigs).(string)
+		add("aggregate", "aggregate_"+short(msg), sigs)
+		for _, handler := range []string{"fast_aggregate_verify", "eth_fast_aggregate_verify"} {
+			add(handler, handler+"_valid_"+short(agg), specFastAggregateVerifyInput{pks, msg, agg})
+			add(handler, handler+"_extra_pubkey_"+short(agg), specFastAggregateVerifyInput{append(append([]string(nil), pks...), pks[0]), msg, agg})
+			add(handler, handler+"_tampered_signature_"+short(agg), specFastAggregateVerifyInput{pks, msg, tamper(agg)})
+		}
+	}
+	add("aggregate", "aggregate_single_signature", []string{sign(specPrivkeys[0], specMessages[0])})
+	add("aggregate", "aggregate_infinity_signature", []string{infSig})
+	add("aggregate", "aggregate_na_signatures", []string{})
+	for _, handler := range []string{"fast_aggregate_verify", "eth_fast_aggregate_verify"} {
+		add(handler, handler+"_na_pubkeys_and_infinity_signature", specFastAggregateVerifyInput{[]string{}, specMessages[0], infSig})
+		add(handler, handler+"_infinity_pubkey", specFastAggregateVerifyInput{[]string{pks[0], infPk}, specMessages[0], infSig})
+	}
+
+	var sigs []string
+	for i, sk := range specPrivkeys {
+		sigs = append(sigs, sign(sk, specMessages[i]))
+	}
+	agg := specAggregate(sigs).(string)
+	add("aggregate_verify", "aggregate_verify_valid", specAggregateVerifyInput{pks, specMessages, agg})
+	add("aggregate_verify", "aggregate_verify_tampered_signature", specAggregateVerifyInput{pks, specMessages, tamper(agg)})
+	add("aggregate_verify", "aggregate_verify_swapped_messages", specAggregateVerifyInput{pks, []string{specMessages[1], specMessages[0], specMessages[2]}, agg})
+	add("aggregate_verify", "aggregate_verify_na_pubkeys_and_infinity_signature", specAggregateVerifyInput{[]string{}, []string{}, infSig})
+	add("aggregate_verify", "aggregate_verify_infinity_pubkey", specAggregateVerifyInput{append(append([]string(nil), pks...), infPk), append(append([]string(nil), specMessages...), specMessages[0]), agg})
+
+	add("eth_aggregate_pubkeys", "eth_aggregate_pubkeys_valid_pubkeys", pks)
+	add("eth_aggregate_pubkeys", "eth_aggregate_pubkeys_single_pubkey", pks[:1])
+	add("eth_aggregate_pubkeys", "eth_aggregate_pubkeys_empty_list", []string{})
+	add("eth_aggregate_pubkeys", "eth_aggregate_pubkeys_infinity_pubkey", []string{pks[0], infPk})
+	return cases
+}
+
+// specLocalOutput evaluates a generated input with its handler
+func specLocalOutput(handler string, input any) (any, error) {
+	var node yaml.Node
+	if err := node.Encode(input); err != nil {
+		return nil, err
+	}
+	return specHandlers[handler](&node)
+}
+
+// specYAML renders a case as the official files are: the input in flow style and every
+// hex value single-quoted, so YAML 1.1 readers do not take 0x... for an integer
+func specYAML(c specCase) ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(c); err != nil {
+		return nil, err
+	}
+	var quote func(n *yaml.Node)
+	quote = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.ScalarNode:
+			if n.Tag == "!!str" {
+				n.Style = yaml.SingleQuotedStyle
+			}
+		case yaml.MappingNode:
+			for i := 1; i < len(n.Content); i += 2 {
+				quote(n.Content[i])
+			}
+		case yaml.SequenceNode:
+			for _, child := range n.Content {
+				quote(child)
+			}
+		}
+	}
+	quote(&doc)
+	// doc is the mapping input, <input>, output, <output>
+	doc.Content[1].Style |= yaml.FlowStyle
+	return yaml.Marshal(&doc)
+}
+
+// runEthSpecGenMode writes new cases in the consensus-spec layout, with the outputs of
+// the local implementation
+func runEthSpecGenMode(args []string) error {
+	fs := newFlagSet("eth-spec-gen")
+	outDir := fs.String("out", "bls", "Output directory: <out>/<handler>/bls/<case>/data.yaml (through --sink)")
+	handlers := fs.String("handlers", "", "Only these handlers (comma-separated, default all): "+strings.Join(specHandlerNames(), ", "))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	only := map[string]bool{}
+	for _, h := range splitList(*handlers) {
+		if specHandlers[h] == nil {
+			return usageErrorf("unknown handler '%s' (valid: %s)", h, strings.Join(specHandlerNames(), ", "))
+		}
+		only[h] = true
+	}
+
+	fmt.Println("=== Consensus-Spec BLS Test Generation ===")
+	counts := map[string]int{}
+	for _, c := range specCases() {
+		if len(only) > 0 && !only[c.handler] {
+			continue
+		}
+		out, err := specLocalOutput(c.handler, c.input)
+		if err != nil {
+			return err
+		}
+		data, err := specYAML(specCase{Input: c.input, Output: out})
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(*outDir, c.handler, "bls", c.name, "data.yaml")
+		if err := outputSink.write(path, data); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		counts[c.handler]++
+		result := "null" // the spec's output for an invalid input
+		if out != nil {
+			result = fmt.Sprint(out)
+		}
+		recordQuietResult(c.handler+"/"+c.name, result)
+	}
+	total := 0
+	for _, h := range specHandlerNames() {
+		if counts[h] > 0 {
+			fmt.Printf("%-26s %3d cases\n", h, counts[h])
+			total += counts[h]
+		}
+	}
+	fmt.Printf("Wrote %d cases to %s\n", total, outputSink.describe(*outDir))
+	reportOutput("cases", total)
+	return nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The official EIP-2537 vectors come in the same JSON layout under two naming schemes:
+// go-ethereum's core/vm/testdata/precompiles (converted from Matter Labs' vectors, also
+// used by ethereum/tests) names them after the operation (blsG1Add.json, blsPairing.json),
+// the EIP's assets directory after the precompile (add_G1_bls.json, pairing_check_bls.json).
+// Failure cases live in fail-<name>.json. Files from the draft era (separate MUL
+// precompiles, other prices) keep the layout; --gas=false skips their gas.
+
+// ethTestsFileOps maps the lower-case base names of both schemes to the operations
+var ethTestsFileOps = map[string]string{
+	"add_g1_bls":        "G1ADD",
+	"add_g2_bls":        "G2ADD",
+	"mul_g1_bls":        "G1MUL",
+	"mul_g2_bls":        "G2MUL",
+	"msm_g1_bls":        "G1MSM",
+	"msm_g2_bls":        "G2MSM",
+	"multiexp_g1_bls":   "G1MSM",
+	"multiexp_g2_bls":   "G2MSM",
+	"pairing_check_bls": "PAIRING",
+	"map_fp_to_g1_bls":  "MAP_FP_TO_G1",
+	"map_fp2_to_g2_bls": "MAP_FP2_TO_G2",
+}
+
+// precompileForEthTestsFile picks the operation of an official vector file from its name
+func precompileForEthTestsFile(path string) (eip2537Precompile, bool) {
+	base := strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(filepath.Base(path), ".json"), "fail-"))
+	for _, p := range eip2537Precompiles {
+		if strings.ToLower(p.File) == base || ethTestsFileOps[base] == p.Name {
+			return p, true
+		}
+	}
+	return eip2537Precompile{}, false
+}
+
+// ethTestsImport collects the passing cases of every file per operation for --out
+type ethTestsImport struct {
+	success  map[string][]eip2537SuccessVector
+	failures map[string][]eip2537FailVector
+	order    []eip2537Precompile
+}
+
+func (im *ethTestsImport) add(p eip2537Precompile, e ethVectorEntry, v ethVerifyVector, code errorCode) {
+	if len(im.success[p.Name]) == 0 && len(im.failures[p.Name]) == 0 {
+		im.order = append(im.order, p)
+	}
+	if v.failure != nil {
+		im.failures[p.Name] = append(im.failures[p.Name], eip2537FailVector{Input: hex.EncodeToString(v.input), ExpectedError: e.ExpectedError, Name: v.name, ErrorCode: code})
+		return
+	}
+	im.success[p.Name] = append(im.success[p.Name], eip2537SuccessVector{Input: hex.EncodeToString(v.input), Expected: hex.EncodeToString(v.expect), Name: v.name, Gas: p.gas(v.input), NoBenchmark: e.NoBenchmark})
+}
+
+// write writes <file>.json and fail-<file>.json of every imported operation
+func (im *ethTestsImport) write(outDir string) error {
+	for _, p := range im.order {
+		for _, f := range []struct {
+			name string
+			data any
+		}{{p.File + ".json", im.success[p.Name]}, {"fail-" + p.File + ".json", im.failures[p.Name]}} {
+			data, err := json.MarshalIndent(f.data, "", "  ")
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(outDir, f.name)
+			if err := outputSink.write(path, append(data, '\n')); err != nil {
+				return fmt.Errorf("failed to write %s: %v", path, err)
+			}
+			fmt.Printf("Wrote %s\n", outputSink.describe(path))
+		}
+	}
+	return nil
+}
+
+// runImportEthTestsMode runs official EIP-2537 vector files through the local
+// implementation and reports every case; expected failures must be rejected in the
+// error category of their message
+func runImportEthTestsMode(args []string) (int, error) {
+	fs := newFlagSet("import-eth-tests")
+	file := fs.String("file", "", "Vector files or directories of *.json files (comma-separated)")
+	op := fs.String("op", "", "Operation of files whose name does not tell: "+strings.Join(eip2537PrecompileNames(), ", "))
+	mapPath := fs.String("map", "", "JSON array of {\"pattern\": ..., \"code\": ...} rules for error messages, tried before the built-in geth rules")
+	checkGas := fs.Bool("gas", true, "Compare the Gas of success cases (disable for draft-era files)")
+	outDir := fs.String("out", "", "Write the passing cases in the eip2537-suite layout, with ErrorCode, to this directory (through --sink)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *file == "" {
+		return 0, usageErrorf("--file is required")
+	}
+	var forced *eip2537Precompile
+	if *op != "" {
+		selected, err := selectEip2537Precompiles(*op)
+		if err != nil {
+			return 0, err
+		}
+		if len(selected) != 1 {
+			return 0, usageErrorf("--op takes a single operation")
+		}
+		forced = &selected[0]
+	}
+	rules := builtinErrorMaps["geth"]
+	if *mapPath != "" {
+		custom, err := loadErrorMap(*mapPath)
+		if err != nil {
+			return 0, err
+		}
+		rules = append(custom, rules...)
+	}
+	files, fromDir, err := collectVectorFiles(splitList(*file))
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictInput(*file)
+	reportInput("file", *file)
+
+	fmt.Println("=== Official EIP-2537 Vectors ===")
+	im := &ethTestsImport{success: map[string][]eip2537SuccessVector{}, failures: map[string][]eip2537FailVector{}}
+	type fileSummary struct {
+		path                         string
+		op                           string
+		cases, failed, uncategorized int
+	}
+	var summaries []fileSummary
+	total, failed, uncategorized := 0, 0, 0
+	for _, path := range files {
+		p, ok := precompileForEthTestsFile(path)
+		if forced != nil {
+			p, ok = *forced, true
+		}
+		if !ok {
+			if fromDir[path] {
+				fmt.Printf("⚠️  %s: skipped, not an EIP-2537 vector file name (pass --op)\n", path)
+				continue
+			}
+			return failed, usageErrorf("cannot infer the operation from %s; pass --op", path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return failed, err
+		}
+		if detectVectorLayout(data) != "ethereum" {
+			if fromDir[path] {
+				fmt.Printf("⚠️  %s: skipped, not in the ethereum/tests layout\n", path)
+				continue
+			}
+			return failed, fmt.Errorf("%s: not in the ethereum/tests layout", path)
+		}
+		entries, err := readEthVectorFile(path)
+		if err != nil {
+			return failed, err
+		}
+		s := fileSummary{path: path, op: p.Name}
+		fmt.Printf("\n--- %s (%s) ---\n", path, p.Name)
+		for i, e := range entries {
+			v, err := e.vector(p, i)
+			if err != nil {
+				return failed, err
+			}
+			s.cases++
+			code, _ := classifyError(v.failure)
+			reason := e.mismatch(v)
+			detail := ""
+			switch {
+			case reason != "":
+			case v.failure != nil:
+				want, known := e.ErrorCode, e.ErrorCode != ""
+				if !known {
+					want, known = mapError(rules, e.ExpectedError)
+				}
+				switch {
+				case !known:
+					detail = fmt.Sprintf("rejected with %s; %q has no error category", code, e.ExpectedError)
+					s.uncategorized++
+				case want != code:
+					reason = fmt.Sprintf("expected %s (%q), rejected with %s: %v", want, e.ExpectedError, code, v.failure)
+				default:
+					detail = fmt.Sprintf("rejected with %s as expected", code)
+				}
+			case *checkGas && e.Gas != nil && p.gas(v.input) != *e.Gas:
+				reason = fmt.Sprintf("file expects gas %d, locally %d", *e.Gas, p.gas(v.input))
+			default:
+				detail = fmt.Sprintf("%d bytes match", len(v.expect))
+			}
+			if reason != "" {
+				fmt.Printf("❌ FAIL %s: %s\n", v.name, reason)
+				s.failed++
+				continue
+			}
+			fmt.Printf("✅ PASS %s: %s\n", v.name, detail)
+			im.add(p, e, v, code)
+		}
+		summaries = append(summaries, s)
+		total += s.cases
+		failed += s.failed
+		uncategorized += s.uncategorized
+	}
+	if len(summaries) == 0 {
+		return 0, fmt.Errorf("no EIP-2537 vector files found in %s", *file)
+	}
+
+	fmt.Println("\n=== Summary ===")
+	for _, s := range summaries {
+		mark := "✅"
+		if s.failed > 0 {
+			mark = "❌"
+		}
+		fmt.Printf("%s %-48s %-14s %5d cases, %d failed", mark, s.path, s.op, s.cases, s.failed)
+		if s.uncategorized > 0 {
+			fmt.Printf(", %d uncategorized errors", s.uncategorized)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Files: %d, cases: %d, passed: %d, failed: %d\n", len(summaries), total, total-failed, failed)
+	if uncategorized > 0 {
+		fmt.Printf("⚠️  %d rejections passed without a category check; add their messages with --map\n", uncategorized)
+	}
+	if failed == 0 {
+		fmt.Println("✅ The local implementation passes every official vector")
+	}
+	if *outDir != "" {
+		if err := im.write(*outDir); err != nil {
+			return failed, err
+		}
+	}
+	reportOutput("files", len(summaries))
+	reportOutput("cases", total)
+	reportOutput("failed", failed)
+	reportOutput("uncategorized", uncategorized)
+	return failed, nil
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// eth_call verification of the EIP-2537 precompiles on an Ethereum node. The final EIP
+// (Prague) occupies 0x0b-0x11 with MUL served by the MSM addresses; earlier drafts, still
+// run by some devnets and clients, used nine addresses 0x0b-0x13 with separate MULs.
+var ethPrecompileAddresses = map[string]map[string]byte{
+	"final": {"G1ADD": 0x0b, "G1MUL": 0x0c, "G1MSM": 0x0c, "G2ADD": 0x0d, "G2MUL": 0x0e, "G2MSM": 0x0e, "PAIRING": 0x0f, "MAP_FP_TO_G1": 0x10, "MAP_FP2_TO_G2": 0x11},
+	"draft": {"G1ADD": 0x0b, "G1MUL": 0x0c, "G1MSM": 0x0d, "G2ADD": 0x0e, "G2MUL": 0x0f, "G2MSM": 0x10, "PAIRING": 0x11, "MAP_FP_TO_G1": 0x12, "MAP_FP2_TO_G2": 0x13},
+}
+
+// ethVerifyVector is one eth_call with the locally computed outcome
+type ethVerifyVector struct {
+	name    string
+	p       eip2537Precompile
+	input   []byte
+	expect  []byte // nil when the precompile must fail
+	failure error  // why it must fail
+}
+
+// ethRPCResponse is the JSON-RPC envelope of eth_call
+type ethRPCResponse struct {
+	Result *string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ethCall runs eth_call against a precompile address. A JSON-RPC error (a failing
+// precompile reverts the call) is returned as callErr; err is set when the call could not
+// be made at all.
+func ethCall(rpcURL string, address byte, input []byte) (out []byte, callErr string, err error) {
+	to := fmt.Sprintf("0x%040x", address)
+	req, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_call",
+		"params":  []interface{}{map[string]string{"to": to, "data": "0x" + hex.EncodeToString(input)}, "latest"},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(rpcURL, "application/json", bytes.NewReader(req))
+	if err != nil {
+		return nil, "", fmt.Errorf("eth_call failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("eth_call failed: HTTP %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	var r ethRPCResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, "", fmt.Errorf("invalid eth_call response: %v", err)
+	}
+	if r.Error != nil {
+		return nil, fmt.Sprintf("%d: %s", r.Error.Code, r.Error.Message), nil
+	}
+	if r.Result == nil {
+		return nil, "", fmt.Errorf("invalid eth_call response: no result")
+	}
+	out, err = hex.DecodeString(strings.TrimPrefix(*r.Result, "0x"))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid eth_call result hex: %v", err)
+	}
+	return out, "", nil
+}
+
+// describeByteDiff lists where got differs from want, per 64-byte field element of the
+// Ethereum encoding (G1: x, y; G2: x.c0, x.c1, y.c0, y.c1; pairing: the 32-byte word)
+func describeByteDiff(got, want []byte) []string {
+	var lines []string
+	if len(got) != len(want) {
+		lines = append(lines, fmt.Sprintf("length: got %d bytes, want %d", len(got), len(want)))
+	}
+	unit := 64
+	if len(want) == 32 {
+		unit = 32
+	}
+	n := min(len(got), len(want))
+	for start := 0; start < n; start += unit {
+		end := min(start+unit, n)
+		if bytes.Equal(got[start:end], want[start:end]) {
+			continue
+		}
+		first := start
+		for got[first] == want[first] {
+			first++
+		}
+		differing := 0
+		for i := start; i < end; i++ {
+			if got[i] != want[i] {
+				differing++
+			}
+		}
+		lines = append(lines, fmt.Sprintf("bytes %d-%d (element %d): %d bytes differ, first at byte %d\n      got  %x\n      want %x",
+			start, end-1, start/unit, differing, first, got[start:end], want[start:end]))
+	}
+	return lines
+}
+
+// precompileForVectorFile picks the operation of an eip2537-suite file from its name
+func precompileForVectorFile(path string) (eip2537Precompile, bool) {
+	base := strings.TrimPrefix(strings.TrimSuffix(filepath.Base(path), ".json"), "fail-")
+	for _, p := range eip2537Precompiles {
+		if p.File == base {
+			return p, true
+		}
+	}
+	return eip2537Precompile{}, false
+}
+
+// ethVectorEntry is one entry of a precompile file in the ethereum/tests layout (also
+// go-ethereum's core/vm/testdata/precompiles and the eip2537-suite output)
+type ethVectorEntry struct {
+	Input         string  `json:"Input"`
+	Expected      string  `json:"Expected"`
+	ExpectedError string  `json:"ExpectedError"`
+	Name          string  `json:"Name"`
+	Gas           *uint64 `json:"Gas"`
+	NoBenchmark   bool    `json:"NoBenchmark"`
+	// ErrorCode is the taxonomy code eip2537-suite adds to its fail- entries
+	ErrorCode errorCode `json:"ErrorCode"`
+}
+
+// readEthVectorFile reads the entries of a precompile file
+func readEthVectorFile(path string) ([]ethVectorEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []ethVectorEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: invalid vector file: %v", path, err)
+	}
+	return entries, nil
+}
+
+// vector decodes the entry and computes its local outcome
+func (e ethVectorEntry) vector(p eip2537Precompile, i int) (ethVerifyVector, error) {
+	name := e.Name
+	if name == "" {
+		name = fmt.Sprintf("%s[%d]", p.File, i)
+	}
+	input, err := hex.DecodeString(strings.TrimPrefix(e.Input, "0x"))
+	if err != nil {
+		return ethVerifyVector{}, fmt.Errorf("%s: invalid Input hex: %v", name, err)
+	}
+	return newEthVerifyVector(name, p, input), nil
+}
+
+// mismatch compares the entry with the local outcome, "" when they agree
+func (e ethVectorEntry) mismatch(v ethVerifyVector) string {
+	switch {
+	case e.ExpectedError != "" && v.failure == nil:
+		return fmt.Sprintf("file expects error %q, locally computed %x", e.ExpectedError, v.expect)
+	case e.ExpectedError == "" && v.failure != nil:
+		return fmt.Sprintf("file expects %s, locally rejected: %v", e.Expected, v.failure)
+	case e.ExpectedError == "" && !strings.EqualFold(strings.TrimPrefix(e.Expected, "0x"), hex.EncodeToString(v.expect)):
+		return fmt.Sprintf("file expects %s, locally computed %x", e.Expected, v.expect)
+	}
+	return ""
+}
+
+// loadEthVerifyVectors reads a success or fail- file of the eip2537-suite layout
+func loadEthVerifyVectors(path string, p eip2537Precompile) ([]ethVerifyVector, int, error) {
+	entries, err := readEthVectorFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	var out []ethVerifyVector
+	broken := 0
+	for i, e := range entries {
+		v, err := e.vector(p, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		// A file entry disagreeing with the local computation is a broken vector, not a node bug
+		if m := e.mismatch(v); m != "" {
+			fmt.Printf("❌ %s: %s\n", v.name, m)
+			broken++
+		}
+		out = append(out, v)
+	}
+	return out, broken, nil
+}
+
+func newEthVerifyVector(name string, p eip2537Precompile, input []byte) ethVerifyVector {
+	v := ethVerifyVector{name: name, p: p, input: input}
+	v.expect, v.failure = p.run(input)
+	return v
+}
+
+// runEthVerifyMode runs EIP-2537 inputs with eth_call on a node and compares the returned
+// bytes with the local Ethereum-format result
+func runEthVerifyMode(args []string) (int, error) {
+	fs := newFlagSet("eth-verify")
+	rpcURL := fs.String("rpc-url", "", "Ethereum JSON-RPC endpoint (e.g. http://localhost:8545)")
+	op := fs.String("op", "", "Operation: "+strings.Join(eip2537PrecompileNames(), ", ")+" (inferred from the --vectors file name)")
+	inputHex := registerInputFlag(fs, "Ethereum-format precompile input hex")
+	vectors := fs.String("vectors", "", "An eip2537-suite file (<file>.json or fail-<file>.json)")
+	addresses := fs.String("addresses", "final", "Precompile addresses: final (0x0b-0x11) or draft (0x0b-0x13)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *rpcURL == "" {
+		return 0, usageErrorf("--rpc-url is required")
+	}
+	table, ok := ethPrecompileAddresses[*addresses]
+	if !ok {
+		return 0, usageErrorf("invalid --addresses '%s' (valid: final, draft)", *addresses)
+	}
+	if (*vectors == "") == (*inputHex == "") {
+		return 0, usageErrorf("use either --input or --vectors")
+	}
+
+	var p eip2537Precompile
+	switch {
+	case *op != "":
+		selected, err := selectEip2537Precompiles(*op)
+		if err != nil {
+			return 0, err
+		}
+		if len(selected) != 1 {
+			return 0, usageErrorf("--op takes a single operation")
+		}
+		p = selected[0]
+	case *vectors != "":
+		if p, ok = precompileForVectorFile(*vectors); !ok {
+			return 0, usageErrorf("cannot infer the operation from %s; pass --op", *vectors)
+		}
+	default:
+		return 0, usageErrorf("--op is required with --input")
+	}
+
+	failed := 0
+	var list []ethVerifyVector
+	if *vectors != "" {
+		var err error
+		if list, failed, err = loadEthVerifyVectors(*vectors, p); err != nil {
+			return 0, err
+		}
+	} else {
+		input, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*inputHex), "0x"))
+		if err != nil {
+			return 0, usageErrorf("invalid --input hex: %v", err)
+		}
+		recordVerdictInput(*inputHex)
+		reportInput("input", *inputHex)
+		list = append(list, newEthVerifyVector(strings.ToLower(p.Name), p, input))
+	}
+
+	address := table[p.Name]
+	fmt.Println("=== Ethereum Precompile Verification ===")
+	fmt.Printf("Node: %s\n", *rpcURL)
+	fmt.Printf("Precompile: %s at 0x%040x (%s addresses)\n", p.Name, address, *addresses)
+	fmt.Printf("Vectors: %d\n", len(list))
+	for _, v := range list {
+		out, callErr, err := ethCall(*rpcURL, address, v.input)
+		if err != nil {
+			return failed, err
+		}
+		switch {
+		case v.failure != nil && callErr != "":
+			fmt.Printf("✅ PASS %s: rejected as expected (%s)\n", v.name, callErr)
+		case v.failure != nil:
+			fmt.Printf("❌ FAIL %s: expected a failure (%v), got %x\n", v.name, v.failure, out)
+			failed++
+		case callErr != "":
+			fmt.Printf("❌ FAIL %s: call failed (%s), expected %x\n", v.name, callErr, v.expect)
+			failed++
+		case bytes.Equal(out, v.expect):
+			fmt.Printf("✅ PASS %s: %d bytes match\n", v.name, len(out))
+		default:
+			fmt.Printf("❌ FAIL %s: result differs\n", v.name)
+			for _, line := range describeByteDiff(out, v.expect) {
+				fmt.Printf("    %s\n", line)
+			}
+			failed++
+		}
+	}
+	fmt.Printf("Vectors: %d, failed: %d\n", len(list), failed)
+	if failed == 0 {
+		fmt.Println("✅ The node matches every locally computed result")
+	}
+	reportOutput("vectors", len(list))
+	reportOutput("failed", failed)
+	return failed, nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// Base field arithmetic for checking a field implementation below the point level.
+// Fp elements are 48 bytes big-endian; Fp2 elements c0 + c1 u are 96 bytes, c1 || c0
+// by default as in compressed G2 and Neo, or c0 || c1 with --order c0c1. Fp is an Fp2
+// element with c1 = 0, so one set of operations covers both fields.
+
+var fieldOps = []string{"add", "sub", "mul", "inv", "sqrt", "legendre", "sgn0", "largest"}
+
+// fieldEncoding is the field and the Fp2 coefficient order of the inputs and results
+type fieldEncoding struct {
+	fp2     bool
+	c0First bool
+}
+
+func (f fieldEncoding) name() string {
+	if f.fp2 {
+		return "Fp2"
+	}
+	return "Fp"
+}
+
+// parse decodes one element, reducing (and reporting) a coefficient that is not below p
+func (f fieldEncoding) parse(name, s string) (bls.E2, error) {
+	var e bls.E2
+	b, err := decodeHexFlag(name, s)
+	if err != nil {
+		return e, err
+	}
+	size := fp.Bytes
+	if f.fp2 {
+		size = 2 * fp.Bytes
+	}
+	if len(b) != size {
+		return e, usageErrorf("--%s: %s element must be %d bytes, got %d", name, f.name(), size, len(b))
+	}
+	if !f.fp2 {
+		var canonical bool
+		if e.A0, canonical = decodedFp(b); !canonical {
+			fmt.Printf("⚠️  --%s is not below p, reduced\n", name)
+		}
+		return e, nil
+	}
+	first, second := b[:fp.Bytes], b[fp.Bytes:]
+	c0, c1 := second, first
+	if f.c0First {
+		c0, c1 = first, second
+	}
+	var ok0, ok1 bool
+	e.A0, ok0 = decodedFp(c0)
+	e.A1, ok1 = decodedFp(c1)
+	if !ok0 || !ok1 {
+		fmt.Printf("⚠️  --%s has a coefficient not below p, reduced\n", name)
+	}
+	return e, nil
+}
+
+// bytes encodes an element in the input layout
+func (f fieldEncoding) bytes(e *bls.E2) []byte {
+	c0, c1 := e.A0.Bytes(), e.A1.Bytes()
+	if !f.fp2 {
+		return c0[:]
+	}
+	if f.c0First {
+		return append(c0[:], c1[:]...)
+	}
+	return append(c1[:], c0[:]...)
+}
+
+// print shows an element in the input layout, then its coefficients in decimal. String
+// would print p - 2 as -2; C# prints the canonical value, so this does too.
+func (f fieldEncoding) print(label string, e *bls.E2) {
+	fmt.Printf("%s: %x\n", label, f.bytes(e))
+	dec := func(c *fp.Element) string { return c.BigInt(new(big.Int)).String() }
+	if f.fp2 {
+		fmt.Printf("  c0: %s\n  c1: %s\n", dec(&e.A0), dec(&e.A1))
+		return
+	}
+	fmt.Printf("  dec: %s\n", dec(&e.A0))
+}
+
+// fieldSgn0 is sgn0 of RFC 9380 section 4.1: the parity of c0, or of c1 when c0 is zero
+func fieldSgn0(e *bls.E2) int {
+	c0, c1 := e.A0.Bytes(), e.A1.Bytes()
+	if !e.A0.IsZero() {
+		return int(c0[fp.Bytes-1] & 1)
+	}
+	return int(c1[fp.Bytes-1] & 1)
+}
+
+// fieldLargest is the sort flag of compressed points: the Neo/zkcrypto
+// LexicographicallyLargest of Fp, or of Fp2 (decided by c1, then by c0 when c1 is zero)
+func fieldLargest(e *bls.E2, fp2 bool) bool {
+	c0, c1 := e.A0.Bytes(), e.A1.Bytes()
+	if !fp2 {
+		return serialization.IsLexicographicallyLargestFp(c0[:])
+	}
+	return serialization.IsLexicographicallyLargestFp2(append(c1[:], c0[:]...))
+}
+
+// fieldLegendre is the quadratic character: 1 for a nonzero square, -1 for a non-square
+// and 0 for zero. An Fp2 element is a square exactly when its norm c0^2 + c1^2 is.
+func fieldLegendre(e *bls.E2, fp2 bool) int {
+	if !fp2 {
+		return e.A0.Legendre()
+	}
+	return e.Legendre()
+}
+
+// runFieldMode runs one operation (or all of them) on --a, and --b for the binary ones,
+// printing each result in the input layout. sqrt prints both roots with their sgn0 and
+// sort flags and checks the square; inv checks a * a^-1 = 1.
+func runFieldMode(args []string) error {
+	fs := newFlagSet("field")
+	op := fs.String("op", "all", "Operation: "+strings.Join(fieldOps, ", ")+" or all")
+	aHex := fs.String("a", "", "First operand (hex: 48 bytes Fp, 96 bytes Fp2)")
+	bHex := fs.String("b", "", "Second operand of add, sub and mul (same size as --a)")
+	field := fs.String("field", "auto", "Field: auto (by the length of --a), fp or fp2")
+	order := fs.String("order", "c1c0", "Fp2 coefficient order: c1c0 (compressed G2, Neo) or c0c1 (EIP-2537)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *aHex == "" {
+		return usageErrorf("--a is required")
+	}
+	ops := fieldOps
+	if *op != "all" {
+		valid := false
+		for _, o := range fieldOps {
+			valid = valid || o == *op
+		}
+		if !valid {
+			return usageErrorf("--op must be one of %s or all, got '%s'", strings.Join(fieldOps, ", "), *op)
+		}
+		ops = []string{*op}
+	}
+	binary := func(o string) bool { return o == "add" || o == "sub" || o == "mul" }
+	if *op != "all" && binary(*op) && *bHex == "" {
+		return usageErrorf("--b is required with --op %s", *op)
+	}
+	var enc fieldEncoding
+	switch *order {
+	case "c1c0":
+	case "c0c1":
+		enc.c0First = true
+	default:
+		return usageErrorf("--order must be c1c0 or c0c1, got '%s'", *order)
+	}
+	switch *field {
+	case "fp":
+	case "fp2":
+		enc.fp2 = true
+	case "auto":
+		enc.fp2 = len(strings.TrimPrefix(strings.TrimSpace(*aHex), "0x")) == 4*fp.Bytes
+	default:
+		return usageErrorf("--field must be auto, fp or fp2, got '%s'", *field)
+	}
+
+	a, err := enc.parse("a", *aHex)
+	if err != nil {
+		return err
+	}
+	var b bls.E2
+	input := enc.bytes(&a)
+	if *bHex != "" {
+		if b, err = enc.parse("b", *bHex); err != nil {
+			return err
+		}
+		input = append(input, enc.bytes(&b)...)
+	}
+	recordVerdictInput(hex.EncodeToString(input))
+	reportInput("a", hex.EncodeToString(enc.bytes(&a)))
+	if *bHex != "" {
+		reportInput("b", hex.EncodeToString(enc.bytes(&b)))
+	}
+
+	fmt.Printf("=== %s Arithmetic ===\n", enc.name())
+	if enc.fp2 {
+		fmt.Printf("Coefficient order: %s\n", *order)
+	}
+	enc.print("a", &a)
+	if *bHex != "" {
+		enc.print("b", &b)
+	}
+	fmt.Println()
+
+	failed := false
+	results := map[string]any{}
+	for _, o := range ops {
+		if binary(o) && *bHex == "" {
+			continue
+		}
+		var z bls.E2
+		switch o {
+		case "add":
+			z.Add(&a, &b)
+		case "sub":
+			z.Sub(&a, &b)
+		case "mul":
+			z.Mul(&a, &b)
+		case "inv":
+			if a.IsZero() {
+				fmt.Println("inv: zero has no inverse")
+				results[o] = nil
+				continue
+			}
+			z.Inverse(&a)
+			var check bls.E2
+			check.Mul(&a, &z)
+			if !check.IsOne() {
+				fmt.Println("❌ a * a^-1 is not 1")
+				failed = true
+			}
+		case "sqrt":
+			if fieldLegendre(&a, enc.fp2) == -1 {
+				fmt.Println("sqrt: a is not a square")
+				results[o] = nil
+				continue
+			}
+			if enc.fp2 {
+				z.Sqrt(&a)
+			} else {
+				z.A0.Sqrt(&a.A0)
+			}
+			var square, neg bls.E2
+			square.Square(&z)
+			if !square.Equal(&a) {
+				fmt.Println("❌ sqrt(a)^2 is not a")
+				failed = true
+			}
+			neg.Neg(&z)
+			roots := []map[string]any{}
+			for i, r := range []*bls.E2{&z, &neg} {
+				enc.print(fmt.Sprintf("sqrt root %d", i), r)
+				fmt.Printf("  sgn0: %d, lexicographically largest: %v\n", fieldSgn0(r), fieldLargest(r, enc.fp2))
+				roots = append(roots, map[string]any{
+					"value":   hex.EncodeToString(enc.bytes(r)),
+					"sgn0":    fieldSgn0(r),
+					"largest": fieldLargest(r, enc.fp2),
+				})
+			}
+			results[o] = roots
+			continue
+		case "legendre":
+			fmt.Printf("legendre: %d\n", fieldLegendre(&a, enc.fp2))
+			results[o] = fieldLegendre(&a, enc.fp2)
+			continue
+		case "sgn0":
+			fmt.Printf("sgn0: %d\n", fieldSgn0(&a))
+			results[o] = fieldSgn0(&a)
+			continue
+		case "largest":
+			fmt.Printf("lexicographically largest: %v\n", fieldLargest(&a, enc.fp2))
+			results[o] = fieldLargest(&a, enc.fp2)
+			continue
+		}
+		label := map[string]string{"add": "a + b", "sub": "a - b", "mul": "a * b", "inv": "a^-1"}[o]
+		enc.print(label, &z)
+		results[o] = hex.EncodeToString(enc.bytes(&z))
+		if len(ops) == 1 {
+			recordVerdictResult(hex.EncodeToString(enc.bytes(&z)))
+		}
+	}
+	reportOutput("field", strings.ToLower(enc.name()))
+	reportOutput("results", results)
+	if failed {
+		return errChecksFailed
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// The fuzzer runs as a pipeline so a slow oracle (an external process, an RPC node) is
+// the only limit on throughput:
+//
+//	generate (1) -> Go decoders (workers) -> oracle (oracle-workers) -> compare (1)
+//
+// Stages are connected by channels of capacity --queue. The generator owns the seeded
+// RNG, so the blob sequence is the same as in a sequential run, and the comparer
+// restores sequence order before counting, so the report and the mismatch list do not
+// depend on scheduling. Each oracle worker has its own oracle instance, since a line
+// protocol process answers one request at a time.
+
+// fuzzCase is one blob travelling through the pipeline
+type fuzzCase struct {
+	seq      int
+	kind     string
+	strategy string
+	data     []byte
+	got      fuzzVerdict // Go decoders
+	want     fuzzVerdict // oracle
+	err      error       // oracle failure
+}
+
+// fuzzPipelineConfig sizes the pipeline
+type fuzzPipelineConfig struct {
+	workers       int
+	oracleWorkers int
+	queue         int
+	deadline      time.Time
+}
+
+// fuzzStageStats accumulates the busy time of one stage
+type fuzzStageStats struct {
+	mu      sync.Mutex
+	busy    time.Duration
+	workers int
+}
+
+func (s *fuzzStageStats) add(d time.Duration) {
+	s.mu.Lock()
+	s.busy += d
+	s.mu.Unlock()
+}
+
+// utilization is the fraction of the stage's worker time spent working
+func (s *fuzzStageStats) utilization(elapsed time.Duration) float64 {
+	if elapsed <= 0 || s.workers == 0 {
+		return 0
+	}
+	return float64(s.busy) / float64(elapsed) / float64(s.workers)
+}
+
+// runFuzzPipeline generates blobs until the deadline and calls compare for every case in
+// sequence order. compare returns false to stop the run early; cases already in flight
+// are drained and discarded. It returns the first oracle error, if any, even when the
+// case that failed is reached only while draining.
+func runFuzzPipeline(cfg fuzzPipelineConfig, rng *rand.Rand, selected []int, newOracle func() (fuzzOracle, error), compare func(fuzzCase) bool) (map[string]*fuzzStageStats, time.Duration, error) {
+	oracles := make([]fuzzOracle, cfg.oracleWorkers)
+	for i := range oracles {
+		o, err := newOracle()
+		if err != nil {
+			for _, prev := range oracles[:i] {
+				prev.close()
+			}
+			return nil, 0, err
+		}
+		oracles[i] = o
+	}
+	defer func() {
+		for _, o := range oracles {
+			o.close()
+		}
+	}()
+
+	stats := map[string]*fuzzStageStats{
+		"generate": {workers: 1},
+		"go":       {workers: cfg.workers},
+		"oracle":   {workers: cfg.oracleWorkers},
+	}
+	start := time.Now()
+	stop := make(chan struct{})
+	generated := make(chan fuzzCase, cfg.queue)
+	decoded := make(chan fuzzCase, cfg.queue)
+	checked := make(chan fuzzCase, cfg.queue)
+
+	go func() {
+		defer close(generated)
+		for seq := 0; time.Now().Before(cfg.deadline); seq++ {
+			t := time.Now()
+			fk := fuzzKinds[selected[rng.Intn(len(selected))]]
+			data, strategy := fuzzBlob(rng, fk.kind, fk.size)
+			stats["generate"].add(time.Since(t))
+			select {
+			case generated <- fuzzCase{seq: seq, kind: fk.kind, strategy: strategy, data: data}:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var goWG sync.WaitGroup
+	for w := 0; w < cfg.workers; w++ {
+		goWG.Add(1)
+		go func() {
+			defer goWG.Done()
+			for c := range generated {
+				t := time.Now()
+				c.got = decodeWithGo(c.kind, c.data)
+				stats["go"].add(time.Since(t))
+				decoded <- c
+			}
+		}()
+	}
+	go func() {
+		goWG.Wait()
+		close(decoded)
+	}()
+
+	var oracleWG sync.WaitGroup
+	for _, o := range oracles {
+		oracleWG.Add(1)
+		go func(o fuzzOracle) {
+			defer oracleWG.Done()
+			failed := false
+			for c := range decoded {
+				select {
+				case <-stop:
+					// Stopping: skip the oracle for cases the comparer will discard
+					c.err = errOracleStopped
+					checked <- c
+					continue
+				default:
+				}
+				if !failed {
+					t := time.Now()
+					c.want, c.err = o.decode(c.kind, c.data)
+					stats["oracle"].add(time.Since(t))
+					// A broken oracle process stays broken; only the first error is reported
+					failed = c.err != nil
+				} else {
+					c.err = errOracleStopped
+				}
+				checked <- c
+			}
+		}(o)
+	}
+	go func() {
+		oracleWG.Wait()
+		close(checked)
+	}()
+
+	// Workers take cases out of order, so the case an oracle failed on can arrive after
+	// the errOracleStopped cases it caused; oracle errors are collected while draining
+	var firstErr error
+	firstErrSeq := 0
+	oracleFailed := false
+	stopped := false
+	next := 0
+	pending := map[int]fuzzCase{}
+	halt := func() {
+		if !stopped {
+			stopped = true
+			close(stop)
+		}
+	}
+	for c := range checked {
+		if c.err != nil && c.err != errOracleStopped && (firstErr == nil || c.seq < firstErrSeq) {
+			firstErr, firstErrSeq = c.err, c.seq
+		}
+		if stopped {
+			continue // draining
+		}
+		pending[c.seq] = c
+		for {
+			c, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if c.err != nil {
+				oracleFailed = true
+				halt()
+				break
+			}
+			if !compare(c) {
+				halt()
+				break
+			}
+		}
+	}
+	if !oracleFailed {
+		// The comparer stopped first; errors of cases it discarded are not reported
+		firstErr = nil
+	}
+	return stats, time.Since(start), firstErr
+}
+
+// errOracleStopped marks cases an oracle worker skipped after its oracle failed
+var errOracleStopped = fmt.Errorf("oracle stopped after an earlier error")
+
+// printFuzzPipelineStats reports how busy each stage was; the busiest one bounds throughput
+func printFuzzPipelineStats(stats map[string]*fuzzStageStats, elapsed time.Duration, total int) {
+	if stats == nil {
+		return
+	}
+	fmt.Printf("Pipeline: %d blobs in %s (%.0f/s)\n", total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+	bottleneck, most := "", -1.0
+	for _, name := range []string{"generate", "go", "oracle"} {
+		s := stats[name]
+		u := s.utilization(elapsed)
+		fmt.Printf("  %-8s %2d worker(s), %5.1f%% busy\n", name, s.workers, 100*u)
+		if u > most {
+			bottleneck, most = name, u
+		}
+	}
+	fmt.Printf("  bottleneck: %s\n", bottleneck)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"testing"
+	"time"
+)
+
+var errFakeOracle = errors.New("fake oracle failure")
+
+// fakeOracle agrees with the Go decoders. With a seqs table it fails on the first case
+// that arrives ahead of a lower sequence number, or on its 200th case if none does, so
+// the cases it skips afterwards include one the comparer reaches before the failure.
+type fakeOracle struct {
+	seqs  map[string]int // blob -> sequence number, as the generator draws them
+	seen  map[int]bool
+	calls int
+}
+
+func (o *fakeOracle) decode(kind string, data []byte) (fuzzVerdict, error) {
+	o.calls++
+	if o.seqs != nil {
+		seq := o.seqs[kind+string(data)]
+		o.seen[seq] = true
+		outOfOrder := false
+		for s := 0; s < seq; s++ {
+			outOfOrder = outOfOrder || !o.seen[s]
+		}
+		if outOfOrder || o.calls == 200 {
+			return fuzzVerdict{}, errFakeOracle
+		}
+	}
+	return decodeWithGo(kind, data), nil
+}
+
+func (o *fakeOracle) close() {}
+
+// fuzzSeqs replays the generator's draws for seed
+func fuzzSeqs(seed int64, selected []int, n int) map[string]int {
+	rng := rand.New(rand.NewSource(seed))
+	seqs := map[string]int{}
+	for seq := 0; seq < n; seq++ {
+		fk := fuzzKinds[selected[rng.Intn(len(selected))]]
+		data, _ := fuzzBlob(rng, fk.kind, fk.size)
+		if _, dup := seqs[fk.kind+string(data)]; !dup {
+			seqs[fk.kind+string(data)] = seq
+		}
+	}
+	return seqs
+}
+
+func TestFuzzPipelineReportsOracleFailure(t *testing.T) {
+	// Out-of-order arrival needs the Go workers to run in parallel, even on one CPU
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	selected := []int{0, 1, 2, 3}
+	workers := 8
+	for run := int64(0); run < 20; run++ {
+		newOracle := func() (fuzzOracle, error) {
+			return &fakeOracle{seqs: fuzzSeqs(run, selected, 1000), seen: map[int]bool{}}, nil
+		}
+		cfg := fuzzPipelineConfig{workers: workers, oracleWorkers: 1, queue: 16, deadline: time.Now().Add(5 * time.Second)}
+		compared := 0
+		_, _, err := runFuzzPipeline(cfg, rand.New(rand.NewSource(run)), selected, newOracle, func(c fuzzCase) bool {
+			compared++
+			return true
+		})
+		if !errors.Is(err, errFakeOracle) {
+			t.Fatalf("run %d: got error %v after %d cases, want the oracle failure", run, err, compared)
+		}
+	}
+}
+
+func TestFuzzPipelineCompareStop(t *testing.T) {
+	newOracle := func() (fuzzOracle, error) { return &fakeOracle{}, nil }
+	cfg := fuzzPipelineConfig{workers: 4, oracleWorkers: 4, queue: 16, deadline: time.Now().Add(5 * time.Second)}
+	compared := 0
+	_, _, err := runFuzzPipeline(cfg, rand.New(rand.NewSource(1)), []int{0, 1, 2, 3}, newOracle, func(c fuzzCase) bool {
+		if c.seq != compared {
+			t.Errorf("case %d compared at position %d", c.seq, compared)
+		}
+		compared++
+		return compared < 50
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if compared != 50 {
+		t.Errorf("compared %d cases, want 50", compared)
+	}
+}
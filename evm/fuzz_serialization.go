@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"evm/logging"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// Serialization fuzzing: random 48/96/128/256-byte blobs are decoded by the Go strict
+// decoders and by an oracle, and the accept/reject decisions and decoded coordinates must
+// agree. Decoded points are compared through their EIP-2537 encoding.
+//
+// An external oracle is a long-running command speaking a line protocol on stdin/stdout:
+//
+//	request:  <kind> <hex>        kind is g1c/g2c (compressed) or g1e/g2e (EIP-2537)
+//	response: ok <eip2537 hex>    the decoded point
+//	          reject [reason]
+//
+// The built-in "gnark" oracle decodes with gnark-crypto's own SetBytes.
+
+// fuzzKinds maps a blob kind to its length
+var fuzzKinds = []struct {
+	kind string
+	size int
+}{
+	{"g1c", 48},
+	{"g2c", 96},
+	{"g1e", 128},
+	{"g2e", 256},
+}
+
+// fuzzVerdict is one decoder's decision for a blob; point is the EIP-2537 encoding
+type fuzzVerdict struct {
+	accept bool
+	point  string
+	reason string
+}
+
+func (v fuzzVerdict) String() string {
+	if v.accept {
+		return "ok " + v.point
+	}
+	return "reject " + v.reason
+}
+
+// decodeWithGo runs the tool's strict decoders (subgroup check included)
+func decodeWithGo(kind string, data []byte) fuzzVerdict {
+	var enc []byte
+	var err error
+	switch kind {
+	case "g1c":
+		var p bls.G1Affine
+		if p, err = serialization.DecodeCompressedG1Point(data); err == nil {
+			enc = serialization.EncodeEthereumG1Point(p)
+		}
+	case "g2c":
+		var p bls.G2Affine
+		if p, err = serialization.DecodeCompressedG2Point(data); err == nil {
+			enc = serialization.EncodeEthereumG2Point(p)
+		}
+	case "g1e":
+		var p bls.G1Affine
+		if p, err = serialization.DecodeEIP2537G1Point(data, true); err == nil {
+			enc = serialization.EncodeEthereumG1Point(p)
+		}
+	case "g2e":
+		var p bls.G2Affine
+		if p, err = serialization.DecodeEIP2537G2Point(data, true); err == nil {
+			enc = serialization.EncodeEthereumG2Point(p)
+		}
+	}
+	if err != nil {
+		return fuzzVerdict{reason: err.Error()}
+	}
+	return fuzzVerdict{accept: true, point: hex.EncodeToString(enc)}
+}
+
+// fuzzOracle decodes a blob independently of the Go implementation
+type fuzzOracle interface {
+	decode(kind string, data []byte) (fuzzVerdict, error)
+	close()
+}
+
+// gnarkOracle decodes with gnark-crypto's SetBytes. EIP-2537 blobs are reduced to gnark's
+// uncompressed layout after the padding check; the all-zero blob is the point at infinity.
+type gnarkOracle struct{}
+
+func (gnarkOracle) close() {}
+
+func (gnarkOracle) decode(kind string, data []byte) (fuzzVerdict, error) {
+	reject := func(err error) (fuzzVerdict, error) { return fuzzVerdict{reason: err.Error()}, nil }
+	switch kind {
+	case "g1c":
+		var p bls.G1Affine
+		if _, err := p.SetBytes(data); err != nil {
+			return reject(err)
+		}
+		return fuzzVerdict{accept: true, point: hex.EncodeToString(serialization.EncodeEthereumG1Point(p))}, nil
+	case "g2c":
+		var p bls.G2Affine
+		if _, err := p.SetBytes(data); err != nil {
+			return reject(err)
+		}
+		return fuzzVerdict{accept: true, point: hex.EncodeToString(serialization.EncodeEthereumG2Point(p))}, nil
+	}
+
+	zero := true
+	for _, b := range data {
+		if b != 0 {
+			zero = false
+			break
+		}
+	}
+	// gnark's uncompressed layout: G1 [x, y], G2 [x.C1, x.C0, y.C1, y.C0]
+	var fields [][]byte
+	for off := 0; off < len(data); off += 64 {
+		if strings.Trim(hex.EncodeToString(data[off:off+16]), "0") != "" {
+			return fuzzVerdict{reason: "non-zero padding"}, nil
+		}
+		fields = append(fields, data[off+16:off+64])
+	}
+	if kind == "g2e" {
+		fields = [][]byte{fields[1], fields[0], fields[3], fields[2]}
+	}
+	raw := make([]byte, 0, len(fields)*48)
+	for _, f := range fields {
+		// Any of the top three bits set puts the element above p; gnark would read them as flags
+		if f[0]&0xE0 != 0 {
+			return fuzzVerdict{reason: "non-canonical field element"}, nil
+		}
+		raw = append(raw, f...)
+	}
+	if kind == "g1e" {
+		var p bls.G1Affine
+		if !zero {
+			if _, err := p.SetBytes(raw); err != nil {
+				return reject(err)
+			}
+		}
+		return fuzzVerdict{accept: true, point: hex.EncodeToString(serialization.EncodeEthereumG1Point(p))}, nil
+	}
+	var p bls.G2Affine
+	if !zero {
+		if _, err := p.SetBytes(raw); err != nil {
+			return reject(err)
+		}
+	}
+	return fuzzVerdict{accept: true, point: hex.EncodeToString(serialization.EncodeEthereumG2Point(p))}, nil
+}
+
+// commandOracle talks the line protocol to a long-running external process
+type commandOracle struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	out *bufio.Reader
+}
+
+func newCommandOracle(command string) (*commandOracle, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start oracle: %v", err)
+	}
+	return &commandOracle{cmd: cmd, in: in, out: bufio.NewReaderSize(out, 1<<16)}, nil
+}
+
+func (o *commandOracle) close() {
+	o.in.Close()
+	o.cmd.Wait()
+}
+
+func (o *commandOracle) decode(kind string, data []byte) (fuzzVerdict, error) {
+	if _, err := fmt.Fprintf(o.in, "%s %x\n", kind, data); err != nil {
+		return fuzzVerdict{}, fmt.Errorf("oracle write failed: %v", err)
+	}
+	line, err := o.out.ReadString('\n')
+	if err != nil {
+		return fuzzVerdict{}, fmt.Errorf("oracle read failed: %v", err)
+	}
+	fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+	switch fields[0] {
+	case "ok":
+		if len(fields) < 2 {
+			return fuzzVerdict{}, fmt.Errorf("oracle response 'ok' without a point")
+		}
+		return fuzzVerdict{accept: true, point: strings.ToLower(strings.TrimPrefix(fields[1], "0x"))}, nil
+	case "reject":
+		v := fuzzVerdict{}
+		if len(fields) == 2 {
+			v.reason = fields[1]
+		}
+		return v, nil
+	}
+	return fuzzVerdict{}, fmt.Errorf("unexpected oracle response: %q", strings.TrimSpace(line))
+}
+
+// fuzzBlob produces a blob of the kind's size with one of several strategies. Pure random
+// bytes are rejected almost immediately, so most blobs start from a valid encoding.
+func fuzzBlob(rng *rand.Rand, kind string, size int) ([]byte, string) {
+	strategy := rng.Intn(6)
+	if strategy == 0 {
+		b := make([]byte, size)
+		rng.Read(b)
+		return b, "random"
+	}
+
+	_, _, g1Gen, g2Gen := bls.Generators()
+	k := rng.Uint64()
+	var valid []byte
+	switch kind {
+	case "g1c", "g1e":
+		var p bls.G1Affine
+		p.ScalarMultiplication(&g1Gen, new(big.Int).SetUint64(k))
+		if kind == "g1c" {
+			valid = serialization.ConvertG1AffineToCompressed(p)
+		} else {
+			valid = serialization.EncodeEthereumG1Point(p)
+		}
+	default:
+		var p bls.G2Affine
+		p.ScalarMultiplication(&g2Gen, new(big.Int).SetUint64(k))
+		if kind == "g2c" {
+			valid = serialization.ConvertG2AffineToCompressed(p)
+		} else {
+			valid = serialization.EncodeEthereumG2Point(p)
+		}
+	}
+
+	switch strategy {
+	case 1:
+		return valid, "valid"
+	case 2:
+		i := rng.Intn(size * 8)
+		valid[i/8] ^= 1 << (7 - uint(i%8))
+		return valid, "bit-flip"
+	case 3:
+		valid[rng.Intn(size)] = byte(rng.Intn(256))
+		return valid, "byte-set"
+	case 4:
+		// Flag bits (compressed) or the top of the first field element (EIP-2537)
+		i := 0
+		if kind == "g1e" || kind == "g2e" {
+			i = 16 * rng.Intn(2)
+		}
+		valid[i] ^= byte(rng.Intn(8)) << 5
+		return valid, "flags"
+	}
+	// Random x with the flag bits kept: on the curve about half the time, and almost
+	// never in the subgroup
+	start := 0
+	if kind == "g1e" || kind == "g2e" {
+		start = 16
+	}
+	rng.Read(valid[start+1 : start+48])
+	valid[start] = valid[start]&0xE0 | byte(rng.Intn(0x1a))
+	return valid, "random-x"
+}
+
+// fuzzMismatch is one disagreement between the Go decoders and the oracle
+type fuzzMismatch struct {
+	kind, strategy string
+	data           []byte
+	got, want      fuzzVerdict
+}
+
+// runFuzzSerializationMode fuzzes the deserializers against an oracle for --duration.
+// Returns the number of mismatches.
+func runFuzzSerializationMode(args []string) (int, error) {
+	fs := newFlagSet("fuzz-serialization")
+	duration := fs.Duration("duration", 30*time.Second, "How long to fuzz")
+	oracleCmd := fs.String("oracle", "gnark", "Oracle: gnark (built-in) or a command speaking the line protocol")
+	kinds := fs.String("kinds", "g1c,g2c,g1e,g2e", "Blob kinds to fuzz (comma-separated)")
+	seed := fs.Int64("seed", 0, "Seed for the blob generator (default: time-based, printed)")
+	maxFailures := fs.Int("max-failures", 10, "Stop after this many mismatches (0 = never)")
+	corpus := fs.String("corpus", "", "Append mismatching blobs to this file as '<kind> <hex>' lines")
+	failures := fs.String("failures", "", "Append mismatches to this file as JSON lines, for replay-divergences")
+	workers := fs.Int("workers", runtime.NumCPU(), "Goroutines running the Go decoders")
+	oracleWorkers := fs.Int("oracle-workers", 0, "Oracle instances queried in parallel (default: one per CPU for gnark, 1 for a command)")
+	queue := fs.Int("queue", 64, "Capacity of the queues between pipeline stages")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *duration <= 0 {
+		return 0, fmt.Errorf("--duration must be positive")
+	}
+	if *oracleWorkers == 0 {
+		*oracleWorkers = 1
+		if *oracleCmd == "gnark" {
+			*oracleWorkers = runtime.NumCPU()
+		}
+	}
+	if *workers < 1 || *oracleWorkers < 1 || *queue < 1 {
+		return 0, fmt.Errorf("--workers, --oracle-workers and --queue must be at least 1")
+	}
+
+	var selected []int
+	for _, k := range strings.Split(*kinds, ",") {
+		k = strings.TrimSpace(k)
+		found := false
+		for i, fk := range fuzzKinds {
+			if fk.kind == k {
+				selected = append(selected, i)
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid kind '%s' (valid: g1c, g2c, g1e, g2e)", k)
+		}
+	}
+
+	newOracle := func() (fuzzOracle, error) { return gnarkOracle{}, nil }
+	if *oracleCmd != "gnark" {
+		newOracle = func() (fuzzOracle, error) { return newCommandOracle(*oracleCmd) }
+	}
+
+	var corpusFile *os.File
+	if *corpus != "" {
+		f, err := os.OpenFile(*corpus, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		corpusFile = f
+	}
+	var failuresFile *os.File
+	if *failures != "" {
+		f, err := os.OpenFile(*failures, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		failuresFile = f
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+
+	fmt.Println("=== Serialization Fuzzing ===")
+	fmt.Printf("Oracle: %s, kinds: %s, duration: %s, seed: %d\n", *oracleCmd, *kinds, *duration, *seed)
+	fmt.Printf("Workers: %d Go, %d oracle, queue %d\n", *workers, *oracleWorkers, *queue)
+
+	var mismatches []fuzzMismatch
+	counts := map[string][2]int{} // kind -> [accepted, rejected]
+	total := 0
+	cfg := fuzzPipelineConfig{workers: *workers, oracleWorkers: *oracleWorkers, queue: *queue, deadline: time.Now().Add(*duration)}
+	stats, elapsed, err := runFuzzPipeline(cfg, rng, selected, newOracle, func(c fuzzCase) bool {
+		total++
+		n := counts[c.kind]
+		if c.got.accept {
+			n[0]++
+		} else {
+			n[1]++
+		}
+		counts[c.kind] = n
+
+		if c.got.accept == c.want.accept && (!c.got.accept || c.got.point == c.want.point) {
+			return true
+		}
+		m := fuzzMismatch{kind: c.kind, strategy: c.strategy, data: c.data, got: c.got, want: c.want}
+		mismatches = append(mismatches, m)
+		fmt.Printf("❌ %s (%s): %x\n   go:     %s\n   oracle: %s\n", m.kind, m.strategy, m.data, m.got, m.want)
+		if corpusFile != nil {
+			fmt.Fprintf(corpusFile, "%s %x\n", m.kind, m.data)
+		}
+		if failuresFile != nil {
+			if err := appendDivergence(failuresFile, newDivergenceRecord(m, *oracleCmd, *seed)); err != nil {
+				logging.Printf("Warning: failed to write %s: %v", *failures, err)
+			}
+		}
+		if *maxFailures > 0 && len(mismatches) >= *maxFailures {
+			fmt.Printf("Stopping after %d mismatch(es)\n", len(mismatches))
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return len(mismatches), err
+	}
+
+	fmt.Printf("\nBlobs: %d\n", total)
+	for _, i := range selected {
+		c := counts[fuzzKinds[i].kind]
+		fmt.Printf("  %s (%d bytes): %d accepted, %d rejected\n", fuzzKinds[i].kind, fuzzKinds[i].size, c[0], c[1])
+	}
+	printFuzzPipelineStats(stats, elapsed, total)
+	if len(mismatches) > 0 {
+		fmt.Printf("%d mismatch(es) FOUND (reproduce with --seed %d)\n", len(mismatches), *seed)
+	} else {
+		fmt.Println("✅ Go decoders and oracle agree on every blob")
+	}
+	return len(mismatches), nil
+}
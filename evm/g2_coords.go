@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// decodeAnyG2 decodes a G2 point from its encoding, selected by length:
+//   - 96 bytes: compressed (Neo/ZCash, x.C1 | x.C0 with flags)
+//   - 192 bytes: gnark Marshal() uncompressed (x.C1 | x.C0 | y.C1 | y.C0)
+//   - 256 bytes: Ethereum EIP-2537 (x.C0 | x.C1 | y.C0 | y.C1, each 64-byte padded)
+//
+// No subgroup check is applied so that any on-curve point can be inspected.
+func decodeAnyG2(data []byte) (bls.G2Affine, string, error) {
+	var p bls.G2Affine
+	switch len(data) {
+	case 96:
+		if err := bls.NewDecoder(bytes.NewReader(data), bls.NoSubgroupChecks()).Decode(&p); err != nil {
+			return p, "", fmt.Errorf("invalid compressed G2 point: %v", err)
+		}
+		return p, "compressed (96 bytes)", nil
+	case 192:
+		if err := bls.NewDecoder(bytes.NewReader(data), bls.NoSubgroupChecks()).Decode(&p); err != nil {
+			return p, "", fmt.Errorf("invalid gnark uncompressed G2 point: %v", err)
+		}
+		return p, "gnark uncompressed (192 bytes)", nil
+	case 256:
+		p, err := serialization.DecodeEIP2537G2Point(data, false)
+		if err != nil {
+			return p, "", fmt.Errorf("invalid Ethereum G2 point: %v", err)
+		}
+		return p, "Ethereum EIP-2537 (256 bytes)", nil
+	}
+	return p, "", fmt.Errorf("unsupported G2 encoding length %d (expected 96, 192 or 256 bytes)", len(data))
+}
+
+// printFpCoordinate prints one Fp coordinate in hex (48 bytes) and decimal
+func printFpCoordinate(label string, e fp.Element) {
+	b := e.Bytes()
+	fmt.Printf("  %-5s hex: %x\n", label, b)
+	fmt.Printf("  %-5s dec: %s\n", label, e.String())
+}
+
+// runG2CoordsMode prints the four Fp coordinates of a G2 point and where each one sits in
+// the gnark and Ethereum layouts
+func runG2CoordsMode(args []string) error {
+	fs := newFlagSet("g2-coords")
+	pointHex := fs.String("point", "", "G2 point hex: compressed (96 bytes), gnark uncompressed (192) or Ethereum (256)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *pointHex == "" {
+		return fmt.Errorf("--point is required")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*pointHex), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid hex: %v", err)
+	}
+	p, encoding, err := decodeAnyG2(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("=== G2 Coordinates ===")
+	fmt.Printf("Input encoding: %s\n", encoding)
+	fmt.Printf("Infinity: %v, on curve: %v, in subgroup: %v\n", p.IsInfinity(), p.IsOnCurve(), p.IsInSubGroup())
+	fmt.Println()
+	fmt.Println("Coordinates (x = x.C0 + x.C1*u, y = y.C0 + y.C1*u):")
+	printFpCoordinate("x.C0", p.X.A0)
+	printFpCoordinate("x.C1", p.X.A1)
+	printFpCoordinate("y.C0", p.Y.A0)
+	printFpCoordinate("y.C1", p.Y.A1)
+	fmt.Println()
+
+	fmt.Println("gnark ordering (Marshal / compressed): x.C1 | x.C0 | y.C1 | y.C0")
+	fmt.Printf("  uncompressed (192 bytes): %x\n", p.Marshal())
+	fmt.Printf("  compressed (96 bytes):    %x\n", serialization.ConvertG2AffineToCompressed(p))
+	fmt.Println("Ethereum ordering (EIP-2537): x.C0 | x.C1 | y.C0 | y.C1, each 16 zero bytes + 48 bytes")
+	fmt.Printf("  (256 bytes): %x\n", serialization.EncodeEthereumG2Point(p))
+	return nil
+}
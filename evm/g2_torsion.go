@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The G2 cofactor h2 = 13^2 * 23^2 * 2713 * 11953 * 262069 * p448 gives E'(Fp2) torsion
+// components of many sizes, and each is a way for a weak subgroup check to fail: a check
+// for small orders misses the p448 component, a check that clears only some primes misses
+// the others, and a point with an r component added looks like a subgroup point to tests
+// that multiply by h. g2-torsion builds a point of exactly the requested order in each of
+// them rather than hoping a random curve point lands there. The 13 and 23 parts are
+// Z/13 x Z/13 and Z/23 x Z/23, so no point has order 169 or 529.
+
+// g2TorsionSamples bounds the curve points tried for one order; a cyclic component yields
+// a point of the full order with probability at least 12/13 per sample
+const g2TorsionSamples = 32
+
+// g2TorsionSpec is one requested order m, a divisor of h2, and whether a subgroup point is
+// added to give order r * m
+type g2TorsionSpec struct {
+	token  string
+	label  string // m as printed: decimal, p448 or a product of primes
+	m      *big.Int
+	plusR  bool
+	primes []*big.Int // the distinct primes of m
+}
+
+// g2TorsionDefaults covers every small prime, the product of the small primes, the large
+// prime and two r * m points
+var g2TorsionDefaults = []string{"13", "23", "2713", "11953", "262069", "small", "p448", "r*13", "r*p448"}
+
+// parseG2TorsionSpec parses an --order token: a decimal divisor of h2, "small" (the product
+// of the distinct small primes) or "p448", optionally prefixed with "r*"
+func parseG2TorsionSpec(token string) (g2TorsionSpec, error) {
+	spec := g2TorsionSpec{token: token}
+	body := token
+	if strings.HasPrefix(body, "r*") {
+		spec.plusR, body = true, strings.TrimPrefix(body, "r*")
+	}
+	c := newCurvePoint(true)
+	large := c.orderH[len(c.orderH)-1]
+	spec.label = body
+	switch body {
+	case "small":
+		spec.m = big.NewInt(1)
+		var factors []string
+		for i, l := range c.orderH[:len(c.orderH)-1] {
+			if i == 0 || c.orderH[i-1].Cmp(l) != 0 {
+				spec.m.Mul(spec.m, l)
+				factors = append(factors, l.String())
+			}
+		}
+		spec.label = strings.Join(factors, " * ")
+	case "p448":
+		spec.m = new(big.Int).Set(large)
+	default:
+		var ok bool
+		if spec.m, ok = new(big.Int).SetString(body, 10); !ok || spec.m.Cmp(big.NewInt(1)) <= 0 {
+			return spec, fmt.Errorf("'%s' is not an order: use a decimal divisor of h2, small or p448, optionally prefixed with r*", token)
+		}
+	}
+	if new(big.Int).Mod(c.cofactor(), spec.m).Sign() != 0 {
+		return spec, fmt.Errorf("%s does not divide the G2 cofactor h2", body)
+	}
+	for i, l := range c.orderH {
+		if (i == 0 || c.orderH[i-1].Cmp(l) != 0) && new(big.Int).Mod(spec.m, l).Sign() == 0 {
+			spec.primes = append(spec.primes, l)
+		}
+	}
+	return spec, nil
+}
+
+// g2TorsionPoint returns a point of order exactly m: [m'/m][r]R for a curve point R whose
+// cofactor part has an order m' divisible by m. It reports false when no sample has such
+// an m', which means E'(Fp2) has no cyclic component of order m.
+func g2TorsionPoint(m *big.Int) (curvePoint, int, bool, error) {
+	for tries := 1; tries <= g2TorsionSamples; tries++ {
+		r, err := randomCurvePoint(true)
+		if err != nil {
+			return r, tries, false, err
+		}
+		order := r.torsionOrder()
+		if new(big.Int).Mod(order, m).Sign() == 0 {
+			return r.mul(fr.Modulus()).mul(new(big.Int).Quo(order, m)), tries, true, nil
+		}
+	}
+	return newCurvePoint(true), g2TorsionSamples, false, nil
+}
+
+// hasExactOrder checks [n]P = O and [n/l]P != O for every prime l of n
+func hasExactOrder(c curvePoint, n *big.Int, primes []*big.Int) bool {
+	if !c.mul(n).isInfinity() {
+		return false
+	}
+	for _, l := range primes {
+		if c.mul(new(big.Int).Quo(n, l)).isInfinity() {
+			return false
+		}
+	}
+	return true
+}
+
+// runG2TorsionMode constructs G2 points of each requested torsion order, verifies the
+// order and checks that the strict decoders and the MSM reject them with NOT_IN_SUBGROUP
+func runG2TorsionMode(args []string) (int, error) {
+	fs := newFlagSet("g2-torsion")
+	orders := fs.String("order", "all", "Orders (comma-separated): a decimal divisor of h2, small or p448, each optionally r*<m>; all for "+strings.Join(g2TorsionDefaults, ","))
+	seed := registerSeedFlag(fs)
+	outPath := fs.String("out", "", "Also write the vectors as a fixture file (JSON, the corrupt-nearby/verify-errors format)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	tokens := g2TorsionDefaults
+	if *orders != "all" {
+		tokens = nil
+		for _, t := range strings.Split(*orders, ",") {
+			tokens = append(tokens, strings.ReplaceAll(strings.TrimSpace(t), " ", ""))
+		}
+	}
+	var specs []g2TorsionSpec
+	for _, t := range tokens {
+		spec, err := parseG2TorsionSpec(t)
+		if err != nil {
+			return 0, usageErrorf("--order: %v", err)
+		}
+		specs = append(specs, spec)
+	}
+	if err := applySeed(*seed); err != nil {
+		return 0, err
+	}
+	subgroup, err := randomSubgroupPoint(true)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Println("=== G2 Torsion Points ===")
+	fmt.Printf("h2 = %s\n", newCurvePoint(true).cofactor().String())
+	fmt.Println()
+	var fixtures []negativeFixture
+	failed := 0
+	for _, spec := range specs {
+		p, tries, found, err := g2TorsionPoint(spec.m)
+		if err != nil {
+			return failed, err
+		}
+		name := "g2_torsion_" + strings.ReplaceAll(strings.ReplaceAll(spec.token, "r*", "r_times_"), "*", "_")
+		if !found {
+			fmt.Printf("%s: ⚠️  no point of order %s in %d samples: E'(Fp2) has no cyclic component of that order\n\n", name, spec.label, tries)
+			continue
+		}
+		n, primes, want := spec.m, spec.primes, spec.label
+		description := fmt.Sprintf("point of order %s", spec.label)
+		if spec.plusR {
+			p = p.add(subgroup)
+			n = new(big.Int).Mul(spec.m, fr.Modulus())
+			primes = append([]*big.Int{fr.Modulus()}, primes...)
+			want = "r * " + spec.label
+			description = fmt.Sprintf("subgroup point plus a point of order %s (order %s)", spec.label, want)
+		}
+		order := p.order()
+		compressed, ethereum := p.compressed(), p.ethereum()
+		fmt.Printf("%s: %s\n", name, description)
+		fmt.Printf("  Order: %s (curve points drawn: %d)\n", order, tries)
+		fmt.Printf("  Compressed: %x\n", compressed)
+		fmt.Printf("  Ethereum: %x\n", ethereum)
+		if !p.p2.IsOnCurve() || !hasExactOrder(p, n, primes) || p.inSubgroup() {
+			fmt.Printf("  ❌ the point is not an on-curve point of order exactly %s\n", want)
+			failed++
+		}
+
+		msmInput := append(append([]byte{}, ethereum...), scalarTo32Bytes(big.NewInt(1))...)
+		_, compressedErr := validatePoint("g2c", compressed)
+		_, ethereumErr := validatePoint("g2e", ethereum)
+		msmCode, _, _ := firstValidationError("eip2537", "g2msm", msmInput)
+		compressedCode, _ := classifyError(compressedErr)
+		ethereumCode, _ := classifyError(ethereumErr)
+		checks := []struct {
+			fx   negativeFixture
+			code errorCode
+		}{
+			{negativeFixture{Name: name + "_compressed", Op: "deserialize-g2", Input: hex.EncodeToString(compressed)}, compressedCode},
+			{negativeFixture{Name: name + "_eip2537", Op: "eip2537-g2", Input: hex.EncodeToString(ethereum)}, ethereumCode},
+			{negativeFixture{Name: name + "_msm", Op: "g2msm", Input: hex.EncodeToString(msmInput)}, msmCode},
+		}
+		for _, c := range checks {
+			fx := c.fx
+			fx.Expected, fx.ErrorCode, fx.Reason = "reject", errNotInSubgroup, description
+			if c.code != fx.ErrorCode {
+				fmt.Printf("  ❌ %s: got %q, want %q\n", fx.Op, c.code, fx.ErrorCode)
+				failed++
+			}
+			fixtures = append(fixtures, fx)
+		}
+		fmt.Println()
+	}
+	if failed == 0 {
+		fmt.Printf("✅ %d fixtures: every torsion point has its order and is rejected as NOT_IN_SUBGROUP\n", len(fixtures))
+	}
+	reportOutput("fixtures", len(fixtures))
+	recordQuietFixtures(fixtures)
+	if *outPath != "" {
+		if err := writeNegativeFixtures(*outPath, "g2-torsion --order "+*orders, fixtures); err != nil {
+			return failed, err
+		}
+		fmt.Printf("Wrote %s\n", *outPath)
+	}
+	return failed, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// eip2537GasOps are the operations the gas mode prices, in EIP order. The MUL operations
+// are one-pair MSMs.
+var eip2537GasOps = []string{"g1add", "g1mul", "g1msm", "g2add", "g2mul", "g2msm", "pairing", "map-fp-to-g1", "map-fp2-to-g2"}
+
+// eip2537InputPairs returns k for an Ethereum-format input of n bytes: the pair count of
+// the MSMs and pairing, 1 for the fixed-size operations. It fails when n is not a whole
+// input, for which the precompile fails and consumes all gas passed to it.
+func eip2537InputPairs(op string, n int) (int, error) {
+	switch op {
+	case "map-fp-to-g1", "map-fp2-to-g2":
+		want := 64
+		if op == "map-fp2-to-g2" {
+			want = 128
+		}
+		if n != want {
+			return 0, fmt.Errorf("%s input must be %d bytes, got %d", op, want, n)
+		}
+		return 1, nil
+	}
+	layout := opLayouts[op]
+	if !layout.fits("ethereum", n) {
+		if layout.exact {
+			return 0, fmt.Errorf("%s input must be %d bytes, got %d", op, layout.ethereum, n)
+		}
+		return 0, fmt.Errorf("%s input must be a non-zero multiple of %d bytes, got %d", op, layout.ethereum, n)
+	}
+	if layout.exact {
+		return 1, nil
+	}
+	return n / layout.ethereum, nil
+}
+
+// eip2537GasFormula spells out how gasCost arrives at the cost of k pairs
+func eip2537GasFormula(op string, k int) string {
+	cost, _ := gasEIP2537.gasCost(op, k)
+	switch op {
+	case "g1mul", "g1msm", "g2mul", "g2msm":
+		base, discount := uint64(12000), eip2537G1MSMDiscount
+		if strings.HasPrefix(op, "g2") {
+			base, discount = 22500, eip2537G2MSMDiscount
+		}
+		d := discount[min(k, len(discount))-1]
+		note := ""
+		if k > len(discount) {
+			note = fmt.Sprintf(" (the table's last entry, used for every k > %d)", len(discount))
+		}
+		return fmt.Sprintf("k * %d * discount(k) / 1000 = %d * %d * %d / 1000 = %d%s", base, k, base, d, cost, note)
+	case "pairing":
+		return fmt.Sprintf("32600 * k + 37700 = 32600 * %d + 37700 = %d", k, cost)
+	}
+	return fmt.Sprintf("fixed %d", cost)
+}
+
+// runGasMode prints the EIP-2537 gas of an input or of k pairs
+func runGasMode(args []string) error {
+	fs := newFlagSet("gas")
+	op := fs.String("op", "", "Operation: "+strings.Join(eip2537GasOps, ", "))
+	inputHex := registerInputFlag(fs, "Ethereum-format input hex; k is derived from its length")
+	pairs := fs.Int("pairs", 0, "Number of pairs k instead of --input (MSMs and pairing)")
+	table := fs.Bool("table", false, "Print the cost for k = 1..129 pairs (MSMs and pairing)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	known := false
+	for _, name := range eip2537GasOps {
+		known = known || name == *op
+	}
+	if !known {
+		return usageErrorf("invalid --op '%s' (valid: %s)", *op, strings.Join(eip2537GasOps, ", "))
+	}
+	perPair := pairSizeForOp(*op) > 0
+	if (*pairs != 0 || *table) && !perPair {
+		return usageErrorf("--pairs and --table only apply to g1msm, g2msm and pairing")
+	}
+
+	fmt.Println("=== EIP-2537 Gas ===")
+	fmt.Printf("Operation: %s\n", *op)
+	if *table {
+		maxK := len(eip2537G1MSMDiscount) + 1
+		fmt.Printf("%5s  %10s  %10s\n", "k", "gas", "per pair")
+		costs := make([]uint64, maxK)
+		for k := 1; k <= maxK; k++ {
+			cost, _ := gasEIP2537.gasCost(*op, k)
+			costs[k-1] = cost
+			fmt.Printf("%5d  %10d  %10d\n", k, cost, cost/uint64(k))
+		}
+		reportOutput("gas_table", costs)
+		return nil
+	}
+
+	k := 1
+	switch {
+	case *inputHex != "" && *pairs != 0:
+		return usageErrorf("use either --input or --pairs")
+	case *inputHex != "":
+		data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*inputHex), "0x"))
+		if err != nil {
+			return usageErrorf("invalid --input hex: %v", err)
+		}
+		recordVerdictInput(*inputHex)
+		reportInput("input", *inputHex)
+		if k, err = eip2537InputPairs(*op, len(data)); err != nil {
+			return err
+		}
+		fmt.Printf("Input: %d bytes\n", len(data))
+	case *pairs < 0:
+		return usageErrorf("--pairs must be positive")
+	case *pairs > 0:
+		k = *pairs
+	case perPair:
+		return usageErrorf("--input, --pairs or --table is required for %s", *op)
+	}
+	if perPair {
+		fmt.Printf("Pairs: %d\n", k)
+	}
+	cost, _ := gasEIP2537.gasCost(*op, k)
+	fmt.Printf("Formula: %s\n", eip2537GasFormula(*op, k))
+	fmt.Printf("Gas: %d\n", cost)
+	reportOutput("pairs", k)
+	reportOutput("gas", cost)
+	return nil
+}
+
+// reportEIP2537Gas adds the EIP-2537 gas of an operation's input to the JSON document;
+// runs with a --chain print their chain's cost instead
+func reportEIP2537Gas(op, inputHex string) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(inputHex), "0x"))
+	if err != nil {
+		return
+	}
+	if k, err := eip2537InputPairs(op, len(data)); err == nil {
+		cost, _ := gasEIP2537.gasCost(op, k)
+		reportOutput("gas", cost)
+	}
+}
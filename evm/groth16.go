@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16bls "github.com/consensys/gnark/backend/groth16/bls12-381"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/logger"
+)
+
+// Groth16 over BLS12-381 for the circuit x^3 + x + 5 == y, the cubic example of gnark:
+// y is public and x is the secret witness. The circuit is compiled with gnark's R1CS
+// frontend and set up, proved and verified with gnark's groth16 backend, so the keys and
+// proofs are gnark's own. The proof is then also checked with the pairing equation
+// e(A, B) = e(alpha, beta) * e(vk_x, gamma) * e(C, delta), vk_x = K_0 + sum_i y_i K_i,
+// and through the EIP-2537 pairing precompile.
+
+// groth16Circuit is x^3 + x + 5 == y
+type groth16Circuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *groth16Circuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+// groth16Cubic evaluates the circuit outside gnark: y = x^3 + x + 5
+func groth16Cubic(x fr.Element) fr.Element {
+	var y, five fr.Element
+	five.SetUint64(5)
+	y.Square(&x).Mul(&y, &x).Add(&y, &x).Add(&y, &five)
+	return y
+}
+
+// groth16Prove proves knowledge of x for y = x^3 + x + 5 with gnark
+func groth16Prove(ccs constraint.ConstraintSystem, pk groth16.ProvingKey, x fr.Element) (*groth16bls.Proof, error) {
+	y := groth16Cubic(x)
+	w, err := frontend.NewWitness(&groth16Circuit{X: x.BigInt(new(big.Int)), Y: y.BigInt(new(big.Int))}, ecc.BLS12_381.ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	proof, err := groth16.Prove(ccs, pk, w)
+	if err != nil {
+		return nil, fmt.Errorf("gnark prover: %v", err)
+	}
+	return proof.(*groth16bls.Proof), nil
+}
+
+// groth16GnarkVerify runs gnark's verifier against the public input y
+func groth16GnarkVerify(vk *groth16bls.VerifyingKey, proof *groth16bls.Proof, y fr.Element) error {
+	w, err := frontend.NewWitness(&groth16Circuit{Y: y.BigInt(new(big.Int))}, ecc.BLS12_381.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return err
+	}
+	return groth16.Verify(proof, vk, w)
+}
+
+// groth16VKX is vk_x = K_0 + sum_i x_i K_i over the public inputs after the constant 1
+func groth16VKX(vk *groth16bls.VerifyingKey, public []fr.Element) bls.G1Affine {
+	var acc bls.G1Jac
+	acc.FromAffine(&vk.G1.K[0])
+	for i, x := range public {
+		var term bls.G1Jac
+		term.FromAffine(&vk.G1.K[i+1])
+		term.ScalarMultiplication(&term, x.BigInt(new(big.Int)))
+		acc.AddAssign(&term)
+	}
+	var p bls.G1Affine
+	return *p.FromJacobian(&acc)
+}
+
+// groth16PairingInput is the EIP-2537 pairing input of
+// e(-A, B) * e(alpha, beta) * e(vk_x, gamma) * e(C, delta) == 1
+func groth16PairingInput(vk *groth16bls.VerifyingKey, proof *groth16bls.Proof, public []fr.Element) []byte {
+	var negA bls.G1Affine
+	negA.Neg(&proof.Ar)
+	vkx := groth16VKX(vk, public)
+	var out []byte
+	for _, pair := range []struct {
+		p bls.G1Affine
+		q bls.G2Affine
+	}{{negA, proof.Bs}, {vk.G1.Alpha, vk.G2.Beta}, {vkx, vk.G2.Gamma}, {proof.Krs, vk.G2.Delta}} {
+		out = append(out, serialization.EncodeEthereumG1Point(pair.p)...)
+		out = append(out, serialization.EncodeEthereumG2Point(pair.q)...)
+	}
+	return out
+}
+
+// groth16Verify checks a proof with the pairing equation, independently of gnark
+func groth16Verify(vk *groth16bls.VerifyingKey, proof *groth16bls.Proof, public []fr.Element) error {
+	var negA bls.G1Affine
+	negA.Neg(&proof.Ar)
+	ok, err := bls.PairingCheck(
+		[]bls.G1Affine{negA, vk.G1.Alpha, groth16VKX(vk, public), proof.Krs},
+		[]bls.G2Affine{proof.Bs, vk.G2.Beta, vk.G2.Gamma, vk.G2.Delta})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("pairing check failed: e(A, B) != e(alpha, beta) * e(vk_x, gamma) * e(C, delta)")
+	}
+	return nil
+}
+
+// gnarkEncodings are the two serializations gnark writes: WriteTo (compressed points)
+// and WriteRawTo (uncompressed points)
+type gnarkEncodings struct {
+	Compressed string `json:"gnark"`
+	Raw        string `json:"gnark_raw"`
+}
+
+func newGnarkEncodings(v interface {
+	WriteTo(io.Writer) (int64, error)
+	WriteRawTo(io.Writer) (int64, error)
+}) (gnarkEncodings, error) {
+	var compressed, raw bytes.Buffer
+	if _, err := v.WriteTo(&compressed); err != nil {
+		return gnarkEncodings{}, err
+	}
+	if _, err := v.WriteRawTo(&raw); err != nil {
+		return gnarkEncodings{}, err
+	}
+	return gnarkEncodings{Compressed: hex.EncodeToString(compressed.Bytes()), Raw: hex.EncodeToString(raw.Bytes())}, nil
+}
+
+// fpHex is a base field element as 48-byte big-endian hex
+func fpHex(e fp.Element) string {
+	b := e.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+// flatG1 and flatG2 list the coordinates, G2 in the EIP-2537 order x.c0, x.c1, y.c0, y.c1
+func flatG1(p bls.G1Affine) []string {
+	return []string{fpHex(p.X), fpHex(p.Y)}
+}
+
+func flatG2(p bls.G2Affine) []string {
+	return []string{fpHex(p.X.A0), fpHex(p.X.A1), fpHex(p.Y.A0), fpHex(p.Y.A1)}
+}
+
+// groth16VKJSON is the verifying key as gnark serializes it, point by point in the
+// compressed encoding of gnark-crypto (and Neo's bls12381Deserialize) and as flattened
+// base field elements
+type groth16VKJSON struct {
+	gnarkEncodings
+	Alpha string   `json:"alpha_g1"`
+	Beta  string   `json:"beta_g2"`
+	Gamma string   `json:"gamma_g2"`
+	Delta string   `json:"delta_g2"`
+	IC    []string `json:"ic"`
+	Flat  []string `json:"flat"`
+}
+
+// groth16ProofJSON is a proof in the same formats
+type groth16ProofJSON struct {
+	gnarkEncodings
+	A    string   `json:"a"`
+	B    string   `json:"b"`
+	C    string   `json:"c"`
+	Flat []string `json:"flat"`
+}
+
+// groth16Vector is one proof, its public inputs and the expected verification result
+type groth16Vector struct {
+	Name         string           `json:"name"`
+	PublicInputs []string         `json:"public_inputs"`
+	Proof        groth16ProofJSON `json:"proof"`
+	PairingInput string           `json:"eip2537_pairing_input"`
+	Valid        bool             `json:"valid"`
+	Reason       string           `json:"reason,omitempty"`
+}
+
+func newGroth16VKJSON(vk *groth16bls.VerifyingKey) (groth16VKJSON, error) {
+	enc, err := newGnarkEncodings(vk)
+	if err != nil {
+		return groth16VKJSON{}, err
+	}
+	j := groth16VKJSON{
+		gnarkEncodings: enc,
+		Alpha:          hex.EncodeToString(serialization.ConvertG1AffineToCompressed(vk.G1.Alpha)),
+		Beta:           hex.EncodeToString(serialization.ConvertG2AffineToCompressed(vk.G2.Beta)),
+		Gamma:          hex.EncodeToString(serialization.ConvertG2AffineToCompressed(vk.G2.Gamma)),
+		Delta:          hex.EncodeToString(serialization.ConvertG2AffineToCompressed(vk.G2.Delta)),
+	}
+	j.Flat = append(append(append(flatG1(vk.G1.Alpha), flatG2(vk.G2.Beta)...), flatG2(vk.G2.Gamma)...), flatG2(vk.G2.Delta)...)
+	for _, p := range vk.G1.K {
+		j.IC = append(j.IC, hex.EncodeToString(serialization.ConvertG1AffineToCompressed(p)))
+		j.Flat = append(j.Flat, flatG1(p)...)
+	}
+	return j, nil
+}
+
+func newGroth16ProofJSON(p *groth16bls.Proof) (groth16ProofJSON, error) {
+	enc, err := newGnarkEncodings(p)
+	if err != nil {
+		return groth16ProofJSON{}, err
+	}
+	return groth16ProofJSON{
+		gnarkEncodings: enc,
+		A:              hex.EncodeToString(serialization.ConvertG1AffineToCompressed(p.Ar)),
+		B:              hex.EncodeToString(serialization.ConvertG2AffineToCompressed(p.Bs)),
+		C:              hex.EncodeToString(serialization.ConvertG1AffineToCompressed(p.Krs)),
+		Flat:           append(append(flatG1(p.Ar), flatG2(p.Bs)...), flatG1(p.Krs)...),
+	}, nil
+}
+
+// runGroth16Mode compiles the cubic circuit with gnark, runs gnark's setup and prover
+// and prints gnark's verifying key and proof with the public input, followed by the
+// vectors a verifier must refuse. Every vector is checked with gnark's verifier, the
+// pairing equation and the EIP-2537 pairing precompile; an unexpected result is a failure.
+func runGroth16Mode(args []string) (int, error) {
+	fs := newFlagSet("groth16")
+	xStr := fs.String("x", "3", "Secret witness x (decimal or hex); the public input is y = x^3 + x + 5")
+	out := fs.String("out", "", "Write the verifying key and the vectors as JSON to this file (through --sink)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	x, err := parseFrElement(*xStr)
+	if err != nil {
+		return 0, usageErrorf("--x: %v", err)
+	}
+
+	logger.Disable() // gnark logs compilation and proving to stdout
+	ccs, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &groth16Circuit{})
+	if err != nil {
+		return 0, fmt.Errorf("gnark compile: %v", err)
+	}
+	pk, vkAny, err := groth16.Setup(ccs)
+	if err != nil {
+		return 0, fmt.Errorf("gnark setup: %v", err)
+	}
+	vk := vkAny.(*groth16bls.VerifyingKey)
+	proof, err := groth16Prove(ccs, pk, x)
+	if err != nil {
+		return 0, err
+	}
+	var x1 fr.Element
+	x1.Add(&x, new(fr.Element).SetOne())
+	otherProof, err := groth16Prove(ccs, pk, x1)
+	if err != nil {
+		return 0, err
+	}
+	y := groth16Cubic(x)
+	var y1 fr.Element
+	y1.Add(&y, new(fr.Element).SetOne())
+	negated := *proof
+	negated.Ar.Neg(&proof.Ar)
+
+	cases := []struct {
+		name   string
+		proof  *groth16bls.Proof
+		public fr.Element
+		valid  bool
+	}{
+		{"valid", proof, y, true},
+		{"wrong-public-input", proof, y1, false},
+		{"proof-of-other-witness", otherProof, y, false},
+		{"negated-a", &negated, y, false},
+	}
+	vkJSON, err := newGroth16VKJSON(vk)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Println("=== Groth16 (BLS12-381, x^3 + x + 5 == y, gnark) ===")
+	fmt.Printf("Constraints: %d, public inputs: %d\n", ccs.GetNbConstraints(), len(vk.G1.K)-1)
+	fmt.Println("⚠️  gnark's single-party setup: the keys are fresh on every run and for tests only")
+	fmt.Printf("Public input y: %s\n", y.String())
+	fmt.Println("\nVerifying key (compressed):")
+	fmt.Printf("  alpha_g1: %s\n  beta_g2:  %s\n  gamma_g2: %s\n  delta_g2: %s\n", vkJSON.Alpha, vkJSON.Beta, vkJSON.Gamma, vkJSON.Delta)
+	for i, ic := range vkJSON.IC {
+		fmt.Printf("  ic[%d]:    %s\n", i, ic)
+	}
+	fmt.Printf("  gnark WriteTo:    %s\n", vkJSON.Compressed)
+	fmt.Printf("  gnark WriteRawTo: %s\n", vkJSON.Raw)
+	fmt.Println()
+	failed := 0
+	var vectors []groth16Vector
+	for _, c := range cases {
+		public := []fr.Element{c.public}
+		input := groth16PairingInput(vk, c.proof, public)
+		proofJSON, err := newGroth16ProofJSON(c.proof)
+		if err != nil {
+			return failed, err
+		}
+		vec := groth16Vector{Name: c.name, PublicInputs: []string{"0x" + hex.EncodeToString(frBytes(c.public))}, Proof: proofJSON, PairingInput: hex.EncodeToString(input), Valid: c.valid}
+		gnarkErr := groth16GnarkVerify(vk, c.proof, c.public)
+		verr := groth16Verify(vk, c.proof, public)
+		if verr != nil {
+			vec.Reason = verr.Error()
+		}
+		pairingOut, perr := eip2537Pairing(input)
+		precompileValid := perr == nil && len(pairingOut) == 32 && pairingOut[31] == 1
+		switch {
+		case (gnarkErr == nil) != c.valid:
+			fmt.Printf("❌ %-22s gnark's verifier returned %v, expected %v\n", c.name, gnarkErr, c.valid)
+			failed++
+		case (verr == nil) != c.valid:
+			fmt.Printf("❌ %-22s the pairing equation gives %v, expected %v\n", c.name, verr == nil, c.valid)
+			failed++
+		case precompileValid != c.valid:
+			fmt.Printf("❌ %-22s the EIP-2537 pairing input gives %x, %v\n", c.name, pairingOut, perr)
+			failed++
+		default:
+			result := "true"
+			if !c.valid {
+				result = "false (" + vec.Reason + ")"
+			}
+			fmt.Printf("✅ %-22s expected %s\n", c.name, result)
+		}
+		if c.valid {
+			fmt.Printf("   A: %s\n   B: %s\n   C: %s\n", vec.Proof.A, vec.Proof.B, vec.Proof.C)
+			fmt.Printf("   gnark WriteTo:    %s\n", vec.Proof.Compressed)
+			fmt.Printf("   gnark WriteRawTo: %s\n", vec.Proof.Raw)
+			fmt.Printf("   EIP-2537 pairing input (0x0f): %s\n", vec.PairingInput)
+		}
+		vectors = append(vectors, vec)
+	}
+	recordVerdictResult(vectors[0].PairingInput)
+	reportOutput("verifying_key", vkJSON)
+	reportOutput("vectors", vectors)
+	if *out != "" {
+		data, err := json.MarshalIndent(map[string]any{"circuit": "x^3 + x + 5 == y", "prover": "gnark groth16", "verifying_key": vkJSON, "vectors": vectors}, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+		if err := outputSink.write(*out, append(data, '\n')); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %v", *out, err)
+		}
+		fmt.Printf("Wrote %s\n", outputSink.describe(*out))
+	}
+	return failed, nil
+}
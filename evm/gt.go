@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// parseGTHex decodes a 576-byte GT element given as hex. format is "gnark", "neo" or "auto";
+// auto tries both encodings and keeps the one that decodes to a member of GT, which is
+// unambiguous for every element except those whose two encodings coincide.
+// It returns the element and the format that was used.
+func parseGTHex(s string, format string) (bls.GT, string, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return bls.GT{}, "", fmt.Errorf("invalid hex: %v", err)
+	}
+
+	switch format {
+	case "gnark":
+		z, err := serialization.DecodeGnarkGT(data)
+		return z, "gnark", err
+	case "neo":
+		z, err := serialization.DecodeNeoGT(data)
+		return z, "neo", err
+	case "auto":
+	default:
+		return bls.GT{}, "", fmt.Errorf("invalid GT format '%s' (valid: auto, gnark, neo)", format)
+	}
+
+	gnarkZ, gnarkErr := serialization.DecodeGnarkGT(data)
+	neoZ, neoErr := serialization.DecodeNeoGT(data)
+	gnarkOK := gnarkErr == nil && gnarkZ.IsInSubGroup()
+	neoOK := neoErr == nil && neoZ.IsInSubGroup()
+	switch {
+	case gnarkOK && neoOK:
+		if !gnarkZ.Equal(&neoZ) {
+			return bls.GT{}, "", fmt.Errorf("ambiguous GT encoding: valid as both gnark and neo, pass --format")
+		}
+		return gnarkZ, "gnark", nil
+	case gnarkOK:
+		return gnarkZ, "gnark", nil
+	case neoOK:
+		return neoZ, "neo", nil
+	case gnarkErr != nil && neoErr != nil:
+		return bls.GT{}, "", fmt.Errorf("not a valid GT encoding: %v", gnarkErr)
+	}
+	return bls.GT{}, "", fmt.Errorf("element is not in GT under either encoding, pass --format to compare anyway")
+}
+
+// runGTEqualMode compares two serialized GT elements with Neo's Bls12381Equal semantics:
+// two Gt values are equal iff their Fp12 values are equal, regardless of how they were
+// produced (one pairing, a multi-pairing product, an exponentiation, ...)
+func runGTEqualMode(args []string) error {
+	fs := newFlagSet("gt-equal")
+	aHex := fs.String("a", "", "First GT element (576 bytes hex)")
+	bHex := fs.String("b", "", "Second GT element (576 bytes hex)")
+	format := fs.String("format", "auto", "Encoding of both inputs: auto, gnark, neo")
+	aFormat := fs.String("a-format", "", "Encoding of --a (overrides --format)")
+	bFormat := fs.String("b-format", "", "Encoding of --b (overrides --format)")
+	gt := registerGTFormatFlags(fs, "neo")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := gt.validate(); err != nil {
+		return err
+	}
+	if *aHex == "" || *bHex == "" {
+		return fmt.Errorf("--a and --b are required")
+	}
+	if *aFormat == "" {
+		*aFormat = *format
+	}
+	if *bFormat == "" {
+		*bFormat = *format
+	}
+
+	a, aUsed, err := parseGTHex(*aHex, *aFormat)
+	if err != nil {
+		return fmt.Errorf("--a: %v", err)
+	}
+	b, bUsed, err := parseGTHex(*bHex, *bFormat)
+	if err != nil {
+		return fmt.Errorf("--b: %v", err)
+	}
+
+	equal := a.Equal(&b)
+	fmt.Println("=== GT Equality ===")
+	fmt.Printf("A encoding: %s (in GT: %v)\n", aUsed, a.IsInSubGroup())
+	fmt.Printf("B encoding: %s (in GT: %v)\n", bUsed, b.IsInSubGroup())
+	gt.print("A", &a)
+	gt.print("B", &b)
+	fmt.Printf("Equal: %v\n", equal)
+	return nil
+}
+
+// gtEqualVector is a pair of GT elements computed two different ways
+type gtEqualVector struct {
+	Name     string
+	A, B     bls.GT
+	Expected bool
+}
+
+// gtEqualVectors derives equal and unequal GT pairs from different pairing computations
+func gtEqualVectors() []gtEqualVector {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	a := big.NewInt(5)
+	b := big.NewInt(7)
+	ab := new(big.Int).Mul(a, b)
+	aPlusOne := new(big.Int).Add(a, big.NewInt(1))
+
+	var aP, abP, a1P, negP, twoP bls.G1Affine
+	aP.ScalarMultiplication(&g1Gen, a)
+	abP.ScalarMultiplication(&g1Gen, ab)
+	a1P.ScalarMultiplication(&g1Gen, aPlusOne)
+	negP.Neg(&g1Gen)
+	twoP.Double(&g1Gen)
+	var aQ, bQ bls.G2Affine
+	aQ.ScalarMultiplication(&g2Gen, a)
+	bQ.ScalarMultiplication(&g2Gen, b)
+
+	pair := func(p []bls.G1Affine, q []bls.G2Affine) bls.GT {
+		z, err := bls.Pair(p, q)
+		if err != nil {
+			panic(fmt.Sprintf("pairing failed: %v", err))
+		}
+		return z
+	}
+
+	base := pair([]bls.G1Affine{g1Gen}, []bls.G2Affine{g2Gen})
+	var one, baseExpA, baseSquared, baseInverse bls.GT
+	one.SetOne()
+	baseExpA.Exp(base, a)
+	baseSquared.Square(&base)
+	baseInverse.Inverse(&base)
+
+	return []gtEqualVector{
+		{"e(aP,Q)_eq_e(P,aQ)", pair([]bls.G1Affine{aP}, []bls.G2Affine{g2Gen}), pair([]bls.G1Affine{g1Gen}, []bls.G2Affine{aQ}), true},
+		{"e(aP,bQ)_eq_e(abP,Q)", pair([]bls.G1Affine{aP}, []bls.G2Affine{bQ}), pair([]bls.G1Affine{abP}, []bls.G2Affine{g2Gen}), true},
+		{"e(P,Q)^a_eq_e(aP,Q)", baseExpA, pair([]bls.G1Affine{aP}, []bls.G2Affine{g2Gen}), true},
+		{"e(P,Q)*e(P,Q)_eq_e(2P,Q)", pair([]bls.G1Affine{g1Gen, g1Gen}, []bls.G2Affine{g2Gen, g2Gen}), pair([]bls.G1Affine{twoP}, []bls.G2Affine{g2Gen}), true},
+		{"e(P,Q)^2_eq_e(2P,Q)", baseSquared, pair([]bls.G1Affine{twoP}, []bls.G2Affine{g2Gen}), true},
+		{"e(P,Q)*e(-P,Q)_eq_identity", pair([]bls.G1Affine{g1Gen, negP}, []bls.G2Affine{g2Gen, g2Gen}), one, true},
+		{"e(-P,Q)_eq_inverse_e(P,Q)", pair([]bls.G1Affine{negP}, []bls.G2Affine{g2Gen}), baseInverse, true},
+		{"e(P,Q)_ne_e(-P,Q)", base, pair([]bls.G1Affine{negP}, []bls.G2Affine{g2Gen}), false},
+		{"e(aP,Q)_ne_e((a+1)P,Q)", pair([]bls.G1Affine{aP}, []bls.G2Affine{g2Gen}), pair([]bls.G1Affine{a1P}, []bls.G2Affine{g2Gen}), false},
+		{"e(aP,bQ)_ne_e(aP,aQ)", pair([]bls.G1Affine{aP}, []bls.G2Affine{bQ}), pair([]bls.G1Affine{aP}, []bls.G2Affine{aQ}), false},
+		{"e(P,Q)_ne_identity", base, one, false},
+	}
+}
+
+// runGTEqualVectors prints the GT equality fixtures in the requested encoding(s)
+func runGTEqualVectors(args []string) error {
+	fs := newFlagSet("gt-equal-vectors")
+	format := fs.String("format", "neo", "Encoding to print: neo, gnark, both (shorthand for --gt-format)")
+	gt := registerGTFormatFlags(fs, "neo")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "neo" && *format != "gnark" && *format != "both" {
+		return fmt.Errorf("invalid format '%s' (valid: neo, gnark, both)", *format)
+	}
+	if !isFlagSet(fs, "gt-format") {
+		gt.Formats = strings.Replace(*format, "both", "neo,gnark", 1)
+	}
+	if err := gt.validate(); err != nil {
+		return err
+	}
+
+	fmt.Println("=== GT Equality Vectors ===")
+	fmt.Println("Neo's Bls12381Equal on two Gt values compares the Fp12 values; encodings are canonical,")
+	fmt.Println("so equal elements have byte-identical encodings within one format.")
+	fmt.Println()
+	for _, v := range gtEqualVectors() {
+		if got := v.A.Equal(&v.B); got != v.Expected {
+			return fmt.Errorf("vector %s: equality is %v, expected %v", v.Name, got, v.Expected)
+		}
+		fmt.Printf("Vector: %s\n", v.Name)
+		gt.print("  A", &v.A)
+		gt.print("  B", &v.B)
+		fmt.Printf("  Expected equal: %v\n", v.Expected)
+		fmt.Println()
+		recordQuietResult(v.Name, neoGTHex(&v.A), neoGTHex(&v.B), fmt.Sprint(v.Expected))
+	}
+	return nil
+}
+
+// runPairingGTMode prints the pairing product itself instead of the 32-byte identity
+// indicator of pairing, to compare with Neo's CryptoLib.Bls12381Pairing, which returns a
+// Gt. Several pairs give the product, i.e. Bls12381Mul over the per-pair results.
+func runPairingGTMode(args []string) error {
+	fs := newFlagSet("pairing-gt")
+	inputHex := registerInputFlag(fs, "Ethereum format input hex string (G1+G2 pairs, each pair is 384 bytes)")
+	inputFormat := fs.String("input-format", "ethereum", "Pair layout: ethereum (128+256 bytes), compressed (48+96 bytes) or auto")
+	gt := registerGTFormatFlags(fs, "neo,gnark")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := gt.validate(); err != nil {
+		return usageError{err}
+	}
+	if !isFlagSet(fs, "input") {
+		return usageErrorf("--input is required (\"\" for the empty product)")
+	}
+
+	normalized := ""
+	if strings.TrimSpace(*inputHex) != "" {
+		var err error
+		if normalized, err = normalizeInputHex("pairing", *inputFormat, *inputHex); err != nil {
+			return err
+		}
+	}
+	z, err := bls12381vec.PairingGT(normalized)
+	if err != nil {
+		return err
+	}
+
+	pairs := len(normalized) / 2 / 384
+	fmt.Println("=== Pairing GT ===")
+	fmt.Printf("Pairs: %d\n", pairs)
+	gt.print("GT", &z)
+	fmt.Printf("Identity: %v\n", z.IsOne())
+
+	neo := hex.EncodeToString(serialization.EncodeNeoGT(&z))
+	recordVerdictInput(normalized)
+	recordVerdictResult(neo)
+	reportInput("input", *inputHex)
+	reportOutput("gt_neo", neo)
+	reportOutput("gt_gnark", hex.EncodeToString(z.Marshal()))
+	reportOutput("identity", z.IsOne())
+	return nil
+}
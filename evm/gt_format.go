@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// gtOutputFormats are the GT element representations --gt-format can select
+var gtOutputFormats = []string{"gnark", "neo", "coeffs", "decimal"}
+
+// gtCoefficientLabels names the Fp coefficients of an Fp12 element in tower order
+// (C0.B0.A0 first), the order of Neo's Gt.ToArray()
+var gtCoefficientLabels = [12]string{
+	"C0.B0.A0", "C0.B0.A1", "C0.B1.A0", "C0.B1.A1", "C0.B2.A0", "C0.B2.A1",
+	"C1.B0.A0", "C1.B0.A1", "C1.B1.A0", "C1.B1.A1", "C1.B2.A0", "C1.B2.A1",
+}
+
+// gtFormatOptions selects how GT elements are printed (--gt-format, --gt-order)
+type gtFormatOptions struct {
+	Formats string
+	Order   string
+	fs      *flag.FlagSet
+}
+
+// registerGTFormatFlags adds --gt-format and --gt-order to a flag set
+func registerGTFormatFlags(fs *flag.FlagSet, defaultFormats string) *gtFormatOptions {
+	o := &gtFormatOptions{fs: fs}
+	fs.StringVar(&o.Formats, "gt-format", defaultFormats, "GT output: gnark (Marshal), neo (Gt.ToArray), coeffs (12 labeled Fp coefficients), decimal (the coefficients in decimal) or all (comma-separated)")
+	fs.StringVar(&o.Order, "gt-order", "tower", "Coefficient order for --gt-format coeffs and decimal: tower (C0.B0.A0 first, Neo order) or reverse (C1.B2.A1 first, gnark Marshal order)")
+	return o
+}
+
+// explicit reports whether either flag was given, so a mode can keep its legacy output otherwise
+func (o *gtFormatOptions) explicit() bool {
+	return o != nil && (isFlagSet(o.fs, "gt-format") || isFlagSet(o.fs, "gt-order"))
+}
+
+// formats returns the selected formats in the order given, without duplicates
+func (o *gtFormatOptions) formats() ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range strings.Split(o.Formats, ",") {
+		f = strings.TrimSpace(f)
+		names := []string{f}
+		if f == "all" {
+			names = gtOutputFormats
+		} else {
+			valid := false
+			for _, g := range gtOutputFormats {
+				valid = valid || f == g
+			}
+			if !valid {
+				return nil, fmt.Errorf("invalid --gt-format '%s' (valid: %s, all)", f, strings.Join(gtOutputFormats, ", "))
+			}
+		}
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+			}
+		}
+	}
+	return out, nil
+}
+
+// validate checks both flags; modes call it right after parsing
+func (o *gtFormatOptions) validate() error {
+	if o.Order != "tower" && o.Order != "reverse" {
+		return fmt.Errorf("invalid --gt-order '%s' (valid: tower, reverse)", o.Order)
+	}
+	_, err := o.formats()
+	return err
+}
+
+// print writes z under label in every selected format
+func (o *gtFormatOptions) print(label string, z *bls.GT) {
+	formats, _ := o.formats()
+	for _, f := range formats {
+		switch f {
+		case "gnark":
+			fmt.Printf("%s (gnark): %x\n", label, z.Marshal())
+		case "neo":
+			fmt.Printf("%s (neo): %x\n", label, serialization.EncodeNeoGT(z))
+		case "coeffs", "decimal":
+			// The Neo encoding is the coefficients in tower order, 48 bytes each
+			neo := serialization.EncodeNeoGT(z)
+			kind := "coefficients"
+			if f == "decimal" {
+				kind = "decimal coefficients"
+			}
+			fmt.Printf("%s (%s, %s order):\n", label, kind, o.Order)
+			for k := 0; k < 12; k++ {
+				i := k
+				if o.Order == "reverse" {
+					i = 11 - k
+				}
+				c := neo[i*fp.Bytes : (i+1)*fp.Bytes]
+				if f == "decimal" {
+					fmt.Printf("  %s: %s\n", gtCoefficientLabels[i], new(big.Int).SetBytes(c).String())
+				} else {
+					fmt.Printf("  %s: %x\n", gtCoefficientLabels[i], c)
+				}
+			}
+		}
+	}
+}
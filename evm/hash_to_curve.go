@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// Default DSTs of hash-g1 / hash-g2: the RFC 9380 test-vector tags (appendix J.9.1 and
+// J.10.1), so the output can be checked against the RFC without extra flags
+var hashToCurveDSTs = map[string]string{
+	"hash-g1": "QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_",
+	"hash-g2": "QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_",
+}
+
+// parseMessageFlags returns the message of --message (UTF-8) or --message-hex, which wins
+func parseMessageFlags(message, messageHex string) ([]byte, error) {
+	if messageHex == "" {
+		return []byte(message), nil
+	}
+	msg, err := hex.DecodeString(strings.TrimPrefix(messageHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --message-hex: %v", err)
+	}
+	return msg, nil
+}
+
+// runHashToCurveMode hashes a message to G1 or G2 with the random-oracle suites of
+// RFC 9380 (BLS12381G1_XMD:SHA-256_SSWU_RO_ / BLS12381G2_XMD:SHA-256_SSWU_RO_) and prints
+// the point in every encoding; --trace adds every intermediate of the computation
+func runHashToCurveMode(mode string, args []string) error {
+	fs := newFlagSet(mode)
+	message := fs.String("message", "", "Message to hash (UTF-8; empty is a valid message)")
+	messageHex := fs.String("message-hex", "", "Message to hash (hex, overrides --message)")
+	dst := fs.String("dst", hashToCurveDSTs[mode], "Domain separation tag (1 to 255 bytes)")
+	trace := fs.Bool("trace", false, "Print every intermediate of hash_to_field, map_to_curve_simple_swu, the isogeny and clear_cofactor")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	msg, err := parseMessageFlags(*message, *messageHex)
+	if err != nil {
+		return usageError{err}
+	}
+	if len(*dst) == 0 || len(*dst) > 255 {
+		// RFC 9380 section 5.3.3: longer tags must be hashed down first
+		return usageErrorf("--dst must be 1 to 255 bytes, got %d", len(*dst))
+	}
+
+	useG2 := mode == "hash-g2"
+	suite := "BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	if useG2 {
+		suite = "BLS12381G2_XMD:SHA-256_SSWU_RO_"
+	}
+	reportInput("message", hex.EncodeToString(msg))
+	reportInput("dst", *dst)
+
+	fmt.Printf("=== Hash to Curve (%s) ===\n", suite)
+	fmt.Printf("Message (hex): %x\n", msg)
+	fmt.Printf("Message length: %d bytes\n", len(msg))
+	fmt.Printf("DST: %s\n", *dst)
+	fmt.Println()
+
+	var compressed, uncompressed, ethereum []byte
+	if useG2 {
+		p, err := bls.HashToG2(msg, []byte(*dst))
+		if err != nil {
+			return fmt.Errorf("hash to G2 failed: %v", err)
+		}
+		compressed = serialization.ConvertG2AffineToCompressed(p)
+		uncompressed = p.Marshal()
+		ethereum = serialization.EncodeEthereumG2Point(p)
+		fmt.Printf("x.c0: 0x%x\n", p.X.A0.Bytes())
+		fmt.Printf("x.c1: 0x%x\n", p.X.A1.Bytes())
+		fmt.Printf("y.c0: 0x%x\n", p.Y.A0.Bytes())
+		fmt.Printf("y.c1: 0x%x\n", p.Y.A1.Bytes())
+	} else {
+		p, err := bls.HashToG1(msg, []byte(*dst))
+		if err != nil {
+			return fmt.Errorf("hash to G1 failed: %v", err)
+		}
+		compressed = serialization.ConvertG1AffineToCompressed(p)
+		uncompressed = p.Marshal()
+		ethereum = serialization.EncodeEthereumG1Point(p)
+		fmt.Printf("x: 0x%x\n", p.X.Bytes())
+		fmt.Printf("y: 0x%x\n", p.Y.Bytes())
+	}
+	fmt.Println()
+	fmt.Printf("Point (compressed, %d bytes): %x\n", len(compressed), compressed)
+	fmt.Printf("Point (uncompressed, %d bytes): %x\n", len(uncompressed), uncompressed)
+	fmt.Printf("Point (Ethereum, %d bytes): %x\n", len(ethereum), ethereum)
+
+	recordVerdictResult(hex.EncodeToString(compressed))
+	reportOutput("compressed", hex.EncodeToString(compressed))
+	reportOutput("uncompressed", hex.EncodeToString(uncompressed))
+	reportOutput("ethereum", hex.EncodeToString(ethereum))
+	if *trace {
+		return traceHashToCurve(msg, []byte(*dst), useG2)
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/hash_to_curve"
+	"github.com/consensys/gnark-crypto/field/hash"
+)
+
+// The --trace of hash-g1 / hash-g2 recomputes RFC 9380 hash_to_curve step by step and
+// prints every intermediate, so a port can find the first value that differs. The map
+// follows the straight-line map_to_curve_simple_swu of appendix F.2 and is numbered like
+// it. Every stage is compared with gnark-crypto's own MapToCurve, isogeny and
+// ClearCofactor.
+//
+// Both groups run through one path over Fp2: a G1 value is an Fp2 element with c1 = 0,
+// on which the Fp2 operations are the Fp ones. Only Z, the curve E' and the isogeny
+// differ: E' is 11-isogenous to E for G1 and 3-isogenous to E2 for G2.
+
+// h_eff of RFC 9380 sections 8.8.1 and 8.8.2
+var (
+	hEffG1, _ = new(big.Int).SetString("d201000000010001", 16)
+	hEffG2, _ = new(big.Int).SetString("bc69f08f2ee75b3584c6a0ea91b352888e2a8e9145ad7689986ff031508ffe1329c2f178731db956d82bf015d1212b02ec0ec69d7477c1ae954cbc06689f6a359894c0adebbf6b4e8020005aaa95551", 16)
+)
+
+// h2cTrace prints the intermediates of one hash_to_curve run and counts the stages that
+// differ from gnark-crypto
+type h2cTrace struct {
+	g2     bool
+	failed int
+}
+
+func liftFp(e fp.Element) bls.E2 {
+	return bls.E2{A0: e}
+}
+
+// fe prints a field element: big-endian hex, per coefficient for Fp2
+func (t *h2cTrace) fe(label string, e *bls.E2) {
+	if t.g2 {
+		fmt.Printf("  %s.c0: %x\n  %s.c1: %x\n", label, e.A0.Bytes(), label, e.A1.Bytes())
+		return
+	}
+	fmt.Printf("  %s: %x\n", label, e.A0.Bytes())
+}
+
+func (t *h2cTrace) check(ok bool, what string) {
+	if !ok {
+		fmt.Printf("  ❌ %s differs from gnark-crypto\n", what)
+		t.failed++
+		return
+	}
+	fmt.Printf("  ✅ %s matches gnark-crypto\n", what)
+}
+
+func (t *h2cTrace) mulByZ(z, x *bls.E2) {
+	if t.g2 {
+		hash_to_curve.G2MulByZ(z, x)
+		return
+	}
+	hash_to_curve.G1MulByZ(&z.A0, &x.A0)
+}
+
+// sqrtRatio is sqrt_ratio(u, v) of RFC 9380 appendix F.2.1: whether u/v is a square, and
+// sqrt(u/v) or sqrt(Z u/v)
+func (t *h2cTrace) sqrtRatio(z, u, v *bls.E2) bool {
+	if t.g2 {
+		return hash_to_curve.G2SqrtRatio(z, u, v) == 0
+	}
+	z.A1.SetZero()
+	return hash_to_curve.G1SqrtRatio(&z.A0, &u.A0, &v.A0) == 0
+}
+
+func (t *h2cTrace) sgn0(e *bls.E2) uint64 {
+	if t.g2 {
+		return hash_to_curve.G2Sgn0(e)
+	}
+	return hash_to_curve.G1Sgn0(&e.A0)
+}
+
+// constants returns Z and the coefficients A', B' of E'
+func (t *h2cTrace) constants() (z, a, b bls.E2) {
+	if t.g2 {
+		a, b = hash_to_curve.G2SSWUIsogenyCurveCoefficients()
+		return hash_to_curve.G2SSWUIsogenyZ(), a, b
+	}
+	a1, b1 := hash_to_curve.G1SSWUIsogenyCurveCoefficients()
+	return liftFp(hash_to_curve.G1SSWUIsogenyZ()), liftFp(a1), liftFp(b1)
+}
+
+// isogenyMap is the x numerator, x denominator, y numerator and y denominator, lowest
+// degree first; the denominators are monic and their leading 1 is left out
+func (t *h2cTrace) isogenyMap() [4][]bls.E2 {
+	if t.g2 {
+		return hash_to_curve.G2IsogenyMap()
+	}
+	var out [4][]bls.E2
+	for i, coeffs := range hash_to_curve.G1IsogenyMap() {
+		for _, c := range coeffs {
+			out[i] = append(out[i], liftFp(c))
+		}
+	}
+	return out
+}
+
+// traceExpand is expand_message_xmd with SHA-256, printing b_0 .. b_ell
+func traceExpand(msg, dst []byte, lenInBytes int) []byte {
+	dstPrime := append(append([]byte(nil), dst...), byte(len(dst)))
+	fmt.Printf("  DST_prime (DST || I2OSP(len(DST), 1)): %x\n", dstPrime)
+	fmt.Printf("  len_in_bytes: %d, ell: %d\n", lenInBytes, (lenInBytes+31)/32)
+	h := sha256.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+	fmt.Printf("  b_0 = H(Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime): %x\n", b0)
+	var out []byte
+	prev := make([]byte, len(b0))
+	for i := 1; len(out) < lenInBytes; i++ {
+		h.Reset()
+		for j := range prev {
+			prev[j] ^= b0[j]
+		}
+		h.Write(prev)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		prev = h.Sum(nil)
+		fmt.Printf("  b_%d: %x\n", i, prev)
+		out = append(out, prev...)
+	}
+	return out[:lenInBytes]
+}
+
+// sswu is map_to_curve_simple_swu(u) onto E', numbered as in RFC 9380 appendix F.2
+func (t *h2cTrace) sswu(u *bls.E2) (x, y bls.E2) {
+	z, a, b := t.constants()
+	var tv1, tv2, tv3, tv4, tv5, tv6, one bls.E2
+	one.SetOne()
+	tv1.Square(u)
+	t.mulByZ(&tv1, &tv1)
+	t.fe("1-2.   tv1 = Z * u^2", &tv1)
+	tv2.Square(&tv1)
+	tv2.Add(&tv2, &tv1)
+	t.fe("3-4.   tv2 = tv1^2 + tv1", &tv2)
+	tv3.Add(&tv2, &one)
+	tv3.Mul(&tv3, &b)
+	t.fe("5-6.   tv3 = B' * (tv2 + 1)", &tv3)
+	tv4 = z
+	if !tv2.IsZero() {
+		tv4.Neg(&tv2)
+	}
+	tv4.Mul(&tv4, &a)
+	t.fe("7-8.   tv4 = A' * CMOV(Z, -tv2, tv2 != 0)", &tv4)
+	tv2.Square(&tv3)
+	tv6.Square(&tv4)
+	tv5.Mul(&tv6, &a)
+	tv2.Add(&tv2, &tv5)
+	tv2.Mul(&tv2, &tv3)
+	tv6.Mul(&tv6, &tv4)
+	tv5.Mul(&tv6, &b)
+	tv2.Add(&tv2, &tv5)
+	t.fe("9-16.  tv2 = (tv3^2 + A' tv4^2) tv3 + B' tv4^3 (gx1 numerator)", &tv2)
+	t.fe("14.    tv6 = tv4^3 (gx1 denominator)", &tv6)
+	x.Mul(&tv1, &tv3)
+	t.fe("17.    x = tv1 * tv3", &x)
+	var y1 bls.E2
+	square := t.sqrtRatio(&y1, &tv2, &tv6)
+	fmt.Printf("  18.    is_gx1_square: %v\n", square)
+	t.fe("18.    y1 = sqrt_ratio(tv2, tv6)", &y1)
+	y.Mul(&tv1, u)
+	y.Mul(&y, &y1)
+	t.fe("19-20. y = tv1 * u * y1", &y)
+	if square {
+		x, y = tv3, y1
+	}
+	t.fe("21.    x = CMOV(x, tv3, is_gx1_square)", &x)
+	t.fe("22.    y = CMOV(y, y1, is_gx1_square)", &y)
+	e1 := t.sgn0(u) == t.sgn0(&y)
+	fmt.Printf("  23.    sgn0(u) = %d, sgn0(y) = %d, e1: %v\n", t.sgn0(u), t.sgn0(&y), e1)
+	if !e1 {
+		y.Neg(&y)
+	}
+	t.fe("24.    y = CMOV(-y, y, e1)", &y)
+	x.Div(&x, &tv4)
+	t.fe("25.    x = x / tv4", &x)
+	return x, y
+}
+
+// isogeny is iso_map of RFC 9380 section 6.6.3 from E' to E
+func (t *h2cTrace) isogeny(xp, yp *bls.E2) (x, y bls.E2) {
+	m := t.isogenyMap()
+	eval := func(coeffs []bls.E2, monic bool) bls.E2 {
+		acc := coeffs[len(coeffs)-1]
+		if monic {
+			acc.Add(&acc, xp)
+		}
+		for i := len(coeffs) - 2; i >= 0; i-- {
+			acc.Mul(&acc, xp)
+			acc.Add(&acc, &coeffs[i])
+		}
+		return acc
+	}
+	xNum, xDen := eval(m[0], false), eval(m[1], true)
+	yNum, yDen := eval(m[2], false), eval(m[3], true)
+	t.fe("x_num(x')", &xNum)
+	t.fe("x_den(x')", &xDen)
+	t.fe("y_num(x')", &yNum)
+	t.fe("y_den(x')", &yDen)
+	x.Div(&xNum, &xDen)
+	y.Div(&yNum, &yDen)
+	y.Mul(&y, yp)
+	t.fe("x = x_num / x_den", &x)
+	t.fe("y = y' * y_num / y_den", &y)
+	return x, y
+}
+
+// traceHashToCurve prints the trace of hash_to_curve(msg) and checks it against gnark-crypto
+func traceHashToCurve(msg, dst []byte, g2 bool) error {
+	t := &h2cTrace{g2: g2}
+	count, curve, iso, hEff := 2, "E'", "11-isogeny", hEffG1
+	if g2 {
+		count, curve, iso, hEff = 4, "E2'", "3-isogeny", hEffG2
+	}
+	fmt.Println()
+	fmt.Println("=== Trace ===")
+	z, a, b := t.constants()
+	fmt.Printf("Constants of %s: y^2 = x^3 + A' x + B'\n", curve)
+	t.fe("Z", &z)
+	t.fe("A'", &a)
+	t.fe("B'", &b)
+
+	fmt.Println("hash_to_field (expand_message_xmd, SHA-256, L = 64):")
+	uniform := traceExpand(msg, dst, count*64)
+	fmt.Printf("  uniform_bytes: %x\n", uniform)
+	var elems []fp.Element
+	for i := 0; i < count; i++ {
+		var e fp.Element
+		e.SetBytes(uniform[i*64 : (i+1)*64])
+		elems = append(elems, e)
+	}
+	want, err := fp.Hash(msg, dst, count)
+	if err != nil {
+		return err
+	}
+	xmd, err := hash.ExpandMsgXmd(msg, dst, count*64)
+	if err != nil {
+		return err
+	}
+	t.check(bytes.Equal(xmd, uniform), "expand_message_xmd")
+	same := true
+	for i := range want {
+		same = same && want[i].Equal(&elems[i])
+	}
+	u := make([]bls.E2, 2)
+	for i := range u {
+		if g2 {
+			u[i] = bls.E2{A0: elems[2*i], A1: elems[2*i+1]}
+		} else {
+			u[i] = liftFp(elems[i])
+		}
+		t.fe(fmt.Sprintf("u[%d]", i), &u[i])
+	}
+	t.check(same, "hash_to_field")
+
+	var q [2]bls.E2
+	var qy [2]bls.E2
+	for i := range u {
+		fmt.Printf("map_to_curve_simple_swu(u[%d]) onto %s:\n", i, curve)
+		xp, yp := t.sswu(&u[i])
+		fmt.Printf("iso_map (%s) of Q%d' onto E:\n", iso, i)
+		q[i], qy[i] = t.isogeny(&xp, &yp)
+		if g2 {
+			ref := bls.MapToCurve2(&u[i])
+			t.check(ref.X.Equal(&xp) && ref.Y.Equal(&yp), fmt.Sprintf("map_to_curve_simple_swu(u[%d])", i))
+			hash_to_curve.G2Isogeny(&ref.X, &ref.Y)
+			t.check(ref.X.Equal(&q[i]) && ref.Y.Equal(&qy[i]), fmt.Sprintf("iso_map(Q%d')", i))
+		} else {
+			ref := bls.MapToCurve1(&u[i].A0)
+			t.check(ref.X.Equal(&xp.A0) && ref.Y.Equal(&yp.A0), fmt.Sprintf("map_to_curve_simple_swu(u[%d])", i))
+			hash_to_curve.G1Isogeny(&ref.X, &ref.Y)
+			t.check(ref.X.Equal(&q[i].A0) && ref.Y.Equal(&qy[i].A0), fmt.Sprintf("iso_map(Q%d')", i))
+		}
+	}
+
+	fmt.Printf("R = Q0 + Q1, then clear_cofactor(R) = h_eff * R with h_eff = 0x%x:\n", hEff)
+	if g2 {
+		var r, p, ref bls.G2Jac
+		p.X.SetOne()
+		p.Y.SetOne() // the point at infinity (1, 1, 0)
+		r.FromAffine(&bls.G2Affine{X: q[0], Y: qy[0]})
+		r.AddAssign(new(bls.G2Jac).FromAffine(&bls.G2Affine{X: q[1], Y: qy[1]}))
+		var ra, pa, refa bls.G2Affine
+		ra.FromJacobian(&r)
+		t.fe("R.x", &ra.X)
+		t.fe("R.y", &ra.Y)
+		for i := hEff.BitLen() - 1; i >= 0; i-- {
+			p.DoubleAssign()
+			if hEff.Bit(i) == 1 {
+				p.AddAssign(&r)
+			}
+		}
+		pa.FromJacobian(&p)
+		t.fe("P.x", &pa.X)
+		t.fe("P.y", &pa.Y)
+		ref.ClearCofactor(&r)
+		refa.FromJacobian(&ref)
+		t.check(refa.Equal(&pa), "clear_cofactor (h_eff * R)")
+		h, err := bls.HashToG2(msg, dst)
+		if err != nil {
+			return err
+		}
+		t.check(h.Equal(&pa), "hash_to_curve")
+	} else {
+		var r, p, ref bls.G1Jac
+		p.X.SetOne()
+		p.Y.SetOne() // the point at infinity (1, 1, 0)
+		r.FromAffine(&bls.G1Affine{X: q[0].A0, Y: qy[0].A0})
+		r.AddAssign(new(bls.G1Jac).FromAffine(&bls.G1Affine{X: q[1].A0, Y: qy[1].A0}))
+		var ra, pa, refa bls.G1Affine
+		ra.FromJacobian(&r)
+		fmt.Printf("  R.x: %x\n  R.y: %x\n", ra.X.Bytes(), ra.Y.Bytes())
+		for i := hEff.BitLen() - 1; i >= 0; i-- {
+			p.DoubleAssign()
+			if hEff.Bit(i) == 1 {
+				p.AddAssign(&r)
+			}
+		}
+		pa.FromJacobian(&p)
+		fmt.Printf("  P.x: %x\n  P.y: %x\n", pa.X.Bytes(), pa.Y.Bytes())
+		ref.ClearCofactor(&r)
+		refa.FromJacobian(&ref)
+		t.check(refa.Equal(&pa), "clear_cofactor (h_eff * R)")
+		h, err := bls.HashToG1(msg, dst)
+		if err != nil {
+			return err
+		}
+		t.check(h.Equal(&pa), "hash_to_curve")
+	}
+	if t.failed > 0 {
+		return errChecksFailed
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hexStyle is how hex is written to stdout (global --hex-style). Fixture files keep the
+// canonical lowercase hex their language literals need. The zero value is plain lowercase hex, the historical output.
+type hexStyle struct {
+	Upper  bool
+	Prefix bool   // 0x before every value
+	Sep    string // between groups: "", " " or "_"
+	Group  int    // bytes per group when Sep is set
+}
+
+// outputHexStyle is the style selected with --hex-style
+var outputHexStyle hexStyle
+
+// parseHexStyle parses a comma-separated list of lower, upper, 0x, space, underscore,
+// none and group=N
+func parseHexStyle(value string) (hexStyle, error) {
+	s := hexStyle{Group: 1}
+	for _, opt := range strings.Split(value, ",") {
+		opt = strings.ToLower(strings.TrimSpace(opt))
+		switch {
+		case opt == "lower":
+			s.Upper = false
+		case opt == "upper":
+			s.Upper = true
+		case opt == "0x":
+			s.Prefix = true
+		case opt == "space":
+			s.Sep = " "
+		case opt == "underscore":
+			s.Sep = "_"
+		case opt == "none" || opt == "plain":
+			s = hexStyle{Group: 1}
+		case strings.HasPrefix(opt, "group="):
+			n, err := strconv.Atoi(strings.TrimPrefix(opt, "group="))
+			if err != nil || n < 1 {
+				return s, fmt.Errorf("invalid --hex-style '%s': group needs a positive byte count", opt)
+			}
+			s.Group = n
+		default:
+			return s, fmt.Errorf("invalid --hex-style '%s' (valid: lower, upper, 0x, space, underscore, group=N, none)", opt)
+		}
+	}
+	return s, nil
+}
+
+// plain reports whether the style writes the same text as %x
+func (s hexStyle) plain() bool {
+	return !s.Upper && !s.Prefix && s.Sep == ""
+}
+
+// format writes b in the style
+func (s hexStyle) format(b []byte) string {
+	h := hex.EncodeToString(b)
+	if s.Upper {
+		h = strings.ToUpper(h)
+	}
+	if s.Sep != "" && len(b) > s.Group {
+		var out strings.Builder
+		step := 2 * s.Group
+		for i := 0; i < len(h); i += step {
+			if i > 0 {
+				out.WriteString(s.Sep)
+			}
+			out.WriteString(h[i:min(i+step, len(h))])
+		}
+		h = out.String()
+	}
+	if s.Prefix {
+		h = "0x" + h
+	}
+	return h
+}
+
+// styleHex formats b with the selected --hex-style for printing
+func styleHex(b []byte) string {
+	return outputHexStyle.format(b)
+}
+
+// hexTokenPattern matches candidate hex values in printed text
+var hexTokenPattern = regexp.MustCompile(`\b(0[xX])?[0-9a-fA-F]+\b`)
+
+// restyleHexLine rewrites every hex value of a printed line. A token counts as hex when
+// it has at least 8 bytes and is 0x-prefixed, contains a letter a-f or starts with 0;
+// shorter tokens and decimal numbers are left alone.
+func restyleHexLine(line string) string {
+	return hexTokenPattern.ReplaceAllStringFunc(line, func(tok string) string {
+		digits := strings.TrimPrefix(strings.TrimPrefix(tok, "0x"), "0X")
+		if len(digits) < 16 || len(digits)%2 != 0 {
+			return tok
+		}
+		if digits == tok && !strings.ContainsAny(strings.ToLower(digits), "abcdef") && digits[0] != '0' {
+			return tok
+		}
+		b, err := hex.DecodeString(digits)
+		if err != nil {
+			return tok
+		}
+		return outputHexStyle.format(b)
+	})
+}
+
+// hexStyleWriter restyles stdout line by line
+type hexStyleWriter struct {
+	w       io.Writer
+	pending []byte
+}
+
+func (h *hexStyleWriter) Write(p []byte) (int, error) {
+	h.pending = append(h.pending, p...)
+	for {
+		i := bytes.IndexByte(h.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if _, err := io.WriteString(h.w, restyleHexLine(string(h.pending[:i]))+"\n"); err != nil {
+			return len(p), err
+		}
+		h.pending = h.pending[i+1:]
+	}
+}
+
+// flush writes a final line without a newline
+func (h *hexStyleWriter) flush() {
+	if len(h.pending) > 0 {
+		io.WriteString(h.w, restyleHexLine(string(h.pending)))
+		h.pending = nil
+	}
+}
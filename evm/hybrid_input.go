@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+)
+
+// Hybrid inputs carry compressed points in the Ethereum pair layout, since Neo-side
+// tooling naturally produces compressed points:
+//   - g1msm: 48-byte compressed G1 + 32-byte scalar per pair (80 bytes)
+//   - g2msm: 96-byte compressed G2 + 32-byte scalar per pair (128 bytes)
+//   - pairing: 48-byte compressed G1 + 96-byte compressed G2 per pair (144 bytes)
+// They are converted to the EIP-2537 layout before computing, so results are identical.
+
+var inputFormats = []string{"ethereum", "compressed", "auto"}
+
+// resolveInputFormat picks the layout for "auto": compressed points always have the
+// compression flag (0x80) in their first byte, while EIP-2537 points start with zero padding
+func resolveInputFormat(format string, data []byte) (string, error) {
+	switch format {
+	case "ethereum", "compressed":
+		return format, nil
+	case "auto":
+		if len(data) > 0 && data[0]&0x80 != 0 {
+			return "compressed", nil
+		}
+		return "ethereum", nil
+	}
+	return "", fmt.Errorf("invalid input format '%s' (valid: %s)", format, strings.Join(inputFormats, ", "))
+}
+
+// convertHybridMSMInput converts compressed point + scalar pairs to the EIP-2537 MSM layout
+func convertHybridMSMInput(data []byte, useG2 bool) ([]byte, error) {
+	pointLen := 48
+	if useG2 {
+		pointLen = 96
+	}
+	pairLen := pointLen + 32
+	if len(data)%pairLen != 0 {
+		return nil, fmt.Errorf("compressed MSM input must be a multiple of %d bytes (%d-byte point + 32-byte scalar), got %d", pairLen, pointLen, len(data))
+	}
+	var out []byte
+	for i, offset := 0, 0; offset < len(data); i, offset = i+1, offset+pairLen {
+		point := data[offset : offset+pointLen]
+		if useG2 {
+			p, err := bls12381vec.DecodeCompressedG2Point(point)
+			if err != nil {
+				return nil, fmt.Errorf("pair %d: %v", i, err)
+			}
+			out = append(out, serialization.EncodeEthereumG2Point(p)...)
+		} else {
+			p, err := bls12381vec.DecodeCompressedG1Point(point)
+			if err != nil {
+				return nil, fmt.Errorf("pair %d: %v", i, err)
+			}
+			out = append(out, serialization.EncodeEthereumG1Point(p)...)
+		}
+		out = append(out, data[offset+pointLen:offset+pairLen]...)
+	}
+	return out, nil
+}
+
+// convertHybridPairingInput converts compressed (G1, G2) pairs to the EIP-2537 pairing layout
+func convertHybridPairingInput(data []byte) ([]byte, error) {
+	const pairLen = 48 + 96
+	if len(data)%pairLen != 0 {
+		return nil, fmt.Errorf("compressed pairing input must be a multiple of %d bytes (48-byte G1 + 96-byte G2), got %d", pairLen, len(data))
+	}
+	var g1Hexes, g2Hexes []string
+	for offset := 0; offset < len(data); offset += pairLen {
+		g1Hexes = append(g1Hexes, hex.EncodeToString(data[offset:offset+48]))
+		g2Hexes = append(g2Hexes, hex.EncodeToString(data[offset+48:offset+pairLen]))
+	}
+	return buildPairingInput(g1Hexes, g2Hexes)
+}
+
+// normalizeInputHex converts a hybrid input for mode (g1msm, g2msm, ethereum or pairing)
+// to the EIP-2537 layout; Ethereum-format input is returned unchanged
+func normalizeInputHex(mode, format, inputHex string) (string, error) {
+	inputHex = strings.TrimSpace(inputHex)
+	data, err := hex.DecodeString(strings.TrimPrefix(inputHex, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input hex: %v", err)
+	}
+	format, err = resolveInputFormat(format, data)
+	if err != nil {
+		return "", err
+	}
+	if format == "ethereum" {
+		return inputHex, nil
+	}
+	var converted []byte
+	switch mode {
+	case "pairing":
+		converted, err = convertHybridPairingInput(data)
+	case "g1msm", "g2msm":
+		converted, err = convertHybridMSMInput(data, mode == "g2msm")
+	default:
+		return "", fmt.Errorf("--input-format compressed is not supported for %s", mode)
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(converted), nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Large MSM and pairing inputs run into shell argument limits (ARG_MAX, or far less on
+// Windows), so every hex --input can also be read from a file with --input-file or from
+// stdin with "--input -" / "--input-file -". Whitespace and newlines are stripped, so a
+// file can hold the input wrapped, one pair per line or with a trailing newline.
+
+// registerInputFlag adds a hex --input flag together with its --input-file counterpart;
+// parseFlags resolves the two into --input
+func registerInputFlag(fs *flag.FlagSet, usage string) *string {
+	input := fs.String("input", "", usage+` ("-" reads it from stdin)`)
+	fs.String("input-file", "", `Read --input from this file ("-" for stdin); whitespace, newlines and a 0x prefix are stripped`)
+	return input
+}
+
+// resolveInputFile replaces --input with the contents of --input-file or, for "--input -",
+// of stdin. An empty file sets --input to "", which modes take as an explicit empty input.
+func resolveInputFile(fs *flag.FlagSet) error {
+	fileFlag := fs.Lookup("input-file")
+	if fileFlag == nil {
+		return nil
+	}
+	path := fileFlag.Value.String()
+	switch {
+	case isFlagSet(fs, "input-file") && isFlagSet(fs, "input"):
+		return usageErrorf("give --input or --input-file, not both")
+	case isFlagSet(fs, "input-file"):
+		if path == "" {
+			return usageErrorf("--input-file needs a path (\"-\" for stdin)")
+		}
+	case fs.Lookup("input").Value.String() == "-":
+		path = "-"
+	default:
+		return nil
+	}
+	data, err := readInputSource(path)
+	if err != nil {
+		return err
+	}
+	return fs.Set("input", cleanInputHex(string(data)))
+}
+
+// readInputSource reads a whole file, or stdin for "-"
+func readInputSource(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --input from stdin: %v", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --input-file: %v", err)
+	}
+	return data, nil
+}
+
+// cleanInputHex removes all whitespace and a leading 0x from hex read from a file
+func cleanInputHex(s string) string {
+	return strings.TrimPrefix(strings.Join(strings.Fields(s), ""), "0x")
+}
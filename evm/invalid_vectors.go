@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// Invalid vectors: one malformed input per rejection rule of the compressed and EIP-2537
+// decoders and the MSM input framing, each with the taxonomy code it must be rejected
+// with. Unlike corrupt-nearby they do not start from a given point, so the suite is the
+// same on every run. A few valid controls (generator, infinity) guard against decoders
+// that reject everything.
+
+// invalidVector is one input with the code a strict implementation must return ("" = accept)
+type invalidVector struct {
+	Name        string
+	Op          string
+	Data        []byte
+	Want        errorCode
+	Pair        int // pair index of the error for MSM inputs, -1 otherwise
+	Description string
+}
+
+// strictCode returns the code and pair index of the first check the input fails
+func strictCode(op string, data []byte) (errorCode, int) {
+	var err error
+	switch op {
+	case "deserialize-g1":
+		_, err = serialization.DecodeCompressedG1Point(data)
+	case "deserialize-g2":
+		_, err = serialization.DecodeCompressedG2Point(data)
+	case "eip2537-g1":
+		_, err = serialization.DecodeEIP2537G1Point(data, true)
+	case "eip2537-g2":
+		_, err = serialization.DecodeEIP2537G2Point(data, true)
+	default:
+		code, pair, _ := firstValidationError("eip2537", op, data)
+		if code == "" {
+			pair = -1
+		}
+		return code, pair
+	}
+	code, _ := classifyError(err)
+	return code, -1
+}
+
+// notOnCurveX returns the smallest x (x + 0*u for G2) for which x^3 + b has no square root,
+// so no point with that x exists
+func notOnCurveX(g2 bool) *big.Int {
+	for x := uint64(0); ; x++ {
+		if g2 {
+			var e bls.E2
+			e.A0.SetUint64(x)
+			if rhs := g2CurveRHS(&e); rhs.Legendre() == -1 {
+				return new(big.Int).SetUint64(x)
+			}
+			continue
+		}
+		var e fp.Element
+		e.SetUint64(x)
+		if rhs := g1CurveRHS(&e); rhs.Legendre() == -1 {
+			return new(big.Int).SetUint64(x)
+		}
+	}
+}
+
+// invalidVectorsForGroup builds the suite of one group
+func invalidVectorsForGroup(g2 bool) []invalidVector {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var compressed, ethereum []byte
+	var offCurve []byte
+	if g2 {
+		compressed, ethereum = serialization.ConvertG2AffineToCompressed(g2Gen), serialization.EncodeEthereumG2Point(g2Gen)
+		offCurve = serialization.EncodeEthereumG2Point(g2PointNotOnCurve(g2Gen))
+	} else {
+		compressed, ethereum = serialization.ConvertG1AffineToCompressed(g1Gen), serialization.EncodeEthereumG1Point(g1Gen)
+		offCurve = serialization.EncodeEthereumG1Point(g1PointNotOnCurve(g1Gen))
+	}
+	cl, el := pointLayouts[len(compressed)], pointLayouts[len(ethereum)]
+	g := strings.ToLower(cl.Group)
+	p := fp.Modulus()
+	max381 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 381), big.NewInt(1))
+
+	var out []invalidVector
+	add := func(name, op string, data []byte, want errorCode, pair int, description string) {
+		out = append(out, invalidVector{Name: g + "_" + name, Op: op, Data: data, Want: want, Pair: pair, Description: description})
+	}
+	withByte := func(data []byte, i int, b byte) []byte {
+		v := append([]byte(nil), data...)
+		v[i] = b
+		return v
+	}
+	zeros := func(n int) []byte { return make([]byte, n) }
+
+	// Compressed (Neo Bls12381Deserialize)
+	op := "deserialize-" + g
+	add("compressed_generator", op, compressed, "", -1, "valid control: the generator")
+	add("compressed_infinity", op, withByte(zeros(len(compressed)), 0, 0xc0), "", -1, "valid control: infinity (0xc0, x = 0)")
+	for i, field := range cl.Fields {
+		add("compressed_"+field+"_equals_p", op, cl.withFieldValue(compressed, i, p), errNonCanonical, -1, field+" = p (flags kept)")
+		add("compressed_"+field+"_max", op, cl.withFieldValue(compressed, i, max381), errNonCanonical, -1, field+" = 2^381 - 1")
+	}
+	add("compressed_compression_flag_unset", op, withByte(compressed, 0, compressed[0]&^0x80), errBadFlags, -1, "generator with the compression flag (0x80) cleared")
+	add("compressed_all_zero", op, zeros(len(compressed)), errBadFlags, -1, "all zero: no compression flag")
+	add("compressed_infinity_nonzero_x", op, withByte(withByte(zeros(len(compressed)), 0, 0xc0), len(compressed)-1, 0x01), errBadFlags, -1, "infinity flag with x = 1")
+	add("compressed_infinity_nonzero_top_bits", op, withByte(zeros(len(compressed)), 0, 0xc1), errBadFlags, -1, "infinity flag with a set x bit in the flag byte")
+	add("compressed_infinity_sort_flag", op, withByte(zeros(len(compressed)), 0, 0xe0), errBadFlags, -1, "infinity flag with the sort flag set")
+	add("compressed_infinity_uncompressed", op, withByte(zeros(len(compressed)), 0, 0x40), errBadFlags, -1, "infinity flag without the compression flag")
+	xi := 0
+	if g2 {
+		xi = 1 // x.C0
+	}
+	add("compressed_not_on_curve", op, cl.withFieldValue(withByte(zeros(len(compressed)), 0, 0x80), xi, notOnCurveX(g2)), errNotOnCurve, -1,
+		fmt.Sprintf("x = %s, for which x^3 + b is not a square", notOnCurveX(g2)))
+	add("compressed_length_short", op, compressed[:len(compressed)-1], errBadLength, -1, "one byte short")
+	add("compressed_length_long", op, append(append([]byte(nil), compressed...), 0), errBadLength, -1, "one trailing byte")
+	add("compressed_length_empty", op, []byte{}, errBadLength, -1, "empty input")
+	add("compressed_length_uncompressed", op, zeros(2*len(compressed)), errBadLength, -1, "uncompressed length")
+
+	// Ethereum (EIP-2537)
+	op = "eip2537-" + g
+	add("eip2537_generator", op, ethereum, "", -1, "valid control: the generator")
+	add("eip2537_infinity", op, zeros(len(ethereum)), "", -1, "valid control: infinity (all zero)")
+	for i, field := range el.Fields {
+		add("eip2537_"+field+"_equals_p", op, el.withFieldValue(ethereum, i, p), errNonCanonical, -1, field+" = p")
+		add("eip2537_"+field+"_plus_p", op, el.withFieldValue(ethereum, i, new(big.Int).Add(el.fieldValue(ethereum, i), p)), errNonCanonical, -1,
+			field+" + p, which reduces to the generator's "+field)
+		word := el.Offsets[i] - 16
+		add("eip2537_"+field+"_padding_first", op, withByte(ethereum, word, 0x01), errBadPadding, -1, "non-zero first padding byte of "+field)
+		add("eip2537_"+field+"_padding_last", op, withByte(ethereum, word+15, 0x01), errBadPadding, -1, "non-zero last padding byte of "+field)
+	}
+	add("eip2537_infinity_padding", op, withByte(zeros(len(ethereum)), 0, 0x01), errBadPadding, -1, "infinity with a non-zero padding byte")
+	add("eip2537_not_on_curve", op, offCurve, errNotOnCurve, -1, "generator with y + 1")
+	add("eip2537_x_zero_y_one", op, el.withFieldValue(zeros(len(ethereum)), el.YFields[0], big.NewInt(1)), errNotOnCurve, -1, "x = 0, y = 1: half of the infinity encoding")
+	add("eip2537_length_short", op, ethereum[:len(ethereum)-1], errBadLength, -1, "one byte short")
+	add("eip2537_length_long", op, append(append([]byte(nil), ethereum...), 0), errBadLength, -1, "one trailing byte")
+	add("eip2537_length_empty", op, []byte{}, errBadLength, -1, "empty input")
+	add("eip2537_length_compressed", op, compressed, errBadLength, -1, "the compressed encoding")
+
+	// MSM input framing (EIP-2537 G1MSM/G2MSM)
+	op = g + "msm"
+	scalar := scalarTo32Bytes(big.NewInt(7))
+	pair := append(append([]byte(nil), ethereum...), scalar...)
+	add("msm_valid_pair", op, pair, "", -1, "valid control: generator, scalar 7")
+	add("msm_scalar_above_r", op, append(append([]byte(nil), ethereum...), bytesOf(0xff, 32)...), "", -1, "valid control: scalars >= r are allowed and reduced")
+	add("msm_empty", op, []byte{}, errBadLength, -1, "empty input")
+	add("msm_truncated_pair", op, pair[:len(pair)-1], errBadLength, -1, "one byte short of a pair")
+	add("msm_point_only", op, ethereum, errBadLength, -1, "point without its scalar")
+	add("msm_second_pair_non_canonical", op, append(append([]byte(nil), pair...), append(el.withFieldValue(ethereum, 0, p), scalar...)...), errNonCanonical, 1,
+		"valid first pair, second point with "+el.Fields[0]+" = p")
+	add("msm_second_pair_padding", op, append(append([]byte(nil), pair...), append(withByte(ethereum, 0, 0x01), scalar...)...), errBadPadding, 1,
+		"valid first pair, second point with non-zero padding")
+	add("msm_second_pair_not_on_curve", op, append(append([]byte(nil), pair...), append(offCurve, scalar...)...), errNotOnCurve, 1,
+		"valid first pair, second point off the curve")
+	return out
+}
+
+func bytesOf(b byte, n int) []byte {
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = b
+	}
+	return out
+}
+
+// runInvalidVectorsMode prints the invalid-input suite and checks every expected code
+// against the strict decoders
+func runInvalidVectorsMode(args []string) (int, error) {
+	fs := newFlagSet("invalid-vectors")
+	group := fs.String("group", "both", "Group: g1, g2 or both")
+	outPath := fs.String("out", "", "Also write the vectors: <file>.json as a fixture file (the verify-errors format), otherwise CSV (name,op,expected,error_code,pair,hex,description)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	groups := map[string][]bool{"g1": {false}, "g2": {true}, "both": {false, true}}[*group]
+	if groups == nil {
+		return 0, usageErrorf("invalid --group '%s' (valid: g1, g2, both)", *group)
+	}
+
+	var vectors []invalidVector
+	for _, g2 := range groups {
+		vectors = append(vectors, invalidVectorsForGroup(g2)...)
+	}
+	fmt.Printf("=== Invalid Vectors (%d) ===\n", len(vectors))
+	failed := 0
+	counts := map[errorCode]int{}
+	for _, v := range vectors {
+		want := string(v.Want)
+		if want == "" {
+			want = "accept"
+		}
+		fmt.Printf("%-40s %-15s %-15s %x\n", v.Name, v.Op, want, v.Data)
+		recordQuietResult(v.Name, v.Op, want)
+		counts[v.Want]++
+		if code, pair := strictCode(v.Op, v.Data); code != v.Want || pair != v.Pair {
+			fmt.Printf("  ❌ strict decoder: %q at pair %d, want %q at pair %d\n", code, pair, v.Want, v.Pair)
+			failed++
+		}
+	}
+	fmt.Println()
+	var summary []string
+	for _, e := range errorTaxonomy {
+		if counts[e.Code] > 0 {
+			summary = append(summary, fmt.Sprintf("%s %d", e.Code, counts[e.Code]))
+		}
+	}
+	fmt.Printf("Vectors: %d (accept %d, %s)\n", len(vectors), counts[""], strings.Join(summary, ", "))
+	if failed == 0 {
+		fmt.Println("✅ Every vector is classified as expected by the strict decoders")
+	}
+	reportOutput("vectors", len(vectors))
+	reportOutput("failed", failed)
+
+	if strings.HasSuffix(*outPath, ".json") {
+		fixtures := make([]negativeFixture, len(vectors))
+		for i, v := range vectors {
+			fixtures[i] = negativeFixture{Name: v.Name, Op: v.Op, Input: hex.EncodeToString(v.Data), Expected: "accept", Reason: v.Description}
+			if v.Want != "" {
+				fixtures[i].Expected, fixtures[i].ErrorCode = "reject", v.Want
+			}
+			if v.Pair >= 0 {
+				pair := v.Pair
+				fixtures[i].Pair = &pair
+			}
+		}
+		if err := writeNegativeFixtures(*outPath, "invalid-vectors --group "+*group, fixtures); err != nil {
+			return failed, err
+		}
+		fmt.Printf("Wrote %s\n", *outPath)
+	} else if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return failed, err
+		}
+		defer f.Close()
+		w := csv.NewWriter(f)
+		w.Write([]string{"name", "op", "expected", "error_code", "pair", "hex", "description"})
+		for _, v := range vectors {
+			expected, pair := "accept", ""
+			if v.Want != "" {
+				expected = "reject"
+			}
+			if v.Pair >= 0 {
+				pair = fmt.Sprint(v.Pair)
+			}
+			w.Write([]string{v.Name, v.Op, expected, string(v.Want), pair, hex.EncodeToString(v.Data), v.Description})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return failed, fmt.Errorf("failed to write %s: %v", *outPath, err)
+		}
+		fmt.Printf("Wrote %s\n", *outPath)
+	}
+	return failed, nil
+}
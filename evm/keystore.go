@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EIP-2335 keystores encrypt a BLS secret key with AES-128-CTR under the first 16 bytes
+// of a scrypt or PBKDF2 key; the checksum SHA256(DK[16:32] || ciphertext) tells a wrong
+// password from a corrupt file. Passwords are NFKD-normalized with the control codes
+// removed.
+
+// keystoreModule is one of the kdf, checksum and cipher modules of a keystore
+type keystoreModule struct {
+	Function string         `json:"function"`
+	Params   map[string]any `json:"params"`
+	Message  string         `json:"message"`
+}
+
+type keystoreCrypto struct {
+	KDF      keystoreModule `json:"kdf"`
+	Checksum keystoreModule `json:"checksum"`
+	Cipher   keystoreModule `json:"cipher"`
+}
+
+// keystoreJSON is an EIP-2335 keystore (version 4)
+type keystoreJSON struct {
+	Crypto      keystoreCrypto `json:"crypto"`
+	Description string         `json:"description"`
+	Pubkey      string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	UUID        string         `json:"uuid"`
+	Version     int            `json:"version"`
+}
+
+// keystorePassword NFKD-normalizes a password and removes its C0, C1 and Delete
+// control codes, as EIP-2335 requires before the KDF
+func keystorePassword(password string) []byte {
+	return []byte(strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= 0x7f && r <= 0x9f {
+			return -1
+		}
+		return r
+	}, norm.NFKD.String(password)))
+}
+
+// keystoreParam reads a numeric KDF parameter, an int when the keystore is being built
+// and a float64 when it was decoded
+func keystoreParam(params map[string]any, name string) (int, error) {
+	switch v := params[name].(type) {
+	case int:
+		if v > 0 {
+			return v, nil
+		}
+	case float64:
+		if v > 0 && v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("kdf parameter '%s' is missing or not a positive integer", name)
+}
+
+// keystoreDecryptionKey runs the KDF module on the password
+func keystoreDecryptionKey(kdf keystoreModule, password []byte) ([]byte, error) {
+	saltHex, _ := kdf.Params["salt"].(string)
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, fmt.Errorf("kdf salt: %v", err)
+	}
+	dklen, err := keystoreParam(kdf.Params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if dklen < 32 {
+		return nil, fmt.Errorf("kdf dklen must be at least 32, got %d", dklen)
+	}
+	switch kdf.Function {
+	case "scrypt":
+		n, err := keystoreParam(kdf.Params, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := keystoreParam(kdf.Params, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := keystoreParam(kdf.Params, "p")
+		if err != nil {
+			return nil, err
+		}
+		return scrypt.Key(password, salt, n, r, p, dklen)
+	case "pbkdf2":
+		if prf, _ := kdf.Params["prf"].(string); prf != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf '%s' (only hmac-sha256)", prf)
+		}
+		c, err := keystoreParam(kdf.Params, "c")
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key(sha256.New, string(password), salt, c, dklen)
+	}
+	return nil, fmt.Errorf("unsupported kdf '%s' (valid: scrypt, pbkdf2)", kdf.Function)
+}
+
+// keystoreChecksum is SHA256(DK[16:32] || cipher message)
+func keystoreChecksum(dk, ciphertext []byte) []byte {
+	sum := sha256.Sum256(append(append([]byte(nil), dk[16:32]...), ciphertext...))
+	return sum[:]
+}
+
+// aes128CTR encrypts and decrypts with the first 16 bytes of the decryption key
+func aes128CTR(dk, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(dk[:16])
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("cipher iv must be %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// newUUIDv4 returns a random RFC 4122 version 4 UUID
+func newUUIDv4() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// randomOrHex decodes a hex flag, or draws n random bytes when it is empty
+func randomOrHex(name, value string, n int) ([]byte, error) {
+	if value == "" {
+		b := make([]byte, n)
+		_, err := rand.Read(b)
+		return b, err
+	}
+	return decodeHexFlag(name, value)
+}
+
+// passwordFlags returns the password bytes of --password or --password-hex
+func passwordFlags(password, passwordHex string) ([]byte, error) {
+	if passwordHex != "" {
+		return decodeHexFlag("password-hex", passwordHex)
+	}
+	return keystorePassword(password), nil
+}
+
+// runKeystoreCreateMode encrypts a secret key, or the key of --seed at --path, into an
+// EIP-2335 keystore
+func runKeystoreCreateMode(args []string) error {
+	fs := newFlagSet("keystore-create")
+	skStr := fs.String("sk", "", "Secret key (decimal or hex), in [1, r-1]")
+	seedHex := fs.String("seed", "", "Derive the key from this seed along --path (EIP-2333) instead of --sk")
+	path := fs.String("path", "", "EIP-2334 path stored in the keystore and, with --seed, the derivation path (default m/12381/3600/0/0/0 with --seed)")
+	password := fs.String("password", "", "Password (NFKD-normalized, control codes removed)")
+	passwordHex := fs.String("password-hex", "", "Raw password bytes (hex, used as is; overrides --password)")
+	kdf := fs.String("kdf", "scrypt", "Key derivation function: scrypt or pbkdf2")
+	cost := fs.Int("cost", 262144, "KDF cost: scrypt n (a power of two) or pbkdf2 c")
+	saltHex := fs.String("salt", "", "KDF salt (hex, default: 32 random bytes)")
+	ivHex := fs.String("iv", "", "AES-128-CTR IV (16 bytes hex, default: random)")
+	uuid := fs.String("uuid", "", "Keystore UUID (default: random version 4)")
+	description := fs.String("description", "", "Keystore description")
+	out := fs.String("out", "", "Write the keystore to this file (through --sink) instead of stdout")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if (*skStr == "") == (*seedHex == "") {
+		return usageErrorf("exactly one of --sk and --seed is required")
+	}
+	if *cost <= 0 {
+		return usageErrorf("--cost must be positive")
+	}
+	pw, err := passwordFlags(*password, *passwordHex)
+	if err != nil {
+		return err
+	}
+	var sk *big.Int
+	if *skStr != "" {
+		if sk, err = parseSecretKey(*skStr); err != nil {
+			return usageError{err}
+		}
+	} else {
+		if *path == "" {
+			*path = "m/12381/3600/0/0/0"
+		}
+		indices, err := parseKeyPath(*path)
+		if err != nil {
+			return usageError{err}
+		}
+		seed, err := decodeHexFlag("seed", *seedHex)
+		if err != nil {
+			return err
+		}
+		if sk, err = blsKeyGen(seed, nil); err != nil {
+			return usageErrorf("--seed: %v", err)
+		}
+		for _, index := range indices {
+			if sk, _, err = deriveChildSK(sk, index); err != nil {
+				return err
+			}
+		}
+	}
+	salt, err := randomOrHex("salt", *saltHex, 32)
+	if err != nil {
+		return err
+	}
+	iv, err := randomOrHex("iv", *ivHex, 16)
+	if err != nil {
+		return err
+	}
+	if len(iv) != 16 {
+		return usageErrorf("--iv must be 16 bytes, got %d", len(iv))
+	}
+	if *uuid == "" {
+		if *uuid, err = newUUIDv4(); err != nil {
+			return err
+		}
+	}
+
+	ks := keystoreJSON{Description: *description, Path: *path, UUID: *uuid, Version: 4}
+	ks.Crypto.KDF = keystoreModule{Function: *kdf, Params: map[string]any{"dklen": 32, "salt": hex.EncodeToString(salt)}}
+	switch *kdf {
+	case "scrypt":
+		if *cost&(*cost-1) != 0 || *cost < 2 {
+			return usageErrorf("scrypt --cost must be a power of two greater than 1")
+		}
+		ks.Crypto.KDF.Params["n"], ks.Crypto.KDF.Params["r"], ks.Crypto.KDF.Params["p"] = *cost, 8, 1
+	case "pbkdf2":
+		ks.Crypto.KDF.Params["c"], ks.Crypto.KDF.Params["prf"] = *cost, "hmac-sha256"
+	default:
+		return usageErrorf("unknown --kdf '%s' (valid: scrypt, pbkdf2)", *kdf)
+	}
+	dk, err := keystoreDecryptionKey(ks.Crypto.KDF, pw)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := aes128CTR(dk, iv, scalarTo32Bytes(sk))
+	if err != nil {
+		return err
+	}
+	ks.Crypto.Checksum = keystoreModule{Function: "sha256", Params: map[string]any{}, Message: hex.EncodeToString(keystoreChecksum(dk, ciphertext))}
+	ks.Crypto.Cipher = keystoreModule{Function: "aes-128-ctr", Params: map[string]any{"iv": hex.EncodeToString(iv)}, Message: hex.EncodeToString(ciphertext)}
+	ks.Pubkey = hex.EncodeToString(blsPublicKey(sk, "min-pk"))
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	recordVerdictResult(ks.Crypto.Cipher.Message)
+	reportOutput("keystore", ks)
+
+	if *out == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := outputSink.write(*out, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %v", *out, err)
+	}
+	fmt.Printf("=== EIP-2335 Keystore (%s) ===\n", *kdf)
+	fmt.Printf("Public key: %s\n", ks.Pubkey)
+	fmt.Printf("Wrote %s\n", outputSink.describe(*out))
+	return nil
+}
+
+// runKeystoreUnlockMode decrypts a keystore and checks its checksum and public key; a
+// wrong password or a public key that does not match is a failure (exit code 1)
+func runKeystoreUnlockMode(args []string) (int, error) {
+	fs := newFlagSet("keystore-unlock")
+	file := fs.String("file", "", "EIP-2335 keystore JSON file")
+	password := fs.String("password", "", "Password (NFKD-normalized, control codes removed)")
+	passwordHex := fs.String("password-hex", "", "Raw password bytes (hex, used as is; overrides --password)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *file == "" {
+		return 0, usageErrorf("--file is required")
+	}
+	pw, err := passwordFlags(*password, *passwordHex)
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return 0, err
+	}
+	var ks keystoreJSON
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return 0, fmt.Errorf("%s: invalid keystore JSON: %v", *file, err)
+	}
+	if ks.Version != 4 {
+		return 0, fmt.Errorf("%s: keystore version %d (only EIP-2335 version 4)", *file, ks.Version)
+	}
+	if ks.Crypto.Checksum.Function != "sha256" || ks.Crypto.Cipher.Function != "aes-128-ctr" {
+		return 0, fmt.Errorf("%s: unsupported checksum '%s' or cipher '%s'", *file, ks.Crypto.Checksum.Function, ks.Crypto.Cipher.Function)
+	}
+	ciphertext, err := hex.DecodeString(ks.Crypto.Cipher.Message)
+	if err != nil {
+		return 0, fmt.Errorf("%s: cipher message: %v", *file, err)
+	}
+	ivHex, _ := ks.Crypto.Cipher.Params["iv"].(string)
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil {
+		return 0, fmt.Errorf("%s: cipher iv: %v", *file, err)
+	}
+	checksum, err := hex.DecodeString(ks.Crypto.Checksum.Message)
+	if err != nil {
+		return 0, fmt.Errorf("%s: checksum message: %v", *file, err)
+	}
+	recordVerdictInput(ks.Crypto.Cipher.Message)
+	reportInput("file", *file)
+
+	fmt.Printf("=== EIP-2335 Keystore Unlock (%s) ===\n", ks.Crypto.KDF.Function)
+	fmt.Printf("UUID: %s\n", ks.UUID)
+	fmt.Printf("Path: %s\n", ks.Path)
+	dk, err := keystoreDecryptionKey(ks.Crypto.KDF, pw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", *file, err)
+	}
+	if !bytes.Equal(keystoreChecksum(dk, ciphertext), checksum) {
+		fmt.Println("❌ Checksum mismatch: wrong password or corrupt keystore")
+		reportOutput("unlocked", false)
+		return 1, nil
+	}
+	plain, err := aes128CTR(dk, iv, ciphertext)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", *file, err)
+	}
+	sk := new(big.Int).SetBytes(plain)
+	if _, err := parseSecretKey(sk.String()); err != nil {
+		fmt.Printf("❌ The decrypted secret key is invalid: %v\n", err)
+		reportOutput("unlocked", false)
+		return 1, nil
+	}
+	pk := hex.EncodeToString(blsPublicKey(sk, "min-pk"))
+	recordVerdictResult(hex.EncodeToString(plain))
+	fmt.Printf("Secret key: %s\n", sk.String())
+	fmt.Printf("Secret key (32 bytes): %x\n", plain)
+	fmt.Printf("Public key: %s\n", pk)
+	reportOutput("secret_key", hex.EncodeToString(plain))
+	reportOutput("public_key", pk)
+	if ks.Pubkey != "" && !strings.EqualFold(strings.TrimPrefix(ks.Pubkey, "0x"), pk) {
+		fmt.Printf("❌ The keystore pubkey %s does not match the decrypted key\n", ks.Pubkey)
+		reportOutput("unlocked", false)
+		return 1, nil
+	}
+	fmt.Println("✅ Checksum and public key match")
+	reportOutput("unlocked", true)
+	return 0, nil
+}
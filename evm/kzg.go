@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// EIP-4844 KZG commitments, following the polynomial-commitments section of the deneb
+// consensus specs: a blob is 4096 field elements, the evaluations of a polynomial over
+// the 4096th roots of unity in bit-reversed order, and the trusted setup gives the
+// Lagrange basis in G1 and [tau] in G2. The point evaluation precompile at 0x0a takes
+// versioned_hash | z | y | commitment | proof (192 bytes) and returns
+// FIELD_ELEMENTS_PER_BLOB | BLS_MODULUS when the proof of p(z) = y is valid.
+
+const kzgBlobElements = 4096
+
+// kzgPointEvaluationAddress is the address of the point evaluation precompile
+const kzgPointEvaluationAddress = 0x0a
+
+// kzgSetup is a trusted setup with the Lagrange points in bit-reversed order, the order
+// of the blob elements
+type kzgSetup struct {
+	G1Lagrange []bls.G1Affine
+	G2Tau      bls.G2Affine
+	Source     string
+}
+
+// kzgSetupJSON is the trusted_setup.json layout of the consensus specs
+type kzgSetupJSON struct {
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// bitReverse reverses the 12 bits of a blob index
+func bitReverse(i int) int {
+	return int(bits.Reverse32(uint32(i)) >> (32 - bits.Len(kzgBlobElements-1)))
+}
+
+// kzgDomain returns the roots of unity in bit-reversed order; the spec takes the
+// 4096th root as 7^((r-1)/4096)
+func kzgDomain() []fr.Element {
+	exponent := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	exponent.Div(exponent, big.NewInt(kzgBlobElements))
+	var root fr.Element
+	root.SetUint64(7)
+	root.Exp(root, exponent)
+	domain := make([]fr.Element, kzgBlobElements)
+	var w fr.Element
+	w.SetOne()
+	for i := 0; i < kzgBlobElements; i++ {
+		domain[bitReverse(i)] = w
+		w.Mul(&w, &root)
+	}
+	return domain
+}
+
+// loadKZGSetup reads a trusted setup, either the JSON of the consensus specs or the
+// text file of c-kzg-4844 (counts, then the G1 Lagrange and the G2 monomial points)
+func loadKZGSetup(path string) (*kzgSetup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var g1, g2 []string
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var j kzgSetupJSON
+		if err := json.Unmarshal(trimmed, &j); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		g1, g2 = j.G1Lagrange, j.G2Monomial
+	} else {
+		fields := strings.Fields(string(data))
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s: missing point counts", path)
+		}
+		n1, err1 := strconv.Atoi(fields[0])
+		n2, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil || len(fields) < 2+n1+n2 {
+			return nil, fmt.Errorf("%s: bad point counts or truncated file", path)
+		}
+		g1, g2 = fields[2:2+n1], fields[2+n1:2+n1+n2]
+	}
+	if len(g1) != kzgBlobElements || len(g2) < 2 {
+		return nil, fmt.Errorf("%s: need %d G1 Lagrange and 2 G2 points, got %d and %d", path, kzgBlobElements, len(g1), len(g2))
+	}
+	s := &kzgSetup{G1Lagrange: make([]bls.G1Affine, kzgBlobElements), Source: path}
+	for i, h := range g1 {
+		b, err := hex.DecodeString(strings.TrimPrefix(h, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: G1 point %d: %v", path, i, err)
+		}
+		if s.G1Lagrange[bitReverse(i)], err = serialization.DecodeCompressedG1Point(b); err != nil {
+			return nil, fmt.Errorf("%s: G1 point %d: %v", path, i, err)
+		}
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(g2[1], "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: G2 point 1: %v", path, err)
+	}
+	if s.G2Tau, err = serialization.DecodeCompressedG2Point(b); err != nil {
+		return nil, fmt.Errorf("%s: G2 point 1: %v", path, err)
+	}
+	return s, nil
+}
+
+// insecureKZGSetup builds the setup of a known tau: L_i(tau) * g1 with
+// L_i(tau) = (tau^n - 1) / n * w_i / (tau - w_i), and tau * g2
+func insecureKZGSetup(tau fr.Element) (*kzgSetup, error) {
+	domain := kzgDomain()
+	var tauN, n fr.Element
+	tauN.Exp(tau, big.NewInt(kzgBlobElements))
+	tauN.Sub(&tauN, new(fr.Element).SetOne())
+	n.SetUint64(kzgBlobElements)
+	tauN.Div(&tauN, &n)
+	scalars := make([]fr.Element, kzgBlobElements)
+	for i := range domain {
+		var den fr.Element
+		den.Sub(&tau, &domain[i])
+		if den.IsZero() {
+			return nil, fmt.Errorf("tau is a root of unity of the blob domain")
+		}
+		scalars[i].Div(&domain[i], &den)
+		scalars[i].Mul(&scalars[i], &tauN)
+	}
+	_, _, g1Gen, g2Gen := bls.Generators()
+	s := &kzgSetup{G1Lagrange: bls.BatchScalarMultiplicationG1(&g1Gen, scalars), Source: "insecure tau " + tau.String()}
+	s.G2Tau.ScalarMultiplication(&g2Gen, tau.BigInt(new(big.Int)))
+	return s, nil
+}
+
+// commit is g1_lincomb of the Lagrange points and the evaluations
+func (s *kzgSetup) commit(poly []fr.Element) (bls.G1Affine, error) {
+	var c bls.G1Affine
+	_, err := c.MultiExp(s.G1Lagrange, poly, ecc.MultiExpConfig{})
+	return c, err
+}
+
+// kzgEvaluate is evaluate_polynomial_in_evaluation_form (barycentric)
+func kzgEvaluate(poly, domain []fr.Element, z fr.Element) fr.Element {
+	var sum fr.Element
+	for i := range domain {
+		if domain[i].Equal(&z) {
+			return poly[i]
+		}
+		var term, den fr.Element
+		den.Sub(&z, &domain[i])
+		term.Mul(&poly[i], &domain[i]).Div(&term, &den)
+		sum.Add(&sum, &term)
+	}
+	var zn, n fr.Element
+	zn.Exp(z, big.NewInt(kzgBlobElements))
+	zn.Sub(&zn, new(fr.Element).SetOne())
+	n.SetUint64(kzgBlobElements)
+	zn.Div(&zn, &n)
+	return *sum.Mul(&sum, &zn)
+}
+
+// computeProof is compute_kzg_proof: the commitment to (p(X) - y) / (X - z), with the
+// in-domain quotient of the spec where z is a root of unity
+func (s *kzgSetup) computeProof(poly []fr.Element, z fr.Element) (bls.G1Affine, fr.Element, error) {
+	domain := kzgDomain()
+	y := kzgEvaluate(poly, domain, z)
+	quotient := make([]fr.Element, kzgBlobElements)
+	inDomain := -1
+	for i := range domain {
+		var num, den fr.Element
+		num.Sub(&poly[i], &y)
+		den.Sub(&domain[i], &z)
+		if den.IsZero() {
+			inDomain = i
+			continue
+		}
+		quotient[i].Div(&num, &den)
+	}
+	if inDomain >= 0 {
+		// q(z) = sum_{i != m} (p_i - y) * w_i / (z * (z - w_i))
+		for i := range domain {
+			if i == inDomain {
+				continue
+			}
+			var num, den fr.Element
+			num.Sub(&poly[i], &y).Mul(&num, &domain[i])
+			den.Sub(&z, &domain[i]).Mul(&den, &z)
+			num.Div(&num, &den)
+			quotient[inDomain].Add(&quotient[inDomain], &num)
+		}
+	}
+	proof, err := s.commit(quotient)
+	return proof, y, err
+}
+
+// verifyProof is verify_kzg_proof: e(C - [y], -g2) * e(proof, [tau - z]) == 1
+func (s *kzgSetup) verifyProof(commitment bls.G1Affine, z, y fr.Element, proof bls.G1Affine) error {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var yG1 bls.G1Affine
+	yG1.ScalarMultiplication(&g1Gen, y.BigInt(new(big.Int)))
+	var pMinusY bls.G1Affine
+	pMinusY.Sub(&commitment, &yG1)
+	var zG2, xMinusZ, negG2 bls.G2Affine
+	zG2.ScalarMultiplication(&g2Gen, z.BigInt(new(big.Int)))
+	xMinusZ.Sub(&s.G2Tau, &zG2)
+	negG2.Neg(&g2Gen)
+	ok, err := bls.PairingCheck([]bls.G1Affine{pMinusY, proof}, []bls.G2Affine{negG2, xMinusZ})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("pairing check failed: e(C - [y], -g2) * e(proof, [tau - z]) != 1")
+	}
+	return nil
+}
+
+// kzgVersionedHash is kzg_to_versioned_hash: 0x01 | SHA-256(commitment)[1:]
+func kzgVersionedHash(commitment []byte) []byte {
+	h := sha256.Sum256(commitment)
+	h[0] = 0x01
+	return h[:]
+}
+
+// kzgPrecompileOutput is FIELD_ELEMENTS_PER_BLOB | BLS_MODULUS, as 32-byte big-endian
+func kzgPrecompileOutput() []byte {
+	out := make([]byte, 64)
+	big.NewInt(kzgBlobElements).FillBytes(out[:32])
+	fr.Modulus().FillBytes(out[32:])
+	return out
+}
+
+// kzgCanonical decodes a 32-byte big-endian field element, which must be below r
+func kzgCanonical(name string, b []byte) (fr.Element, error) {
+	var e fr.Element
+	v := new(big.Int).SetBytes(b)
+	if v.Cmp(fr.Modulus()) >= 0 {
+		return e, fmt.Errorf("%s is not a canonical field element", name)
+	}
+	e.SetBigInt(v)
+	return e, nil
+}
+
+// pointEvaluation runs the point evaluation precompile on an input
+func (s *kzgSetup) pointEvaluation(input []byte) ([]byte, error) {
+	if len(input) != 192 {
+		return nil, fmt.Errorf("invalid input length: expected 192 bytes, got %d", len(input))
+	}
+	commitment := input[96:144]
+	if !bytes.Equal(input[:32], kzgVersionedHash(commitment)) {
+		return nil, fmt.Errorf("mismatched versioned hash")
+	}
+	z, err := kzgCanonical("z", input[32:64])
+	if err != nil {
+		return nil, err
+	}
+	y, err := kzgCanonical("y", input[64:96])
+	if err != nil {
+		return nil, err
+	}
+	c, err := serialization.DecodeCompressedG1Point(commitment)
+	if err != nil {
+		return nil, fmt.Errorf("commitment: %v", err)
+	}
+	proof, err := serialization.DecodeCompressedG1Point(input[144:192])
+	if err != nil {
+		return nil, fmt.Errorf("proof: %v", err)
+	}
+	if err := s.verifyProof(c, z, y, proof); err != nil {
+		return nil, err
+	}
+	return kzgPrecompileOutput(), nil
+}
+
+// kzgPrecompileInput concatenates the precompile input
+func kzgPrecompileInput(versionedHash, z, y, commitment, proof []byte) []byte {
+	return bytes.Join([][]byte{versionedHash, z, y, commitment, proof}, nil)
+}
+
+// frBytes is a field element as 32-byte big-endian
+func frBytes(e fr.Element) []byte {
+	b := e.Bytes()
+	return b[:]
+}
+
+// seededFieldElement derives a field element from a seed and a label with HKDF-SHA256,
+// so generated vectors are reproducible from their seed
+func seededFieldElement(seed []byte, label string) (fr.Element, error) {
+	var e fr.Element
+	okm, err := hkdf.Key(sha256.New, seed, nil, label, 48)
+	if err != nil {
+		return e, err
+	}
+	e.SetBigInt(new(big.Int).SetBytes(okm))
+	return e, nil
+}
+
+// loadBlob reads a blob of 4096 canonical field elements from a hex file
+func loadBlob(path string) ([]fr.Element, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid hex: %v", path, err)
+	}
+	if len(raw) != 32*kzgBlobElements {
+		return nil, fmt.Errorf("%s: a blob is %d bytes, got %d", path, 32*kzgBlobElements, len(raw))
+	}
+	blob := make([]fr.Element, kzgBlobElements)
+	for i := range blob {
+		if blob[i], err = kzgCanonical(fmt.Sprintf("blob element %d", i), raw[32*i:32*(i+1)]); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return blob, nil
+}
+
+// kzgVector is one point evaluation precompile input and its expected result
+type kzgVector struct {
+	Name     string `json:"name"`
+	Input    string `json:"input"`
+	Expected string `json:"expected_output,omitempty"`
+	Valid    bool   `json:"valid"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// kzgVectors builds the valid precompile input and the inputs the precompile must
+// refuse
+func kzgVectors(s *kzgSetup, blob []fr.Element, commitment []byte, z, y fr.Element, proof []byte) ([]kzgVector, error) {
+	vh := kzgVersionedHash(commitment)
+	zb, yb := frBytes(z), frBytes(y)
+	var y1, z1 fr.Element
+	y1.Add(&y, new(fr.Element).SetOne())
+	z1.Add(&z, new(fr.Element).SetOne())
+	otherProof, _, err := s.computeProof(blob, z1)
+	if err != nil {
+		return nil, err
+	}
+	otherProofBytes := serialization.ConvertG1AffineToCompressed(otherProof)
+	version2 := append([]byte{0x02}, vh[1:]...)
+	zPlusR := new(big.Int).Add(z.BigInt(new(big.Int)), fr.Modulus()).FillBytes(make([]byte, 32))
+	yPlusR := new(big.Int).Add(y.BigInt(new(big.Int)), fr.Modulus()).FillBytes(make([]byte, 32))
+	offCurve := make([]byte, 48)
+	notOnCurveX(false).FillBytes(offCurve)
+	offCurve[0] |= 0x80
+	notInSubgroup := suiteNotInSubgroup(false).compressed()
+	valid := kzgPrecompileInput(vh, zb, yb, commitment, proof)
+
+	vectors := []kzgVector{
+		{Name: "valid", Input: hex.EncodeToString(valid), Valid: true},
+		{Name: "wrong-y", Input: hex.EncodeToString(kzgPrecompileInput(vh, zb, frBytes(y1), commitment, proof))},
+		{Name: "proof-of-other-point", Input: hex.EncodeToString(kzgPrecompileInput(vh, zb, yb, commitment, otherProofBytes))},
+		{Name: "versioned-hash-version", Input: hex.EncodeToString(kzgPrecompileInput(version2, zb, yb, commitment, proof))},
+		{Name: "versioned-hash-mismatch", Input: hex.EncodeToString(kzgPrecompileInput(kzgVersionedHash(otherProofBytes), zb, yb, commitment, proof))},
+		{Name: "z-not-canonical", Input: hex.EncodeToString(kzgPrecompileInput(vh, zPlusR, yb, commitment, proof))},
+		{Name: "y-not-canonical", Input: hex.EncodeToString(kzgPrecompileInput(vh, zb, yPlusR, commitment, proof))},
+		{Name: "commitment-not-on-curve", Input: hex.EncodeToString(kzgPrecompileInput(kzgVersionedHash(offCurve), zb, yb, offCurve, proof))},
+		{Name: "proof-not-in-subgroup", Input: hex.EncodeToString(kzgPrecompileInput(vh, zb, yb, commitment, notInSubgroup))},
+		{Name: "short-input", Input: hex.EncodeToString(valid[:191])},
+	}
+	return vectors, nil
+}
+
+// runKZGMode loads or generates a trusted setup, commits to a blob, proves its value
+// at z and prints the point evaluation precompile vectors, each run through the local
+// precompile (and with --diff-geth through geth's). An unexpected result is a failure.
+func runKZGMode(args []string) (int, error) {
+	fs := newFlagSet("kzg")
+	setupPath := fs.String("setup", "", "Trusted setup: trusted_setup.json of the consensus specs or c-kzg-4844 trusted_setup.txt")
+	insecureSeed := fs.String("insecure-seed", "", "Generate an INSECURE setup whose tau is derived from this seed (hex) instead of --setup")
+	blobPath := fs.String("blob", "", "Blob file (hex, 4096 canonical 32-byte big-endian field elements)")
+	blobSeed := fs.String("blob-seed", "00", "Derive the blob from this seed (hex) when --blob is not given")
+	zStr := fs.String("z", "", "Evaluation point (decimal or hex, default: derived from the blob seed)")
+	zDomain := fs.Int("z-domain", -1, "Evaluate at the root of unity of this blob index instead of --z")
+	diffGeth := fs.Bool("diff-geth", false, "Also run every vector on geth's 0x0a precompile (needs the mainnet setup)")
+	out := fs.String("out", "", "Write the blob, the values and the vectors as JSON to this file (through --sink)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if (*setupPath == "") == (*insecureSeed == "") {
+		return 0, usageErrorf("exactly one of --setup and --insecure-seed is required")
+	}
+	if *diffGeth && *setupPath == "" {
+		return 0, usageErrorf("--diff-geth needs --setup: geth verifies against the mainnet setup")
+	}
+	if *zDomain >= kzgBlobElements {
+		return 0, usageErrorf("--z-domain must be a blob index in [0, %d)", kzgBlobElements)
+	}
+	var setup *kzgSetup
+	var err error
+	if *setupPath != "" {
+		if setup, err = loadKZGSetup(*setupPath); err != nil {
+			return 0, usageError{err}
+		}
+	} else {
+		seed, err := decodeHexFlag("insecure-seed", *insecureSeed)
+		if err != nil {
+			return 0, err
+		}
+		tau, err := seededFieldElement(seed, "kzg insecure tau")
+		if err != nil {
+			return 0, err
+		}
+		if setup, err = insecureKZGSetup(tau); err != nil {
+			return 0, usageError{err}
+		}
+	}
+
+	var blob []fr.Element
+	seed, err := decodeHexFlag("blob-seed", *blobSeed)
+	if err != nil {
+		return 0, err
+	}
+	if *blobPath != "" {
+		if blob, err = loadBlob(*blobPath); err != nil {
+			return 0, usageError{err}
+		}
+	} else {
+		blob = make([]fr.Element, kzgBlobElements)
+		for i := range blob {
+			if blob[i], err = seededFieldElement(seed, fmt.Sprintf("kzg blob element %d", i)); err != nil {
+				return 0, err
+			}
+		}
+	}
+	var z fr.Element
+	switch {
+	case *zDomain >= 0:
+		z = kzgDomain()[*zDomain]
+	case *zStr != "":
+		v, _, err := parseScalarAnyForm(*zStr, "auto")
+		if err != nil {
+			return 0, usageErrorf("--z: %v", err)
+		}
+		if z, err = kzgCanonical("--z", v.FillBytes(make([]byte, 32))); err != nil {
+			return 0, usageError{err}
+		}
+	default:
+		if z, err = seededFieldElement(seed, "kzg evaluation point"); err != nil {
+			return 0, err
+		}
+	}
+
+	c, err := setup.commit(blob)
+	if err != nil {
+		return 0, err
+	}
+	proofPoint, y, err := setup.computeProof(blob, z)
+	if err != nil {
+		return 0, err
+	}
+	commitment := serialization.ConvertG1AffineToCompressed(c)
+	proof := serialization.ConvertG1AffineToCompressed(proofPoint)
+	vectors, err := kzgVectors(setup, blob, commitment, z, y, proof)
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictResult(vectors[0].Input)
+
+	fmt.Println("=== KZG Point Evaluation (EIP-4844) ===")
+	fmt.Printf("Setup: %s\n", setup.Source)
+	if *insecureSeed != "" {
+		fmt.Println("⚠️  The setup is INSECURE: tau is known, so any proof can be forged")
+	}
+	blobBytes := make([]byte, 0, 32*kzgBlobElements)
+	for i := range blob {
+		blobBytes = append(blobBytes, frBytes(blob[i])...)
+	}
+	blobHash := sha256.Sum256(blobBytes)
+	fmt.Printf("Blob SHA-256: %x\n", blobHash)
+	fmt.Printf("Commitment: %x\n", commitment)
+	fmt.Printf("Versioned hash: %x\n", kzgVersionedHash(commitment))
+	fmt.Printf("z: %x\n", frBytes(z))
+	fmt.Printf("y: %x\n", frBytes(y))
+	fmt.Printf("Proof: %x\n", proof)
+	fmt.Printf("Precompile input (0x%02x): %s\n", kzgPointEvaluationAddress, vectors[0].Input)
+	fmt.Printf("Expected output: %x\n", kzgPrecompileOutput())
+	fmt.Println()
+
+	geth := vm.PrecompiledContractsCancun[common.BytesToAddress([]byte{kzgPointEvaluationAddress})]
+	failed := 0
+	for i := range vectors {
+		v := &vectors[i]
+		input, _ := hex.DecodeString(v.Input)
+		output, verr := setup.pointEvaluation(input)
+		if verr == nil {
+			v.Expected = hex.EncodeToString(output)
+		} else {
+			v.Reason = verr.Error()
+		}
+		if (verr == nil) != v.Valid {
+			fmt.Printf("❌ %-24s precompile returned %v, expected %v\n", v.Name, verr == nil, v.Valid)
+			failed++
+			continue
+		}
+		result := "success"
+		if !v.Valid {
+			result = "failure (" + v.Reason + ")"
+		}
+		fmt.Printf("✅ %-24s expected %s\n", v.Name, result)
+		if *diffGeth {
+			gethOut, gethErr := geth.Run(input)
+			if (gethErr == nil) != v.Valid || (gethErr == nil && !bytes.Equal(gethOut, output)) {
+				fmt.Printf("❌ %-24s geth returned %x, %v\n", v.Name, gethOut, gethErr)
+				failed++
+			}
+		}
+	}
+	reportOutput("commitment", hex.EncodeToString(commitment))
+	reportOutput("versioned_hash", hex.EncodeToString(kzgVersionedHash(commitment)))
+	reportOutput("z", hex.EncodeToString(frBytes(z)))
+	reportOutput("y", hex.EncodeToString(frBytes(y)))
+	reportOutput("proof", hex.EncodeToString(proof))
+	reportOutput("vectors", vectors)
+	if *out != "" {
+		data, err := json.MarshalIndent(map[string]any{
+			"setup":          setup.Source,
+			"blob":           hex.EncodeToString(blobBytes),
+			"commitment":     hex.EncodeToString(commitment),
+			"versioned_hash": hex.EncodeToString(kzgVersionedHash(commitment)),
+			"z":              hex.EncodeToString(frBytes(z)),
+			"y":              hex.EncodeToString(frBytes(y)),
+			"proof":          hex.EncodeToString(proof),
+			"vectors":        vectors,
+		}, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+		if err := outputSink.write(*out, append(data, '\n')); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %v", *out, err)
+		}
+		fmt.Printf("Wrote %s\n", outputSink.describe(*out))
+	}
+	return failed, nil
+}
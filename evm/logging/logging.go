@@ -0,0 +1,95 @@
+// Package logging writes the diagnostics of the pairing_gen CLI and the packages behind it
+// to stderr, filtered by one process-wide level:
+//
+//	Quiet    results only: no notes or warnings
+//	Normal   notes and warnings (default)
+//	Verbose  also progress and decisions (which layout a lenient parse recovered, ...)
+//	Debug    also byte dumps of parsed inputs
+//
+// Errors are not logged here; commands return them and the CLI prints them at every level.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Level is a verbosity level, ordered from least to most output
+type Level int32
+
+const (
+	Quiet Level = iota
+	Normal
+	Verbose
+	Debug
+)
+
+// String returns the name of the level as used by the global CLI flags
+func (l Level) String() string {
+	switch l {
+	case Quiet:
+		return "quiet"
+	case Normal:
+		return "normal"
+	case Verbose:
+		return "verbose"
+	case Debug:
+		return "debug"
+	}
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// level is atomic like the other process-wide switches, so it can be read from
+// concurrent decoders
+var level atomic.Int32
+
+// mu keeps the lines of concurrent callers whole
+var mu sync.Mutex
+
+func init() {
+	level.Store(int32(Normal))
+}
+
+// SetLevel sets the process-wide level
+func SetLevel(l Level) {
+	level.Store(int32(l))
+}
+
+// CurrentLevel returns the process-wide level
+func CurrentLevel() Level {
+	return Level(level.Load())
+}
+
+// Enabled reports whether messages of level l are written. Callers use it to skip
+// building expensive dumps.
+func Enabled(l Level) bool {
+	return CurrentLevel() >= l
+}
+
+// logf writes one message when level l is enabled, adding the final newline
+func logf(l Level, prefix, format string, args ...any) {
+	if !Enabled(l) {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	mu.Lock()
+	defer mu.Unlock()
+	fmt.Fprintf(os.Stderr, "%s%s\n", prefix, msg)
+}
+
+// Printf writes a note or warning (Normal), e.g. "Warning: ..." with the caller's prefix
+func Printf(format string, args ...any) {
+	logf(Normal, "", format, args...)
+}
+
+// Verbosef writes a progress message (Verbose)
+func Verbosef(format string, args ...any) {
+	logf(Verbose, "", format, args...)
+}
+
+// Debugf writes a debug dump line with a "Debug: " prefix (Debug)
+func Debugf(format string, args ...any) {
+	logf(Debug, "Debug: ", format, args...)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// multiExpTraceStep is one pair of an Ethereum-format MultiExp input together with the
+// running sum after adding point × scalar. Printed as one JSON object per line.
+type multiExpTraceStep struct {
+	Pair        int    `json:"pair"`
+	Point       string `json:"point"`  // compressed
+	Scalar      string `json:"scalar"` // decimal, as read (not reduced)
+	Product     string `json:"product"`
+	Accumulator string `json:"accumulator"`
+}
+
+// traceMultiExpFromEthereumFormat parses the input like bls12381vec.MultiExpFromEthereumFormat
+// and records every step of the sum
+func traceMultiExpFromEthereumFormat(inputHex string, useG2 bool) ([]multiExpTraceStep, error) {
+	inputBytes, err := hex.DecodeString(strings.TrimSpace(inputHex))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse input hex: %v", err)
+	}
+	pairLen, pointLen := 160, 128
+	if useG2 {
+		pairLen, pointLen = 288, 256
+	}
+	if len(inputBytes)%pairLen != 0 {
+		return nil, fmt.Errorf("input length must be multiple of %d bytes, got %d", pairLen, len(inputBytes))
+	}
+
+	var steps []multiExpTraceStep
+	var acc1 bls.G1Jac
+	var acc2 bls.G2Jac
+	for i, offset := 0, 0; offset < len(inputBytes); i, offset = i+1, offset+pairLen {
+		pointBytes := inputBytes[offset : offset+pointLen]
+		scalar := bls12381vec.ParseEthereumScalar(inputBytes[offset+pointLen : offset+pairLen])
+		step := multiExpTraceStep{Pair: i, Scalar: scalar.String()}
+		if useG2 {
+			p, err := serialization.ParseEthereumG2PointFromBytes(pointBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse G2 point at pair %d: %v", i, err)
+			}
+			var pJac, prod bls.G2Jac
+			pJac.FromAffine(&p)
+			prod.ScalarMultiplication(&pJac, scalar)
+			if i == 0 {
+				acc2.Set(&prod)
+			} else {
+				acc2.AddAssign(&prod)
+			}
+			var prodAff, accAff bls.G2Affine
+			prodAff.FromJacobian(&prod)
+			accAff.FromJacobian(&acc2)
+			step.Point = hex.EncodeToString(serialization.ConvertG2AffineToCompressed(p))
+			step.Product = hex.EncodeToString(serialization.ConvertG2AffineToCompressed(prodAff))
+			step.Accumulator = hex.EncodeToString(serialization.ConvertG2AffineToCompressed(accAff))
+		} else {
+			p, err := serialization.ParseEthereumG1PointFromBytes(pointBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse G1 point at pair %d: %v", i, err)
+			}
+			var pJac, prod bls.G1Jac
+			pJac.FromAffine(&p)
+			prod.ScalarMultiplication(&pJac, scalar)
+			if i == 0 {
+				acc1.Set(&prod)
+			} else {
+				acc1.AddAssign(&prod)
+			}
+			var prodAff, accAff bls.G1Affine
+			prodAff.FromJacobian(&prod)
+			accAff.FromJacobian(&acc1)
+			step.Point = hex.EncodeToString(serialization.ConvertG1AffineToCompressed(p))
+			step.Product = hex.EncodeToString(serialization.ConvertG1AffineToCompressed(prodAff))
+			step.Accumulator = hex.EncodeToString(serialization.ConvertG1AffineToCompressed(accAff))
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// printMultiExpTrace prints the per-pair breakdown as JSON lines, each prefixed with
+// "PAIR " so it can be grepped out of the surrounding human-readable output
+func printMultiExpTrace(steps []multiExpTraceStep) error {
+	fmt.Println("\n=== Per-Pair Breakdown (JSON lines) ===")
+	for _, s := range steps {
+		line, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("PAIR %s\n", line)
+	}
+	return nil
+}
+
+// scalarsAtLeastR lists the pairs whose scalar is >= r. The MultiExp reduces them
+// implicitly, a common cause of vector mismatches between implementations.
+func scalarsAtLeastR(steps []multiExpTraceStep) []int {
+	var pairs []int
+	for _, s := range steps {
+		v, _ := new(big.Int).SetString(s.Scalar, 10)
+		if v.Cmp(fr.Modulus()) >= 0 {
+			pairs = append(pairs, s.Pair)
+		}
+	}
+	return pairs
+}
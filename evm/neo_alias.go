@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"evm/bls12381vec"
+)
+
+// Neo's Ethereum-compatible alias methods take the whole EIP-2537 input as a single
+// byte-array argument and return the EIP-2537 output, so an alias test only needs the
+// input and expected output as base64 (the encoding of ByteArray/ByteString values in
+// Neo's JSON-RPC and test contracts).
+
+// neoContractParam is a ContractParameter as written in invokefunction params
+type neoContractParam struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// neoAliasArgs is what the neo-alias emitter and mode produce for one input
+type neoAliasArgs struct {
+	Fixture     string             `json:"fixture,omitempty"`
+	Operation   string             `json:"operation"`
+	Params      []neoContractParam `json:"params"`
+	Expected    neoContractParam   `json:"expected"`
+	InputHex    string             `json:"input_hex"`
+	ExpectedHex string             `json:"expected_hex"`
+}
+
+func newNeoAliasArgs(fixture, operation string, input, expected []byte) neoAliasArgs {
+	return neoAliasArgs{
+		Fixture:     fixture,
+		Operation:   operation,
+		Params:      []neoContractParam{{Type: "ByteArray", Value: base64.StdEncoding.EncodeToString(input)}},
+		Expected:    neoContractParam{Type: "ByteString", Value: base64.StdEncoding.EncodeToString(expected)},
+		InputHex:    hex.EncodeToString(input),
+		ExpectedHex: hex.EncodeToString(expected),
+	}
+}
+
+// renderNeoAliasFixture renders a MultiExp fixture as the alias method argument (JSON)
+func renderNeoAliasFixture(f multiExpFixture) string {
+	op := "g1msm"
+	if f.UseG2 {
+		op = "g2msm"
+	}
+	data, _ := json.MarshalIndent(newNeoAliasArgs(f.Name, op, f.EthereumInput, f.ExpectedEthereum), "", "  ")
+	return string(data) + "\n"
+}
+
+// printNeoAliasArgs prints the alias argument for an input in copy-paste form
+func printNeoAliasArgs(operation string, input, expected []byte) {
+	a := newNeoAliasArgs("", operation, input, expected)
+	params, _ := json.Marshal(a.Params)
+	fmt.Printf("=== Neo Ethereum Alias Argument (%s) ===\n", operation)
+	fmt.Printf("Argument (base64, %d bytes): %s\n", len(input), a.Params[0].Value)
+	fmt.Printf("invokefunction params: %s\n", params)
+	fmt.Printf("Expected result (base64, %d bytes): %s\n", len(expected), a.Expected.Value)
+	fmt.Printf("Expected result (hex): %s\n", a.ExpectedHex)
+}
+
+// runNeoAliasArgsMode formats an EIP-2537 input (optionally in the hybrid compressed
+// layout) as the single byte-array argument of Neo's alias method for the operation
+func runNeoAliasArgsMode(args []string) error {
+	fs := newFlagSet("neo-alias-args")
+	op := fs.String("op", "", "Operation: g1add, g2add, g1mul, g2mul, g1msm, g2msm or pairing")
+	inputHex := registerInputFlag(fs, "EIP-2537 input hex")
+	inputFormat := fs.String("input-format", "ethereum", "Pair layout for g1msm/g2msm/pairing: ethereum, compressed or auto")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *inputHex == "" {
+		return fmt.Errorf("--input is required")
+	}
+
+	input := strings.TrimPrefix(strings.TrimSpace(*inputHex), "0x")
+	var result string
+	var err error
+	switch *op {
+	case "g1msm", "g2msm", "pairing":
+		if input, err = normalizeInputHex(*op, *inputFormat, input); err != nil {
+			return err
+		}
+	case "g1add", "g2add", "g1mul", "g2mul":
+		if *inputFormat != "ethereum" {
+			return fmt.Errorf("--input-format is only supported for g1msm, g2msm and pairing")
+		}
+	default:
+		return fmt.Errorf("invalid --op '%s' (valid: g1add, g2add, g1mul, g2mul, g1msm, g2msm, pairing)", *op)
+	}
+	switch *op {
+	case "g1add":
+		result, err = bls12381vec.G1Add(input)
+	case "g2add":
+		result, err = bls12381vec.G2Add(input)
+	case "g1mul":
+		result, err = bls12381vec.G1Mul(input)
+	case "g2mul":
+		result, err = bls12381vec.G2Mul(input)
+	case "g1msm":
+		result, err = bls12381vec.G1MSM(input)
+	case "g2msm":
+		result, err = bls12381vec.G2MSM(input)
+	case "pairing":
+		result, err = bls12381vec.Pairing(input)
+	}
+	if err != nil {
+		return err
+	}
+	inputBytes, _ := hex.DecodeString(input)
+	expected, err := hex.DecodeString(result)
+	if err != nil {
+		return fmt.Errorf("unexpected result encoding: %v", err)
+	}
+	printNeoAliasArgs(*op, inputBytes, expected)
+	return nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// neoInvokeResult is the result object of Neo's invokescript / invokefunction RPC calls
+type neoInvokeResult struct {
+	State       string         `json:"state"`
+	GasConsumed string         `json:"gasconsumed"`
+	Exception   *string        `json:"exception"`
+	Stack       []neoStackItem `json:"stack"`
+}
+
+type neoStackItem struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// neoRPCResponse is the JSON-RPC envelope; a bare result object is accepted as well
+type neoRPCResponse struct {
+	Result *neoInvokeResult `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// parseNeoInvokeResponse accepts either a full JSON-RPC response or just its result object
+func parseNeoInvokeResponse(data []byte) (neoInvokeResult, error) {
+	var resp neoRPCResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return neoInvokeResult{}, fmt.Errorf("invalid Neo RPC response: %v", err)
+	}
+	if resp.Error != nil {
+		return neoInvokeResult{}, fmt.Errorf("Neo RPC error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+	if resp.Result != nil {
+		return *resp.Result, nil
+	}
+	var result neoInvokeResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("invalid Neo RPC response: %v", err)
+	}
+	if result.State == "" {
+		return result, fmt.Errorf("invalid Neo RPC response: no state found")
+	}
+	return result, nil
+}
+
+// comparable renders a stack item the way --expected is written: ByteString and Buffer
+// as hex, Integer as decimal, Boolean as true/false
+func (it neoStackItem) comparable() (string, error) {
+	switch it.Type {
+	case "ByteString", "Buffer":
+		var b64 string
+		if err := json.Unmarshal(it.Value, &b64); err != nil {
+			return "", fmt.Errorf("invalid %s value: %v", it.Type, err)
+		}
+		b, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return "", fmt.Errorf("invalid base64 in %s value: %v", it.Type, err)
+		}
+		return hex.EncodeToString(b), nil
+	case "Integer":
+		var s string
+		if err := json.Unmarshal(it.Value, &s); err != nil {
+			return "", fmt.Errorf("invalid Integer value: %v", err)
+		}
+		return s, nil
+	case "Boolean":
+		var v bool
+		if err := json.Unmarshal(it.Value, &v); err != nil {
+			return "", fmt.Errorf("invalid Boolean value: %v", err)
+		}
+		return fmt.Sprintf("%t", v), nil
+	}
+	return "", fmt.Errorf("stack item type %s cannot be compared (serialize the result in the script)", it.Type)
+}
+
+// neoExpectation is what a vector expects from the node: a value, or a FAULT with an
+// optional exception message substring
+type neoExpectation struct {
+	Value           string
+	Fault           bool
+	ExceptionSubstr string
+}
+
+// checkNeoResult compares a node result with the expectation. It returns whether the
+// result matched and a one-line explanation.
+func checkNeoResult(r neoInvokeResult, exp neoExpectation) (bool, string) {
+	exception := ""
+	if r.Exception != nil {
+		exception = *r.Exception
+	}
+	if exp.Fault {
+		if r.State != "FAULT" {
+			return false, fmt.Sprintf("expected FAULT, got %s", r.State)
+		}
+		if exp.ExceptionSubstr != "" && !strings.Contains(strings.ToLower(exception), strings.ToLower(exp.ExceptionSubstr)) {
+			return false, fmt.Sprintf("FAULT as expected, but exception %q does not contain %q", exception, exp.ExceptionSubstr)
+		}
+		return true, fmt.Sprintf("FAULT as expected (exception: %q)", exception)
+	}
+
+	if r.State != "HALT" {
+		return false, fmt.Sprintf("expected HALT, got %s (exception: %q)", r.State, exception)
+	}
+	if len(r.Stack) == 0 {
+		return false, "HALT with an empty stack"
+	}
+	got, err := r.Stack[0].comparable()
+	if err != nil {
+		return false, err.Error()
+	}
+	want := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(exp.Value)), "0x")
+	if got != want {
+		return false, fmt.Sprintf("value mismatch: got %s, expected %s", got, want)
+	}
+	return true, "value matches"
+}
+
+// neoInvokeScriptRequest is the JSON-RPC body of an invokescript call
+func neoInvokeScriptRequest(script string, id int) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "invokescript",
+		"params":  []string{script},
+	})
+}
+
+// invokeNeoScript sends a base64 script to a Neo node with invokescript
+func invokeNeoScript(rpcURL, script string) ([]byte, error) {
+	req, err := neoInvokeScriptRequest(script, 1)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(rpcURL, "application/json", bytes.NewReader(req))
+	if err != nil {
+		return nil, fmt.Errorf("invokescript failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("invokescript failed: HTTP %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// runNeoCompareMode compares a Neo invocation result against an expected value or an
+// expected FAULT, so rejection behavior of negative vectors is checked automatically too
+func runNeoCompareMode(args []string) error {
+	fs := newFlagSet("neo-compare")
+	responsePath := fs.String("response", "", "File with the invokescript/invokefunction JSON response (- for stdin)")
+	rpcURL := fs.String("rpc", "", "Neo RPC endpoint to run --script against (instead of --response)")
+	script := fs.String("script", "", "Base64 script for invokescript (with --rpc)")
+	expected := fs.String("expected", "", "Expected top stack item: hex for ByteString/Buffer, decimal for Integer, true/false for Boolean")
+	expectFault := fs.Bool("expect-fault", false, "Expect the invocation to FAULT instead of returning a value")
+	expectException := fs.String("expect-exception", "", "Expect a FAULT whose exception message contains this text (implies --expect-fault)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	exp := neoExpectation{Value: *expected, Fault: *expectFault || *expectException != "", ExceptionSubstr: *expectException}
+	if exp.Fault && exp.Value != "" {
+		return fmt.Errorf("--expected and --expect-fault/--expect-exception are mutually exclusive")
+	}
+	if !exp.Fault && exp.Value == "" {
+		return fmt.Errorf("--expected, --expect-fault or --expect-exception is required")
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case *rpcURL != "":
+		if *script == "" {
+			return fmt.Errorf("--script is required with --rpc")
+		}
+		data, err = invokeNeoScript(*rpcURL, *script)
+	case *responsePath == "" || *responsePath == "-":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(*responsePath)
+	}
+	if err != nil {
+		return err
+	}
+	result, err := parseNeoInvokeResponse(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("=== Neo Compare ===")
+	fmt.Printf("State: %s\n", result.State)
+	if result.GasConsumed != "" {
+		fmt.Printf("Gas consumed: %s\n", result.GasConsumed)
+	}
+	if result.Exception != nil {
+		fmt.Printf("Exception: %s\n", *result.Exception)
+	}
+	if exp.Fault {
+		if exp.ExceptionSubstr != "" {
+			fmt.Printf("Expected: FAULT (exception containing %q)\n", exp.ExceptionSubstr)
+		} else {
+			fmt.Println("Expected: FAULT")
+		}
+	} else {
+		fmt.Printf("Expected: %s\n", exp.Value)
+	}
+
+	ok, detail := checkNeoResult(result, exp)
+	if !ok {
+		fmt.Printf("❌ %s\n", detail)
+		return fmt.Errorf("neo result does not match expectation")
+	}
+	fmt.Printf("✅ %s\n", detail)
+	return nil
+}
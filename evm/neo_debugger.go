@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// The Neo smart contract debugger shows ByteString/Buffer values as 0x-prefixed hex in
+// byte order and Integer values in decimal, and its watch and evaluate boxes accept the
+// same literals. Integers are stored little-endian in the VM, so when a contract builds
+// a scalar with Convert/ToByteArray the value to compare against is the little-endian
+// form, not the 32-byte big-endian EIP-2537 field.
+
+// neoDebuggerHex renders bytes as a debugger ByteString literal
+func neoDebuggerHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// renderNeoDebuggerFixture renders a MultiExp fixture as one "name = literal" line per
+// value, so every right-hand side can be pasted into a live debugging session
+func renderNeoDebuggerFixture(f multiExpFixture) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Neo debugger watch/evaluate literals for fixture %s (%s MultiExp, %d pairs)\n", f.Name, f.groupName(), len(f.Points))
+	b.WriteString("// ByteString: 0x-prefixed hex in byte order; Integer: decimal, \"bytes\" is the little-endian VM encoding\n")
+	for i, p := range f.Points {
+		fmt.Fprintf(&b, "points[%d] = %s\n", i, neoDebuggerHex(p))
+	}
+	for i, s := range f.Scalars {
+		fmt.Fprintf(&b, "scalars[%d] = %s\n", i, s.String())
+		fmt.Fprintf(&b, "scalars[%d] bytes = %s\n", i, neoDebuggerHex(csharpBigIntegerBytes(s)))
+	}
+	fmt.Fprintf(&b, "ethereumInput = %s\n", neoDebuggerHex(f.EthereumInput))
+	fmt.Fprintf(&b, "expected = %s\n", neoDebuggerHex(f.Expected))
+	fmt.Fprintf(&b, "expectedEthereum = %s\n", neoDebuggerHex(f.ExpectedEthereum))
+	return b.String()
+}
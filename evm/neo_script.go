@@ -0,0 +1,439 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// NeoVM scripts calling the native CryptoLib on compressed points, built like
+// Bls12381MultiExpHelper.cs does with Neo's ScriptBuilder: every call is
+// EmitDynamicCall(CryptoLib, method, args), i.e. the arguments in reverse order, PACK,
+// CallFlags.All, the method name, the contract hash and SYSCALL System.Contract.Call.
+// Points are passed through bls12381Deserialize and the result through bls12381Serialize,
+// so the script returns a ByteString that neo-compare can check.
+
+// NeoVM opcodes used by the scripts
+const (
+	neoOpPushInt8  = 0x00
+	neoOpPushInt16 = 0x01
+	neoOpPushInt32 = 0x02
+	neoOpPushT     = 0x08
+	neoOpPushF     = 0x09
+	neoOpPushData1 = 0x0c
+	neoOpPushData2 = 0x0d
+	neoOpPushData4 = 0x0e
+	neoOpPush0     = 0x10
+	neoOpSyscall   = 0x41
+	neoOpPack      = 0xc0
+)
+
+// neoCryptoLibHash is CryptoLib's script hash as displayed (big-endian); scripts carry
+// the UInt160 little-endian
+const neoCryptoLibHash = "726cb6e0cd8628a1350a611384688911ab75f51b"
+
+var (
+	// neoSyscallContractCall is the interop hash of System.Contract.Call
+	neoSyscallContractCall = []byte{0x62, 0x7d, 0x5b, 0x52}
+	// neoCallFlagsAll is CallFlags.All
+	neoCallFlagsAll int64 = 0x0f
+)
+
+// neoScriptBuilder emits NeoVM bytecode with the same encodings as Neo's ScriptBuilder
+type neoScriptBuilder struct {
+	buf bytes.Buffer
+}
+
+func (b *neoScriptBuilder) emit(op byte, operand ...byte) {
+	b.buf.WriteByte(op)
+	b.buf.Write(operand)
+}
+
+// pushBytes emits PUSHDATA1/2/4 with the shortest length prefix
+func (b *neoScriptBuilder) pushBytes(data []byte) {
+	switch n := len(data); {
+	case n < 0x100:
+		b.emit(neoOpPushData1, byte(n))
+	case n < 0x10000:
+		b.emit(neoOpPushData2, byte(n), byte(n>>8))
+	default:
+		b.emit(neoOpPushData4, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	b.buf.Write(data)
+}
+
+// pushInt emits PUSH0..PUSH16 or the smallest PUSHINT8/16/32 (little-endian)
+func (b *neoScriptBuilder) pushInt(v int64) {
+	switch {
+	case v >= 0 && v <= 16:
+		b.emit(neoOpPush0 + byte(v))
+	case v >= -128 && v <= 127:
+		b.emit(neoOpPushInt8, byte(v))
+	case v >= -32768 && v <= 32767:
+		b.emit(neoOpPushInt16, binary.LittleEndian.AppendUint16(nil, uint16(v))...)
+	default:
+		b.emit(neoOpPushInt32, binary.LittleEndian.AppendUint32(nil, uint32(v))...)
+	}
+}
+
+func (b *neoScriptBuilder) pushBool(v bool) {
+	if v {
+		b.emit(neoOpPushT)
+	} else {
+		b.emit(neoOpPushF)
+	}
+}
+
+// call packs the argc values on top of the stack (pushed last argument first) and calls
+// a CryptoLib method with them
+func (b *neoScriptBuilder) call(method string, argc int) {
+	b.pushInt(int64(argc))
+	b.emit(neoOpPack)
+	b.pushInt(neoCallFlagsAll)
+	b.pushBytes([]byte(method))
+	hash, _ := hex.DecodeString(neoCryptoLibHash)
+	b.pushBytes(reverseBytes(hash))
+	b.emit(neoOpSyscall, neoSyscallContractCall...)
+}
+
+// deserialize pushes the point object of a compressed encoding
+func (b *neoScriptBuilder) deserialize(point []byte) {
+	b.pushBytes(point)
+	b.call("bls12381Deserialize", 1)
+}
+
+// neoScriptPoint is one decoded --a/--b/--point/--g1/--g2/--points value. err is set when
+// bls12381Deserialize will FAULT on it; the script is still built for negative tests.
+type neoScriptPoint struct {
+	data []byte
+	g2   bool
+	p1   bls.G1Affine
+	p2   bls.G2Affine
+	err  error
+}
+
+func parseNeoScriptPoint(flagName, s string) (neoScriptPoint, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return neoScriptPoint{}, usageErrorf("invalid %s hex: %v", flagName, err)
+	}
+	p := neoScriptPoint{data: data}
+	switch len(data) {
+	case 48:
+		p.p1, p.err = serialization.DecodeCompressedG1Point(data)
+	case 96:
+		p.g2 = true
+		p.p2, p.err = serialization.DecodeCompressedG2Point(data)
+	default:
+		return p, usageErrorf("%s must be a compressed G1 (48 bytes) or G2 (96 bytes) point, got %d bytes", flagName, len(data))
+	}
+	return p, nil
+}
+
+// neoScriptResult is the script and the outcome a conforming node must produce
+type neoScriptResult struct {
+	script   []byte
+	expected []byte // serialized result; nil when fault is set
+	fault    string // why the invocation must FAULT
+}
+
+// neoScriptFlags are the operation and input flags shared by neo-script and neo-verify
+type neoScriptFlags struct {
+	op, a, b, point, scalar, scalarFormat *string
+	neg                                   *bool
+	g1, g2, points, scalars               *string
+}
+
+func addNeoScriptFlags(fs *flag.FlagSet) *neoScriptFlags {
+	return &neoScriptFlags{
+		op:           fs.String("op", "", "Operation: add, mul, pairing or multiexp"),
+		a:            fs.String("a", "", "add: first compressed point (G1 or G2)"),
+		b:            fs.String("b", "", "add: second compressed point (same group)"),
+		point:        fs.String("point", "", "mul: compressed point"),
+		scalar:       fs.String("scalar", "", "mul: scalar (decimal, 0x-hex)"),
+		scalarFormat: fs.String("scalar-format", "auto", "Scalar format: auto, dec, hex or le-hex"),
+		neg:          fs.Bool("neg", false, "mul: negate the product (bls12381Mul's neg argument)"),
+		g1:           fs.String("g1", "", "pairing: compressed G1 points (comma-separated)"),
+		g2:           fs.String("g2", "", "pairing: compressed G2 points (comma-separated, same count)"),
+		points:       fs.String("points", "", "multiexp: compressed points (comma-separated, one group)"),
+		scalars:      fs.String("scalars", "", "multiexp: scalars (comma-separated, same count)"),
+	}
+}
+
+// build assembles the script of the selected operation
+func (f *neoScriptFlags) build() (neoScriptResult, error) {
+	switch *f.op {
+	case "add":
+		return buildNeoAddScript(*f.a, *f.b)
+	case "mul":
+		return buildNeoMulScript(*f.point, *f.scalar, *f.scalarFormat, *f.neg)
+	case "pairing":
+		return buildNeoPairingScript(splitPointList(*f.g1), splitPointList(*f.g2))
+	case "multiexp":
+		return buildNeoMultiExpScript(splitPointList(*f.points), splitPointList(*f.scalars), *f.scalarFormat)
+	}
+	return neoScriptResult{}, usageErrorf("invalid --op '%s' (valid: add, mul, pairing, multiexp)", *f.op)
+}
+
+// runNeoScriptMode builds the invokescript payload for one CryptoLib operation
+func runNeoScriptMode(args []string) error {
+	fs := newFlagSet("neo-script")
+	inputs := addNeoScriptFlags(fs)
+	id := fs.Int("id", 1, "JSON-RPC request id")
+	bodyOut := fs.String("body-out", "", "Also write the JSON-RPC request body to this file (for curl -d @file)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	res, err := inputs.build()
+	if err != nil {
+		return err
+	}
+	op := inputs.op
+
+	script := base64.StdEncoding.EncodeToString(res.script)
+	body, err := neoInvokeScriptRequest(script, *id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("=== Neo invokescript (%s) ===\n", *op)
+	fmt.Printf("Script (hex, %d bytes): %s\n", len(res.script), hex.EncodeToString(res.script))
+	fmt.Printf("Script (base64): %s\n", script)
+	fmt.Printf("Request body: %s\n", body)
+	if res.fault != "" {
+		fmt.Printf("Expected: FAULT (%s)\n", res.fault)
+		fmt.Printf("Check with: go run . neo-compare --rpc <url> --script %s --expect-fault\n", script)
+	} else {
+		fmt.Printf("Expected result (ByteString, %d bytes): %s\n", len(res.expected), hex.EncodeToString(res.expected))
+		fmt.Printf("Check with: go run . neo-compare --rpc <url> --script %s --expected %s\n", script, hex.EncodeToString(res.expected))
+	}
+	if *bodyOut != "" {
+		if err := os.WriteFile(*bodyOut, append(body, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", *bodyOut, err)
+		}
+		fmt.Printf("Wrote %s\n", *bodyOut)
+	}
+
+	recordVerdictResult(hex.EncodeToString(res.script))
+	reportOutput("script_base64", script)
+	reportOutput("script_hex", hex.EncodeToString(res.script))
+	reportOutput("request", string(body))
+	if res.fault != "" {
+		reportOutput("expected_fault", res.fault)
+	} else {
+		reportOutput("expected", hex.EncodeToString(res.expected))
+	}
+	return nil
+}
+
+func buildNeoAddScript(aHex, bHex string) (neoScriptResult, error) {
+	if aHex == "" || bHex == "" {
+		return neoScriptResult{}, usageErrorf("--a and --b are required for add")
+	}
+	pa, err := parseNeoScriptPoint("--a", aHex)
+	if err != nil {
+		return neoScriptResult{}, err
+	}
+	pb, err := parseNeoScriptPoint("--b", bHex)
+	if err != nil {
+		return neoScriptResult{}, err
+	}
+	if pa.g2 != pb.g2 {
+		return neoScriptResult{}, usageErrorf("--a and --b must be in the same group")
+	}
+
+	var sb neoScriptBuilder
+	sb.deserialize(pb.data)
+	sb.deserialize(pa.data)
+	sb.call("bls12381Add", 2)
+	sb.call("bls12381Serialize", 1)
+	res := neoScriptResult{script: sb.buf.Bytes()}
+	switch {
+	case pa.err != nil:
+		res.fault = "--a: " + pa.err.Error()
+	case pb.err != nil:
+		res.fault = "--b: " + pb.err.Error()
+	case pa.g2:
+		var sum bls.G2Affine
+		sum.Add(&pa.p2, &pb.p2)
+		res.expected = serialization.ConvertG2AffineToCompressed(sum)
+	default:
+		var sum bls.G1Affine
+		sum.Add(&pa.p1, &pb.p1)
+		res.expected = serialization.ConvertG1AffineToCompressed(sum)
+	}
+	return res, nil
+}
+
+func buildNeoMulScript(pointHex, scalarValue, scalarFormat string, neg bool) (neoScriptResult, error) {
+	if pointHex == "" || scalarValue == "" {
+		return neoScriptResult{}, usageErrorf("--point and --scalar are required for mul")
+	}
+	p, err := parseNeoScriptPoint("--point", pointHex)
+	if err != nil {
+		return neoScriptResult{}, err
+	}
+	k, _, err := parseScalarAnyForm(scalarValue, scalarFormat)
+	if err != nil {
+		return neoScriptResult{}, usageError{err}
+	}
+	if k.Sign() < 0 || k.BitLen() > 256 {
+		return neoScriptResult{}, usageErrorf("--scalar must fit in 32 unsigned bytes (use --neg for a negative product)")
+	}
+
+	// bls12381Mul takes the 32-byte little-endian Scalar encoding
+	var sb neoScriptBuilder
+	sb.pushBool(neg)
+	sb.pushBytes(reverseBytes(scalarTo32Bytes(k)))
+	sb.deserialize(p.data)
+	sb.call("bls12381Mul", 3)
+	sb.call("bls12381Serialize", 1)
+	res := neoScriptResult{script: sb.buf.Bytes()}
+	if k.Cmp(fr.Modulus()) >= 0 {
+		res.fault = "scalar >= r is not a canonical Scalar"
+		return res, nil
+	}
+	if p.err != nil {
+		res.fault = "--point: " + p.err.Error()
+		return res, nil
+	}
+	if neg {
+		k = new(big.Int).Neg(k)
+	}
+	if p.g2 {
+		var q bls.G2Affine
+		q.ScalarMultiplication(&p.p2, k)
+		res.expected = serialization.ConvertG2AffineToCompressed(q)
+	} else {
+		var q bls.G1Affine
+		q.ScalarMultiplication(&p.p1, k)
+		res.expected = serialization.ConvertG1AffineToCompressed(q)
+	}
+	return res, nil
+}
+
+// buildNeoPairingScript multiplies the pairings of all pairs in GT with bls12381Add
+func buildNeoPairingScript(g1Hexes, g2Hexes []string) (neoScriptResult, error) {
+	if len(g1Hexes) == 0 || len(g1Hexes) != len(g2Hexes) {
+		return neoScriptResult{}, usageErrorf("--g1 and --g2 need the same, non-zero number of points (got %d and %d)", len(g1Hexes), len(g2Hexes))
+	}
+	var sb neoScriptBuilder
+	var g1Points []bls.G1Affine
+	var g2Points []bls.G2Affine
+	fault := ""
+	for i := range g1Hexes {
+		p, err := parseNeoScriptPoint(fmt.Sprintf("--g1[%d]", i), g1Hexes[i])
+		if err != nil {
+			return neoScriptResult{}, err
+		}
+		q, err := parseNeoScriptPoint(fmt.Sprintf("--g2[%d]", i), g2Hexes[i])
+		if err != nil {
+			return neoScriptResult{}, err
+		}
+		if p.g2 || !q.g2 {
+			return neoScriptResult{}, usageErrorf("pair %d: --g1 needs a G1 point and --g2 a G2 point", i)
+		}
+		if fault == "" && p.err != nil {
+			fault = fmt.Sprintf("--g1[%d]: %v", i, p.err)
+		}
+		if fault == "" && q.err != nil {
+			fault = fmt.Sprintf("--g2[%d]: %v", i, q.err)
+		}
+		g1Points, g2Points = append(g1Points, p.p1), append(g2Points, q.p2)
+
+		sb.deserialize(q.data)
+		sb.deserialize(p.data)
+		sb.call("bls12381Pairing", 2)
+		if i > 0 {
+			sb.call("bls12381Add", 2)
+		}
+	}
+	sb.call("bls12381Serialize", 1)
+	res := neoScriptResult{script: sb.buf.Bytes(), fault: fault}
+	if fault == "" {
+		z, err := bls.Pair(g1Points, g2Points)
+		if err != nil {
+			return res, err
+		}
+		res.expected = serialization.EncodeNeoGT(&z)
+	}
+	return res, nil
+}
+
+// buildNeoMultiExpScript passes [[point, scalar], ...] to bls12381MultiExp with 32-byte
+// big-endian scalars, like Bls12381MultiExpHelper.CreateMultiExpScript
+func buildNeoMultiExpScript(pointHexes, scalarValues []string, scalarFormat string) (neoScriptResult, error) {
+	if len(pointHexes) == 0 || len(pointHexes) != len(scalarValues) {
+		return neoScriptResult{}, usageErrorf("--points and --scalars need the same, non-zero number of values (got %d and %d)", len(pointHexes), len(scalarValues))
+	}
+	var sb neoScriptBuilder
+	var g1Points []bls.G1Affine
+	var g2Points []bls.G2Affine
+	ks := make([]*big.Int, len(pointHexes))
+	fault := ""
+	useG2 := false
+	for i := range pointHexes {
+		p, err := parseNeoScriptPoint(fmt.Sprintf("--points[%d]", i), pointHexes[i])
+		if err != nil {
+			return neoScriptResult{}, err
+		}
+		if i == 0 {
+			useG2 = p.g2
+		} else if p.g2 != useG2 {
+			return neoScriptResult{}, usageErrorf("--points[%d] is in the other group than --points[0]", i)
+		}
+		k, _, err := parseScalarAnyForm(scalarValues[i], scalarFormat)
+		if err != nil {
+			return neoScriptResult{}, usageError{err}
+		}
+		if k.Sign() < 0 || k.BitLen() > 256 {
+			return neoScriptResult{}, usageErrorf("--scalars[%d] must fit in 32 unsigned bytes", i)
+		}
+		if fault == "" && p.err != nil {
+			fault = fmt.Sprintf("--points[%d]: %v", i, p.err)
+		}
+		if fault == "" && k.Cmp(fr.Modulus()) >= 0 {
+			fault = fmt.Sprintf("--scalars[%d] >= r is not a canonical Scalar", i)
+		}
+		ks[i] = k
+		g1Points, g2Points = append(g1Points, p.p1), append(g2Points, p.p2)
+
+		sb.pushBytes(scalarTo32Bytes(k))
+		sb.deserialize(p.data)
+		sb.pushInt(2)
+		sb.emit(neoOpPack)
+	}
+	sb.pushInt(int64(len(pointHexes)))
+	sb.emit(neoOpPack)
+	sb.call("bls12381MultiExp", 1)
+	sb.call("bls12381Serialize", 1)
+	res := neoScriptResult{script: sb.buf.Bytes(), fault: fault}
+	if fault != "" {
+		return res, nil
+	}
+	if useG2 {
+		r, err := bls12381vec.MultiExpG2(g2Points, ks)
+		if err != nil {
+			return res, err
+		}
+		res.expected = serialization.ConvertG2AffineToCompressed(r)
+	} else {
+		r, err := bls12381vec.MultiExpG1(g1Points, ks)
+		if err != nil {
+			return res, err
+		}
+		res.expected = serialization.ConvertG1AffineToCompressed(r)
+	}
+	return res, nil
+}
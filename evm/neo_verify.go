@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// neoVerifyVector is one script to run on the node with what it must return
+type neoVerifyVector struct {
+	name string
+	res  neoScriptResult
+}
+
+// runNeoVerifyMode runs vectors on a Neo node with invokescript and checks the result
+// stack against the expectation computed locally
+func runNeoVerifyMode(args []string) (int, error) {
+	fs := newFlagSet("neo-verify")
+	rpcURL := fs.String("rpc-url", "", "Neo N3 RPC endpoint (e.g. http://localhost:10332)")
+	vectors := fs.String("vectors", "", "MultiExp fixtures: a --format json document, an array of its fixtures or one fixture")
+	inputs := addNeoScriptFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *rpcURL == "" {
+		return 0, usageErrorf("--rpc-url is required")
+	}
+	if (*vectors == "") == (*inputs.op == "") {
+		return 0, usageErrorf("use either --vectors or --op with its inputs")
+	}
+
+	failed := 0
+	var list []neoVerifyVector
+	if *vectors != "" {
+		fixtures, err := loadJSONFixtures(*vectors)
+		if err != nil {
+			return 0, err
+		}
+		for i, f := range fixtures {
+			name := f.Name
+			if name == "" {
+				name = fmt.Sprintf("fixture[%d]", i)
+			}
+			res, err := buildNeoMultiExpScript(f.Points, f.Scalars, "dec")
+			if err != nil {
+				return 0, fmt.Errorf("%s: %v", name, err)
+			}
+			// A fixture disagreeing with the local computation is a broken vector, not a node bug
+			if local := hex.EncodeToString(res.expected); f.Expected != "" && (res.fault != "" || local != f.Expected) {
+				fmt.Printf("❌ %s: fixture expects %s, locally computed %s\n", name, f.Expected, describeNeoExpectation(res))
+				failed++
+			}
+			list = append(list, neoVerifyVector{name: name, res: res})
+		}
+	} else {
+		res, err := inputs.build()
+		if err != nil {
+			return 0, err
+		}
+		list = append(list, neoVerifyVector{name: *inputs.op, res: res})
+	}
+
+	fmt.Println("=== Neo RPC Verification ===")
+	fmt.Printf("Node: %s\n", *rpcURL)
+	fmt.Printf("Vectors: %d\n", len(list))
+	for _, v := range list {
+		data, err := invokeNeoScript(*rpcURL, base64.StdEncoding.EncodeToString(v.res.script))
+		if err != nil {
+			return failed, err
+		}
+		result, err := parseNeoInvokeResponse(data)
+		if err != nil {
+			return failed, fmt.Errorf("%s: %v", v.name, err)
+		}
+		exp := neoExpectation{Fault: v.res.fault != "", Value: hex.EncodeToString(v.res.expected)}
+		if ok, detail := checkNeoResult(result, exp); ok {
+			fmt.Printf("✅ PASS %s: %s (gas %s)\n", v.name, detail, result.GasConsumed)
+		} else {
+			if v.res.fault != "" {
+				detail += " (" + v.res.fault + ")"
+			}
+			fmt.Printf("❌ FAIL %s: %s\n", v.name, detail)
+			failed++
+		}
+	}
+	fmt.Printf("Vectors: %d, failed: %d\n", len(list), failed)
+	if failed == 0 {
+		fmt.Println("✅ The node matches every locally computed expectation")
+	}
+	reportOutput("vectors", len(list))
+	reportOutput("failed", failed)
+	return failed, nil
+}
+
+// describeNeoExpectation renders the expected outcome of a script for messages
+func describeNeoExpectation(res neoScriptResult) string {
+	if res.fault != "" {
+		return fmt.Sprintf("FAULT (%s)", res.fault)
+	}
+	return hex.EncodeToString(res.expected)
+}
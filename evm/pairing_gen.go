@@ -2,92 +2,26 @@ package main
 
 import (
 	"encoding/hex"
-	"flag"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"evm/bls12381vec"
+	"evm/logging"
+	"evm/serialization"
 
 	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
 
-// BLS12-381 base field modulus p
-// p = 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab
-var bls12_381_p, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
-
-// bls12_381_p_half = (p-1)/2
-var bls12_381_p_half = new(big.Int)
-
-func init() {
-	// Calculate (p-1)/2
-	bls12_381_p_half.Sub(bls12_381_p, big.NewInt(1))
-	bls12_381_p_half.Rsh(bls12_381_p_half, 1)
-}
-
-// isLexicographicallyLargestFp checks if an Fp element (48 bytes, big-endian) is lexicographically largest
-// This matches Neo C# Fp.LexicographicallyLargest() implementation
-// An element is lexicographically largest if it is greater than (p-1)/2
-// Note: yBytes is in big-endian format (as returned by gnark-crypto Marshal())
-// Neo uses constant 0xdcff_7fff_ffff_d556 which is (p-1)/2 + 1, and checks t >= constant
-// This means t > (p-1)/2, which is equivalent to y > (p-1)/2
-func isLexicographicallyLargestFp(yBytes []byte) bool {
-	if len(yBytes) != 48 {
-		return false
-	}
-	// gnark-crypto Marshal() returns big-endian format
-	// big.Int.SetBytes() interprets bytes as big-endian, so we can use directly
-	y := new(big.Int).SetBytes(yBytes)
-	// Compare with (p-1)/2
-	// Neo uses (p-1)/2 + 1 and checks t >= constant, which is equivalent to t > (p-1)/2
-	return y.Cmp(bls12_381_p_half) > 0
-}
-
-// isLexicographicallyLargestFp2 checks if an Fp2 element (96 bytes, big-endian) is lexicographically largest
-// This matches Neo C# Fp2.LexicographicallyLargest() implementation
-// An Fp2 element is lexicographically largest if:
-//   - C1 is lexicographically largest, OR
-//   - C1 is zero AND C0 is lexicographically largest
-//
-// Note: yBytes format from gnark-crypto G2 Marshal() is [y.C1 (48 bytes) + y.C0 (48 bytes)] in big-endian
-func isLexicographicallyLargestFp2(yBytes []byte) bool {
-	if len(yBytes) != 96 {
-		return false
-	}
-	// Extract C1 (first 48 bytes) and C0 (last 48 bytes)
-	// Format: [y.C1 (big-endian, 48 bytes) + y.C0 (big-endian, 48 bytes)]
-	c1Bytes := yBytes[0:48]
-	c0Bytes := yBytes[48:96]
-
-	// Check if C1 is lexicographically largest
-	c1IsLargest := isLexicographicallyLargestFp(c1Bytes)
-	if c1IsLargest {
-		return true
-	}
-
-	// Check if C1 is zero
-	c1IsZero := true
-	for _, b := range c1Bytes {
-		if b != 0 {
-			c1IsZero = false
-			break
-		}
-	}
-
-	// If C1 is zero, check if C0 is lexicographically largest
-	if c1IsZero {
-		return isLexicographicallyLargestFp(c0Bytes)
-	}
-
-	return false
-}
-
 // randomOnG1 generates a random G1 point (similar to RandomOnG2)
 func randomOnG1() (bls.G1Affine, error) {
 	g1GenJac, _, _, _ := bls.Generators()
-	var scalar fr.Element
-	if _, err := scalar.SetRandom(); err != nil {
+	scalar, err := randomFr()
+	if err != nil {
 		return bls.G1Affine{}, err
 	}
 	var g1Jac bls.G1Jac
@@ -97,243 +31,43 @@ func randomOnG1() (bls.G1Affine, error) {
 	return P, nil
 }
 
-// computeMultiExpFromEthereumFormat computes MultiExp result from Ethereum format (uncompressed) G1/G2 point and scalars
-// This function is convenient for using Neo's Ethereum test vectors directly
-// Parameters:
-//   - inputHex: Ethereum format input (for G1: 160 bytes = 128 bytes point + 32 bytes scalar per pair)
-//   - useG2: true for G2, false for G1
-//
-// Returns: Compressed result point in hex string
-func computeMultiExpFromEthereumFormat(inputHex string, useG2 bool) (string, error) {
-	inputHex = strings.TrimSpace(inputHex)
-	inputBytes, err := hex.DecodeString(inputHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse input hex: %v", err)
-	}
-
-	if useG2 {
-		// G2 format: 288 bytes per pair = 256 bytes point + 32 bytes scalar
-		if len(inputBytes)%288 != 0 {
-			return "", fmt.Errorf("G2 input length must be multiple of 288 bytes, got %d", len(inputBytes))
-		}
-
-		var points []bls.G2Affine
-		var scalars []*big.Int
-
-		for offset := 0; offset < len(inputBytes); offset += 288 {
-			pointBytes := inputBytes[offset : offset+256]
-			scalarBytes := inputBytes[offset+256 : offset+288]
-
-			// Parse G2 point from Ethereum format (256 bytes)
-			g2Point, err := parseEthereumG2PointFromBytes(pointBytes)
-			if err != nil {
-				return "", fmt.Errorf("failed to parse G2 point at offset %d: %v", offset, err)
-			}
-
-			scalar := parseEthereumScalarFromBytes(scalarBytes)
-			points = append(points, g2Point)
-			scalars = append(scalars, scalar)
-		}
-
-		// Compute MultiExp: point1 × scalar1 + point2 × scalar2 + ...
-		var resultJac bls.G2Jac
-		for i := 0; i < len(points); i++ {
-			var g2Jac bls.G2Jac
-			g2Jac.FromAffine(&points[i])
-			var tempJac bls.G2Jac
-			tempJac.ScalarMultiplication(&g2Jac, scalars[i])
-			if i == 0 {
-				resultJac.Set(&tempJac)
-			} else {
-				resultJac.AddAssign(&tempJac)
-			}
-		}
-		var resultAffine bls.G2Affine
-		resultAffine.FromJacobian(&resultJac)
-
-		resultCompressed := convertG2AffineToCompressed(resultAffine)
-		return hex.EncodeToString(resultCompressed), nil
-	} else {
-		// G1 format: 160 bytes per pair = 128 bytes point + 32 bytes scalar
-		if len(inputBytes)%160 != 0 {
-			return "", fmt.Errorf("G1 input length must be multiple of 160 bytes, got %d", len(inputBytes))
-		}
-
-		var points []bls.G1Affine
-		var scalars []*big.Int
-
-		for offset := 0; offset < len(inputBytes); offset += 160 {
-			pointBytes := inputBytes[offset : offset+128]
-			scalarBytes := inputBytes[offset+128 : offset+160]
-
-			// Parse G1 point from Ethereum format (128 bytes)
-			g1Point, err := parseEthereumG1PointFromBytes(pointBytes)
-			if err != nil {
-				return "", fmt.Errorf("failed to parse G1 point at offset %d: %v", offset, err)
-			}
-
-			scalar := parseEthereumScalarFromBytes(scalarBytes)
-			points = append(points, g1Point)
-			scalars = append(scalars, scalar)
-		}
-
-		// Compute MultiExp: point1 × scalar1 + point2 × scalar2 + ...
-		var resultJac bls.G1Jac
-		for i := 0; i < len(points); i++ {
-			var g1Jac bls.G1Jac
-			g1Jac.FromAffine(&points[i])
-			var tempJac bls.G1Jac
-			tempJac.ScalarMultiplication(&g1Jac, scalars[i])
-			if i == 0 {
-				resultJac.Set(&tempJac)
-			} else {
-				resultJac.AddAssign(&tempJac)
-			}
-		}
-		var resultAffine bls.G1Affine
-		resultAffine.FromJacobian(&resultJac)
-
-		resultCompressed := convertG1AffineToCompressed(resultAffine)
-		return hex.EncodeToString(resultCompressed), nil
-	}
-}
-
-// computeMultiExpFromCompressed computes MultiExp result from compressed G1/G2 point and scalars
-// This function uses gnark-crypto API directly, independent of C# implementation logic
-// Parameters:
-//   - pointHex: Compressed G1 (96 hex chars) or G2 (192 hex chars) point in hex string
-//   - scalars: Array of scalar values (BigInteger values)
-//   - useG2: true for G2, false for G1
-//
-// Returns: Compressed result point in hex string
-func computeMultiExpFromCompressed(pointHex string, scalars []*big.Int, useG2 bool) (string, error) {
-	// Parse hex string to bytes
-	pointHex = strings.TrimSpace(pointHex)
-	pointBytes, err := hex.DecodeString(pointHex)
+// randomOnG2 generates a random G2 point. Unlike bls.RandomOnG2 it draws from vectorRand,
+// so --seed covers it.
+func randomOnG2() (bls.G2Affine, error) {
+	_, g2GenJac, _, _ := bls.Generators()
+	scalar, err := randomFr()
 	if err != nil {
-		return "", fmt.Errorf("failed to parse point hex: %v", err)
-	}
-
-	if useG2 {
-		// G2 MultiExp
-		if len(pointBytes) != 96 {
-			return "", fmt.Errorf("G2 point must be 96 bytes (compressed), got %d", len(pointBytes))
-		}
-
-		// Deserialize compressed G2 point
-		var g2Affine bls.G2Affine
-		if _, err := g2Affine.SetBytes(pointBytes); err != nil {
-			return "", fmt.Errorf("failed to deserialize G2 point: %v", err)
-		}
-
-		// Convert to Jacobian for efficient operations
-		var g2Jac bls.G2Jac
-		g2Jac.FromAffine(&g2Affine)
-
-		// Compute MultiExp: point × scalar₁ + point × scalar₂ + ... = point × (scalar₁ + scalar₂ + ...)
-		// For proper MultiExp, we should compute: point₁ × scalar₁ + point₂ × scalar₂ + ...
-		// But if all points are the same, we can optimize: point × (scalar₁ + scalar₂ + ...)
-		// However, for comparison purposes, we'll compute each multiplication separately and add them
-		var resultG2Jac bls.G2Jac
-		resultG2Jac.Set(&g2Jac)
-		resultG2Jac.ScalarMultiplication(&g2Jac, scalars[0])
-
-		// Add remaining point × scalar pairs
-		for i := 1; i < len(scalars); i++ {
-			var tempG2Jac bls.G2Jac
-			tempG2Jac.ScalarMultiplication(&g2Jac, scalars[i])
-			resultG2Jac.AddAssign(&tempG2Jac)
-		}
-
-		// Convert back to Affine
-		var resultG2 bls.G2Affine
-		resultG2.FromJacobian(&resultG2Jac)
-
-		// Serialize to compressed format
-		g2ResultUncompressed := resultG2.Marshal()
-		if len(g2ResultUncompressed) != 192 {
-			return "", fmt.Errorf("unexpected G2 uncompressed length: %d", len(g2ResultUncompressed))
-		}
-
-		// Use the helper function to ensure correct format
-		g2ResultCompressed := convertG2AffineToCompressed(resultG2)
-		return fmt.Sprintf("%x", g2ResultCompressed), nil
-	} else {
-		// G1 MultiExp
-		if len(pointBytes) != 48 {
-			return "", fmt.Errorf("G1 point must be 48 bytes (compressed), got %d", len(pointBytes))
-		}
-
-		// Deserialize compressed G1 point
-		var g1Affine bls.G1Affine
-		if _, err := g1Affine.SetBytes(pointBytes); err != nil {
-			return "", fmt.Errorf("failed to deserialize G1 point: %v", err)
-		}
-
-		// Convert to Jacobian for efficient operations
-		var g1Jac bls.G1Jac
-		g1Jac.FromAffine(&g1Affine)
-
-		// Compute MultiExp: point × scalar₁ + point × scalar₂ + ... = point × (scalar₁ + scalar₂ + ...)
-		// For proper MultiExp, we should compute: point₁ × scalar₁ + point₂ × scalar₂ + ...
-		// But if all points are the same, we can optimize: point × (scalar₁ + scalar₂ + ...)
-		// However, for comparison purposes, we'll compute each multiplication separately and add them
-		var resultG1Jac bls.G1Jac
-		resultG1Jac.Set(&g1Jac)
-		resultG1Jac.ScalarMultiplication(&g1Jac, scalars[0])
-
-		// Add remaining point × scalar pairs
-		for i := 1; i < len(scalars); i++ {
-			var tempG1Jac bls.G1Jac
-			tempG1Jac.ScalarMultiplication(&g1Jac, scalars[i])
-			resultG1Jac.AddAssign(&tempG1Jac)
-		}
-
-		// Convert back to Affine
-		var resultG1 bls.G1Affine
-		resultG1.FromJacobian(&resultG1Jac)
-
-		// Serialize to compressed format
-		g1ResultUncompressed := resultG1.Marshal()
-		if len(g1ResultUncompressed) != 96 {
-			return "", fmt.Errorf("unexpected G1 uncompressed length: %d", len(g1ResultUncompressed))
-		}
-
-		// Convert to compressed format (48 bytes)
-		g1ResultCompressed := make([]byte, 48)
-		copy(g1ResultCompressed, g1ResultUncompressed[:48]) // Extract x coordinate
-		g1ResultCompressed[0] |= 0x80                       // Set compression flag
-
-		// Set y coordinate sort flag using lexicographically largest check
-		yBytes := g1ResultUncompressed[48:96]
-		if isLexicographicallyLargestFp(yBytes) {
-			g1ResultCompressed[0] |= 0x20
-		}
-
-		return fmt.Sprintf("%x", g1ResultCompressed), nil
+		return bls.G2Affine{}, err
 	}
+	var g2Jac bls.G2Jac
+	g2Jac.ScalarMultiplication(&g2GenJac, scalar.BigInt(new(big.Int)))
+	var Q bls.G2Affine
+	Q.FromJacobian(&g2Jac)
+	return Q, nil
 }
 
 // runRandomMode runs the random generation mode
 // This generates random G1/G2 points and scalars, then computes MultiExp
 // useG2: true for G2, false for G1
-func runRandomMode(maxScalars int, useG2 bool) {
+// Returns the generated vector as a fixture so it can be emitted for other languages, or
+// an error when generation fails or the fixture diverges from gnark's compression
+func runRandomMode(name string, maxScalars int, useG2 bool, scalarRange scalarRange, gt *gtFormatOptions) (multiExpFixture, error) {
 	// Generate random G1 point
 	P, err := randomOnG1()
 	if err != nil {
-		panic(fmt.Sprintf("failed to generate random G1 point: %v", err))
+		return multiExpFixture{}, fmt.Errorf("failed to generate random G1 point: %v", err)
 	}
 
 	// Generate random G2 point
-	Q, err := bls.RandomOnG2()
+	Q, err := randomOnG2()
 	if err != nil {
-		panic(fmt.Sprintf("failed to generate random G2 point: %v", err))
+		return multiExpFixture{}, fmt.Errorf("failed to generate random G2 point: %v", err)
 	}
 
 	// Compute pairing: Pair function accepts slices
 	result, err := bls.Pair([]bls.G1Affine{P}, []bls.G2Affine{Q})
 	if err != nil {
-		panic(fmt.Sprintf("pairing failed: %v", err))
+		return multiExpFixture{}, fmt.Errorf("pairing failed: %v", err)
 	}
 
 	// gnark-crypto's Marshal() method:
@@ -364,7 +98,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 
 		// Extract y coordinate to determine sort flag using lexicographically largest check
 		yBytes := g1Uncompressed[48:96]
-		if isLexicographicallyLargestFp(yBytes) {
+		if serialization.IsLexicographicallyLargestFp(yBytes) {
 			g1Compressed[0] |= 0x20 // Set sort flag
 		}
 
@@ -380,7 +114,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 	g2Uncompressed := Q.Marshal()
 	if len(g2Uncompressed) == 192 {
 		// Use the helper function to ensure correct format
-		g2Compressed := convertG2AffineToCompressed(Q)
+		g2Compressed := serialization.ConvertG2AffineToCompressed(Q)
 
 		fmt.Printf("G2 (compressed, 96 bytes): %x\n", g2Compressed)
 		fmt.Printf("G2 (uncompressed, 192 bytes): %x\n", g2Uncompressed)
@@ -388,7 +122,11 @@ func runRandomMode(maxScalars int, useG2 bool) {
 		fmt.Printf("G2: %x\n", g2Uncompressed)
 	}
 
-	fmt.Printf("Pairing result: %x\n", result.Marshal())
+	if gt.explicit() {
+		gt.print("Pairing result", &result)
+	} else {
+		fmt.Printf("Pairing result: %x\n", result.Marshal())
+	}
 
 	// ============================================
 	// Compute MultiExp result for comparison with Neo invokescript result
@@ -401,9 +139,9 @@ func runRandomMode(maxScalars int, useG2 bool) {
 
 	// Randomly generate number of scalars between 1 and maxScalars
 	// Use fr.Element to generate random number count
-	var countScalar fr.Element
-	if _, err := countScalar.SetRandom(); err != nil {
-		panic(fmt.Sprintf("failed to generate random count: %v", err))
+	countScalar, err := randomFr()
+	if err != nil {
+		return multiExpFixture{}, fmt.Errorf("failed to generate random count: %v", err)
 	}
 	// Convert fr.Element to big.Int and use Mod to get value in range [0, maxScalars-minScalars]
 	countBig := countScalar.BigInt(new(big.Int))
@@ -430,14 +168,14 @@ func runRandomMode(maxScalars int, useG2 bool) {
 		numPoints = 1
 	}
 
-	// C# int.MaxValue = 2,147,483,647 = 2^31 - 1
-	// Limit scalar values to [0, int.MaxValue] for C# compatibility
-	csharpIntMaxValue := big.NewInt(2147483647) // int.MaxValue
-
 	fmt.Println("\n=== Generating Random Scalars (BLS12-381 Standard) ===")
 	fmt.Printf("Max scalars limit: %d\n", maxScalars)
 	fmt.Printf("Number of scalars: %d (randomly generated in range: %d-%d)\n", numScalars, minScalars, maxScalars)
-	fmt.Printf("Scalar value range: [0, %s] (limited to C# int.MaxValue for compatibility)\n", csharpIntMaxValue.String())
+	if scalarRange.name == "csharp-int" {
+		fmt.Printf("Scalar value range: %s, limited to C# int.MaxValue for compatibility\n", scalarRange.describe())
+	} else {
+		fmt.Printf("Scalar value range: %s\n", scalarRange.describe())
+	}
 	fmt.Println("Using gnark-crypto fr.Element for standard-compliant generation")
 
 	if useMultiplePoints {
@@ -460,52 +198,40 @@ func runRandomMode(maxScalars int, useG2 bool) {
 			// Generate additional random points
 			newP, err := randomOnG1()
 			if err != nil {
-				panic(fmt.Sprintf("failed to generate random G1 point %d: %v", i, err))
+				return multiExpFixture{}, fmt.Errorf("failed to generate random G1 point %d: %v", i, err)
 			}
 			g1Points[i] = newP
 
-			newQ, err := bls.RandomOnG2()
+			newQ, err := randomOnG2()
 			if err != nil {
-				panic(fmt.Sprintf("failed to generate random G2 point %d: %v", i, err))
+				return multiExpFixture{}, fmt.Errorf("failed to generate random G2 point %d: %v", i, err)
 			}
 			g2Points[i] = newQ
 		}
 	}
 
-	for i := 0; i < numScalars; i++ {
-		// Use gnark-crypto's fr.Element to generate standard-compliant random scalar
-		var scalar fr.Element
-		if _, err := scalar.SetRandom(); err != nil {
-			panic(fmt.Sprintf("failed to generate random scalar: %v", err))
+	// Use gnark-crypto's fr.Element to generate standard-compliant random scalars, reduced
+	// into the --scalar-range interval
+	sample := func(bound *big.Int) (*big.Int, error) {
+		scalar, err := randomFr()
+		if err != nil {
+			return nil, err
 		}
-		// Convert fr.Element to big.Int
-		scalarBig := scalar.BigInt(new(big.Int))
-
-		// Limit scalar value to [0, int.MaxValue] for C# compatibility
-		// Use Mod to get value in range [0, int.MaxValue]
-		// Mod divisor = int.MaxValue + 1 to get range [0, int.MaxValue]
-		modDivisor := new(big.Int).Add(csharpIntMaxValue, big.NewInt(1))
-		scalarBig.Mod(scalarBig, modDivisor)
-
-		// Ensure scalar is not zero, as MultiExp skips zero scalars
-		if scalarBig.Sign() == 0 {
-			scalarBig.SetInt64(1) // Set to 1 if it's zero
+		v := scalar.BigInt(new(big.Int))
+		return v.Mod(v, bound), nil
+	}
+	for i := 0; i < numScalars; i++ {
+		scalarBig, err := scalarRange.draw(i, sample)
+		if err != nil {
+			return multiExpFixture{}, fmt.Errorf("failed to generate random scalar: %v", err)
 		}
-
 		scalars[i] = scalarBig
 		fmt.Printf("Scalar[%d]: %s\n", i, scalars[i].String())
 	}
 
 	// Output scalars in C# array format for easy copy-paste
 	fmt.Println("\n=== C# Array Format (copy to Bls12381MultiExpHelper.cs) ===")
-	fmt.Print("private static readonly BigInteger[] SCALARS = new BigInteger[] { ")
-	for i, s := range scalars {
-		if i > 0 {
-			fmt.Print(", ")
-		}
-		fmt.Print(s.String())
-	}
-	fmt.Println(" };")
+	fmt.Printf("private static readonly BigInteger[] SCALARS = new BigInteger[] { %s };\n", strings.Join(csharpScalarLiterals(scalars), ", "))
 
 	// Output points in C# array format
 	if useMultiplePoints {
@@ -519,7 +245,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 				copy(g1Compressed, g1Uncompressed[:48])
 				g1Compressed[0] |= 0x80
 				yBytes := g1Uncompressed[48:96]
-				if isLexicographicallyLargestFp(yBytes) {
+				if serialization.IsLexicographicallyLargestFp(yBytes) {
 					g1Compressed[0] |= 0x20
 				}
 				fmt.Printf("    \"%x\"%s  // Point[%d], will be used with Scalar[%d] = %s\n", g1Compressed, func() string {
@@ -539,7 +265,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 			g2Uncompressed := q.Marshal()
 			if len(g2Uncompressed) == 192 {
 				// Use the helper function to ensure correct format
-				g2Compressed := convertG2AffineToCompressed(q)
+				g2Compressed := serialization.ConvertG2AffineToCompressed(q)
 				fmt.Printf("    \"%x\"%s  // Point[%d], will be used with Scalar[%d] = %s\n", g2Compressed, func() string {
 					if i < len(g2Points)-1 {
 						return ","
@@ -563,7 +289,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 	}())
 	if useG2 {
 		// G2 MultiExp: point1 × scalar1 + point2 × scalar2 + ...
-		var resultG2Jac bls.G2Jac
+		pairPoints := make([]bls.G2Affine, len(scalars))
 		fmt.Printf("\n=== G2 MultiExp Calculation Details ===\n")
 		for i := 0; i < len(scalars); i++ {
 			pointIdx := i
@@ -574,33 +300,26 @@ func runRandomMode(maxScalars int, useG2 bool) {
 			}
 
 			// Output point and scalar for this iteration
-			g2PointCompressed := convertG2AffineToCompressed(g2Points[pointIdx])
+			g2PointCompressed := serialization.ConvertG2AffineToCompressed(g2Points[pointIdx])
 			fmt.Printf("  Pair[%d]: point[%d] = %x, scalar = %s\n", i, pointIdx, g2PointCompressed, scalars[i].String())
-
-			var g2Jac bls.G2Jac
-			g2Jac.FromAffine(&g2Points[pointIdx])
-			var tempJac bls.G2Jac
-			tempJac.ScalarMultiplication(&g2Jac, scalars[i])
-			if i == 0 {
-				resultG2Jac.Set(&tempJac)
-			} else {
-				resultG2Jac.AddAssign(&tempJac)
-			}
+			pairPoints[i] = g2Points[pointIdx]
+		}
+		resultG2, err := bls12381vec.MultiExpG2(pairPoints, scalars)
+		if err != nil {
+			return multiExpFixture{}, err
 		}
-		var resultG2 bls.G2Affine
-		resultG2.FromJacobian(&resultG2Jac)
 
 		// Serialize G2 result
 		g2ResultUncompressed := resultG2.Marshal()
 		if len(g2ResultUncompressed) == 192 {
 			// Use the helper function to ensure correct format
-			g2ResultCompressed := convertG2AffineToCompressed(resultG2)
+			g2ResultCompressed := serialization.ConvertG2AffineToCompressed(resultG2)
 			fmt.Printf("G2 MultiExp result (compressed, 96 bytes): %x\n", g2ResultCompressed)
 			fmt.Printf("Expected result (for comparison with Neo invokescript): %x\n", g2ResultCompressed)
 		}
 	} else {
 		// G1 MultiExp: point1 × scalar1 + point2 × scalar2 + ...
-		var resultG1Jac bls.G1Jac
+		pairPoints := make([]bls.G1Affine, len(scalars))
 		fmt.Printf("\n=== G1 MultiExp Calculation Details ===\n")
 		for i := 0; i < len(scalars); i++ {
 			pointIdx := i
@@ -611,21 +330,14 @@ func runRandomMode(maxScalars int, useG2 bool) {
 			}
 
 			// Output point and scalar for this iteration
-			g1PointCompressed := convertG1AffineToCompressed(g1Points[pointIdx])
+			g1PointCompressed := serialization.ConvertG1AffineToCompressed(g1Points[pointIdx])
 			fmt.Printf("  Pair[%d]: point[%d] = %x, scalar = %s\n", i, pointIdx, g1PointCompressed, scalars[i].String())
-
-			var g1Jac bls.G1Jac
-			g1Jac.FromAffine(&g1Points[pointIdx])
-			var tempJac bls.G1Jac
-			tempJac.ScalarMultiplication(&g1Jac, scalars[i])
-			if i == 0 {
-				resultG1Jac.Set(&tempJac)
-			} else {
-				resultG1Jac.AddAssign(&tempJac)
-			}
+			pairPoints[i] = g1Points[pointIdx]
+		}
+		resultG1, err := bls12381vec.MultiExpG1(pairPoints, scalars)
+		if err != nil {
+			return multiExpFixture{}, err
 		}
-		var resultG1 bls.G1Affine
-		resultG1.FromJacobian(&resultG1Jac)
 
 		// Serialize G1 result
 		g1ResultUncompressed := resultG1.Marshal()
@@ -634,7 +346,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 			copy(g1ResultCompressed, g1ResultUncompressed[:48])
 			g1ResultCompressed[0] |= 0x80
 			yBytes := g1ResultUncompressed[48:96]
-			if isLexicographicallyLargestFp(yBytes) {
+			if serialization.IsLexicographicallyLargestFp(yBytes) {
 				g1ResultCompressed[0] |= 0x20
 			}
 			fmt.Printf("G1 MultiExp result (compressed, 48 bytes): %x\n", g1ResultCompressed)
@@ -642,6 +354,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 		}
 	}
 
+	return newMultiExpFixture(name, g1Points, g2Points, scalars, useG2)
 }
 
 // runEthereumMode runs the Ethereum format calculation mode
@@ -649,7 +362,7 @@ func runRandomMode(maxScalars int, useG2 bool) {
 // Input format: For G1, 160 bytes per pair (128 bytes point + 32 bytes scalar)
 //
 //	For G2, 288 bytes per pair (256 bytes point + 32 bytes scalar)
-func runEthereumMode(inputHex string, useG2 bool) error {
+func runEthereumMode(inputHex string, useG2 bool, verbose bool) error {
 	inputHex = strings.TrimSpace(inputHex)
 	if inputHex == "" {
 		return fmt.Errorf("input hex is required")
@@ -662,10 +375,11 @@ func runEthereumMode(inputHex string, useG2 bool) error {
 		fmt.Printf("Expected format: 160 bytes per pair (128 bytes G1 point + 32 bytes scalar)\n")
 	}
 	fmt.Printf("Input hex length: %d characters\n", len(inputHex))
+	checkInputSanity(groupPrefix(useG2)+"msm", "ethereum", inputHex, scalarReduce)
 
 	// Compute MultiExp using Ethereum format
 	fmt.Println("\n=== Computing MultiExp using Ethereum format ===")
-	result, err := computeMultiExpFromEthereumFormat(inputHex, useG2)
+	result, err := bls12381vec.MultiExpFromEthereumFormat(inputHex, useG2)
 	if err != nil {
 		return fmt.Errorf("failed to compute MultiExp: %v", err)
 	}
@@ -676,6 +390,41 @@ func runEthereumMode(inputHex string, useG2 bool) error {
 	}
 	fmt.Printf("MultiExp result (compressed, %d hex chars): %s\n", expectedLength, result)
 	fmt.Println("This result can be compared with Neo invokescript output")
+	recordVerdictResult(result)
+	reportOutput("result_compressed", result)
+	if report != nil {
+		if uncompressedHex, err := bls12381vec.CompressedToUncompressedHex(result, useG2); err == nil {
+			reportOutput("result_uncompressed", uncompressedHex)
+		}
+	}
+	// The precompile returns the padded point; g1msm/g2msm print it with every EIP-2537 check
+	compressed, err := hex.DecodeString(result)
+	if err != nil {
+		return fmt.Errorf("failed to decode MultiExp result: %v", err)
+	}
+	kind, op := "g1c", "g1msm"
+	if useG2 {
+		kind, op = "g2c", "g2msm"
+	}
+	if g1, g2, err := decodePointKind(kind, compressed, true); err == nil {
+		ethereumHex := hex.EncodeToString(encodePointFormat("ethereum", useG2, g1, g2))
+		fmt.Printf("Precompile result (Ethereum format, %d hex chars): %s\n", len(ethereumHex), ethereumHex)
+		fmt.Printf("For byte comparison with geth/Nethermind use '%s --input <hex>' (with --quiet it prints only this value)\n", op)
+		reportOutput("result_ethereum", ethereumHex)
+	}
+
+	if verbose {
+		steps, err := traceMultiExpFromEthereumFormat(inputHex, useG2)
+		if err != nil {
+			return err
+		}
+		if err := printMultiExpTrace(steps); err != nil {
+			return err
+		}
+		if pairs := scalarsAtLeastR(steps); len(pairs) > 0 {
+			fmt.Printf("Note: scalars of pairs %v are >= r and are reduced mod r by the MultiExp\n", pairs)
+		}
+	}
 
 	return nil
 }
@@ -711,8 +460,8 @@ func runManualMode(g1Hex, g2Hex string, scalarsStr string, useG2 bool) error {
 		// If scalarsStr doesn't contain comma but user likely intended multiple scalars,
 		// we can't detect it here, but we can at least show the count
 		if !strings.Contains(scalarsStr, ",") {
-			fmt.Fprintf(os.Stderr, "Note: Only 1 scalar provided. If you intended multiple scalars, wrap them in quotes:\n")
-			fmt.Fprintf(os.Stderr, "  --scalars \"val1,val2,val3\" (with quotes)\n")
+			logging.Printf("Note: Only 1 scalar provided. If you intended multiple scalars, wrap them in quotes:")
+			logging.Printf("  --scalars \"val1,val2,val3\" (with quotes)")
 		}
 	}
 
@@ -733,10 +482,14 @@ func runManualMode(g1Hex, g2Hex string, scalarsStr string, useG2 bool) error {
 	}
 
 	fmt.Printf("Using scalars (%d total): %v\n", len(scalars), scalars)
+	checkPointSanity("--"+groupPrefix(useG2), pointHex, useG2)
+	checkScalarsSanity("manual", scalars)
+	reportInput("point", pointHex)
+	reportInput("scalars", scalarStrings(scalars))
 
-	// Compute MultiExp using computeMultiExpFromCompressed function
+	// Compute MultiExp using bls12381vec.MultiExpFromCompressed function
 	fmt.Println("\n=== Computing MultiExp using gnark-crypto API ===")
-	result, err := computeMultiExpFromCompressed(pointHex, scalars, useG2)
+	result, err := bls12381vec.MultiExpFromCompressed(pointHex, scalars, useG2)
 	if err != nil {
 		return fmt.Errorf("failed to compute MultiExp: %v", err)
 	}
@@ -746,15 +499,18 @@ func runManualMode(g1Hex, g2Hex string, scalarsStr string, useG2 bool) error {
 		expectedLength = 192
 	}
 	fmt.Printf("MultiExp result (compressed, %d hex chars): %s\n", expectedLength, result)
+	recordVerdictResult(result)
+	reportOutput("result_compressed", result)
 
-	if uncompressedHex, err := compressedToUncompressedHex(result, useG2); err == nil {
+	if uncompressedHex, err := bls12381vec.CompressedToUncompressedHex(result, useG2); err == nil {
 		uncompressedBytes := 96
 		if useG2 {
 			uncompressedBytes = 192
 		}
 		fmt.Printf("MultiExp result (uncompressed, %d bytes = %d hex chars): %s\n", uncompressedBytes, uncompressedBytes*2, uncompressedHex)
+		reportOutput("result_uncompressed", uncompressedHex)
 	} else {
-		fmt.Fprintf(os.Stderr, "Warning: unable to decode uncompressed result: %v\n", err)
+		logging.Printf("Warning: unable to decode uncompressed result: %v", err)
 	}
 	fmt.Println("This result can be compared with Neo invokescript output")
 
@@ -763,14 +519,35 @@ func runManualMode(g1Hex, g2Hex string, scalarsStr string, useG2 bool) error {
 
 func printUsage() {
 	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  Help for one command (synopsis and flags):\n")
+	fmt.Fprintf(os.Stderr, "    go run . help <command>\n")
+	fmt.Fprintf(os.Stderr, "    go run . <command> --help\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Global flags (before the command):\n")
+	fmt.Fprintf(os.Stderr, "    --format text|json   # json: one JSON document (inputs, outputs, fixtures, flags, output lines)\n")
+	fmt.Fprintf(os.Stderr, "    --msm-cross-check    # also run every MSM with the naive pair-by-pair loop and fail on a mismatch\n")
+	fmt.Fprintf(os.Stderr, "    --hex-style <opts>   # hex printed on stdout (fixture files stay plain): lower|upper, 0x, space|underscore, group=N, none (comma-separated)\n")
+	fmt.Fprintf(os.Stderr, "    --sink <sink>        # where --emit files and campaign manifests go: dir (default), stdout, file:<path.jsonl>, http(s)://<collector>\n")
+	fmt.Fprintf(os.Stderr, "    --quiet | --verbose | --debug # stderr diagnostics: errors only and results-only stdout, or also progress, or also byte dumps\n")
+	fmt.Fprintf(os.Stderr, "    --strict | --lenient # Ethereum G2 points with non-zero padding: rejected as EIP-2537 requires (--strict, default) or recovered heuristically (--lenient)\n")
+	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  Random mode (default):\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go [max_scalars]\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go random [max_scalars]\n")
+	fmt.Fprintf(os.Stderr, "    go run . [max_scalars]\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars]\n")
 	fmt.Fprintf(os.Stderr, "      - max_scalars: Maximum number of scalars (default: 128)\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars] --seed <hex>   # Reproducible run (also pairing-random, g2add-random)\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars] --emit all [--emit-dir <dir>]\n")
+	fmt.Fprintf(os.Stderr, "      - --emit: Also write fixtures (csharp, go, go-bytes, rust, solidity, python, neo-alias, neo-debugger, gotest, all; comma-separated)\n")
+	fmt.Fprintf(os.Stderr, "      - --emit-dir: Output directory for fixtures (default: fixtures)\n")
+	fmt.Fprintf(os.Stderr, "      - --template: C# template of --emit csharp: snippet (default), class or a text/template file\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars] --count N   # N independent vectors (emitted to <emit-dir>/random-NNNN)\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars] --seed <hex> --fixture i   # Only vector i of the seeded batch (its Reproduce line)\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars] --count N --seed <hex> --out-dir <dir>   # One JSON file per vector plus index.json\n")
+	fmt.Fprintf(os.Stderr, "    go run . random [max_scalars] --scalar-range full-fr[,include-zero][,boundaries]   # Also 32-bit, 64-bit, bits:<n>\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  Manual mode (compressed format):\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go manual --g1 <hex> --scalars \"<scalar1,scalar2,...>\"\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go manual --g2 <hex> --scalars \"<scalar1,scalar2,...>\" --use-g2\n")
+	fmt.Fprintf(os.Stderr, "    go run . manual --g1 <hex> --scalars \"<scalar1,scalar2,...>\"\n")
+	fmt.Fprintf(os.Stderr, "    go run . manual --g2 <hex> --scalars \"<scalar1,scalar2,...>\" --use-g2\n")
 	fmt.Fprintf(os.Stderr, "      - --g1: Compressed G1 point (96 hex chars, 48 bytes)\n")
 	fmt.Fprintf(os.Stderr, "      - --g2: Compressed G2 point (192 hex chars, 96 bytes)\n")
 	fmt.Fprintf(os.Stderr, "      - --scalars: Comma-separated list of scalar values (MUST be wrapped in quotes)\n")
@@ -778,285 +555,281 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "      Note: Always wrap --scalars value in quotes, e.g., --scalars \"123,456,789\"\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  Ethereum mode (uncompressed format, for Neo test vectors):\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go ethereum --input <hex> [--use-g2]\n")
+	fmt.Fprintf(os.Stderr, "    go run . ethereum --input <hex> [--use-g2] [--verbose]\n")
 	fmt.Fprintf(os.Stderr, "      - --input: Ethereum format input hex string\n")
 	fmt.Fprintf(os.Stderr, "        For G1: 160 bytes per pair (128 bytes point + 32 bytes scalar)\n")
 	fmt.Fprintf(os.Stderr, "        For G2: 288 bytes per pair (256 bytes point + 32 bytes scalar)\n")
 	fmt.Fprintf(os.Stderr, "      - --use-g2: Use G2 format (default: false, uses G1)\n")
-	fmt.Fprintf(os.Stderr, "      Example: go run pairing_gen.go ethereum --input <EthG1MultiExpSingleInputHex>\n")
+	fmt.Fprintf(os.Stderr, "      - --verbose: Per-pair breakdown as JSON lines prefixed with PAIR\n")
+	fmt.Fprintf(os.Stderr, "      Example: go run . ethereum --input <EthG1MultiExpSingleInputHex>\n")
+	fmt.Fprintf(os.Stderr, "      Every hex --input also takes --input-file <path> or - (stdin); whitespace and newlines are stripped:\n")
+	fmt.Fprintf(os.Stderr, "        go run . g1msm --input-file msm.hex\n")
+	fmt.Fprintf(os.Stderr, "        cat pairing.hex | go run . pairing --input -\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  G1/G2 Add/Mul operations (Ethereum format):\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go g1add --input <hex>\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go g2add --input <hex>\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go g1mul --input <hex>\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go g2mul --input <hex>\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go g2add-random  # Random G2 addition test\n")
+	fmt.Fprintf(os.Stderr, "    go run . g1add --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . g2add --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . g1mul --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . g2mul --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . g2add-random [--seed <hex>]  # Random G2 addition test\n")
 	fmt.Fprintf(os.Stderr, "      - --input: Ethereum format input hex string\n")
 	fmt.Fprintf(os.Stderr, "        g1add: 256 bytes (128 bytes point1 + 128 bytes point2)\n")
 	fmt.Fprintf(os.Stderr, "        g2add: 512 bytes (256 bytes point1 + 256 bytes point2)\n")
 	fmt.Fprintf(os.Stderr, "        g1mul: 160 bytes (128 bytes point + 32 bytes scalar)\n")
 	fmt.Fprintf(os.Stderr, "        g2mul: 288 bytes (256 bytes point + 32 bytes scalar)\n")
+	fmt.Fprintf(os.Stderr, "      - --trace (g1mul/g2mul): Print every double-and-add step (affine and Jacobian) and the GLV decomposition\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  G1/G2 negation, doubling and subtraction (compressed, uncompressed or Ethereum input):\n")
+	fmt.Fprintf(os.Stderr, "    go run . g1neg --point <hex>      # also g2neg\n")
+	fmt.Fprintf(os.Stderr, "    go run . g1double --point <hex>   # also g2double\n")
+	fmt.Fprintf(os.Stderr, "    go run . g1sub --a <hex> --b <hex> # also g2sub\n")
+	fmt.Fprintf(os.Stderr, "      - --format: auto (default, by length and flags), compressed, uncompressed or ethereum\n")
+	fmt.Fprintf(os.Stderr, "      - --no-subgroup-check: Accept on-curve points outside the prime-order subgroup\n")
+	fmt.Fprintf(os.Stderr, "      - Prints the result compressed and in Ethereum format, and the g1add/g2add input with the same meaning\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  G1/G2 MSM operations (exact EIP-2537 semantics, Ethereum format output):\n")
+	fmt.Fprintf(os.Stderr, "    go run . g1msm --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . g2msm --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "      - g1msm: k * 160 bytes (128 bytes point + 32 bytes scalar), k >= 1\n")
+	fmt.Fprintf(os.Stderr, "      - g2msm: k * 288 bytes (256 bytes point + 32 bytes scalar), k >= 1\n")
+	fmt.Fprintf(os.Stderr, "      - --input-format compressed (g1msm/g2msm): 48/96-byte compressed point + 32-byte scalar per pair; auto detects\n")
+	fmt.Fprintf(os.Stderr, "      - Every point is subgroup-checked, scalars are reduced mod r, empty input is rejected\n")
+	fmt.Fprintf(os.Stderr, "      - --profile: Empty-input semantics (eip2537 default, neo, gnark); use --input \"\" for empty input\n")
+	fmt.Fprintf(os.Stderr, "      - --empty: Override empty-input result: error or identity\n")
+	fmt.Fprintf(os.Stderr, "      - --timing: Print the execution time; --slow-threshold (default 100ms) flags slow results\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Chain profiles (g1add, g2add, g1mul, g2mul, g1msm, g2msm, pairing accept --chain):\n")
+	fmt.Fprintf(os.Stderr, "    go run . chain-profiles [--chain neo-n3|neox|eth-mainnet]\n")
+	fmt.Fprintf(os.Stderr, "      - --chain sets --profile and --input-format (explicit flags still win), the scalar\n")
+	fmt.Fprintf(os.Stderr, "        policy, add-operand subgroup checks, and prints the operation's gas\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  EIP-2537 gas of an input (MSM discount by pair count included):\n")
+	fmt.Fprintf(os.Stderr, "    go run . gas --op <g1add|g1mul|g1msm|g2add|g2mul|g2msm|pairing|map-fp-to-g1|map-fp2-to-g2> (--input <hex> | --pairs k | --table)\n")
+	fmt.Fprintf(os.Stderr, "      - Without --chain, the JSON output of the operations carries the same value under \"gas\"\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Empty-input vectors per chain profile:\n")
+	fmt.Fprintf(os.Stderr, "    go run . empty-input-vectors [--profile eip2537|neo|gnark]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Infinity, zero-scalar and r-boundary edge cases with expected results:\n")
+	fmt.Fprintf(os.Stderr, "    go run . edge-cases [--op add,mul,msm,pairing|all] [--group g1|g2|both] [--chain <c>] [--verbose] [--out cases.json]\n")
+	fmt.Fprintf(os.Stderr, "      - Scalars 0, 1, r-1, r, r+1, 2^256-1 on G and infinity, single- and two-pair MSMs, pairings with infinity\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Validation-order conformance vectors (inputs with several defects):\n")
+	fmt.Fprintf(os.Stderr, "    go run . validation-order [--profile eip2537|neo] [--out vectors.json]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  GT equality (576-byte elements, gnark or Neo encoding):\n")
+	fmt.Fprintf(os.Stderr, "    go run . gt-equal --a <hex> --b <hex> [--format auto|gnark|neo]\n")
+	fmt.Fprintf(os.Stderr, "    go run . gt-equal-vectors [--format neo|gnark|both]\n")
+	fmt.Fprintf(os.Stderr, "      - GT output (also random, pairing-random, pairing-gt, miller-loop, final-exp, gtmul, gtexp, gtinv): --gt-format gnark,neo,coeffs,decimal|all [--gt-order tower|reverse]\n")
+	fmt.Fprintf(os.Stderr, "    go run . pairing-gt --input <hex> [--input-format ethereum|compressed|auto]   # The pairing product itself (576 bytes)\n")
+	fmt.Fprintf(os.Stderr, "    go run . miller-loop --input <hex> [--input-format ethereum|compressed|auto]  # The Miller loop alone (Fp12)\n")
+	fmt.Fprintf(os.Stderr, "    go run . final-exp --a <576-byte hex> [--format neo|gnark]   # The final exponentiation alone, with its easy part\n")
+	fmt.Fprintf(os.Stderr, "    go run . gtmul --a <hex> --b <hex> | gtexp --a <hex> --scalar <k> | gtinv --a <hex>   # GT arithmetic (576-byte elements)\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Named deterministic fixture presets:\n")
+	fmt.Fprintf(os.Stderr, "    go run . --preset list\n")
+	fmt.Fprintf(os.Stderr, "    go run . preset <name>   # same as --preset <name>\n")
+	fmt.Fprintf(os.Stderr, "    go run . preset <name> --case <case>   # Only one case\n")
+	fmt.Fprintf(os.Stderr, "    go run . --preset neo-basic|neo-edge|eip2537-smoke [--emit all] [--emit-dir fixtures] [--timing]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Weighted MultiExp (scalars from a weight CSV, e.g. validator stakes):\n")
+	fmt.Fprintf(os.Stderr, "    go run . weighted --weights stakes.csv [--column stake] [--count N] [--use-g2] [--emit <targets>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Re-check C# arrays pasted from Bls12381MultiExpHelper.cs (G1_POINTS/G2_POINTS/SCALARS):\n")
+	fmt.Fprintf(os.Stderr, "    go run . import-csharp --file snippet.cs [--group g1|g2] [--expected <hex>] [--emit <targets>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Neo Ethereum alias method argument (base64 byte array) and expected result:\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-alias-args --op <g1add|g2add|g1mul|g2mul|g1msm|g2msm|pairing> --input <hex> [--input-format ethereum|compressed|auto]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Nearby corruption variants of a valid point, labeled accept/reject:\n")
+	fmt.Fprintf(os.Stderr, "    go run . corrupt-nearby --point <hex> [--all-bits] [--out variants.csv|variants.json]\n")
+	fmt.Fprintf(os.Stderr, "      - --point: Compressed G1/G2 (48/96 bytes) or Ethereum G1/G2 (128/256 bytes)\n")
+	fmt.Fprintf(os.Stderr, "      - --out *.json: self-describing fixture file with the error taxonomy embedded\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Malformed inputs for every rejection rule (flags, x >= p, padding, lengths, off-curve):\n")
+	fmt.Fprintf(os.Stderr, "    go run . invalid-vectors [--group g1|g2|both] [--out vectors.csv|vectors.json]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  EIP-2537 suite (all nine operations, ethereum/tests JSON layout):\n")
+	fmt.Fprintf(os.Stderr, "    go run . eip2537-suite [--out <dir>] [--op G1ADD,G1MSM,...|all] [--dry-run]\n")
+	fmt.Fprintf(os.Stderr, "      - Writes <file>.json (Input, Expected, Name, Gas, NoBenchmark) and fail-<file>.json (Input, ExpectedError, Name, ErrorCode)\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Differential test against go-ethereum's EIP-2537 precompiles:\n")
+	fmt.Fprintf(os.Stderr, "    go run . diff-geth [--op G1ADD,PAIRING,...|all] [--mutations N] [--seed <n>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . diff-geth --op G1MSM --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Differential test against blst (binary built with -tags blst, needs cgo):\n")
+	fmt.Fprintf(os.Stderr, "    go run -tags blst . diff-blst [--op serialization,add,mul,msm,pairing,hash-to-curve|all] [--count N] [--seed <n>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Canonical generators, identities and standard test points (EIP-2537, eth2, RFC 9380):\n")
+	fmt.Fprintf(os.Stderr, "    go run . well-known [--name <entry>] [--group g1|g2|all] [--to compressed,uncompressed,ethereum|all] [--list]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Subgroup membership and on-curve points outside the subgroup:\n")
+	fmt.Fprintf(os.Stderr, "    go run . subgroup-check --point <hex> [--format auto|g1c|g2c|g1u|g2u|g1e|g2e]\n")
+	fmt.Fprintf(os.Stderr, "    go run . subgroup-vectors [--group g1|g2|both] [--seed <hex>] [--out vectors.json]\n")
+	fmt.Fprintf(os.Stderr, "      - Low-order points of every small cofactor prime, the same plus a subgroup point, [r]R and uncleared points\n")
+	fmt.Fprintf(os.Stderr, "    go run . g2-torsion [--order all|13,23,2713,11953,262069,small,p448,r*<m>] [--seed <hex>] [--out torsion.json]\n")
+	fmt.Fprintf(os.Stderr, "      - G2 points of exact torsion order; small is 13 * 23 * 2713 * 11953 * 262069, r*<m> adds a subgroup point\n")
+	fmt.Fprintf(os.Stderr, "    go run . sample-curve-point [--group g1|g2|both] [--count N] [--clear] [--seed <hex>]\n")
+	fmt.Fprintf(os.Stderr, "      - Random x until x^3 + b is a square; --clear also prints each point with the cofactor cleared\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Validate a file of point encodings (one per line, mixed formats):\n")
+	fmt.Fprintf(os.Stderr, "    go run . validate-file --file dump.txt [--format auto|g1c|g2c|g1u|g2u|g1e|g2e] [--invalid-only] [--out verdicts.csv]\n")
+	fmt.Fprintf(os.Stderr, "      - auto: 48 g1c, 96 g2c (compression flag set) or g1u, 192 g2u, 128 g1e, 256 g2e bytes\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Check an implementation's errors against the fixture error codes:\n")
+	fmt.Fprintf(os.Stderr, "    go run . verify-errors --fixtures variants.json --results results.csv [--impl gnark|geth|go] [--map rules.json]\n")
+	fmt.Fprintf(os.Stderr, "    go run . verify-errors --fixtures vectors.json --self\n")
+	fmt.Fprintf(os.Stderr, "      - --results: CSV rows name,error (empty error = accepted); --map: [{\"pattern\": ..., \"code\": ...}]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Compare a Neo invocation result (invokescript JSON) with the expectation:\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-compare --response resp.json --expected <hex|int|bool>\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-compare --rpc http://localhost:10332 --script <base64> --expect-fault [--expect-exception <text>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Build the NeoVM script and invokescript request for a CryptoLib call:\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-script --op add --a <hex> --b <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-script --op mul --point <hex> --scalar <k> [--neg]\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-script --op pairing --g1 <hex,...> --g2 <hex,...>\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-script --op multiexp --points <hex,...> --scalars <k,...> [--body-out request.json]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Verify vectors directly on a Neo N3 node:\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-verify --rpc-url http://localhost:10332 --vectors fixtures.json\n")
+	fmt.Fprintf(os.Stderr, "    go run . neo-verify --rpc-url http://localhost:10332 --op mul --point <hex> --scalar <k>\n")
+	fmt.Fprintf(os.Stderr, "      - --vectors: the --format json document of a generating command (its fixtures are run as multiexp)\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Verify EIP-2537 precompiles on an Ethereum node with eth_call:\n")
+	fmt.Fprintf(os.Stderr, "    go run . eth-verify --rpc-url http://localhost:8545 --op G1ADD --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . eth-verify --rpc-url http://localhost:8545 --vectors eip2537-suite/blsG1MultiExp.json [--addresses final|draft]\n")
+	fmt.Fprintf(os.Stderr, "      - final: 0x0b-0x11 (MUL on the MSM addresses); draft: nine addresses 0x0b-0x13\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Recompute a JSON corpus (conformance runner, exit code 1 on any mismatch):\n")
+	fmt.Fprintf(os.Stderr, "    go run . verify-file --file eip2537-suite [--verbose]\n")
+	fmt.Fprintf(os.Stderr, "    go run . verify-file --file fixtures.json,vectors/blsG1Add.json [--op G1ADD]\n")
+	fmt.Fprintf(os.Stderr, "      - ethereum/tests precompile files (op from the file name or --op) or MultiExp fixture JSON\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Run the official EIP-2537 vectors (go-ethereum/ethereum/tests or EIP asset names):\n")
+	fmt.Fprintf(os.Stderr, "    go run . import-eth-tests --file go-ethereum/core/vm/testdata/precompiles [--map rules.json] [--out imported]\n")
+	fmt.Fprintf(os.Stderr, "      - expected failures must be rejected in the error category of their message; --gas=false for draft-era files\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Hash and sign (everything a contract test needs for one signature):\n")
+	fmt.Fprintf(os.Stderr, "    go run . hash-and-sign --message <text> | --message-hex <hex> --sk <key> [--ciphersuite min-pk|min-sig] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  BLS signatures (IETF draft KeyGen, Sign and Verify; default scheme pop):\n")
+	fmt.Fprintf(os.Stderr, "    go run . keygen [--ikm <hex>] [--key-info <text> | --key-info-hex <hex>] [--ciphersuite min-pk|min-sig]\n")
+	fmt.Fprintf(os.Stderr, "    go run . sign --sk <key> --message <text> | --message-hex <hex> [--ciphersuite min-pk|min-sig] [--scheme basic|aug|pop] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . verify --pk <hex> --signature <hex> --message <text> | --message-hex <hex> [--ciphersuite ...] [--scheme ...] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . aggregate --sks <k1,k2,...> --message <text> | --messages-hex <h1,h2,...> [--ciphersuite ...] [--scheme ...]\n")
+	fmt.Fprintf(os.Stderr, "    go run . aggregate-verify --pks <hex,...> --signature <hex> --message <text> | --messages-hex <h1,h2,...> [--ciphersuite ...] [--scheme ...]\n")
+	fmt.Fprintf(os.Stderr, "      - a common message under pop is checked with FastAggregateVerify, anything else with AggregateVerify\n")
+	fmt.Fprintf(os.Stderr, "    go run . pop-prove --sk <key> [--wrong-sk <key>] [--negative=false] [--ciphersuite min-pk|min-sig] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . pop-verify --pk <hex> --proof <hex> [--ciphersuite min-pk|min-sig] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . derive --seed <hex> | --sk <key> [--path m/12381/3600/0/0/0] [--lamport] [--ciphersuite min-pk|min-sig]\n")
+	fmt.Fprintf(os.Stderr, "    go run . keystore-create --sk <key> | --seed <hex> [--path <path>] --password <text> [--kdf scrypt|pbkdf2] [--cost N] [--out <file>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . keystore-unlock --file <keystore.json> --password <text> | --password-hex <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . eth-spec-gen [--out bls] [--handlers sign,verify,...]\n")
+	fmt.Fprintf(os.Stderr, "    go run . eth-spec-run --dir consensus-spec-tests/tests/general/phase0/bls [--handlers ...] [--verbose]\n")
+	fmt.Fprintf(os.Stderr, "      - consensus-spec data.yaml cases (min-pk, pop): sign, verify, aggregate, fast_aggregate_verify, aggregate_verify, eth_aggregate_pubkeys, eth_fast_aggregate_verify\n")
+	fmt.Fprintf(os.Stderr, "    go run . threshold-split [--sk <key>] -t 2 -n 3 [--coefficients <a1,...>] --message <text> [--ciphersuite ...] [--scheme ...]\n")
+	fmt.Fprintf(os.Stderr, "    go run . threshold-combine --ids 1,3 --partials <hex1,hex3> [--pk <group pk> --message <text>] [--ciphersuite ...] [--scheme ...]\n")
+	fmt.Fprintf(os.Stderr, "    go run . dkg-sim -t 2 -n 3 [--seed <hex>] [--bad-shares 1:2] [--refuse 1] [--message <text>] [--ciphersuite ...] [--out <file>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . vrf --sk <key> --input <text> [--negative=false] [--ciphersuite min-pk|min-sig] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . vrf --pk <hex> --proof <hex> [--output <hex>] --input <text> [--ciphersuite ...] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Hash to curve (RFC 9380 random-oracle suites, SHA-256 + SSWU):\n")
+	fmt.Fprintf(os.Stderr, "    go run . hash-g1 --message <text> | --message-hex <hex> [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . hash-g2 --message <text> | --message-hex <hex> [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "      - --trace: Print every intermediate (hash_to_field, SSWU steps, isogeny, clear_cofactor)\n")
+	fmt.Fprintf(os.Stderr, "      - Default DST: the RFC 9380 test-vector tag (QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_, G2 for hash-g2)\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Aggregate public keys / signatures (sum of compressed points):\n")
+	fmt.Fprintf(os.Stderr, "    go run . agg-pubkeys --points <hex,hex,...> [--ciphersuite min-pk|min-sig]\n")
+	fmt.Fprintf(os.Stderr, "    go run . agg-sigs --file sigs.txt [--ciphersuite min-pk|min-sig]\n")
+	fmt.Fprintf(os.Stderr, "      - min-pk (default): public keys in G1, signatures in G2; min-sig: the reverse\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Generation campaigns (JSON config of presets and random batches):\n")
+	fmt.Fprintf(os.Stderr, "    go run . campaign --config campaign.json [--dry-run]\n")
+	fmt.Fprintf(os.Stderr, "    go run . campaign --config campaign.json --shard <i>/<n> [--seed <hex>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . campaign --config campaign.json --merge\n")
+	fmt.Fprintf(os.Stderr, "      - --shard: Generate only shard i of n (0-based); each shard derives its own sub-seed\n")
+	fmt.Fprintf(os.Stderr, "      - --merge: Combine the shard manifests in emit_dir into manifest.json\n")
+	fmt.Fprintf(os.Stderr, "      - --fixture k: Generate only global fixture index k (printed as its Reproduce line)\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Scalar representations (decimal, BE/LE bytes, mod r, C# BigInteger):\n")
+	fmt.Fprintf(os.Stderr, "    go run . scalar-report --scalar <value> [--format auto|dec|hex|le-hex]\n")
+	fmt.Fprintf(os.Stderr, "  Fr scalar utilities (results in decimal, 32-byte BE/LE and C# BigInteger bytes):\n")
+	fmt.Fprintf(os.Stderr, "    go run . scalar --op reduce|neg|inv --value <value> [--format auto|dec|hex|le-hex]\n")
+	fmt.Fprintf(os.Stderr, "    go run . scalar --op random [--count N] [--seed <hex>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  G2 coordinates (x.C0, x.C1, y.C0, y.C1) with gnark and Ethereum orderings:\n")
+	fmt.Fprintf(os.Stderr, "    go run . convert --point <hex> [--from auto|compressed|uncompressed|ethereum] [--to compressed,uncompressed,ethereum|all] [--group g1|g2] [--no-subgroup-check]\n")
+	fmt.Fprintf(os.Stderr, "    go run . decode --point <hex> [--format auto|g1c|g2c|g1u|g2u|g1e|g2e]   # Inspect any point encoding\n")
+	fmt.Fprintf(os.Stderr, "    go run . g2-coords --point <hex>   # 96, 192 or 256 bytes\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Build a pairing input (384 bytes per pair) from compressed points:\n")
+	fmt.Fprintf(os.Stderr, "    go run . build-pairing-input --g1 <hex,hex,...> --g2 <hex,hex,...> [--quiet]\n")
+	fmt.Fprintf(os.Stderr, "    go run . build-pairing-input --g1-file g1.txt --g2-file g2.txt\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Base field arithmetic (Fp 48 bytes, Fp2 96 bytes c1 || c0, big-endian hex):\n")
+	fmt.Fprintf(os.Stderr, "    go run . field --a <hex> [--b <hex>] [--op add|sub|mul|inv|sqrt|legendre|sgn0|largest|all]\n")
+	fmt.Fprintf(os.Stderr, "    go run . field --a <96-byte hex> --order c0c1\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Polynomials over Fr (coefficients lowest degree first, decimal or 0x hex):\n")
+	fmt.Fprintf(os.Stderr, "    go run . poly-eval --coeffs c0,c1,... --z <value>\n")
+	fmt.Fprintf(os.Stderr, "    go run . poly-interpolate --xs x0,x1,... --ys y0,y1,...\n")
+	fmt.Fprintf(os.Stderr, "    go run . poly-divide --coeffs c0,c1,... --z <value>\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Bilinear accumulator: membership witness and its pairing-check input:\n")
+	fmt.Fprintf(os.Stderr, "    go run . accumulator --elements x1,x2,... [--member <y>] [--trapdoor <s> | --seed <hex>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Pedersen commitments and DLEQ proofs (hash-to-curve generators):\n")
+	fmt.Fprintf(os.Stderr, "    go run . pedersen [--values v1,v2,...] [--blinding <r>] [--add-values ...] [--add-blinding <r>] [--seed <hex>] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "    go run . dleq [--secret <k>] [--nonce <w>] [--groups g1|g1g2] [--h-message <text>] [--seed <hex>] [--dst <tag>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  KZG commitments (EIP-4844 point evaluation precompile, 0x0a):\n")
+	fmt.Fprintf(os.Stderr, "    go run . kzg --setup trusted_setup.json | --insecure-seed <hex> [--blob <file> | --blob-seed <hex>] [--z <value> | --z-domain <i>] [--diff-geth] [--out <file>]\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Groth16 verification vectors (x^3 + x + 5 == y):\n")
+	fmt.Fprintf(os.Stderr, "    go run . groth16 [--x 3] [--out <file>]   # gnark setup and prover\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  Pairing operation (Ethereum format):\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go pairing --input <hex>\n")
+	fmt.Fprintf(os.Stderr, "    go run . pairing --input <hex> [--input-format ethereum|compressed|auto]\n")
+	fmt.Fprintf(os.Stderr, "      - --input-format compressed: 48-byte G1 + 96-byte G2 compressed point per pair\n")
 	fmt.Fprintf(os.Stderr, "      - --input: Ethereum format input hex string\n")
 	fmt.Fprintf(os.Stderr, "        Each pair: 384 bytes (128 bytes G1 + 256 bytes G2)\n")
 	fmt.Fprintf(os.Stderr, "        Multiple pairs can be concatenated (must be multiple of 384 bytes)\n")
 	fmt.Fprintf(os.Stderr, "        Result: 32 bytes, last byte is 1 if pairing product is identity, 0 otherwise\n")
+	fmt.Fprintf(os.Stderr, "      - --profile: Empty-input semantics (neo default: identity, eip2537: error, gnark: identity)\n")
+	fmt.Fprintf(os.Stderr, "      - --empty: Override empty-input result: error or identity\n")
+	fmt.Fprintf(os.Stderr, "      - --timing: Print the execution time; --slow-threshold (default 100ms) flags slow results\n")
+	fmt.Fprintf(os.Stderr, "      - --naive: One pairing per pair multiplied in GT, compared with the single multi-Miller loop\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "  Pairing random test mode (generates test scenarios):\n")
-	fmt.Fprintf(os.Stderr, "    go run pairing_gen.go pairing-random\n")
+	fmt.Fprintf(os.Stderr, "    go run . pairing-random [--seed <hex>]\n")
 	fmt.Fprintf(os.Stderr, "      - Generates random G1 and G2 points\n")
 	fmt.Fprintf(os.Stderr, "      - Tests single pair: e(g1, g2)\n")
 	fmt.Fprintf(os.Stderr, "      - Tests multiple pairs with bilinearity: e(g1, g2) * e(-g1, g2) = 1\n")
 	fmt.Fprintf(os.Stderr, "      - Outputs C# array format for Bls12381MultiExpHelper.cs\n")
 	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "  Self-tests:\n")
+	fmt.Fprintf(os.Stderr, "    go run . ethereum-test        # Verify Ethereum MultiExp test vectors\n")
+	fmt.Fprintf(os.Stderr, "    go run . compression-check [--count N]  # Manual compression flags vs gnark Bytes() for edge cases and random points\n")
+	fmt.Fprintf(os.Stderr, "    go run . fuzz-serialization [--duration 30s] [--oracle gnark|<command>] [--kinds g1c,g2c,g1e,g2e] [--failures <file.jsonl>] [--oracle-workers N]  # Deserializers vs an oracle, pipelined\n")
+	fmt.Fprintf(os.Stderr, "    go run . replay-divergences --file <failures.jsonl> [--oracle gnark|<command>|none]  # Recorded divergences as a regression suite\n")
+	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "Examples:\n")
-	fmt.Fprintf(os.Stderr, "  go run pairing_gen.go 5\n")
-	fmt.Fprintf(os.Stderr, "  go run pairing_gen.go manual --g1 b2deb4e364cc09aceb924ebe236d28b5d180e27ee0428697f3d088b7c83637820c3c0c95b83189a6301dbaa405792564 --scalars \"1732363698,436226955,507793302,1540421097\"\n")
-	fmt.Fprintf(os.Stderr, "  go run pairing_gen.go ethereum --input 0000000000000000000000000000000017f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb0000000000000000000000000000000008b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e10000000000000000000000000000000000000000000000000000000000000011\n")
-	fmt.Fprintf(os.Stderr, "  go run pairing_gen.go g1add --input <256_bytes_hex>\n")
-	fmt.Fprintf(os.Stderr, "  go run pairing_gen.go g1mul --input <160_bytes_hex>\n")
+	fmt.Fprintf(os.Stderr, "  go run . 5\n")
+	fmt.Fprintf(os.Stderr, "  go run . manual --g1 b2deb4e364cc09aceb924ebe236d28b5d180e27ee0428697f3d088b7c83637820c3c0c95b83189a6301dbaa405792564 --scalars \"1732363698,436226955,507793302,1540421097\"\n")
+	fmt.Fprintf(os.Stderr, "  go run . ethereum --input 0000000000000000000000000000000017f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb0000000000000000000000000000000008b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e10000000000000000000000000000000000000000000000000000000000000011\n")
+	fmt.Fprintf(os.Stderr, "  go run . g1add --input <256_bytes_hex>\n")
+	fmt.Fprintf(os.Stderr, "  go run . g1mul --input <160_bytes_hex>\n")
 	fmt.Fprintf(os.Stderr, "  Note: In PowerShell, use single quotes or escape: --scalars 'val1,val2' or --scalars \\\"val1,val2\\\"\n")
 }
 
-// parseEthereumG1PointFromBytes parses a G1 point from Ethereum format (128 bytes)
-// Ethereum format: 64 bytes x (first 16 bytes are 0, last 48 bytes are big-endian) +
-//
-//	64 bytes y (first 16 bytes are 0, last 48 bytes are big-endian)
-func parseEthereumG1PointFromBytes(data []byte) (bls.G1Affine, error) {
-	if len(data) != 128 {
-		return bls.G1Affine{}, fmt.Errorf("ethereum G1 point must be 128 bytes, got %d", len(data))
-	}
-
-	// Check if this is an infinity point (all coordinates are zero)
-	// Infinity point in Ethereum format: all 128 bytes are zero
-	isInfinity := true
-	for i := 0; i < 128; i++ {
-		if data[i] != 0 {
-			isInfinity = false
-			break
-		}
-	}
-
-	if isInfinity {
-		// Return infinity point directly
-		var infinityPoint bls.G1Affine
-		// G1Affine zero value is infinity point
-		return infinityPoint, nil
-	}
-
-	// Check that first 16 bytes of each field element are zero
-	for i := 0; i < 16; i++ {
-		if data[i] != 0 || data[64+i] != 0 {
-			return bls.G1Affine{}, fmt.Errorf("non-zero padding bytes in Ethereum format at positions %d or %d", i, 64+i)
-		}
-	}
-
-	// Extract x and y (last 48 bytes of each 64-byte field element, big-endian)
-	xBytesBE := data[16:64]  // Last 48 bytes of x (big-endian)
-	yBytesBE := data[80:128] // Last 48 bytes of y (big-endian)
-
-	// gnark-crypto SetBytes accepts uncompressed format (96 bytes)
-	// Format: [x (48 bytes) + y (48 bytes)]
-	// Note: gnark-crypto's Marshal() actually returns big-endian format!
-	// So we can use Ethereum's big-endian bytes directly
-	uncompressedPoint := append(xBytesBE, yBytesBE...)
-
-	var g1Point bls.G1Affine
-	bytesRead, err := g1Point.SetBytes(uncompressedPoint)
-	if err != nil {
-		return bls.G1Affine{}, fmt.Errorf("SetBytes failed: %v", err)
-	}
-	if bytesRead != 96 {
-		return bls.G1Affine{}, fmt.Errorf("SetBytes read %d bytes, expected 96", bytesRead)
-	}
-	return g1Point, nil
-}
-
-// parseEthereumScalarFromBytes parses a scalar from Ethereum format (32 bytes, big-endian)
-func parseEthereumScalarFromBytes(data []byte) *big.Int {
-	return new(big.Int).SetBytes(data)
-}
-
-// encodeEthereumG1Point encodes a G1 point to Ethereum format (128 bytes)
-// Format: 64 bytes x (first 16 bytes are 0, last 48 bytes are big-endian) +
-//
-//	64 bytes y (first 16 bytes are 0, last 48 bytes are big-endian)
-func encodeEthereumG1Point(point bls.G1Affine) []byte {
-	if point.IsInfinity() {
-		return make([]byte, 128)
-	}
-
-	uncompressed := point.Marshal()
-	if len(uncompressed) != 96 {
-		panic(fmt.Sprintf("unexpected G1 uncompressed length: %d", len(uncompressed)))
-	}
-
-	// Extract x and y (48 bytes each, big-endian)
-	xBytes := uncompressed[0:48]
-	yBytes := uncompressed[48:96]
-
-	// Ethereum format: 64 bytes per field element (first 16 bytes are 0, last 48 bytes are the value)
-	output := make([]byte, 128)
-	// Explicitly zero out padding bytes to ensure they are zero
-	// x padding: bytes 0-15
-	// y padding: bytes 64-79
-	for i := 0; i < 16; i++ {
-		output[i] = 0    // x padding
-		output[64+i] = 0 // y padding
-	}
-	copy(output[16:64], xBytes)  // x: skip first 16 bytes, then 48 bytes
-	copy(output[80:128], yBytes) // y: skip first 16 bytes, then 48 bytes
-
-	return output
-}
-
-// encodeEthereumG2Point encodes a G2 point to Ethereum format (256 bytes)
-// Format: 64 bytes x.C0 + 64 bytes x.C1 + 64 bytes y.C0 + 64 bytes y.C1
-// Each 64-byte field: first 16 bytes are 0, last 48 bytes are big-endian
-func encodeEthereumG2Point(point bls.G2Affine) []byte {
-	if point.IsInfinity() {
-		return make([]byte, 256)
-	}
-
-	uncompressed := point.Marshal()
-	if len(uncompressed) != 192 {
-		panic(fmt.Sprintf("unexpected G2 uncompressed length: %d", len(uncompressed)))
-	}
-
-	// gnark-crypto format: [x.C1 (48 bytes) + x.C0 (48 bytes) + y.C1 (48 bytes) + y.C0 (48 bytes)]
-	// Ethereum format: [x.C0 (64 bytes) + x.C1 (64 bytes) + y.C0 (64 bytes) + y.C1 (64 bytes)]
-	xC1Bytes := uncompressed[0:48]
-	xC0Bytes := uncompressed[48:96]
-	yC1Bytes := uncompressed[96:144]
-	yC0Bytes := uncompressed[144:192]
-
-	output := make([]byte, 256)
-	// Explicitly zero out all padding bytes to ensure they are zero
-	// Each 64-byte field has 16 bytes of padding at the start
-	for i := 0; i < 16; i++ {
-		output[i] = 0     // x.C0 padding: bytes 0-15
-		output[64+i] = 0  // x.C1 padding: bytes 64-79
-		output[128+i] = 0 // y.C0 padding: bytes 128-143
-		output[192+i] = 0 // y.C1 padding: bytes 192-207
-	}
-	// x.C0: first 64 bytes, skip first 16, then 48 bytes
-	copy(output[16:64], xC0Bytes)
-	// x.C1: second 64 bytes, skip first 16, then 48 bytes
-	copy(output[80:128], xC1Bytes)
-	// y.C0: third 64 bytes, skip first 16, then 48 bytes
-	copy(output[144:192], yC0Bytes)
-	// y.C1: fourth 64 bytes, skip first 16, then 48 bytes
-	copy(output[208:256], yC1Bytes)
-
-	return output
-}
-
-// computeG1Add computes G1 point addition: p1 + p2
-// Input: two Ethereum format G1 points (128 bytes each = 256 bytes total)
-// Output: Ethereum format G1 point (128 bytes)
-func computeG1Add(inputHex string) (string, error) {
-	inputHex = strings.TrimSpace(inputHex)
-	inputBytes, err := hex.DecodeString(inputHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse input hex: %v", err)
-	}
-
-	if len(inputBytes) != 256 {
-		return "", fmt.Errorf("G1 add input must be 256 bytes (128 bytes per point), got %d", len(inputBytes))
-	}
-
-	// Parse two G1 points
-	p1, err := parseEthereumG1PointFromBytes(inputBytes[0:128])
-	if err != nil {
-		return "", fmt.Errorf("failed to parse first G1 point: %v", err)
-	}
-
-	p2, err := parseEthereumG1PointFromBytes(inputBytes[128:256])
-	if err != nil {
-		return "", fmt.Errorf("failed to parse second G1 point: %v", err)
-	}
-
-	// Compute addition: p1 + p2
-	var p1Jac bls.G1Jac
-	p1Jac.FromAffine(&p1)
-	var p2Jac bls.G1Jac
-	p2Jac.FromAffine(&p2)
-	p1Jac.AddAssign(&p2Jac)
-
-	var result bls.G1Affine
-	result.FromJacobian(&p1Jac)
-
-	// Encode result to Ethereum format
-	resultBytes := encodeEthereumG1Point(result)
-	return hex.EncodeToString(resultBytes), nil
-}
-
-// computeG2Add computes G2 point addition: p1 + p2
-// Input: two Ethereum format G2 points (256 bytes each = 512 bytes total)
-// Output: Ethereum format G2 point (256 bytes)
-// This function follows gnark-crypto standard and is compatible with Bls12381MultiExpHelper.cs
-func computeG2Add(inputHex string) (string, error) {
-	inputHex = strings.TrimSpace(inputHex)
-	inputBytes, err := hex.DecodeString(inputHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse input hex: %v", err)
-	}
-
-	if len(inputBytes) != 512 {
-		return "", fmt.Errorf("G2 add input must be 512 bytes (256 bytes per point), got %d", len(inputBytes))
-	}
-
-	// Parse two G2 points from Ethereum format
-	// Create separate slices to avoid potential slice sharing issues
-	point1Data := make([]byte, 256)
-	copy(point1Data, inputBytes[0:256])
-	point2Data := make([]byte, 256)
-	copy(point2Data, inputBytes[256:512])
-
-	// Verify point2Data's x.C0 padding is zero before parsing
-	for i := 0; i < 16; i++ {
-		if point2Data[i] != 0 {
-			return "", fmt.Errorf("second point x.C0 padding byte[%d] is non-zero: 0x%02x. Input data may be corrupted. First point y.C0 data (bytes 144-160): %x", i, point2Data[i], inputBytes[144:160])
-		}
-	}
-
-	p1, err := parseEthereumG2PointFromBytes(point1Data)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse first G2 point: %v", err)
-	}
-
-	p2, err := parseEthereumG2PointFromBytes(point2Data)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse second G2 point: %v", err)
-	}
-
-	// Compute addition: p1 + p2 using gnark-crypto standard API
-	// Convert to Jacobian coordinates for efficient addition
-	var p1Jac bls.G2Jac
-	p1Jac.FromAffine(&p1)
-	var p2Jac bls.G2Jac
-	p2Jac.FromAffine(&p2)
-
-	// Perform addition: p1Jac = p1Jac + p2Jac
-	p1Jac.AddAssign(&p2Jac)
-
-	// Convert back to Affine coordinates
-	var result bls.G2Affine
-	result.FromJacobian(&p1Jac)
-
-	// Encode result to Ethereum format
-	resultBytes := encodeEthereumG2Point(result)
-	return hex.EncodeToString(resultBytes), nil
-}
-
 // runPairingRandomMode runs the random pairing mode
 // This generates random G1 and G2 points, and can test multiple pairing scenarios:
 // - Single pair: e(g1, g2)
 // - Multiple pairs with bilinearity: e(g1, g2) * e(-g1, g2) = 1
 // This matches Neo's TestBls12PairingAliasMultiplePairs test scenario
-func runPairingRandomMode() {
+func runPairingRandomMode(gt *gtFormatOptions) {
 	fmt.Println("=== BLS12-381 Pairing Random Test Mode ===")
 	fmt.Println("Generating random G1 and G2 points for pairing test...")
 	fmt.Println()
@@ -1067,14 +840,14 @@ func runPairingRandomMode() {
 		panic(fmt.Sprintf("failed to generate random G1 point: %v", err))
 	}
 
-	Q, err := bls.RandomOnG2()
+	Q, err := randomOnG2()
 	if err != nil {
 		panic(fmt.Sprintf("failed to generate random G2 point: %v", err))
 	}
 
 	// Convert to compressed format for output
-	g1Compressed := convertG1AffineToCompressed(P)
-	g2Compressed := convertG2AffineToCompressed(Q)
+	g1Compressed := serialization.ConvertG1AffineToCompressed(P)
+	g2Compressed := serialization.ConvertG2AffineToCompressed(Q)
 
 	fmt.Println("Generated Points (compressed format):")
 	fmt.Printf("G1 (compressed, 48 bytes, 96 hex chars): %x\n", g1Compressed)
@@ -1092,7 +865,11 @@ func runPairingRandomMode() {
 	identity.SetOne()
 	isIdentity1 := singlePairResult.Equal(&identity)
 	fmt.Printf("Result is identity: %v\n", isIdentity1)
-	fmt.Printf("Pairing result (GT element): %x\n", singlePairResult.Marshal())
+	if gt.explicit() {
+		gt.print("Pairing result (GT element)", &singlePairResult)
+	} else {
+		fmt.Printf("Pairing result (GT element): %x\n", singlePairResult.Marshal())
+	}
 	fmt.Println()
 
 	// Test Scenario 2: Multiple pairs with bilinearity e(g1, g2) * e(-g1, g2) = 1
@@ -1131,9 +908,9 @@ func runPairingRandomMode() {
 	fmt.Println()
 
 	// Encode points to Ethereum format for Neo compatibility
-	g1Ethereum := encodeEthereumG1Point(P)
-	negG1Ethereum := encodeEthereumG1Point(negP)
-	g2Ethereum := encodeEthereumG2Point(Q)
+	g1Ethereum := serialization.EncodeEthereumG1Point(P)
+	negG1Ethereum := serialization.EncodeEthereumG1Point(negP)
+	g2Ethereum := serialization.EncodeEthereumG2Point(Q)
 
 	// Build input for multiple pairs: [g1, g2] + [-g1, g2]
 	const pairLength = 128 + 256 // 384 bytes
@@ -1150,15 +927,17 @@ func runPairingRandomMode() {
 	fmt.Printf("Input hex: %x\n", multiplePairsInput)
 	fmt.Println()
 
-	// Compute using computePairing to verify
+	// Compute using bls12381vec.Pairing to verify
 	inputHex := hex.EncodeToString(multiplePairsInput)
-	result, err := computePairing(inputHex)
+	result, err := bls12381vec.Pairing(inputHex)
 	if err != nil {
-		panic(fmt.Sprintf("computePairing failed: %v", err))
+		panic(fmt.Sprintf("bls12381vec.Pairing failed: %v", err))
 	}
 
-	fmt.Println("=== Expected Result (from computePairing) ===")
+	fmt.Println("=== Expected Result (from bls12381vec.Pairing) ===")
 	fmt.Printf("Result (32 bytes, 64 hex chars): %s\n", result)
+	recordQuietResult("input", inputHex)
+	recordQuietResult("expected", result)
 	fmt.Printf("Last byte: 0x%02x (1 = identity, 0 = non-identity)\n", result[len(result)-2:])
 	if result[len(result)-2:] == "01" {
 		fmt.Println("✅ Result correctly identifies as identity!")
@@ -1173,7 +952,7 @@ func runPairingRandomMode() {
 	fmt.Println("// This tests: e(g1, g2) * e(-g1, g2) = 1")
 	fmt.Print("private static readonly string[] G1_PAIRS = new string[]\n{\n")
 	fmt.Printf("    \"%x\",  // Pair 0: G1 point\n", g1Compressed)
-	fmt.Printf("    \"%x\"   // Pair 1: -G1 point (negation)\n", convertG1AffineToCompressed(negP))
+	fmt.Printf("    \"%x\"   // Pair 1: -G1 point (negation)\n", serialization.ConvertG1AffineToCompressed(negP))
 	fmt.Println("};")
 	fmt.Println()
 	fmt.Print("private static readonly string[] G2_PAIRS = new string[]\n{\n")
@@ -1186,6 +965,11 @@ func runPairingRandomMode() {
 	fmt.Println()
 	fmt.Println("// Note: This matches Neo's TestBls12PairingAliasMultiplePairs test scenario")
 	fmt.Println("//       e(g1, g2) * e(-g1, g2) = e(g1, g2) * e(g1, g2)^(-1) = 1")
+	fmt.Println()
+
+	// The alias method takes the whole input as one byte-array argument
+	resultBytes, _ := hex.DecodeString(result)
+	printNeoAliasArgs("pairing", multiplePairsInput, resultBytes)
 }
 
 // runG2AddRandomMode runs the random G2 addition mode
@@ -1197,19 +981,19 @@ func runG2AddRandomMode() {
 	fmt.Println()
 
 	// Generate two random G2 points using gnark-crypto standard API
-	Q1, err := bls.RandomOnG2()
+	Q1, err := randomOnG2()
 	if err != nil {
 		panic(fmt.Sprintf("failed to generate random G2 point 1: %v", err))
 	}
 
-	Q2, err := bls.RandomOnG2()
+	Q2, err := randomOnG2()
 	if err != nil {
 		panic(fmt.Sprintf("failed to generate random G2 point 2: %v", err))
 	}
 
 	// Encode both points to Ethereum format
-	point1Ethereum := encodeEthereumG2Point(Q1)
-	point2Ethereum := encodeEthereumG2Point(Q2)
+	point1Ethereum := serialization.EncodeEthereumG2Point(Q1)
+	point2Ethereum := serialization.EncodeEthereumG2Point(Q2)
 
 	// Concatenate: point1 (256 bytes) + point2 (256 bytes) = 512 bytes
 	inputBytes := make([]byte, 512)
@@ -1238,14 +1022,14 @@ func runG2AddRandomMode() {
 
 	// Output point information
 	fmt.Println("Point 1 (compressed):")
-	g2Compressed1 := convertG2AffineToCompressed(Q1)
+	g2Compressed1 := serialization.ConvertG2AffineToCompressed(Q1)
 	fmt.Printf("  %x\n", g2Compressed1)
 	fmt.Println("Point 1 (Ethereum format, first 64 bytes of x.C0):")
 	fmt.Printf("  %x...\n", point1Ethereum[0:64])
 
 	fmt.Println()
 	fmt.Println("Point 2 (compressed):")
-	g2Compressed2 := convertG2AffineToCompressed(Q2)
+	g2Compressed2 := serialization.ConvertG2AffineToCompressed(Q2)
 	fmt.Printf("  %x\n", g2Compressed2)
 	fmt.Println("Point 2 (Ethereum format, first 64 bytes of x.C0):")
 	fmt.Printf("  %x...\n", point2Ethereum[0:64])
@@ -1256,8 +1040,8 @@ func runG2AddRandomMode() {
 	fmt.Printf("  First 128 hex chars: %s...\n", inputHex[0:128])
 	fmt.Printf("  Last 128 hex chars: ...%s\n", inputHex[len(inputHex)-128:])
 
-	// Compute addition using computeG2Add
-	resultHex, err := computeG2Add(inputHex)
+	// Compute addition using bls12381vec.G2Add
+	resultHex, err := bls12381vec.G2Add(inputHex)
 	if err != nil {
 		panic(fmt.Sprintf("failed to compute G2 addition: %v", err))
 	}
@@ -1266,6 +1050,8 @@ func runG2AddRandomMode() {
 	fmt.Println("=== Result ===")
 	fmt.Printf("Result (Ethereum format, 256 bytes = 512 hex chars):\n")
 	fmt.Printf("  %s\n", resultHex)
+	recordQuietResult("input", inputHex)
+	recordQuietResult("expected", resultHex)
 
 	// Verify: Compute expected result using gnark-crypto directly
 	fmt.Println()
@@ -1278,7 +1064,7 @@ func runG2AddRandomMode() {
 	var expectedResult bls.G2Affine
 	expectedResult.FromJacobian(&Q1Jac)
 
-	expectedEthereum := encodeEthereumG2Point(expectedResult)
+	expectedEthereum := serialization.EncodeEthereumG2Point(expectedResult)
 	expectedHex := hex.EncodeToString(expectedEthereum)
 
 	fmt.Printf("Expected (Ethereum format):\n")
@@ -1309,471 +1095,8 @@ func runG2AddRandomMode() {
 	fmt.Printf("  %s\n", inputHex)
 }
 
-// computeG1Mul computes G1 point multiplication: point * scalar
-// Input: Ethereum format G1 point (128 bytes) + scalar (32 bytes) = 160 bytes total
-// Output: Ethereum format G1 point (128 bytes)
-func computeG1Mul(inputHex string) (string, error) {
-	inputHex = strings.TrimSpace(inputHex)
-	inputBytes, err := hex.DecodeString(inputHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse input hex: %v", err)
-	}
-
-	if len(inputBytes) != 160 {
-		return "", fmt.Errorf("G1 mul input must be 160 bytes (128 bytes point + 32 bytes scalar), got %d", len(inputBytes))
-	}
-
-	// Parse G1 point and scalar
-	point, err := parseEthereumG1PointFromBytes(inputBytes[0:128])
-	if err != nil {
-		return "", fmt.Errorf("failed to parse G1 point: %v", err)
-	}
-
-	scalar := parseEthereumScalarFromBytes(inputBytes[128:160])
-
-	// Compute multiplication: point * scalar
-	var pointJac bls.G1Jac
-	pointJac.FromAffine(&point)
-	pointJac.ScalarMultiplication(&pointJac, scalar)
-
-	var result bls.G1Affine
-	result.FromJacobian(&pointJac)
-
-	// Encode result to Ethereum format
-	resultBytes := encodeEthereumG1Point(result)
-	return hex.EncodeToString(resultBytes), nil
-}
-
-// computeG2Mul computes G2 point multiplication: point * scalar
-// Input: Ethereum format G2 point (256 bytes) + scalar (32 bytes) = 288 bytes total
-// Output: Ethereum format G2 point (256 bytes)
-func computeG2Mul(inputHex string) (string, error) {
-	inputHex = strings.TrimSpace(inputHex)
-	inputBytes, err := hex.DecodeString(inputHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse input hex: %v", err)
-	}
-
-	if len(inputBytes) != 288 {
-		return "", fmt.Errorf("G2 mul input must be 288 bytes (256 bytes point + 32 bytes scalar), got %d", len(inputBytes))
-	}
-
-	// Parse G2 point and scalar
-	point, err := parseEthereumG2PointFromBytes(inputBytes[0:256])
-	if err != nil {
-		return "", fmt.Errorf("failed to parse G2 point: %v", err)
-	}
-
-	scalar := parseEthereumScalarFromBytes(inputBytes[256:288])
-
-	// Compute multiplication: point * scalar
-	var pointJac bls.G2Jac
-	pointJac.FromAffine(&point)
-	pointJac.ScalarMultiplication(&pointJac, scalar)
-
-	var result bls.G2Affine
-	result.FromJacobian(&pointJac)
-
-	// Encode result to Ethereum format
-	resultBytes := encodeEthereumG2Point(result)
-	return hex.EncodeToString(resultBytes), nil
-}
-
-// computePairing computes BLS12-381 pairing: e(g1_1, g2_1) * e(g1_2, g2_2) * ...
-// Input: Ethereum format pairs, each pair is G1 (128 bytes) + G2 (256 bytes) = 384 bytes
-// Output: 32 bytes, last byte is 1 if pairing result is identity (unit element), 0 otherwise
-// This matches Neo's Bls12Pairing implementation
-func computePairing(inputHex string) (string, error) {
-	inputHex = strings.TrimSpace(inputHex)
-	inputBytes, err := hex.DecodeString(inputHex)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse input hex: %v", err)
-	}
-	// Create a copy to avoid any potential modifications by gnark-crypto
-	inputBytesCopy := make([]byte, len(inputBytes))
-	copy(inputBytesCopy, inputBytes)
-	inputBytes = inputBytesCopy
-
-	// Each pair is 384 bytes: 128 bytes G1 + 256 bytes G2
-	const pairLength = 128 + 256 // 384 bytes
-	if len(inputBytes) == 0 {
-		// Empty input: return identity (unit element) = 1
-		result := make([]byte, 32)
-		result[31] = 1
-		return hex.EncodeToString(result), nil
-	}
-
-	if len(inputBytes)%pairLength != 0 {
-		return "", fmt.Errorf("pairing input must be multiple of %d bytes (each pair is %d bytes), got %d", pairLength, pairLength, len(inputBytes))
-	}
-
-	// Parse all pairs and compute pairing product
-	var accumulator bls.GT
-	accumulator.SetOne() // Start with identity element
-
-	numPairs := len(inputBytes) / pairLength
-	for i := 0; i < numPairs; i++ {
-		offset := i * pairLength
-		g1Bytes := inputBytes[offset : offset+128]
-		g2Bytes := inputBytes[offset+128 : offset+pairLength]
-
-		// Create copies to avoid any potential modifications to inputBytes by gnark-crypto
-		g1BytesCopy := make([]byte, len(g1Bytes))
-		copy(g1BytesCopy, g1Bytes)
-		g2BytesCopy := make([]byte, len(g2Bytes))
-		copy(g2BytesCopy, g2Bytes)
-
-		// Parse G1 point from Ethereum format (using copy)
-		g1Point, err := parseEthereumG1PointFromBytes(g1BytesCopy)
-		if err != nil {
-			return "", fmt.Errorf("failed to parse G1 point at pair %d: %v", i, err)
-		}
-
-		// Parse G2 point from Ethereum format (using copy)
-		g2Point, err := parseEthereumG2PointFromBytes(g2BytesCopy)
-		if err != nil {
-			return "", fmt.Errorf("failed to parse G2 point at pair %d: %v", i, err)
-		}
-
-		// Compute pairing: e(g1, g2)
-		pairResult, err := bls.Pair([]bls.G1Affine{g1Point}, []bls.G2Affine{g2Point})
-		if err != nil {
-			return "", fmt.Errorf("failed to compute pairing at pair %d: %v", i, err)
-		}
-
-		// Multiply accumulator by pair result: accumulator = accumulator * pairResult
-		accumulator.Mul(&accumulator, &pairResult)
-	}
-
-	// Check if result is identity (unit element)
-	// In gnark-crypto, GT.Identity() is the unit element
-	// We check if accumulator == 1 (identity)
-	var identity bls.GT
-	identity.SetOne()
-	isIdentity := accumulator.Equal(&identity)
-
-	// Encode result: 32 bytes, last byte is 1 if identity, 0 otherwise
-	result := make([]byte, 32)
-	if isIdentity {
-		result[31] = 1
-	} else {
-		result[31] = 0
-	}
-
-	return hex.EncodeToString(result), nil
-}
-
-// convertG1AffineToCompressed converts a G1Affine point to compressed format (48 bytes)
-func convertG1AffineToCompressed(point bls.G1Affine) []byte {
-	uncompressed := point.Marshal()
-	compressed := make([]byte, 48)
-	copy(compressed, uncompressed[:48])
-	compressed[0] |= 0x80 // Set compression flag
-	yBytes := uncompressed[48:96]
-	if isLexicographicallyLargestFp(yBytes) {
-		compressed[0] |= 0x20 // Set y coordinate sort flag
-	}
-	return compressed
-}
-
-// compressedToUncompressedHex converts a compressed point hex string back to the
-// uncompressed hex form (96 bytes for G1, 192 bytes for G2) for display.
-func compressedToUncompressedHex(compressedHex string, useG2 bool) (string, error) {
-	bytes, err := hex.DecodeString(strings.TrimSpace(compressedHex))
-	if err != nil {
-		return "", fmt.Errorf("invalid compressed hex: %w", err)
-	}
-
-	if useG2 {
-		if len(bytes) != 96 {
-			return "", fmt.Errorf("compressed G2 value must be 96 bytes, got %d", len(bytes))
-		}
-		var point bls.G2Affine
-		if _, err := point.SetBytes(bytes); err != nil {
-			return "", fmt.Errorf("failed to parse compressed G2: %w", err)
-		}
-		return hex.EncodeToString(point.Marshal()), nil
-	}
-
-	if len(bytes) != 48 {
-		return "", fmt.Errorf("compressed G1 value must be 48 bytes, got %d", len(bytes))
-	}
-	var point bls.G1Affine
-	if _, err := point.SetBytes(bytes); err != nil {
-		return "", fmt.Errorf("failed to parse compressed G1: %w", err)
-	}
-	return hex.EncodeToString(point.Marshal()), nil
-}
-
-// convertG2AffineToCompressed converts a G2Affine point to compressed format (96 bytes)
-// Format matches Neo's G2Affine.ToCompressed():
-// - First 48 bytes: x.C1
-// - Next 48 bytes: x.C0
-// - First byte flags: 0x80 (compression), 0x40 (infinity), 0x20 (sort)
-// The flags are stored in the upper 3 bits of the first byte, while the lower 5 bits
-// are part of the x.C1 coordinate data.
-func convertG2AffineToCompressed(point bls.G2Affine) []byte {
-	uncompressed := point.Marshal()
-	compressed := make([]byte, 96)
-
-	// Extract x coordinate: gnark-crypto format is [x.C1 (48) + x.C0 (48) + y.C1 (48) + y.C0 (48)]
-	// Neo format is [x.C1 (48) + x.C0 (48)]
-	copy(compressed, uncompressed[:96]) // Extract x coordinate (x.C1 + x.C0)
-
-	// Clear only the flag bits (0x80, 0x40, 0x20) from the first byte before setting them
-	// The lower 5 bits (0x1F) are part of the x.C1 coordinate data and must be preserved
-	// Note: We use & 0x1F to clear the upper 3 bits (flags) while preserving the lower 5 bits (data)
-	compressed[0] &= 0x1F
-
-	// Set compression flag (MSB) - always set for compressed format
-	compressed[0] |= 0x80
-
-	// Check if point is at infinity
-	if point.IsInfinity() {
-		compressed[0] |= 0x40 // Set infinity flag
-		// For infinity point, Neo's validation requires: infinity -> !sort_flag & x.IsZero
-		// The sort flag should NOT be set for infinity points
-		return compressed
-	}
-
-	// Extract y coordinate to determine sort flag
-	yBytes := uncompressed[96:192] // y coordinate (y.C1 + y.C0)
-	if isLexicographicallyLargestFp2(yBytes) {
-		compressed[0] |= 0x20 // Set y coordinate sort flag
-	}
-
-	return compressed
-}
-
-// parseEthereumG2PointFromBytes parses a G2 point from Ethereum format (256 bytes)
-// Ethereum format: 64 bytes x.C0 (first 16 bytes are 0, last 48 bytes are big-endian) +
-//
-//	64 bytes x.C1 (first 16 bytes are 0, last 48 bytes are big-endian) +
-//	64 bytes y.C0 (first 16 bytes are 0, last 48 bytes are big-endian) +
-//	64 bytes y.C1 (first 16 bytes are 0, last 48 bytes are big-endian)
-//
-// This matches Neo's EncodeEthereumG2 format: [x.C0, x.C1, y.C0, y.C1]
-func parseEthereumG2PointFromBytes(data []byte) (bls.G2Affine, error) {
-	if len(data) != 256 {
-		return bls.G2Affine{}, fmt.Errorf("ethereum G2 point must be 256 bytes, got %d", len(data))
-	}
-
-	// Debug: Check what data we actually received
-	fmt.Fprintf(os.Stderr, "Debug: parseEthereumG2PointFromBytes received data:\n")
-	fmt.Fprintf(os.Stderr, "  x.C0 padding (bytes 0-16): %x\n", data[0:16])
-	fmt.Fprintf(os.Stderr, "  x.C0 data (bytes 16-64): %x\n", data[16:64])
-	fmt.Fprintf(os.Stderr, "  x.C1 padding (bytes 64-80): %x\n", data[64:80])
-	fmt.Fprintf(os.Stderr, "  x.C1 data (bytes 80-128): %x\n", data[80:128])
-	fmt.Fprintf(os.Stderr, "  y.C0 padding (bytes 128-144): %x\n", data[128:144])
-	fmt.Fprintf(os.Stderr, "  y.C0 data (bytes 144-192): %x\n", data[144:192])
-	fmt.Fprintf(os.Stderr, "  y.C1 padding (bytes 192-208): %x\n", data[192:208])
-	fmt.Fprintf(os.Stderr, "  y.C1 data (bytes 208-256): %x\n", data[208:256])
-
-	// Check that first 16 bytes of each field element are zero
-	// Ethereum format: each 64-byte field element has 16 bytes of padding (zeros) followed by 48 bytes of data
-	// Note: We'll warn about non-zero padding but continue, as the actual data is in the last 48 bytes
-	hasNonZeroPadding := false
-	var paddingErrors []string
-	for i := 0; i < 16; i++ {
-		if data[i] != 0 {
-			hasNonZeroPadding = true
-			paddingErrors = append(paddingErrors, fmt.Sprintf("x.C0[%d]=0x%02x", i, data[i]))
-		}
-		if data[64+i] != 0 {
-			hasNonZeroPadding = true
-			paddingErrors = append(paddingErrors, fmt.Sprintf("x.C1[%d]=0x%02x", 64+i, data[64+i]))
-		}
-		if data[128+i] != 0 {
-			hasNonZeroPadding = true
-			paddingErrors = append(paddingErrors, fmt.Sprintf("y.C0[%d]=0x%02x", 128+i, data[128+i]))
-		}
-		if data[192+i] != 0 {
-			hasNonZeroPadding = true
-			paddingErrors = append(paddingErrors, fmt.Sprintf("y.C1[%d]=0x%02x", 192+i, data[192+i]))
-		}
-	}
-	if hasNonZeroPadding {
-		// Log warning but continue - the actual coordinate data is in the last 48 bytes of each field
-		fmt.Fprintf(os.Stderr, "Warning: non-zero padding bytes in Ethereum format G2 point: %v\n", paddingErrors)
-		fmt.Fprintf(os.Stderr, "  Continuing anyway - coordinate data is in bytes [16:64], [80:128], [144:192], [208:256]\n")
-	}
-
-	// Extract coordinates (last 48 bytes of each 64-byte field element, big-endian)
-	// Ethereum/Neo format: [x.C0 (64 bytes), x.C1 (64 bytes), y.C0 (64 bytes), y.C1 (64 bytes)]
-	// Each 64-byte field: first 16 bytes are 0, last 48 bytes are the value
-	// However, if padding bytes are non-zero, the data might be in a different location
-	// Let's try both: standard location and alternative location (if padding is non-zero)
-
-	// Standard extraction (assuming padding is correct)
-	xC0Bytes := data[16:64]   // x.C0 (48 bytes, big-endian) - first 64 bytes, skip first 16
-	xC1Bytes := data[80:128]  // x.C1 (48 bytes, big-endian) - second 64 bytes, skip first 16
-	yC0Bytes := data[144:192] // y.C0 (48 bytes, big-endian) - third 64 bytes, skip first 16
-	yC1Bytes := data[208:256] // y.C1 (48 bytes, big-endian) - fourth 64 bytes, skip first 16
-
-	// Check if this is an infinity point (all coordinates are zero)
-	// Infinity point in Ethereum format: all 256 bytes are zero
-	isInfinity := true
-	for i := 0; i < 256; i++ {
-		if data[i] != 0 {
-			isInfinity = false
-			break
-		}
-	}
-
-	if isInfinity {
-		// Return infinity point directly
-		var infinityPoint bls.G2Affine
-		// G2Affine zero value is infinity point
-		return infinityPoint, nil
-	}
-
-	// If padding is non-zero, the data might actually be in the first 48 bytes of each field
-	// Let's check if the standard extraction produces valid data, and if not, try alternative
-	if hasNonZeroPadding {
-		fmt.Fprintf(os.Stderr, "  Attempting to extract coordinates from standard location [16:64], [80:128], [144:192], [208:256]\n")
-		// If this fails, we might need to try alternative locations
-	}
-
-	// gnark-crypto's G2Affine.SetBytes only supports compressed format (96 bytes), not uncompressed (192 bytes)
-	// We need to convert Ethereum format to compressed format first
-	// Compressed format: [x.C1 (48 bytes) + x.C0 (48 bytes)] with flags in first byte
-	// This matches the approach used in computeMultiExpFromCompressed for G2 points
-
-	// Construct compressed format from x coordinate
-	// Format: [xC1, xC0] (96 bytes total)
-	compressed := make([]byte, 96)
-	copy(compressed[0:48], xC1Bytes)  // x.C1 (first 48 bytes)
-	copy(compressed[48:96], xC0Bytes) // x.C0 (next 48 bytes)
-
-	// Clear flag bits (upper 3 bits) while preserving lower 5 bits of first byte
-	// The lower 5 bits are part of the x.C1 coordinate data
-	compressed[0] &= 0x1F
-
-	// Set compression flag (MSB) - always set for compressed format
-	compressed[0] |= 0x80
-
-	// Determine sort flag based on y coordinate
-	// y coordinate format: [y.C1, y.C0] (96 bytes, big-endian)
-	yBytes := append(yC1Bytes, yC0Bytes...)
-	if isLexicographicallyLargestFp2(yBytes) {
-		compressed[0] |= 0x20 // Set y coordinate sort flag
-	}
-
-	// Parse compressed format using SetBytes (same as computeMultiExpFromCompressed)
-	// Debug: Show compressed format before parsing
-	fmt.Fprintf(os.Stderr, "Debug: Constructed compressed format (first 16 bytes): %x\n", compressed[0:16])
-	fmt.Fprintf(os.Stderr, "Debug: xC1Bytes (first 16 bytes): %x\n", xC1Bytes[0:16])
-	fmt.Fprintf(os.Stderr, "Debug: xC0Bytes (first 16 bytes): %x\n", xC0Bytes[0:16])
-
-	var g2Point bls.G2Affine
-	bytesRead, err := g2Point.SetBytes(compressed)
-	if err != nil {
-		// If padding was non-zero and parsing failed, try alternative location
-		// Data might be in compact format [0:48], [48:96], [96:144], [144:192] instead of Ethereum format [16:64], [80:128], [144:192], [208:256]
-		if hasNonZeroPadding {
-			fmt.Fprintf(os.Stderr, "  Standard location failed, trying alternative location [0:48], [48:96], [96:144], [144:192] (compact format)\n")
-
-			// Try multiple alternative formats
-			// Format 1: Compact format [0:48], [48:96], [96:144], [144:192]
-			xC0BytesAlt1 := data[0:48]
-			xC1BytesAlt1 := data[48:96]
-			yC0BytesAlt1 := data[96:144]
-			yC1BytesAlt1 := data[144:192]
-
-			// Format 2: If padding bytes contain actual data, the format might be wrong
-			// Try using the padding bytes themselves as part of the coordinate data
-			// This is a last resort - if padding bytes are non-zero, maybe they ARE the data
-			// Format: Use first 16 bytes (padding) + next 32 bytes for x.C0, etc.
-			// Actually, let's try a different approach: maybe data is shifted
-			// Format 2: [16:64] for x.C0 (standard), but [0:48] for x.C1 (if padding is wrong)
-			// Or maybe the entire format is different - let's try using padding bytes as coordinate data
-			// Format 2: If padding bytes are non-zero, maybe data is shifted
-			// Try: x.C0 from [0:48] (including padding), x.C1 from [64:112], y.C0 from [128:176], y.C1 from [192:240]
-			// This assumes data might be in a mixed format where some fields use padding bytes
-			xC0BytesAlt2 := data[0:48]    // First 48 bytes (including padding)
-			xC1BytesAlt2 := data[64:112]  // Second field, first 48 bytes (skip padding)
-			yC0BytesAlt2 := data[128:176] // Third field, first 48 bytes (skip padding)
-			yC1BytesAlt2 := data[192:240] // Fourth field, first 48 bytes (skip padding)
-
-			// Try Format 1 first (compact)
-			xC0BytesAlt := xC0BytesAlt1
-			xC1BytesAlt := xC1BytesAlt1
-			yC0BytesAlt := yC0BytesAlt1
-			yC1BytesAlt := yC1BytesAlt1
-
-			// Construct compressed format from alternative location
-			compressedAlt := make([]byte, 96)
-			copy(compressedAlt[0:48], xC1BytesAlt)  // x.C1 (first 48 bytes)
-			copy(compressedAlt[48:96], xC0BytesAlt) // x.C0 (next 48 bytes)
-
-			// Clear flag bits and set compression flag
-			compressedAlt[0] &= 0x1F
-			compressedAlt[0] |= 0x80
-
-			// Determine sort flag based on y coordinate
-			yBytesAlt := append(yC1BytesAlt, yC0BytesAlt...)
-			if isLexicographicallyLargestFp2(yBytesAlt) {
-				compressedAlt[0] |= 0x20
-			}
-
-			// Try parsing with Format 1 (compact)
-			fmt.Fprintf(os.Stderr, "    Trying Format 1 (compact): [0:48], [48:96], [96:144], [144:192]\n")
-			bytesReadAlt, errAlt := g2Point.SetBytes(compressedAlt)
-			if errAlt != nil {
-				// Try Format 2
-				fmt.Fprintf(os.Stderr, "    Format 1 failed (%v), trying Format 2 (padding bytes included)\n", errAlt)
-				compressedAlt2 := make([]byte, 96)
-				copy(compressedAlt2[0:48], xC1BytesAlt2[0:48])
-				copy(compressedAlt2[48:96], xC0BytesAlt2[0:48])
-				compressedAlt2[0] &= 0x1F
-				compressedAlt2[0] |= 0x80
-				yBytesAlt2 := append(yC1BytesAlt2[0:48], yC0BytesAlt2[0:48]...)
-				if isLexicographicallyLargestFp2(yBytesAlt2) {
-					compressedAlt2[0] |= 0x20
-				}
-
-				bytesReadAlt2, errAlt2 := g2Point.SetBytes(compressedAlt2)
-				if errAlt2 != nil {
-					return bls.G2Affine{}, fmt.Errorf("failed to parse G2 point from compressed format (tried standard and 2 alternative formats): "+
-						"standard=%v, alt1(compact)=%v, alt2(mixed)=%v. "+
-						"Input: [x.C1(%d), x.C0(%d), y.C1(%d), y.C0(%d)] = %d bytes. "+
-						"Standard compressed: %x (first 16 bytes), "+
-						"Alt1 compressed: %x (first 16 bytes), "+
-						"Alt2 compressed: %x (first 16 bytes)",
-						err, errAlt, errAlt2, len(xC1Bytes), len(xC0Bytes), len(yC1Bytes), len(yC0Bytes), 256,
-						compressed[:16], compressedAlt[:16], compressedAlt2[:16])
-				}
-				bytesReadAlt = bytesReadAlt2
-				errAlt = nil
-				fmt.Fprintf(os.Stderr, "    Format 2 succeeded\n")
-			} else {
-				fmt.Fprintf(os.Stderr, "    Format 1 (compact) succeeded\n")
-			}
-			if bytesReadAlt != 96 {
-				return bls.G2Affine{}, fmt.Errorf("SetBytes(alternative) read %d bytes, expected 96", bytesReadAlt)
-			}
-			fmt.Fprintf(os.Stderr, "  Successfully parsed using alternative location\n")
-		} else {
-			return bls.G2Affine{}, fmt.Errorf("failed to parse G2 point from compressed format: %v. "+
-				"Input: [x.C1(%d), x.C0(%d), y.C1(%d), y.C0(%d)] = %d bytes. "+
-				"Compressed format: %x (first 16 bytes)",
-				err, len(xC1Bytes), len(xC0Bytes), len(yC1Bytes), len(yC0Bytes), 256, compressed[:16])
-		}
-	}
-	if bytesRead != 96 {
-		return bls.G2Affine{}, fmt.Errorf("SetBytes read %d bytes, expected 96", bytesRead)
-	}
-
-	// Verify the point is on the curve
-	if !g2Point.IsOnCurve() {
-		return bls.G2Affine{}, fmt.Errorf("point is not on the curve")
-	}
-
-	return g2Point, nil
-}
-
 // runEthereumVectorTest runs Ethereum test vector verification
-// Note: Ethereum format is different from pairing_gen.go's computeMultiExpFromCompressed format
+// Note: Ethereum format is different from pairing_gen.go's bls12381vec.MultiExpFromCompressed format
 // - Ethereum: 160 bytes = 128 bytes point (uncompressed) + 32 bytes scalar
 // - pairing_gen.go: compressed point (48 bytes) + scalar array
 func runEthereumVectorTest() {
@@ -1798,37 +1121,37 @@ func runEthereumVectorTest() {
 	scalarBytes := input1[128:160]
 
 	// Parse point from Ethereum format
-	g1Point, err := parseEthereumG1PointFromBytes(pointBytes)
+	g1Point, err := serialization.ParseEthereumG1PointFromBytes(pointBytes)
 	if err != nil {
 		fmt.Printf("Error parsing Ethereum G1 point: %v\n", err)
 		return
 	}
 
 	// Parse scalar from Ethereum format (big-endian)
-	scalar := parseEthereumScalarFromBytes(scalarBytes)
+	scalar := bls12381vec.ParseEthereumScalar(scalarBytes)
 
-	// Convert to compressed format for computeMultiExpFromCompressed
-	g1Compressed := convertG1AffineToCompressed(g1Point)
+	// Convert to compressed format for bls12381vec.MultiExpFromCompressed
+	g1Compressed := serialization.ConvertG1AffineToCompressed(g1Point)
 	g1CompressedHex := hex.EncodeToString(g1Compressed)
 
 	fmt.Printf("Point (Ethereum format, 128 bytes): %x\n", pointBytes)
 	fmt.Printf("Point (compressed format, 48 bytes): %s\n", g1CompressedHex)
 	fmt.Printf("Scalar: %s (0x%x)\n", scalar.String(), scalar)
 
-	// Compute MultiExp using pairing_gen.go's computeMultiExpFromCompressed
-	result, err := computeMultiExpFromCompressed(g1CompressedHex, []*big.Int{scalar}, false)
+	// Compute MultiExp using pairing_gen.go's bls12381vec.MultiExpFromCompressed
+	result, err := bls12381vec.MultiExpFromCompressed(g1CompressedHex, []*big.Int{scalar}, false)
 	if err != nil {
 		fmt.Printf("Error computing MultiExp: %v\n", err)
 		return
 	}
 
 	// Parse expected result from Ethereum format
-	expectedPoint, err := parseEthereumG1PointFromBytes(expected1)
+	expectedPoint, err := serialization.ParseEthereumG1PointFromBytes(expected1)
 	if err != nil {
 		fmt.Printf("Error parsing expected point: %v\n", err)
 		return
 	}
-	expectedCompressed := convertG1AffineToCompressed(expectedPoint)
+	expectedCompressed := serialization.ConvertG1AffineToCompressed(expectedPoint)
 	expectedCompressedHex := hex.EncodeToString(expectedCompressed)
 
 	fmt.Printf("\nResult (compressed):   %s\n", result)
@@ -1864,49 +1187,38 @@ func runEthereumVectorTest() {
 		pointBytes := input2[offset : offset+128]
 		scalarBytes := input2[offset+128 : offset+160]
 
-		point, err := parseEthereumG1PointFromBytes(pointBytes)
+		point, err := serialization.ParseEthereumG1PointFromBytes(pointBytes)
 		if err != nil {
 			fmt.Printf("Error parsing point at offset %d: %v\n", offset, err)
 			return
 		}
-		scalar := parseEthereumScalarFromBytes(scalarBytes)
+		scalar := bls12381vec.ParseEthereumScalar(scalarBytes)
 
 		points = append(points, point)
 		scalars = append(scalars, scalar)
 
-		compressed := convertG1AffineToCompressed(point)
+		compressed := serialization.ConvertG1AffineToCompressed(point)
 		fmt.Printf("  Point %d (compressed): %x\n", len(points), compressed)
 		fmt.Printf("  Scalar %d: %s (0x%x)\n", len(scalars), scalar.String(), scalar)
 	}
 
 	// Compute MultiExp: point1 × scalar1 + point2 × scalar2 + ...
-	// Note: computeMultiExpFromCompressed only handles same point with different scalars
-	// For different points, we need to compute manually
-	var resultJac bls.G1Jac
-	for i := 0; i < len(points); i++ {
-		var g1Jac bls.G1Jac
-		g1Jac.FromAffine(&points[i])
-		var tempJac bls.G1Jac
-		tempJac.ScalarMultiplication(&g1Jac, scalars[i])
-		if i == 0 {
-			resultJac.Set(&tempJac)
-		} else {
-			resultJac.AddAssign(&tempJac)
-		}
+	resultAffine, err := bls12381vec.MultiExpG1(points, scalars)
+	if err != nil {
+		fmt.Printf("Error computing MultiExp: %v\n", err)
+		return
 	}
-	var resultAffine bls.G1Affine
-	resultAffine.FromJacobian(&resultJac)
 
-	resultCompressed := convertG1AffineToCompressed(resultAffine)
+	resultCompressed := serialization.ConvertG1AffineToCompressed(resultAffine)
 	resultCompressedHex := hex.EncodeToString(resultCompressed)
 
 	// Parse expected result
-	expectedPoint2, err := parseEthereumG1PointFromBytes(expected2)
+	expectedPoint2, err := serialization.ParseEthereumG1PointFromBytes(expected2)
 	if err != nil {
 		fmt.Printf("Error parsing expected point: %v\n", err)
 		return
 	}
-	expectedCompressed2 := convertG1AffineToCompressed(expectedPoint2)
+	expectedCompressed2 := serialization.ConvertG1AffineToCompressed(expectedPoint2)
 	expectedCompressedHex2 := hex.EncodeToString(expectedCompressed2)
 
 	fmt.Printf("\nResult (compressed):   %s\n", resultCompressedHex)
@@ -1927,195 +1239,439 @@ func runEthereumVectorTest() {
 }
 
 func main() {
-	if len(os.Args) >= 2 && os.Args[1] == "ethereum-test" {
-		runEthereumVectorTest()
-		return
+	args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
 	}
+	startVerdict(append([]string{os.Args[0]}, args...))
+	defer func() {
+		if r := recover(); r != nil {
+			finishVerdict("panic", 2)
+			panic(r)
+		}
+		finishVerdict("ok", 0)
+	}()
 
-	if len(os.Args) < 2 {
-		// No arguments: run random mode with default max_scalars (G1)
-		runRandomMode(128, false)
-		return
+	if code := runCLI(args); code != 0 {
+		exit(code)
 	}
+}
 
-	// Check if first argument is "manual", "random", "ethereum", "g1add", "g2add", "g1mul", "g2mul", "pairing", "pairing-random", or "g2add-random"
-	mode := os.Args[1]
-	if mode == "g2add-random" {
-		// G2 addition random mode
-		runG2AddRandomMode()
-	} else if mode == "pairing-random" {
-		// Pairing random mode (generates test scenarios including bilinearity test)
-		runPairingRandomMode()
-	} else if mode == "pairing" {
-		// Pairing operation mode
-		pairingFlags := flag.NewFlagSet("pairing", flag.ExitOnError)
-		inputHex := pairingFlags.String("input", "", "Ethereum format input hex string (G1+G2 pairs, each pair is 384 bytes)")
-
-		if err := pairingFlags.Parse(os.Args[2:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
-			printUsage()
-			os.Exit(1)
-		}
+// runPairingRandomCommand generates random pairing scenarios (including the bilinearity test)
+func runPairingRandomCommand(args []string) error {
+	fs := newFlagSet("pairing-random")
+	gt := registerGTFormatFlags(fs, "gnark")
+	seed := registerSeedFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := gt.validate(); err != nil {
+		return err
+	}
+	if err := applySeed(*seed); err != nil {
+		return err
+	}
+	runPairingRandomMode(gt)
+	return nil
+}
+
+// runPairingCommand runs the pairing check on an Ethereum-format (or compressed) input
+func runPairingCommand(args []string) error {
+	fs := newFlagSet("pairing")
+	inputHex := registerInputFlag(fs, "Ethereum format input hex string (G1+G2 pairs, each pair is 384 bytes)")
+	profile := fs.String("profile", "neo", "Empty-input semantics profile: eip2537, neo, gnark")
+	emptyPolicy := fs.String("empty", "", "Override empty-input semantics: error or identity")
+	inputFormat := fs.String("input-format", "ethereum", "Pair layout: ethereum (128+256 bytes), compressed (48+96 bytes) or auto")
+	naive := fs.Bool("naive", false, "Compute one pairing per pair and multiply in GT instead of a single multi-Miller loop, and compare with the single-loop result")
+	chainName := registerChainFlag(fs)
+	timing := registerTimingFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	chain, err := applyChain(fs, *chainName, profile, inputFormat)
+	if err != nil {
+		return err
+	}
 
-		if *inputHex == "" {
-			fmt.Fprintf(os.Stderr, "Error: --input is required\n")
-			printUsage()
-			os.Exit(1)
+	if *inputHex == "" && !isFlagSet(fs, "input") {
+		*inputHex = promptInputOrExit()
+	}
+	if *inputHex == "" && !isFlagSet(fs, "input") {
+		return usageErrorf("--input is required")
+	}
+
+	var result, normalized string
+	recordVerdictInput(*inputHex)
+	reportInput("input", *inputHex)
+	start := time.Now()
+	if strings.TrimSpace(*inputHex) == "" {
+		// Explicit --input "": apply the profile's empty-input semantics
+		policy, perr := resolveEmptyInputPolicy(*profile, *emptyPolicy, true)
+		if perr != nil {
+			return perr
 		}
+		result, err = emptyInputResult("pairing", policy)
+	} else {
+		checkInputSanity("pairing", sanityInputFormat(*inputFormat, *inputHex), *inputHex, scalarReduce)
+		if normalized, err = normalizeInputHex("pairing", *inputFormat, *inputHex); err == nil {
+			if *naive {
+				result, err = bls12381vec.PairingNaive(normalized)
+			} else {
+				result, err = bls12381vec.Pairing(normalized)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
 
-		result, err := computePairing(*inputHex)
+	elapsed := time.Since(start)
+	if *naive && normalized != "" {
+		single, err := bls12381vec.Pairing(normalized)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("single Miller loop failed where the naive path succeeded: %v", err)
 		}
-
-		fmt.Printf("Operation: pairing\n")
-		fmt.Printf("Input length: %d hex chars\n", len(*inputHex))
-		fmt.Printf("Result (32 bytes, 64 hex chars): %s\n", result)
-		fmt.Println("This result can be compared with Neo invokescript output")
-	} else if mode == "g1add" || mode == "g2add" || mode == "g1mul" || mode == "g2mul" {
-		// Add/Mul operations mode
-		addMulFlags := flag.NewFlagSet(mode, flag.ExitOnError)
-		inputHex := addMulFlags.String("input", "", "Ethereum format input hex string")
-
-		if err := addMulFlags.Parse(os.Args[2:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
-			printUsage()
-			os.Exit(1)
+		if single != result {
+			return fmt.Errorf("naive pairing result %s differs from the single Miller loop result %s", result, single)
 		}
+		fmt.Println("Naive pair-by-pair product matches the single Miller loop")
+	}
 
-		if *inputHex == "" {
-			fmt.Fprintf(os.Stderr, "Error: --input is required\n")
-			printUsage()
-			os.Exit(1)
-		}
+	fmt.Printf("Operation: pairing\n")
+	fmt.Printf("Input length: %d hex chars\n", len(*inputHex))
+	fmt.Printf("Result (32 bytes, 64 hex chars): %s\n", result)
+	recordVerdictResult(result)
+	reportOutput("result", result)
+	timing.report("pairing", elapsed)
+	if chain != nil {
+		chain.reportGas(*chainName, "pairing", normalized)
+	} else {
+		reportEIP2537Gas("pairing", normalized)
+	}
+	fmt.Println("This result can be compared with Neo invokescript output")
+	return nil
+}
+
+// runPrecompileCommand runs one of the add/mul/MSM precompiles (g1add, g2add, g1mul,
+// g2mul, g1msm, g2msm) on an Ethereum-format input
+func runPrecompileCommand(mode string, args []string) error {
+	fs := newFlagSet(mode)
+	inputHex := registerInputFlag(fs, "Ethereum format input hex string")
+	profile := fs.String("profile", "eip2537", "Empty-input semantics profile for g1msm/g2msm: eip2537, neo, gnark")
+	emptyPolicy := fs.String("empty", "", "Override empty-input semantics for g1msm/g2msm: error or identity")
+	inputFormat := fs.String("input-format", "ethereum", "Pair layout for g1msm/g2msm: ethereum, compressed (48/96-byte point + 32-byte scalar) or auto")
+	trace := fs.Bool("trace", false, "g1mul/g2mul: print every double-and-add step and the GLV decomposition")
+	chainName := registerChainFlag(fs)
+	timing := registerTimingFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	isMSM := mode == "g1msm" || mode == "g2msm"
+	if *trace && mode != "g1mul" && mode != "g2mul" {
+		return usageErrorf("--trace is only supported for g1mul and g2mul")
+	}
+	var formatFlag *string
+	if isMSM {
+		// Only the MSMs take other layouts; add and mul stay Ethereum-format
+		formatFlag = inputFormat
+	}
+	chain, err := applyChain(fs, *chainName, profile, formatFlag)
+	if err != nil {
+		return err
+	}
+	if !isMSM && *inputFormat != "ethereum" {
+		return usageErrorf("--input-format is only supported for g1msm, g2msm and pairing")
+	}
+	if *inputHex == "" && !isFlagSet(fs, "input") {
+		*inputHex = promptInputOrExit()
+	}
+	if *inputHex == "" && !(isMSM && isFlagSet(fs, "input")) {
+		return usageErrorf("--input is required")
+	}
+
+	var result string
+	input := *inputHex
+	recordVerdictInput(*inputHex)
+	reportInput("input", *inputHex)
 
-		var result string
-		var err error
-
-		switch mode {
-		case "g1add":
-			result, err = computeG1Add(*inputHex)
-		case "g2add":
-			result, err = computeG2Add(*inputHex)
-		case "g1mul":
-			result, err = computeG1Mul(*inputHex)
-		case "g2mul":
-			result, err = computeG2Mul(*inputHex)
+	start := time.Now()
+	if isMSM && strings.TrimSpace(*inputHex) == "" {
+		// Explicit --input "": apply the profile's empty-input semantics
+		policy, perr := resolveEmptyInputPolicy(*profile, *emptyPolicy, false)
+		if perr != nil {
+			return perr
 		}
+		result, err = emptyInputResult(mode, policy)
+	} else {
+		format, scalars := "ethereum", scalarReduce
+		if isMSM {
+			format = sanityInputFormat(*inputFormat, input)
+		}
+		if chain != nil {
+			scalars = chain.Scalars
+		}
+		checkInputSanity(mode, format, input, scalars)
+		if isMSM {
+			input, err = normalizeInputHex(mode, *inputFormat, input)
+		}
+		if err == nil && chain != nil {
+			err = chain.checkInput(mode, input)
+		}
+		if err == nil {
+			switch mode {
+			case "g1add":
+				result, err = bls12381vec.G1Add(input)
+			case "g2add":
+				result, err = bls12381vec.G2Add(input)
+			case "g1mul":
+				result, err = bls12381vec.G1Mul(input)
+			case "g2mul":
+				result, err = bls12381vec.G2Mul(input)
+			case "g1msm":
+				result, err = bls12381vec.G1MSM(input)
+			case "g2msm":
+				result, err = bls12381vec.G2MSM(input)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
 
+	elapsed := time.Since(start)
+
+	fmt.Printf("Operation: %s\n", mode)
+	fmt.Printf("Input length: %d hex chars\n", len(*inputHex))
+	fmt.Printf("Result (Ethereum format, %d hex chars): %s\n", len(result), result)
+	recordVerdictResult(result)
+	reportOutput("result", result)
+	timing.report(mode, elapsed)
+	if chain != nil {
+		chain.reportGas(*chainName, mode, input)
+	} else {
+		reportEIP2537Gas(mode, input)
+	}
+	fmt.Println("This result can be compared with Neo invokescript output")
+	if *trace {
+		return traceScalarMul(mode, input, result)
+	}
+	return nil
+}
+
+// runEthereumCommand computes the MultiExp of an Ethereum-format (uncompressed) input
+func runEthereumCommand(args []string) error {
+	fs := newFlagSet("ethereum")
+	inputHex := registerInputFlag(fs, "Ethereum format input hex string")
+	useG2 := fs.Bool("use-g2", false, "Use G2 format (default: false, uses G1)")
+	verbose := fs.Bool("verbose", false, "Print a per-pair breakdown (point, scalar, running sum) as JSON lines")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *inputHex == "" {
+		*inputHex = promptInputOrExit()
+	}
+	if *inputHex == "" {
+		return usageErrorf("--input is required")
+	}
+
+	recordVerdictInput(*inputHex)
+	reportInput("input", *inputHex)
+	return runEthereumMode(*inputHex, *useG2, *verbose)
+}
+
+// runManualCommand computes the MultiExp of one compressed point and a list of scalars
+func runManualCommand(args []string) error {
+	fs := newFlagSet("manual")
+	g1Hex := fs.String("g1", "", "Compressed G1 point (96 hex chars)")
+	g2Hex := fs.String("g2", "", "Compressed G2 point (192 hex chars)")
+	scalarsStr := fs.String("scalars", "", "Comma-separated list of scalar values")
+	useG2 := fs.Bool("use-g2", false, "Use G2 point (default: false, uses G1)")
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+
+	stray, err := collectStrayArgs(fs)
+	if err != nil {
+		return usageError{err}
+	}
+	if joined, ok := recoverSplitScalars(*scalarsStr, stray); ok {
+		logging.Printf("Warning: %d extra argument(s) joined into --scalars (the shell split the list; quote it to avoid this)", len(stray))
+		*scalarsStr = joined
+	}
+
+	if *scalarsStr == "" {
+		// PowerShell-friendly fallback: read scalars from stdin line by line
+		s, err := promptScalars()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
+		*scalarsStr = s
+	}
+	if *scalarsStr == "" {
+		return usageErrorf("--scalars is required")
+	}
 
-		fmt.Printf("Operation: %s\n", mode)
-		fmt.Printf("Input length: %d hex chars\n", len(*inputHex))
-		fmt.Printf("Result (Ethereum format, %d hex chars): %s\n", len(result), result)
-		fmt.Println("This result can be compared with Neo invokescript output")
-	} else if mode == "ethereum" {
-		// Ethereum mode: parse flags
-		ethereumFlags := flag.NewFlagSet("ethereum", flag.ExitOnError)
-		inputHex := ethereumFlags.String("input", "", "Ethereum format input hex string")
-		useG2 := ethereumFlags.Bool("use-g2", false, "Use G2 format (default: false, uses G1)")
-
-		if err := ethereumFlags.Parse(os.Args[2:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
-			printUsage()
-			os.Exit(1)
-		}
+	return runManualMode(*g1Hex, *g2Hex, *scalarsStr, *useG2)
+}
 
-		if *inputHex == "" {
-			fmt.Fprintf(os.Stderr, "Error: --input is required\n")
-			printUsage()
-			os.Exit(1)
+// runRandomCommand generates one or --count random MultiExp fixtures; max_scalars may come
+// before or after the flags (e.g. "random 10 --use-g2")
+func runRandomCommand(args []string) error {
+	fs := newFlagSet("random")
+	useG2 := fs.Bool("use-g2", false, "Use G2 format (default: false, uses G1)")
+	emit := fs.String("emit", "", "Write fixtures for targets: csharp, go, go-bytes, rust, solidity, python, neo-alias, neo-debugger, gotest or all (comma-separated)")
+	registerCSharpTemplateFlag(fs)
+	emitDir := fs.String("emit-dir", "fixtures", "Output directory for --emit fixtures")
+	gt := registerGTFormatFlags(fs, "gnark")
+	scalarRangeFlag := registerScalarRangeFlag(fs)
+	seed := registerSeedFlag(fs)
+	count := fs.Int("count", 1, "Number of independent vectors to generate")
+	only := fs.Int("fixture", 0, "Regenerate only vector i (1-based) of a seeded batch; requires --seed")
+	outDir := fs.String("out-dir", "", "Write each vector to <dir>/<name>.json plus an index.json manifest instead of printing it")
+	maxScalars := 128
+
+	if err := fs.Parse(args); err != nil {
+		return usageError{err}
+	}
+	if fs.NArg() > 0 {
+		arg, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return usageErrorf("invalid max_scalars '%s' (must be a positive integer)", fs.Arg(0))
 		}
-
-		if err := runEthereumMode(*inputHex, *useG2); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+		if arg < 1 {
+			return usageErrorf("max_scalars must be at least 1, got: %d", arg)
 		}
-	} else if mode == "manual" {
-		// Manual mode: parse flags
-		manualFlags := flag.NewFlagSet("manual", flag.ExitOnError)
-		g1Hex := manualFlags.String("g1", "", "Compressed G1 point (96 hex chars)")
-		g2Hex := manualFlags.String("g2", "", "Compressed G2 point (192 hex chars)")
-		scalarsStr := manualFlags.String("scalars", "", "Comma-separated list of scalar values")
-		useG2 := manualFlags.Bool("use-g2", false, "Use G2 point (default: false, uses G1)")
-
-		if err := manualFlags.Parse(os.Args[2:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
-			printUsage()
-			os.Exit(1)
+		maxScalars = arg
+		if err := parseFlags(fs, fs.Args()[1:]); err != nil {
+			return err
 		}
-
-		if *scalarsStr == "" {
-			fmt.Fprintf(os.Stderr, "Error: --scalars is required\n")
-			printUsage()
-			os.Exit(1)
+	}
+	if *emit != "" {
+		// Validate targets before generating so a typo does not waste a run
+		if _, err := parseEmitTargets(*emit); err != nil {
+			return err
 		}
-
-		if err := runManualMode(*g1Hex, *g2Hex, *scalarsStr, *useG2); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+	}
+	if err := gt.validate(); err != nil {
+		return err
+	}
+	scalarRange, err := parseScalarRange(*scalarRangeFlag)
+	if err != nil {
+		return usageError{err}
+	}
+	if err := applySeed(*seed); err != nil {
+		return err
+	}
+	if *count < 1 {
+		return usageErrorf("--count must be at least 1, got: %d", *count)
+	}
+	if isFlagSet(fs, "fixture") {
+		switch {
+		case *seed == "":
+			return usageErrorf("--fixture requires --seed (unseeded vectors cannot be regenerated)")
+		case *only < 1:
+			return usageErrorf("--fixture must be at least 1, got: %d", *only)
+		case isFlagSet(fs, "count") && *only > *count:
+			return usageErrorf("--fixture %d is beyond --count %d", *only, *count)
 		}
-	} else if mode == "random" {
-		// Random mode with optional max_scalars argument and --use-g2 flag
-		randomFlags := flag.NewFlagSet("random", flag.ExitOnError)
-		useG2 := randomFlags.Bool("use-g2", false, "Use G2 format (default: false, uses G1)")
-		maxScalars := 128
-
-		// Parse flags first
-		if err := randomFlags.Parse(os.Args[2:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
-			printUsage()
-			os.Exit(1)
+		if *outDir != "" {
+			return usageErrorf("--out-dir writes a whole batch; regenerate the corpus without --fixture")
 		}
-
-		// Check for max_scalars as positional argument
-		if len(randomFlags.Args()) > 0 {
-			arg, err := strconv.Atoi(randomFlags.Args()[0])
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: Invalid max_scalars '%s'. Must be a positive integer\n", randomFlags.Args()[0])
-				printUsage()
-				os.Exit(1)
-			}
-			if arg < 1 {
-				fmt.Fprintf(os.Stderr, "Error: max_scalars must be at least 1, got: %d\n", arg)
-				os.Exit(1)
-			}
-			maxScalars = arg
+		return regenerateRandomVector(*only, maxScalars, *useG2, scalarRange, *seed, gt, *emit, *emitDir)
+	}
+	if *outDir != "" {
+		return writeRandomCorpus(*outDir, *count, maxScalars, *useG2, scalarRange, *seed, gt, *emit, *emitDir)
+	}
+	if *count == 1 {
+		fixture, err := runRandomMode("random", maxScalars, *useG2, scalarRange, gt)
+		if err != nil {
+			return err
 		}
-		runRandomMode(maxScalars, *useG2)
-	} else {
-		// Try to parse as max_scalars (backward compatibility)
-		// Check if there's a --use-g2 flag
-		useG2 := false
-		argsWithoutFlags := []string{}
-		for _, arg := range os.Args[1:] {
-			if arg == "--use-g2" {
-				useG2 = true
-			} else if !strings.HasPrefix(arg, "--") {
-				argsWithoutFlags = append(argsWithoutFlags, arg)
+		if *seed != "" {
+			recordReproduce(fixture.Name, randomReproduce(maxScalars, *useG2, scalarRange, *seed, 0))
+		}
+		if *emit != "" {
+			fmt.Println("\n=== Emitting Fixtures ===")
+			if err := emitFixtures(fixture, *emit, *emitDir); err != nil {
+				return err
 			}
 		}
+		return nil
+	}
 
-		if len(argsWithoutFlags) > 0 {
-			maxScalars, err := strconv.Atoi(argsWithoutFlags[0])
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: Unknown mode '%s'\n", mode)
-				printUsage()
-				os.Exit(1)
+	// Batch: every vector draws its own points and scalars from the same source (one
+	// seeded stream with --seed) and is emitted into its own subdirectory
+	fixtures := make([]multiExpFixture, 0, *count)
+	for i := 1; i <= *count; i++ {
+		name := fmt.Sprintf("random-%04d", i)
+		fmt.Printf("=== Vector %d/%d: %s ===\n", i, *count, name)
+		f, err := runRandomMode(name, maxScalars, *useG2, scalarRange, gt)
+		if err != nil {
+			return err
+		}
+		if *seed != "" {
+			recordReproduce(name, randomReproduce(maxScalars, *useG2, scalarRange, *seed, i))
+		}
+		if *emit != "" {
+			fmt.Println("\n=== Emitting Fixtures ===")
+			if err := emitFixtures(f, *emit, filepath.Join(*emitDir, name)); err != nil {
+				return err
 			}
-			if maxScalars < 1 {
-				fmt.Fprintf(os.Stderr, "Error: max_scalars must be at least 1, got: %d\n", maxScalars)
-				os.Exit(1)
+		}
+		fmt.Println()
+		fixtures = append(fixtures, f)
+	}
+	fmt.Printf("=== Batch Summary (%d vectors) ===\n", len(fixtures))
+	for _, f := range fixtures {
+		fmt.Printf("  %s: %s, %3d pairs, expected %x\n", f.Name, f.groupName(), len(f.Points), f.Expected)
+	}
+	return nil
+}
+
+// regenerateRandomVector prints (and emits) only vector i of a seeded batch. The vectors
+// share one seeded stream, so the ones before it are generated without output.
+func regenerateRandomVector(i, maxScalars int, useG2 bool, scalarRange scalarRange, seed string, gt *gtFormatOptions, emit, emitDir string) error {
+	err := quietly(func() error {
+		for j := 1; j < i; j++ {
+			if _, err := runRandomMode(fmt.Sprintf("random-%04d", j), maxScalars, useG2, scalarRange, gt); err != nil {
+				return err
 			}
-			runRandomMode(maxScalars, useG2)
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: Unknown mode '%s'\n", mode)
-			printUsage()
-			os.Exit(1)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("random-%04d", i)
+	fmt.Printf("=== Vector %d: %s ===\n", i, name)
+	f, err := runRandomMode(name, maxScalars, useG2, scalarRange, gt)
+	if err != nil {
+		return err
+	}
+	recordReproduce(name, randomReproduce(maxScalars, useG2, scalarRange, seed, i))
+	if emit != "" {
+		fmt.Println("\n=== Emitting Fixtures ===")
+		return emitFixtures(f, emit, filepath.Join(emitDir, name))
+	}
+	return nil
+}
+
+// randomReproduce is the command that regenerates a seeded random vector; i is its 1-based
+// index in a batch, or 0 for a single vector
+func randomReproduce(maxScalars int, useG2 bool, scalarRange scalarRange, seed string, i int) string {
+	args := []string{"random", strconv.Itoa(maxScalars)}
+	if useG2 {
+		args = append(args, "--use-g2")
+	}
+	if v := scalarRange.flagValue(); v != "csharp-int" {
+		args = append(args, "--scalar-range", v)
+	}
+	args = append(args, "--seed", seed)
+	if i > 0 {
+		args = append(args, "--fixture", strconv.Itoa(i))
 	}
+	return reproduceCommand(args...)
 }
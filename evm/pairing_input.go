@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+)
+
+// splitPointList splits a list of hex points separated by commas, whitespace or newlines
+func splitPointList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+}
+
+// readPointList returns the points given inline or, if path is set, read from a file
+// (one or more hex points per line; lines starting with # are ignored)
+func readPointList(inline, path string) ([]string, error) {
+	if path == "" {
+		return splitPointList(inline), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var points []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		points = append(points, splitPointList(line)...)
+	}
+	return points, nil
+}
+
+// buildPairingInput assembles the EIP-2537 pairing input (128-byte G1 + 256-byte G2 per pair)
+// from compressed points, the inverse of what bls12381vec.Pairing consumes.
+// Points are decoded strictly (flags, canonical x, on curve, subgroup).
+func buildPairingInput(g1Hexes, g2Hexes []string) ([]byte, error) {
+	if len(g1Hexes) != len(g2Hexes) {
+		return nil, fmt.Errorf("got %d G1 points and %d G2 points, counts must match", len(g1Hexes), len(g2Hexes))
+	}
+	out := make([]byte, 0, len(g1Hexes)*384)
+	for i := range g1Hexes {
+		g1Bytes, err := hex.DecodeString(strings.TrimPrefix(g1Hexes[i], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("G1 point %d: invalid hex: %v", i, err)
+		}
+		g1, err := bls12381vec.DecodeCompressedG1Point(g1Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("G1 point %d: %v", i, err)
+		}
+		g2Bytes, err := hex.DecodeString(strings.TrimPrefix(g2Hexes[i], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("G2 point %d: invalid hex: %v", i, err)
+		}
+		g2, err := bls12381vec.DecodeCompressedG2Point(g2Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("G2 point %d: %v", i, err)
+		}
+		out = append(out, serialization.EncodeEthereumG1Point(g1)...)
+		out = append(out, serialization.EncodeEthereumG2Point(g2)...)
+	}
+	return out, nil
+}
+
+// runBuildPairingInput runs the build-pairing-input mode
+func runBuildPairingInput(args []string) error {
+	fs := newFlagSet("build-pairing-input")
+	g1List := fs.String("g1", "", "Compressed G1 points (48 bytes hex each), comma separated")
+	g2List := fs.String("g2", "", "Compressed G2 points (96 bytes hex each), comma separated")
+	g1File := fs.String("g1-file", "", "File with compressed G1 points (overrides --g1)")
+	g2File := fs.String("g2-file", "", "File with compressed G2 points (overrides --g2)")
+	quiet := fs.Bool("quiet", false, "Print only the input hex")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	g1Hexes, err := readPointList(*g1List, *g1File)
+	if err != nil {
+		return fmt.Errorf("G1 points: %v", err)
+	}
+	g2Hexes, err := readPointList(*g2List, *g2File)
+	if err != nil {
+		return fmt.Errorf("G2 points: %v", err)
+	}
+	if len(g1Hexes) == 0 {
+		return fmt.Errorf("no points given (use --g1/--g2 or --g1-file/--g2-file)")
+	}
+
+	input, err := buildPairingInput(g1Hexes, g2Hexes)
+	if err != nil {
+		return err
+	}
+	inputHex := hex.EncodeToString(input)
+	recordVerdictResult(inputHex)
+	if *quiet {
+		fmt.Println(inputHex)
+		return nil
+	}
+
+	result, err := bls12381vec.Pairing(inputHex)
+	if err != nil {
+		return fmt.Errorf("pairing check on the assembled input failed: %v", err)
+	}
+	fmt.Println("=== Pairing Input (Ethereum format) ===")
+	fmt.Printf("Pairs: %d (%d bytes)\n", len(g1Hexes), len(input))
+	fmt.Printf("Input: %s\n", inputHex)
+	fmt.Printf("Pairing result: %s\n", result)
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// The pairing split into its two stages: miller-loop prints f = prod f_{x,Q_i}(P_i) and
+// final-exp raises an Fp12 element to (p^12 - 1) / r. The Miller loop value is only
+// defined up to factors the final exponentiation removes (line scaling, the sign of the
+// loop parameter), so two correct implementations can disagree on f and still agree on
+// final-exp(f). Comparing final-exp of each other's f tells a Miller loop bug from a
+// final exponentiation bug.
+
+// finalExpEasy is the easy part f^((p^6 - 1)(p^2 + 1)) of the final exponentiation
+func finalExpEasy(f *bls.GT) bls.GT {
+	var t, inv bls.GT
+	t.Conjugate(f)
+	inv.Inverse(f)
+	t.Mul(&t, &inv)
+	inv.FrobeniusSquare(&t)
+	t.Mul(&t, &inv)
+	return t
+}
+
+// runMillerLoopMode prints the Miller loop of a pairing input, per pair when there are
+// several, and checks that its final exponentiation is the pairing product
+func runMillerLoopMode(args []string) error {
+	fs := newFlagSet("miller-loop")
+	inputHex := registerInputFlag(fs, "Ethereum format input hex string (G1+G2 pairs, each pair is 384 bytes)")
+	inputFormat := fs.String("input-format", "ethereum", "Pair layout: ethereum (128+256 bytes), compressed (48+96 bytes) or auto")
+	gt := registerGTFormatFlags(fs, "neo,gnark")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := gt.validate(); err != nil {
+		return usageError{err}
+	}
+	if !isFlagSet(fs, "input") {
+		return usageErrorf("--input is required (\"\" for the empty product)")
+	}
+	normalized := ""
+	if strings.TrimSpace(*inputHex) != "" {
+		var err error
+		if normalized, err = normalizeInputHex("pairing", *inputFormat, *inputHex); err != nil {
+			return err
+		}
+	}
+	f, err := bls12381vec.MillerLoop(normalized)
+	if err != nil {
+		return err
+	}
+	pairing, err := bls12381vec.PairingGT(normalized)
+	if err != nil {
+		return err
+	}
+
+	const pairHex = 2 * 384
+	pairs := len(normalized) / pairHex
+	fmt.Println("=== Miller Loop ===")
+	fmt.Printf("Pairs: %d\n", pairs)
+	failed := false
+	if pairs > 1 {
+		var product bls.GT
+		product.SetOne()
+		for i := 0; i < pairs; i++ {
+			fi, err := bls12381vec.MillerLoop(normalized[i*pairHex : (i+1)*pairHex])
+			if err != nil {
+				return err
+			}
+			gt.print(fmt.Sprintf("Miller loop of pair %d", i), &fi)
+			product.Mul(&product, &fi)
+		}
+		if !product.Equal(&f) {
+			fmt.Println("❌ the product of the per-pair Miller loops is not the Miller loop of the input")
+			failed = true
+		} else {
+			fmt.Println("✅ the product of the per-pair Miller loops is the Miller loop of the input")
+		}
+	}
+	gt.print("Miller loop f", &f)
+	fmt.Printf("f in GT: %v (not expected before the final exponentiation)\n", f.IsInSubGroup())
+	e := bls.FinalExponentiation(&f)
+	gt.print("final-exp(f)", &e)
+	if !e.Equal(&pairing) {
+		fmt.Println("❌ final-exp(f) is not the pairing product")
+		failed = true
+	} else {
+		fmt.Println("✅ final-exp(f) is the pairing product")
+	}
+
+	neo := hex.EncodeToString(serialization.EncodeNeoGT(&f))
+	recordVerdictInput(normalized)
+	recordVerdictResult(neo)
+	reportInput("input", *inputHex)
+	reportOutput("miller_loop_neo", neo)
+	reportOutput("miller_loop_gnark", hex.EncodeToString(f.Marshal()))
+	reportOutput("final_exp_neo", hex.EncodeToString(serialization.EncodeNeoGT(&e)))
+	if failed {
+		return errChecksFailed
+	}
+	return nil
+}
+
+// runFinalExpMode raises any nonzero Fp12 element, typically a Miller loop output, to
+// (p^12 - 1) / r, printing the easy part on the way
+func runFinalExpMode(args []string) error {
+	fs := newFlagSet("final-exp")
+	a := registerGTOperand(fs, "a", "Fp12 element (576 bytes hex), e.g. a Miller loop output")
+	format := fs.String("format", "neo", "Encoding of the input: neo (tower order) or gnark; an Fp12 value is usually outside GT, so there is no auto")
+	gt := registerGTFormatFlags(fs, "neo,gnark")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := gt.validate(); err != nil {
+		return usageError{err}
+	}
+	if err := a.require(); err != nil {
+		return err
+	}
+	if *format != "neo" && *format != "gnark" {
+		return usageErrorf("--format must be neo or gnark, got '%s'", *format)
+	}
+
+	fmt.Println("=== Final Exponentiation ===")
+	f, err := a.parse(*format)
+	if err != nil {
+		return err
+	}
+	if f.IsZero() {
+		return fmt.Errorf("--a: zero is not invertible and has no final exponentiation")
+	}
+	easy := finalExpEasy(&f)
+	gt.print("Easy part f^((p^6 - 1)(p^2 + 1))", &easy)
+	e := bls.FinalExponentiation(&f)
+	reportGTResult(gt, &e)
+	fmt.Printf("Identity: %v\n", e.IsOne())
+	if !e.IsInSubGroup() {
+		fmt.Println("❌ the result is not in GT")
+		return errChecksFailed
+	}
+	fmt.Println("✅ the result is in GT")
+	recordVerdictInput(neoGTHex(&f))
+	reportInput("a", *a.hex)
+	reportOutput("easy_part_neo", neoGTHex(&easy))
+	reportOutput("identity", e.IsOne())
+	return nil
+}
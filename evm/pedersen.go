@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Pedersen commitments over G1: C = v_1 G_1 + ... + v_k G_k + r H. The generators are
+// hash-to-curve outputs of the labels G1..Gk and H under one DST, so nobody knows a
+// discrete logarithm between them and the commitment is binding as well as hiding.
+// Commitments add: C(v, r) + C(v', r') = C(v + v', r + r').
+
+const pedersenDefaultDST = "PEDERSEN_BLS12381G1_XMD:SHA-256_SSWU_RO_"
+
+// pedersenGenerators derives k value generators and the blinding generator H
+func pedersenGenerators(k int, dst string) ([]bls.G1Affine, bls.G1Affine, error) {
+	gens := make([]bls.G1Affine, k)
+	for i := range gens {
+		var err error
+		if gens[i], err = bls.HashToG1([]byte(fmt.Sprintf("G%d", i+1)), []byte(dst)); err != nil {
+			return nil, bls.G1Affine{}, err
+		}
+	}
+	h, err := bls.HashToG1([]byte("H"), []byte(dst))
+	return gens, h, err
+}
+
+// pedersenCommit is sum v_i G_i + r H
+func pedersenCommit(gens []bls.G1Affine, h bls.G1Affine, values []fr.Element, r fr.Element) (bls.G1Affine, error) {
+	var c bls.G1Affine
+	_, err := c.MultiExp(append(append([]bls.G1Affine(nil), gens...), h), append(append([]fr.Element(nil), values...), r), ecc.MultiExpConfig{})
+	return c, err
+}
+
+// pedersenMSMInput is the G1MSM input (G_i, v_i), ..., (H, r) that computes the
+// commitment on-chain
+func pedersenMSMInput(gens []bls.G1Affine, h bls.G1Affine, values []fr.Element, r fr.Element) []byte {
+	var out []byte
+	for i := range gens {
+		out = append(out, serialization.EncodeEthereumG1Point(gens[i])...)
+		out = append(out, frBytes(values[i])...)
+	}
+	out = append(out, serialization.EncodeEthereumG1Point(h)...)
+	return append(out, frBytes(r)...)
+}
+
+// pedersenOpening is one claimed opening of a commitment and whether it must be accepted
+type pedersenOpening struct {
+	Name     string   `json:"name"`
+	Values   []string `json:"values"`
+	Blinding string   `json:"blinding"`
+	Valid    bool     `json:"valid"`
+}
+
+// runPedersenMode commits to the values, prints the commitment, its openings (the valid
+// one and the ones a verifier must refuse) and a homomorphic addition, with the
+// EIP-2537 G1MSM and G1ADD inputs that recompute them. Every case is checked; an
+// unexpected result is a failure.
+func runPedersenMode(args []string) (int, error) {
+	fs := newFlagSet("pedersen")
+	valueList := fs.String("values", "42", "Committed values in Fr, comma separated (one generator each)")
+	blindingStr := fs.String("blinding", "", "Blinding factor r (default: derived from --seed)")
+	addList := fs.String("add-values", "", "Values of the second commitment of the addition (default: derived from --seed)")
+	addBlindingStr := fs.String("add-blinding", "", "Blinding of the second commitment (default: derived from --seed)")
+	seedHex := fs.String("seed", "", "Seed of the defaults (hex, default: 32 random bytes)")
+	dst := fs.String("dst", pedersenDefaultDST, "Hash-to-curve DST of the generators")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	values, err := parseFrList(*valueList)
+	if err != nil {
+		return 0, usageErrorf("--values: %v", err)
+	}
+	if len(values) == 0 {
+		return 0, usageErrorf("--values needs at least one value")
+	}
+	seed, err := randomOrHex("seed", *seedHex, 32)
+	if err != nil {
+		return 0, err
+	}
+	scalarFlag := func(name, value, label string) (fr.Element, error) {
+		if value != "" {
+			e, err := parseFrElement(value)
+			if err != nil {
+				return e, usageErrorf("--%s: %v", name, err)
+			}
+			return e, nil
+		}
+		return seededFieldElement(seed, "pedersen "+label)
+	}
+	r, err := scalarFlag("blinding", *blindingStr, "blinding")
+	if err != nil {
+		return 0, err
+	}
+	r2, err := scalarFlag("add-blinding", *addBlindingStr, "second blinding")
+	if err != nil {
+		return 0, err
+	}
+	values2 := make([]fr.Element, len(values))
+	if *addList != "" {
+		if values2, err = parseFrList(*addList); err != nil {
+			return 0, usageErrorf("--add-values: %v", err)
+		}
+		if len(values2) != len(values) {
+			return 0, usageErrorf("--add-values needs %d values, got %d", len(values), len(values2))
+		}
+	} else {
+		for i := range values2 {
+			if values2[i], err = seededFieldElement(seed, fmt.Sprintf("pedersen second value %d", i)); err != nil {
+				return 0, err
+			}
+		}
+	}
+	gens, h, err := pedersenGenerators(len(values), *dst)
+	if err != nil {
+		return 0, err
+	}
+	c1, err := pedersenCommit(gens, h, values, r)
+	if err != nil {
+		return 0, err
+	}
+	c2, err := pedersenCommit(gens, h, values2, r2)
+	if err != nil {
+		return 0, err
+	}
+	sumValues := make([]fr.Element, len(values))
+	for i := range values {
+		sumValues[i].Add(&values[i], &values2[i])
+	}
+	var sumR fr.Element
+	sumR.Add(&r, &r2)
+	sum, err := pedersenCommit(gens, h, sumValues, sumR)
+	if err != nil {
+		return 0, err
+	}
+	compressed := func(p bls.G1Affine) string { return hex.EncodeToString(serialization.ConvertG1AffineToCompressed(p)) }
+	eth := func(p bls.G1Affine) string { return hex.EncodeToString(serialization.EncodeEthereumG1Point(p)) }
+	recordVerdictResult(compressed(c1))
+
+	fmt.Printf("=== Pedersen Commitment (G1, %d value(s)) ===\n", len(values))
+	fmt.Printf("DST: %s\n", *dst)
+	for i := range gens {
+		fmt.Printf("G%d: %s\n", i+1, compressed(gens[i]))
+	}
+	fmt.Printf("H: %s\n", compressed(h))
+	printFrList("Values", values)
+	printFrValue("Blinding", r)
+	fmt.Printf("Commitment: %s\n", compressed(c1))
+	fmt.Printf("Commitment (Ethereum): %s\n", eth(c1))
+	msm := pedersenMSMInput(gens, h, values, r)
+	fmt.Printf("G1MSM input (0x0c): %s\n", hex.EncodeToString(msm))
+	fmt.Println()
+
+	failed := 0
+	check := func(ok bool, name, detail string) {
+		if !ok {
+			fmt.Printf("❌ %-24s %s\n", name, detail)
+			failed++
+			return
+		}
+		fmt.Printf("✅ %-24s %s\n", name, detail)
+	}
+	if out, err := eip2537MSM(false)(msm); err != nil || !bytes.Equal(out, serialization.EncodeEthereumG1Point(c1)) {
+		check(false, "g1msm", fmt.Sprintf("the G1MSM input gives %x, %v", out, err))
+	} else {
+		check(true, "g1msm", "the G1MSM input gives the commitment")
+	}
+
+	// openings: the valid one, then a wrong value, a wrong blinding and swapped roles
+	var one fr.Element
+	one.SetOne()
+	wrongValue := append([]fr.Element(nil), values...)
+	wrongValue[0].Add(&wrongValue[0], &one)
+	var wrongR fr.Element
+	wrongR.Add(&r, &one)
+	swapped := append([]fr.Element{r}, values[1:]...)
+	openings := []struct {
+		name   string
+		values []fr.Element
+		r      fr.Element
+		valid  bool
+	}{
+		{"valid", values, r, true},
+		{"wrong-value", wrongValue, r, false},
+		{"wrong-blinding", values, wrongR, false},
+		{"swapped-roles", swapped, values[0], false},
+	}
+	if values[0].Equal(&r) {
+		// swapping a value and the blinding changes nothing
+		openings = openings[:3]
+	}
+	var openingJSON []pedersenOpening
+	for _, o := range openings {
+		c, err := pedersenCommit(gens, h, o.values, o.r)
+		if err != nil {
+			return 0, err
+		}
+		opens := c.Equal(&c1)
+		expected := "opens the commitment"
+		if !o.valid {
+			expected = "does not open the commitment"
+		}
+		check(opens == o.valid, "opening "+o.name, expected)
+		openingJSON = append(openingJSON, pedersenOpening{Name: o.name, Values: frStrings(o.values), Blinding: o.r.String(), Valid: o.valid})
+	}
+
+	// homomorphic addition
+	var added bls.G1Affine
+	added.Add(&c1, &c2)
+	addInput := append(serialization.EncodeEthereumG1Point(c1), serialization.EncodeEthereumG1Point(c2)...)
+	fmt.Println()
+	printFrList("Second values", values2)
+	printFrValue("Second blinding", r2)
+	fmt.Printf("Second commitment: %s\n", compressed(c2))
+	fmt.Printf("Sum commitment: %s\n", compressed(sum))
+	fmt.Printf("G1ADD input (0x0b): %s\n", hex.EncodeToString(addInput))
+	check(added.Equal(&sum), "addition", "C(v, r) + C(v', r') = C(v + v', r + r')")
+	if out, err := eip2537Add(false)(addInput); err != nil || !bytes.Equal(out, serialization.EncodeEthereumG1Point(sum)) {
+		check(false, "g1add", fmt.Sprintf("the G1ADD input gives %x, %v", out, err))
+	} else {
+		check(true, "g1add", "the G1ADD input gives the sum commitment")
+	}
+	var negC2, diff bls.G1Affine
+	negC2.Neg(&c2)
+	diff.Add(&sum, &negC2)
+	check(diff.Equal(&c1), "subtraction", "C(v + v', r + r') - C(v', r') = C(v, r)")
+
+	generators := make([]string, len(gens))
+	for i := range gens {
+		generators[i] = compressed(gens[i])
+	}
+	reportOutput("dst", *dst)
+	reportOutput("generators", generators)
+	reportOutput("h", compressed(h))
+	reportOutput("commitment", compressed(c1))
+	reportOutput("g1msm_input", hex.EncodeToString(msm))
+	reportOutput("openings", openingJSON)
+	reportOutput("addition", map[string]any{
+		"values":       frStrings(values2),
+		"blinding":     r2.String(),
+		"commitment":   compressed(c2),
+		"sum_values":   frStrings(sumValues),
+		"sum_blinding": sumR.String(),
+		"sum":          compressed(sum),
+		"g1add_input":  hex.EncodeToString(addInput),
+	})
+	return failed, nil
+}
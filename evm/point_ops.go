@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// Negation, doubling and subtraction, the operations g1add / g2add leave out. Operands
+// are taken in any point encoding (compressed, uncompressed or Ethereum) and results are
+// printed compressed and in Ethereum format, so a composite case such as P + (-P) = O
+// can be assembled for either the precompiles or Neo's CryptoLib. Each result is checked
+// against the EIP-2537 addition.
+
+// neg is -P, the point (x, -y); infinity stays infinity
+func (c curvePoint) neg() curvePoint {
+	if c.g2 {
+		c.p2.Neg(&c.p2)
+	} else {
+		c.p1.Neg(&c.p1)
+	}
+	return c
+}
+
+// double is 2P with gnark's doubling formula rather than the addition formula
+func (c curvePoint) double() curvePoint {
+	if c.g2 {
+		var j bls.G2Jac
+		j.FromAffine(&c.p2)
+		c.p2.FromJacobian(j.DoubleAssign())
+	} else {
+		var j bls.G1Jac
+		j.FromAffine(&c.p1)
+		c.p1.FromJacobian(j.DoubleAssign())
+	}
+	return c
+}
+
+// parsePointOperand decodes one operand of a point operation in the group of the mode
+func parsePointOperand(name, s, format string, g2, subgroupCheck bool) (curvePoint, string, error) {
+	c := newCurvePoint(g2)
+	data, err := decodeHexFlag(name, s)
+	if err != nil {
+		return c, "", err
+	}
+	kind, err := convertInputKind(data, format, strings.ToLower(c.group()))
+	if err != nil {
+		return c, "", fmt.Errorf("--%s: %v", name, err)
+	}
+	if c.p1, c.p2, err = decodePointKind(kind, data, subgroupCheck); err != nil {
+		return c, "", fmt.Errorf("--%s: invalid %s: %v", name, pointKindInfo[kind].Description, err)
+	}
+	return c, kind, nil
+}
+
+// runPointOpMode runs g1neg, g2neg, g1double, g2double, g1sub or g2sub
+func runPointOpMode(mode string, args []string) error {
+	g2 := mode[1] == '2'
+	op := mode[2:]
+	fs := newFlagSet(mode)
+	pointHex := fs.String("point", "", "Point hex (neg, double)")
+	aHex := fs.String("a", "", "Minuend hex (sub)")
+	bHex := fs.String("b", "", "Subtrahend hex (sub)")
+	format := fs.String("format", "auto", "Input format: auto (by length and flags), "+strings.Join(pointFormats, ", "))
+	noSubgroupCheck := fs.Bool("no-subgroup-check", false, "Accept points on the curve outside the prime-order subgroup")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	names := []string{"point"}
+	values := []string{*pointHex}
+	if op == "sub" {
+		names, values = []string{"a", "b"}, []string{*aHex, *bHex}
+		if *pointHex != "" {
+			return usageErrorf("--point is for neg and double; sub takes --a and --b")
+		}
+	} else if *aHex != "" || *bHex != "" {
+		return usageErrorf("--a and --b are for sub; %s takes --point", op)
+	}
+	var operands []curvePoint
+	var kinds []string
+	for i, name := range names {
+		if values[i] == "" {
+			return usageErrorf("--%s is required", name)
+		}
+		c, kind, err := parsePointOperand(name, values[i], *format, g2, !*noSubgroupCheck)
+		if err != nil {
+			return err
+		}
+		operands = append(operands, c)
+		kinds = append(kinds, kind)
+	}
+
+	group := newCurvePoint(g2).group()
+	add := eip2537Add(g2)
+	failed := false
+	check := func(ok bool, what string) {
+		if !ok {
+			fmt.Printf("❌ %s\n", what)
+			failed = true
+			return
+		}
+		fmt.Printf("✅ %s\n", what)
+	}
+	show := func(label string, c curvePoint) {
+		fmt.Printf("%s (infinity: %v, in subgroup: %v):\n", label, c.isInfinity(), c.inSubgroup())
+		fmt.Printf("  compressed: %x\n", c.compressed())
+		fmt.Printf("  ethereum:   %x\n", c.ethereum())
+	}
+	// sum is the EIP-2537 addition of two points, Ethereum-encoded
+	sum := func(x, y curvePoint) (string, error) {
+		out, err := add(append(x.ethereum(), y.ethereum()...))
+		return hex.EncodeToString(out), err
+	}
+
+	fmt.Printf("=== %s %s ===\n", group, map[string]string{"neg": "Negation", "double": "Doubling", "sub": "Subtraction"}[op])
+	for i, name := range names {
+		show(fmt.Sprintf("Input %s, %s", name, pointKindInfo[kinds[i]].Description), operands[i])
+	}
+	fmt.Println()
+
+	p := operands[0]
+	var result curvePoint
+	// addInput is a g1add / g2add input built from the operands: P + (-P) for neg and
+	// the input with the same result for double and sub
+	var addInput []byte
+	switch op {
+	case "neg":
+		result = p.neg()
+		addInput = append(p.ethereum(), result.ethereum()...)
+		show("-P", result)
+		zero, err := sum(p, result)
+		if err != nil {
+			return err
+		}
+		check(zero == hex.EncodeToString(newCurvePoint(g2).ethereum()), "P + (-P) = infinity")
+		check(hex.EncodeToString(result.neg().ethereum()) == hex.EncodeToString(p.ethereum()), "-(-P) = P")
+	case "double":
+		result = p.double()
+		addInput = append(p.ethereum(), p.ethereum()...)
+		show("2P", result)
+		twice, err := sum(p, p)
+		if err != nil {
+			return err
+		}
+		check(twice == hex.EncodeToString(result.ethereum()), "2P = P + P")
+	case "sub":
+		q := operands[1]
+		result = p.add(q.neg())
+		addInput = append(p.ethereum(), q.neg().ethereum()...)
+		show("-B", q.neg())
+		show("A - B", result)
+		viaAdd, err := sum(p, q.neg())
+		if err != nil {
+			return err
+		}
+		check(viaAdd == hex.EncodeToString(result.ethereum()), "A - B = A + (-B)")
+		back, err := sum(result, q)
+		if err != nil {
+			return err
+		}
+		check(back == hex.EncodeToString(p.ethereum()), "(A - B) + B = A")
+	}
+	fmt.Printf("%sadd input (%s): %x\n", strings.ToLower(group),
+		map[string]string{"neg": "P + (-P)", "double": "P + P", "sub": "A + (-B)"}[op], addInput)
+
+	var input []byte
+	for _, c := range operands {
+		input = append(input, c.ethereum()...)
+	}
+	recordVerdictInput(hex.EncodeToString(input))
+	recordVerdictResult(hex.EncodeToString(result.ethereum()))
+	for i, name := range names {
+		reportInput(name, values[i])
+	}
+	reportOutput("compressed", hex.EncodeToString(result.compressed()))
+	reportOutput("ethereum", hex.EncodeToString(result.ethereum()))
+	reportOutput("infinity", result.isInfinity())
+	reportOutput("add_input", hex.EncodeToString(addInput))
+	if failed {
+		return errChecksFailed
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Polynomials over Fr are coefficient slices, lowest degree first: p(X) = c[0] + c[1]*X + ...
+
+// parseFrElement parses a decimal or 0x-prefixed hex value and reduces it modulo r
+func parseFrElement(s string) (fr.Element, error) {
+	var e fr.Element
+	s = strings.TrimSpace(s)
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+		base = 16
+	}
+	v, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return e, fmt.Errorf("invalid Fr value '%s'", s)
+	}
+	e.SetBigInt(v)
+	return e, nil
+}
+
+// parseFrList parses a comma-separated list of Fr values
+func parseFrList(s string) ([]fr.Element, error) {
+	var out []fr.Element
+	for i, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		e, err := parseFrElement(part)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %v", i, err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// polyEval evaluates p at z using Horner's rule
+func polyEval(p []fr.Element, z fr.Element) fr.Element {
+	var acc fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		acc.Mul(&acc, &z)
+		acc.Add(&acc, &p[i])
+	}
+	return acc
+}
+
+// polyDivideLinear divides p by (X - z) with synthetic division, returning the quotient
+// q and the remainder p(z), so that p(X) = q(X)*(X - z) + p(z). This is the KZG opening
+// quotient when applied to p(X) - p(z).
+func polyDivideLinear(p []fr.Element, z fr.Element) ([]fr.Element, fr.Element) {
+	if len(p) == 0 {
+		return nil, fr.Element{}
+	}
+	q := make([]fr.Element, len(p)-1)
+	var carry fr.Element
+	for i := len(p) - 1; i >= 1; i-- {
+		carry.Mul(&carry, &z)
+		carry.Add(&carry, &p[i])
+		q[i-1] = carry
+	}
+	var remainder fr.Element
+	remainder.Mul(&carry, &z)
+	remainder.Add(&remainder, &p[0])
+	return q, remainder
+}
+
+// polyInterpolate returns the unique polynomial of degree < len(xs) through the points
+// (xs[i], ys[i]) using Lagrange interpolation. The xs must be distinct.
+func polyInterpolate(xs, ys []fr.Element) ([]fr.Element, error) {
+	if len(xs) != len(ys) {
+		return nil, fmt.Errorf("got %d x values and %d y values", len(xs), len(ys))
+	}
+	if len(xs) == 0 {
+		return nil, fmt.Errorf("at least one point is required")
+	}
+	n := len(xs)
+	result := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		// basis(X) = prod_{j != i} (X - x_j), denominator = prod_{j != i} (x_i - x_j)
+		basis := []fr.Element{fr.One()}
+		denominator := fr.One()
+		for j := 0; j < n; j++ {
+			if j == i {
+				continue
+			}
+			if xs[i].Equal(&xs[j]) {
+				return nil, fmt.Errorf("duplicate x value at indices %d and %d", i, j)
+			}
+			next := make([]fr.Element, len(basis)+1)
+			for k := range basis {
+				var t fr.Element
+				t.Mul(&basis[k], &xs[j])
+				next[k].Sub(&next[k], &t)
+				next[k+1].Add(&next[k+1], &basis[k])
+			}
+			basis = next
+			var diff fr.Element
+			diff.Sub(&xs[i], &xs[j])
+			denominator.Mul(&denominator, &diff)
+		}
+		var factor fr.Element
+		factor.Inverse(&denominator)
+		factor.Mul(&factor, &ys[i])
+		for k := range basis {
+			var t fr.Element
+			t.Mul(&basis[k], &factor)
+			result[k].Add(&result[k], &t)
+		}
+	}
+	return result, nil
+}
+
+// printFrList prints Fr values in decimal and as 32-byte big-endian hex
+func printFrList(label string, values []fr.Element) {
+	fmt.Printf("%s (%d):\n", label, len(values))
+	for i := range values {
+		b := values[i].Bytes()
+		fmt.Printf("  [%d] %s (0x%x)\n", i, values[i].String(), b)
+	}
+}
+
+// printFrValue prints one Fr value in decimal and as 32-byte big-endian hex
+func printFrValue(label string, v fr.Element) {
+	b := v.Bytes()
+	fmt.Printf("%s: %s (0x%x)\n", label, v.String(), b)
+}
+
+// runPolyMode runs poly-eval, poly-interpolate and poly-divide
+func runPolyMode(mode string, args []string) error {
+	fs := newFlagSet(mode)
+	coeffsStr := fs.String("coeffs", "", "Comma-separated coefficients, lowest degree first (decimal or 0x hex)")
+	zStr := fs.String("z", "", "Evaluation / division point")
+	xsStr := fs.String("xs", "", "Comma-separated x values (poly-interpolate)")
+	ysStr := fs.String("ys", "", "Comma-separated y values (poly-interpolate)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch mode {
+	case "poly-eval", "poly-divide":
+		if *coeffsStr == "" || *zStr == "" {
+			return fmt.Errorf("--coeffs and --z are required")
+		}
+		coeffs, err := parseFrList(*coeffsStr)
+		if err != nil {
+			return fmt.Errorf("--coeffs: %v", err)
+		}
+		z, err := parseFrElement(*zStr)
+		if err != nil {
+			return fmt.Errorf("--z: %v", err)
+		}
+		printFrList("Coefficients", coeffs)
+		printFrValue("z", z)
+		if mode == "poly-eval" {
+			printFrValue("p(z)", polyEval(coeffs, z))
+			return nil
+		}
+		quotient, remainder := polyDivideLinear(coeffs, z)
+		fmt.Println("p(X) = q(X) * (X - z) + p(z)")
+		printFrList("Quotient q(X)", quotient)
+		printFrValue("Remainder p(z)", remainder)
+
+	case "poly-interpolate":
+		if *xsStr == "" || *ysStr == "" {
+			return fmt.Errorf("--xs and --ys are required")
+		}
+		xs, err := parseFrList(*xsStr)
+		if err != nil {
+			return fmt.Errorf("--xs: %v", err)
+		}
+		ys, err := parseFrList(*ysStr)
+		if err != nil {
+			return fmt.Errorf("--ys: %v", err)
+		}
+		coeffs, err := polyInterpolate(xs, ys)
+		if err != nil {
+			return err
+		}
+		printFrList("Coefficients", coeffs)
+		for i := range xs {
+			if got := polyEval(coeffs, xs[i]); !got.Equal(&ys[i]) {
+				return fmt.Errorf("interpolation check failed at point %d", i)
+			}
+		}
+		fmt.Println("✅ Polynomial passes through every point")
+
+	default:
+		return fmt.Errorf("unknown polynomial mode '%s'", mode)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"evm/bls12381vec"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// presetCase describes one deterministic MultiExp fixture. Points are multiples of the
+// generator (0 is infinity, negative values negate), so every run produces the same bytes.
+type presetCase struct {
+	Name           string
+	UseG2          bool
+	PointMultiples []int64
+	Scalars        []string // decimal; "r-1" style expressions are resolved by presetScalar
+}
+
+// fixturePreset is a named, well-defined suite selectable with --preset
+type fixturePreset struct {
+	Description string
+	Cases       []presetCase
+}
+
+// fixturePresets lists the standard suites
+var fixturePresets = map[string]fixturePreset{
+	"neo-basic": {
+		Description: "Small Neo MultiExp vectors: single pair, several pairs, G1 and G2",
+		Cases: []presetCase{
+			{"g1_single", false, []int64{1}, []string{"1"}},
+			{"g1_three_points", false, []int64{1, 2, 3}, []string{"1", "2", "3"}},
+			{"g1_int_max", false, []int64{1, 7}, []string{"2147483647", "123456789"}},
+			{"g2_single", true, []int64{1}, []string{"1"}},
+			{"g2_three_points", true, []int64{1, 2, 3}, []string{"1", "2", "3"}},
+			{"g2_int_max", true, []int64{1, 7}, []string{"2147483647", "123456789"}},
+		},
+	},
+	"neo-edge": {
+		Description: "Neo MultiExp edge cases: zero scalars, infinity, cancellation, r-1, duplicates",
+		Cases: []presetCase{
+			{"g1_zero_scalar", false, []int64{1, 2}, []string{"0", "5"}},
+			{"g1_all_zero_scalars", false, []int64{1, 2}, []string{"0", "0"}},
+			{"g1_infinity_point", false, []int64{0, 3}, []string{"9", "4"}},
+			{"g1_cancellation", false, []int64{1, -1}, []string{"11", "11"}},
+			{"g1_scalar_r_minus_1", false, []int64{1}, []string{"r-1"}},
+			{"g1_duplicate_points", false, []int64{5, 5, 5}, []string{"1", "2", "3"}},
+			{"g2_zero_scalar", true, []int64{1, 2}, []string{"0", "5"}},
+			{"g2_infinity_point", true, []int64{0, 3}, []string{"9", "4"}},
+			{"g2_cancellation", true, []int64{1, -1}, []string{"11", "11"}},
+			{"g2_scalar_r_minus_1", true, []int64{1}, []string{"r-1"}},
+		},
+	},
+	"msm-duplicates": {
+		Description: "MSM with exact duplicate points and duplicate point/scalar pairs (bucket-method edge cases)",
+		Cases: []presetCase{
+			{"g1_same_point_distinct_scalars", false, []int64{5, 5, 5, 5, 5, 5, 5, 5}, []string{"1", "2", "3", "4", "5", "6", "7", "8"}},
+			{"g1_duplicate_pair", false, []int64{3, 3}, []string{"7", "7"}},
+			{"g1_duplicate_pairs_interleaved", false, []int64{1, 2, 1, 2, 1, 2}, []string{"9", "4", "9", "4", "9", "4"}},
+			{"g1_duplicates_sum_to_r", false, []int64{4, 4}, []string{"1", "r-1"}},
+			{"g1_duplicates_with_negation", false, []int64{6, -6, 6}, []string{"5", "5", "5"}},
+			{"g1_duplicate_pair_r_minus_1", false, []int64{2, 2, 2, 2}, []string{"r-1", "r-1", "r-1", "r-1"}},
+			repeatedPresetCase("g1_duplicate_pair_x64", false, 11, "r-2", 64),
+			{"g2_same_point_distinct_scalars", true, []int64{5, 5, 5, 5, 5, 5, 5, 5}, []string{"1", "2", "3", "4", "5", "6", "7", "8"}},
+			{"g2_duplicate_pair", true, []int64{3, 3}, []string{"7", "7"}},
+			{"g2_duplicate_pairs_interleaved", true, []int64{1, 2, 1, 2, 1, 2}, []string{"9", "4", "9", "4", "9", "4"}},
+			{"g2_duplicates_sum_to_r", true, []int64{4, 4}, []string{"1", "r-1"}},
+			{"g2_duplicates_with_negation", true, []int64{6, -6, 6}, []string{"5", "5", "5"}},
+			repeatedPresetCase("g2_duplicate_pair_x64", true, 11, "r-2", 64),
+		},
+	},
+	"eip2537-smoke": {
+		Description: "Minimal EIP-2537 G1MSM/G2MSM smoke vectors (generator multiples, small scalars)",
+		Cases: []presetCase{
+			{"g1msm_generator", false, []int64{1}, []string{"1"}},
+			{"g1msm_two_pairs", false, []int64{1, 2}, []string{"2", "3"}},
+			{"g1msm_large_scalar", false, []int64{1}, []string{"r-1"}},
+			{"g2msm_generator", true, []int64{1}, []string{"1"}},
+			{"g2msm_two_pairs", true, []int64{1, 2}, []string{"2", "3"}},
+			{"g2msm_large_scalar", true, []int64{1}, []string{"r-1"}},
+		},
+	},
+}
+
+// repeatedPresetCase is n copies of one point/scalar pair. With a full-width scalar every
+// bucket of a bucket-method MSM receives the same point repeatedly, which needs a doubling
+// where the batched additions of distinct points never do.
+func repeatedPresetCase(name string, useG2 bool, multiple int64, scalar string, n int) presetCase {
+	c := presetCase{Name: name, UseG2: useG2}
+	for i := 0; i < n; i++ {
+		c.PointMultiples = append(c.PointMultiples, multiple)
+		c.Scalars = append(c.Scalars, scalar)
+	}
+	return c
+}
+
+func fixturePresetNames() []string {
+	names := make([]string, 0, len(fixturePresets))
+	for name := range fixturePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// presetScalar resolves a scalar expression: a decimal value or "r-N"
+func presetScalar(s string) (*big.Int, error) {
+	if strings.HasPrefix(s, "r-") {
+		n, ok := new(big.Int).SetString(s[2:], 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid scalar expression '%s'", s)
+		}
+		return new(big.Int).Sub(fr.Modulus(), n), nil
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid scalar '%s'", s)
+	}
+	return v, nil
+}
+
+// buildPresetFixture computes the fixture for one preset case
+func buildPresetFixture(c presetCase) (multiExpFixture, error) {
+	if len(c.PointMultiples) != len(c.Scalars) {
+		return multiExpFixture{}, fmt.Errorf("case %s: %d points and %d scalars", c.Name, len(c.PointMultiples), len(c.Scalars))
+	}
+	_, _, g1Gen, g2Gen := bls.Generators()
+	scalars := make([]*big.Int, len(c.Scalars))
+	g1Points := make([]bls.G1Affine, len(c.PointMultiples))
+	g2Points := make([]bls.G2Affine, len(c.PointMultiples))
+	for i, k := range c.PointMultiples {
+		s, err := presetScalar(c.Scalars[i])
+		if err != nil {
+			return multiExpFixture{}, fmt.Errorf("case %s: %v", c.Name, err)
+		}
+		scalars[i] = s
+		m := big.NewInt(k)
+		if c.UseG2 {
+			g2Points[i].ScalarMultiplication(&g2Gen, m)
+		} else {
+			g1Points[i].ScalarMultiplication(&g1Gen, m)
+		}
+	}
+	f, err := newMultiExpFixture(c.Name, g1Points, g2Points, scalars, c.UseG2)
+	if err != nil {
+		return f, err
+	}
+	if err := checkFixtureMSM(f); err != nil {
+		return f, fmt.Errorf("case %s: %v", c.Name, err)
+	}
+	return f, nil
+}
+
+// checkFixtureMSM recomputes the fixture with the EIP-2537 MSM and with the naive
+// pair-by-pair loop; the bucket-method result the fixture was built with must match both
+func checkFixtureMSM(f multiExpFixture) error {
+	msm := bls12381vec.G1MSM
+	if f.UseG2 {
+		msm = bls12381vec.G2MSM
+	}
+	input := hex.EncodeToString(f.EthereumInput)
+	got, err := msm(input)
+	if err != nil {
+		return fmt.Errorf("MSM rejected the fixture input: %v", err)
+	}
+	naive, err := bls12381vec.NaiveMSM(input, f.UseG2)
+	if err != nil {
+		return fmt.Errorf("naive MSM rejected the fixture input: %v", err)
+	}
+	if want := hex.EncodeToString(f.ExpectedEthereum); got != want || naive != want {
+		return fmt.Errorf("MSM result %s and naive loop %s differ from the fixture result %s", got, naive, want)
+	}
+	return nil
+}
+
+// printMultiExpFixture prints a fixture in the same representations the emitters use
+func printMultiExpFixture(f multiExpFixture) {
+	fmt.Printf("Fixture: %s (%s MultiExp, %d pairs)\n", f.Name, f.groupName(), len(f.Points))
+	for i, p := range f.Points {
+		fmt.Printf("  Point[%d]: %x  Scalar[%d]: %s\n", i, p, i, f.Scalars[i].String())
+	}
+	fmt.Printf("  Ethereum input: %x\n", f.EthereumInput)
+	fmt.Printf("  Expected (compressed): %x\n", f.Expected)
+	fmt.Printf("  Expected (Ethereum): %x\n", f.ExpectedEthereum)
+}
+
+// runPresetMode generates every fixture of a named preset, optionally emitting each one
+// into its own subdirectory of --emit-dir
+func runPresetMode(args []string) error {
+	fs := newFlagSet("preset")
+	name := fs.String("preset", "", "Preset to generate ("+strings.Join(fixturePresetNames(), ", ")+", or list)")
+	emit := fs.String("emit", "", "Write fixtures for targets: csharp, go, go-bytes, rust, solidity, python, neo-alias, neo-debugger, gotest or all (comma-separated)")
+	registerCSharpTemplateFlag(fs)
+	emitDir := fs.String("emit-dir", "fixtures", "Output directory for --emit fixtures (one subdirectory per case)")
+	caseName := fs.String("case", "", "Generate only the case with this name")
+	timing := registerTimingFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || *name == "list" {
+		fmt.Println("Available presets:")
+		for _, n := range fixturePresetNames() {
+			fmt.Printf("  %-15s %s (%d fixtures)\n", n, fixturePresets[n].Description, len(fixturePresets[n].Cases))
+		}
+		return nil
+	}
+	if _, ok := fixturePresets[*name]; !ok {
+		return fmt.Errorf("unknown preset '%s' (valid: %s)", *name, strings.Join(fixturePresetNames(), ", "))
+	}
+	if *emit != "" {
+		if _, err := parseEmitTargets(*emit); err != nil {
+			return err
+		}
+	}
+	if *caseName != "" {
+		c, ok := findPresetCase(*name, *caseName)
+		if !ok {
+			return fmt.Errorf("preset %s has no case '%s'", *name, *caseName)
+		}
+		if _, _, err := generatePresetCase(*name, c, *emit, *emitDir, timing, presetReproduce(*name, c.Name)); err != nil {
+			return err
+		}
+	} else if err := generatePreset(*name, *emit, *emitDir, timing); err != nil {
+		return err
+	}
+	timing.printSummary()
+	return nil
+}
+
+// findPresetCase looks up one case of a preset by name
+func findPresetCase(name, caseName string) (presetCase, bool) {
+	for _, c := range fixturePresets[name].Cases {
+		if c.Name == caseName {
+			return c, true
+		}
+	}
+	return presetCase{}, false
+}
+
+// presetReproduce is the command that regenerates one preset case
+func presetReproduce(name, caseName string) string {
+	return reproduceCommand("preset", name, "--case", caseName)
+}
+
+// generatePreset prints every fixture of a preset and, if emit is set, writes each one
+// into emitDir/<preset>/<case>. timing may be nil.
+func generatePreset(name, emit, emitDir string, timing *timingOptions) error {
+	preset := fixturePresets[name]
+	fmt.Printf("=== Preset: %s ===\n", name)
+	fmt.Println(preset.Description)
+	fmt.Println()
+	for _, c := range preset.Cases {
+		if _, _, err := generatePresetCase(name, c, emit, emitDir, timing, presetReproduce(name, c.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatePresetCase prints (and optionally emits) one preset fixture with the command
+// that reproduces it. It returns the fixture and the directory it was emitted to ("" without emit).
+func generatePresetCase(name string, c presetCase, emit, emitDir string, timing *timingOptions, reproduce string) (multiExpFixture, string, error) {
+	start := time.Now()
+	f, err := buildPresetFixture(c)
+	if err != nil {
+		return f, "", err
+	}
+	elapsed := time.Since(start)
+	printMultiExpFixture(f)
+	recordReproduce(f.Name, reproduce)
+	timing.report(name+"/"+f.Name, elapsed)
+	dir := ""
+	if emit != "" {
+		dir = filepath.Join(emitDir, name, f.Name)
+		if err := emitFixtures(f, emit, dir); err != nil {
+			return f, "", err
+		}
+	}
+	fmt.Println()
+	return f, dir, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Some shells (notably PowerShell) split or mangle quoted, comma-separated values, so
+// "--scalars 1, 2, 3" arrives as "--scalars 1," plus stray arguments. When a required
+// value is missing it can instead be read from stdin line by line, which needs no quoting.
+
+// stdinIsTerminal reports whether stdin is an interactive console
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// readValueLines reads lines from stdin until an empty line (interactive) or EOF (piped).
+// Blank lines before the first value and lines starting with # are skipped.
+func readValueLines(name, hint string) ([]string, error) {
+	interactive := stdinIsTerminal()
+	if interactive {
+		fmt.Fprintf(os.Stderr, "%s not given. Enter %s, one or more per line; finish with an empty line:\n", name, hint)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for {
+		if interactive {
+			fmt.Fprint(os.Stderr, "> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			if interactive && len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s from stdin: %v", name, err)
+	}
+	return lines, nil
+}
+
+// promptScalars reads scalars from stdin (comma- or whitespace-separated, any number per
+// line) and returns them as the comma-separated list --scalars expects
+func promptScalars() (string, error) {
+	lines, err := readValueLines("--scalars", "decimal scalars")
+	if err != nil {
+		return "", err
+	}
+	var scalars []string
+	for _, line := range lines {
+		scalars = append(scalars, splitPointList(line)...)
+	}
+	return strings.Join(scalars, ","), nil
+}
+
+// promptInputHex reads an --input hex string from stdin; lines are concatenated so long
+// inputs can be pasted in pieces
+func promptInputHex() (string, error) {
+	lines, err := readValueLines("--input", "the input hex")
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.TrimPrefix(strings.Join(strings.Fields(line), ""), "0x"))
+	}
+	return b.String(), nil
+}
+
+// recoverSplitScalars re-joins scalars the shell split into separate arguments
+// (e.g. "--scalars 1, 2, 3" arriving as "1," "2," "3"). It returns the combined list and
+// whether any stray arguments were absorbed.
+func recoverSplitScalars(scalarsStr string, stray []string) (string, bool) {
+	if len(stray) == 0 {
+		return scalarsStr, false
+	}
+	parts := []string{}
+	for _, s := range append([]string{scalarsStr}, stray...) {
+		parts = append(parts, splitPointList(s)...)
+	}
+	return strings.Join(parts, ","), true
+}
+
+// collectStrayArgs parses fs to the end, returning positional arguments interleaved with
+// flags instead of stopping at the first one as flag.Parse does
+func collectStrayArgs(fs *flag.FlagSet) ([]string, error) {
+	var stray []string
+	for rest := fs.Args(); len(rest) > 0; rest = fs.Args() {
+		if strings.HasPrefix(rest[0], "-") {
+			if err := fs.Parse(rest); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		stray = append(stray, rest[0])
+		if err := fs.Parse(rest[1:]); err != nil {
+			return nil, err
+		}
+	}
+	return stray, nil
+}
+
+// promptInputOrExit reads a missing --input from stdin, exiting on read errors.
+// An empty result leaves the caller's "--input is required" handling in place.
+func promptInputOrExit() string {
+	input, err := promptInputHex()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(1)
+	}
+	return input
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// divergenceRecord is one diverging input of a differential run, one JSON object per line
+// of the --failures file. Expected is optional: once a divergence is understood it can be
+// set to the correct verdict ("ok <eip2537 hex>" or "reject"), so the record also checks
+// the Go decoders without an oracle.
+type divergenceRecord struct {
+	Kind     string `json:"kind"`
+	Input    string `json:"input"`
+	Strategy string `json:"strategy,omitempty"`
+	Go       string `json:"go,omitempty"`
+	Oracle   string `json:"oracle,omitempty"`
+	OracleBy string `json:"oracle_command,omitempty"`
+	Seed     int64  `json:"seed,omitempty"`
+	Found    string `json:"found,omitempty"` // RFC 3339 time of the run
+	Expected string `json:"expected,omitempty"`
+}
+
+// appendDivergence writes one record as a JSON line
+func appendDivergence(f *os.File, r divergenceRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readDivergences reads a --failures JSONL file. The '<kind> <hex>' lines of a --corpus
+// file are accepted too, so older corpora replay without conversion. Blank lines and
+// # comments are skipped.
+func readDivergences(path string) ([]divergenceRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []divergenceRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r divergenceRecord
+		if strings.HasPrefix(line, "{") {
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+			}
+		} else {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s:%d: expected a JSON record or '<kind> <hex>'", path, n)
+			}
+			r = divergenceRecord{Kind: fields[0], Input: fields[1]}
+		}
+		size := 0
+		for _, fk := range fuzzKinds {
+			if fk.kind == r.Kind {
+				size = fk.size
+			}
+		}
+		if size == 0 {
+			return nil, fmt.Errorf("%s:%d: invalid kind '%s' (valid: g1c, g2c, g1e, g2e)", path, n, r.Kind)
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(r.Input, "0x"))
+		if err != nil || len(data) != size {
+			return nil, fmt.Errorf("%s:%d: input must be %d bytes of hex for %s", path, n, size, r.Kind)
+		}
+		r.Input = hex.EncodeToString(data)
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return records, nil
+}
+
+// verdictsAgree applies the comparison of fuzz-serialization: same decision and, when
+// both accept, the same point
+func verdictsAgree(a, b fuzzVerdict) bool {
+	return a.accept == b.accept && (!a.accept || a.point == b.point)
+}
+
+// parseExpectedVerdict parses the expected field of a record
+func parseExpectedVerdict(s string) (fuzzVerdict, error) {
+	fields := strings.SplitN(strings.TrimSpace(s), " ", 2)
+	switch fields[0] {
+	case "ok":
+		if len(fields) < 2 {
+			return fuzzVerdict{}, fmt.Errorf("expected 'ok' without a point")
+		}
+		return fuzzVerdict{accept: true, point: strings.ToLower(strings.TrimPrefix(fields[1], "0x"))}, nil
+	case "reject":
+		return fuzzVerdict{}, nil
+	}
+	return fuzzVerdict{}, fmt.Errorf("invalid expected verdict %q (want 'ok <hex>' or 'reject')", s)
+}
+
+// runReplayDivergencesMode re-runs every recorded divergence as a regression case: the Go
+// decoders must agree with the oracle and with the record's expected verdict, if any
+func runReplayDivergencesMode(args []string) (int, error) {
+	fs := newFlagSet("replay-divergences")
+	path := fs.String("file", "", "Failure records (JSONL from fuzz-serialization --failures, or a --corpus file)")
+	oracleCmd := fs.String("oracle", "gnark", "Oracle: gnark (built-in), a command speaking the fuzz-serialization line protocol, or none")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *path == "" {
+		return 0, usageErrorf("--file is required")
+	}
+	records, err := readDivergences(*path)
+	if err != nil {
+		return 0, err
+	}
+
+	var oracle fuzzOracle
+	switch *oracleCmd {
+	case "none":
+	case "gnark":
+		oracle = gnarkOracle{}
+	default:
+		o, err := newCommandOracle(*oracleCmd)
+		if err != nil {
+			return 0, err
+		}
+		oracle = o
+	}
+	if oracle != nil {
+		defer oracle.close()
+	}
+
+	fmt.Printf("=== Replay Divergences: %s (%d records, oracle %s) ===\n", *path, len(records), *oracleCmd)
+	var passed, failed, skipped int
+	for i, r := range records {
+		data, _ := hex.DecodeString(r.Input)
+		label := fmt.Sprintf("#%d %s", i+1, r.Kind)
+		if r.Strategy != "" {
+			label += " (" + r.Strategy + ")"
+		}
+		got := decodeWithGo(r.Kind, data)
+		var problems []string
+		checked := false
+		if oracle != nil {
+			want, err := oracle.decode(r.Kind, data)
+			if err != nil {
+				return failed, err
+			}
+			checked = true
+			if !verdictsAgree(got, want) {
+				problems = append(problems, fmt.Sprintf("%-9s %s", "oracle:", want))
+			}
+		}
+		if r.Expected != "" {
+			want, err := parseExpectedVerdict(r.Expected)
+			if err != nil {
+				return failed, fmt.Errorf("record %d: %v", i+1, err)
+			}
+			checked = true
+			if !verdictsAgree(got, want) {
+				problems = append(problems, fmt.Sprintf("%-9s %s", "expected:", want))
+			}
+		}
+		switch {
+		case !checked:
+			fmt.Printf("⚠️  %s: no oracle and no expected verdict\n", label)
+			skipped++
+		case len(problems) > 0:
+			fmt.Printf("❌ %s: %s\n   %-9s %s\n   %s\n", label, r.Input, "go:", got, strings.Join(problems, "\n   "))
+			failed++
+		default:
+			passed++
+		}
+	}
+	fmt.Printf("Passed: %d, failed: %d, skipped: %d\n", passed, failed, skipped)
+	reportOutput("passed", passed)
+	reportOutput("failed", failed)
+	reportOutput("skipped", skipped)
+	if failed == 0 && skipped == 0 {
+		fmt.Println("✅ No recorded divergence reproduces")
+	}
+	return failed + skipped, nil
+}
+
+// newDivergenceRecord builds the --failures record of a fuzz-serialization mismatch
+func newDivergenceRecord(m fuzzMismatch, oracle string, seed int64) divergenceRecord {
+	return divergenceRecord{
+		Kind:     m.kind,
+		Input:    hex.EncodeToString(m.data),
+		Strategy: m.strategy,
+		Go:       m.got.String(),
+		Oracle:   m.want.String(),
+		OracleBy: oracle,
+		Seed:     seed,
+		Found:    time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Every deterministic fixture carries the command that regenerates just that fixture:
+// preset cases are fixed, seeded random fixtures are fixed by the seed and their index.
+// The command is printed under the fixture, stored in the JSON document and in the
+// campaign manifest. Unseeded random fixtures have none.
+
+// reproduceCommand formats a CLI invocation, quoting arguments the shell would split
+func reproduceCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return "go run . " + strings.Join(quoted, " ")
+}
+
+// shellQuote single-quotes a word unless it only has characters the shell leaves alone
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// recordReproduce prints the reproduction command of a fixture and attaches it to the
+// fixture's entry in the JSON document
+func recordReproduce(name, command string) {
+	fmt.Printf("Reproduce: %s\n", command)
+	if report == nil {
+		return
+	}
+	for i := len(report.Fixtures) - 1; i >= 0; i-- {
+		if report.Fixtures[i].Name == name {
+			report.Fixtures[i].Reproduce = command
+			return
+		}
+	}
+}
+
+// quietly runs fn with stdout discarded and the JSON document and verdict detached, for
+// fixtures that are only generated to advance a seeded stream
+func quietly(fn func() error) error {
+	devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer devnull.Close()
+	stdout, doc, v := os.Stdout, report, verdict
+	os.Stdout, report, verdict = devnull, nil, nil
+	defer func() { os.Stdout, report, verdict = stdout, doc, v }()
+	return fn()
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/logging"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Sanity warnings flag inputs that parse, or fail with an unhelpful decoder error, but
+// look like a mistake: the other point format, the other group's length or scalars the
+// run silently reduces. They go to stderr (not at --quiet), so they never change a
+// fixture, and into the JSON document under "warnings".
+
+// Warning codes
+const (
+	warnCompressedAsEthereum = "COMPRESSED_AS_ETHEREUM"
+	warnEthereumAsCompressed = "ETHEREUM_AS_COMPRESSED"
+	warnOtherGroupLength     = "OTHER_GROUP_LENGTH"
+	warnScalarReduced        = "SCALAR_REDUCED"
+)
+
+// inputWarning is one structured sanity warning
+type inputWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// warnInput prints a sanity warning and records it in the JSON document
+func warnInput(code, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	logging.Printf("Warning [%s]: %s", code, msg)
+	if report == nil {
+		return
+	}
+	report.Warnings = append(report.Warnings, inputWarning{Code: code, Message: msg})
+}
+
+// opLayout is the byte layout of one operation's input in both point formats. Add, mul
+// and MSM are unit-sized (add: exactly one unit); pairing and the MSMs take any number.
+type opLayout struct {
+	ethereum   int    // Ethereum-format unit length
+	compressed int    // compressed-format unit length
+	scalar     bool   // each unit ends with a 32-byte scalar
+	exact      bool   // exactly one unit
+	twin       string // the same operation in the other group
+}
+
+var opLayouts = map[string]opLayout{
+	"g1add":   {256, 96, false, true, "g2add"},
+	"g2add":   {512, 192, false, true, "g1add"},
+	"g1mul":   {160, 80, true, true, "g2mul"},
+	"g2mul":   {288, 128, true, true, "g1mul"},
+	"g1msm":   {160, 80, true, false, "g2msm"},
+	"g2msm":   {288, 128, true, false, "g1msm"},
+	"pairing": {384, 144, false, false, ""},
+}
+
+// fits reports whether n bytes are a whole input of the layout in the given format
+func (l opLayout) fits(format string, n int) bool {
+	unit := l.ethereum
+	if format == "compressed" {
+		unit = l.compressed
+	}
+	if l.exact {
+		return n == unit
+	}
+	return n > 0 && n%unit == 0
+}
+
+// sanityInputFormat resolves an --input-format for the checks; auto looks at the flag
+// bit of the first byte like normalizeInputHex
+func sanityInputFormat(format, inputHex string) string {
+	data, _ := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(inputHex), "0x"))
+	if resolved, err := resolveInputFormat(format, data); err == nil {
+		return resolved
+	}
+	return format
+}
+
+// checkInputSanity warns about red flags of an op input given in format (ethereum or
+// compressed, already resolved from auto). scalars is the scalar policy of the run.
+func checkInputSanity(op, format, inputHex string, scalars scalarPolicy) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(inputHex), "0x"))
+	layout, ok := opLayouts[op]
+	if err != nil || !ok || len(data) == 0 {
+		return
+	}
+	formatName := map[string]string{"ethereum": "Ethereum-format", "compressed": "compressed"}
+	other := "ethereum"
+	if format == "ethereum" {
+		other = "compressed"
+	}
+
+	// An Ethereum-format input starts with zero padding, a compressed one with the 0x80 flag
+	switch {
+	case format == "ethereum" && data[0]&0x80 != 0 && layout.fits("compressed", len(data)):
+		hint := "pass --input-format compressed"
+		if layout.exact {
+			hint = "convert the points with 'convert --to ethereum'"
+		}
+		warnInput(warnCompressedAsEthereum, "%s input of %d bytes has the compressed flag 0x80 set and fits the compressed layout (%d bytes per unit, Ethereum: %d); %s",
+			op, len(data), layout.compressed, layout.ethereum, hint)
+		return
+	case format == "compressed" && data[0]&0x80 == 0 && layout.fits("ethereum", len(data)):
+		warnInput(warnEthereumAsCompressed, "%s input of %d bytes lacks the compressed flag 0x80 and fits the Ethereum layout (%d bytes per unit, compressed: %d); pass --input-format ethereum",
+			op, len(data), layout.ethereum, layout.compressed)
+		return
+	}
+	if !layout.fits(format, len(data)) {
+		switch {
+		case layout.twin != "" && opLayouts[layout.twin].fits(format, len(data)):
+			warnInput(warnOtherGroupLength, "%d bytes is a whole %s %s input, not a %s one; check the command or --use-g2",
+				len(data), formatName[format], layout.twin, op)
+		case layout.twin != "" && opLayouts[layout.twin].fits(other, len(data)):
+			warnInput(warnOtherGroupLength, "%d bytes is a whole %s %s input, not a %s %s one; check the command, --use-g2 and --input-format",
+				len(data), formatName[other], layout.twin, formatName[format], op)
+		}
+		return
+	}
+
+	if layout.scalar && scalars == scalarReduce {
+		unit := layout.ethereum
+		if format == "compressed" {
+			unit = layout.compressed
+		}
+		var pairs []int
+		for i := 0; (i+1)*unit <= len(data); i++ {
+			s := new(big.Int).SetBytes(data[(i+1)*unit-32 : (i+1)*unit])
+			if s.Cmp(fr.Modulus()) >= 0 {
+				pairs = append(pairs, i)
+			}
+		}
+		if len(pairs) > 0 {
+			warnInput(warnScalarReduced, "%s: scalars of pairs %v are >= r and are reduced mod r; chains with canonical scalars (--chain neo-n3) reject this input",
+				op, pairs)
+		}
+	}
+}
+
+// checkPointSanity warns when a --g1/--g2 point of a compressed-point command has the
+// other group's length or is Ethereum-encoded
+func checkPointSanity(flagName, pointHex string, useG2 bool) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(pointHex), "0x"))
+	if err != nil || len(data) == 0 {
+		return
+	}
+	want, otherLen, ethLen, group, other := 48, 96, 128, "G1", "G2"
+	if useG2 {
+		want, otherLen, ethLen, group, other = 96, 48, 256, "G2", "G1"
+	}
+	switch {
+	case len(data) == want:
+	case len(data) == otherLen && data[0]&0x80 != 0:
+		warnInput(warnOtherGroupLength, "%s has %d bytes, the length of a compressed %s point, not %s; check --use-g2", flagName, len(data), other, group)
+	case len(data) == ethLen && data[0] == 0:
+		warnInput(warnEthereumAsCompressed, "%s has %d bytes with zero padding, an Ethereum-format %s point; convert it with 'convert --to compressed'", flagName, len(data), group)
+	}
+}
+
+// checkScalarsSanity warns about scalars >= r of a command that reduces them
+func checkScalarsSanity(op string, scalars []*big.Int) {
+	var idx []int
+	for i, s := range scalars {
+		if s.Cmp(fr.Modulus()) >= 0 {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) > 0 {
+		warnInput(warnScalarReduced, "%s: scalars %v are >= r and are reduced mod r; chains with canonical scalars (--chain neo-n3) reject them",
+			op, idx)
+	}
+}
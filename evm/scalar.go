@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Scalar utilities over Fr. Ethereum encodes scalars as 32 bytes big-endian; Neo's
+// CryptoLib takes 32 bytes little-endian and C# code usually holds them as BigInteger, so
+// every result is printed in all three forms.
+
+// printScalarEncodings prints a reduced scalar in decimal, 32-byte BE and LE hex and as
+// C# BigInteger.ToByteArray() bytes, and returns the BE hex
+func printScalarEncodings(label string, e fr.Element) string {
+	v := e.BigInt(new(big.Int))
+	be := e.Bytes()
+	fmt.Printf("%s: %s\n", label, v.String())
+	fmt.Printf("  32-byte big-endian (Ethereum): %x\n", be)
+	fmt.Printf("  32-byte little-endian (Neo):   %x\n", reverseBytes(be[:]))
+	fmt.Printf("  C# BigInteger.ToByteArray():   %x\n", csharpBigIntegerBytes(v))
+	return hex.EncodeToString(be[:])
+}
+
+// runScalarMode reduces --value mod r, negates or inverts it, or draws random Fr elements
+// (from --seed when given, so the output can be reproduced)
+func runScalarMode(args []string) error {
+	fs := newFlagSet("scalar")
+	op := fs.String("op", "reduce", "Operation: reduce, neg, inv or random")
+	valueStr := fs.String("value", "", "Value: any-size integer, decimal (may be negative), 0x hex or bare hex")
+	format := fs.String("format", "auto", "Input form: auto, dec, hex (big-endian), le-hex (little-endian)")
+	count := fs.Int("count", 1, "Number of random elements (random)")
+	seedHex := fs.String("seed", "", "Seed of the random elements (hex, default: 32 random bytes)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *op == "random" {
+		if *count < 1 {
+			return usageErrorf("--count must be at least 1")
+		}
+		seed, err := randomOrHex("seed", *seedHex, 32)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("=== Random Fr Elements (%d) ===\n", *count)
+		fmt.Printf("Seed: %x\n", seed)
+		recordVerdictInput(hex.EncodeToString(seed))
+		var values []string
+		for i := 0; i < *count; i++ {
+			e, err := seededFieldElement(seed, fmt.Sprintf("scalar random %d", i))
+			if err != nil {
+				return err
+			}
+			values = append(values, printScalarEncodings(fmt.Sprintf("[%d]", i), e))
+		}
+		if len(values) == 1 {
+			recordVerdictResult(values[0])
+		}
+		reportInput("seed", hex.EncodeToString(seed))
+		reportOutput("values", values)
+		return nil
+	}
+
+	if *op != "reduce" && *op != "neg" && *op != "inv" {
+		return usageErrorf("--op must be reduce, neg, inv or random, got '%s'", *op)
+	}
+	if *valueStr == "" {
+		return usageErrorf("--value is required with --op %s", *op)
+	}
+	v, used, err := parseScalarAnyForm(*valueStr, *format)
+	if err != nil {
+		return usageErrorf("--value: %v", err)
+	}
+	r := fr.Modulus()
+	reduced := new(big.Int).Mod(v, r) // Euclidean, so a negative value lands in [0, r)
+	var a fr.Element
+	a.SetBigInt(reduced)
+
+	fmt.Printf("=== Fr Scalar (%s) ===\n", *op)
+	fmt.Printf("Parsed as: %s\n", used)
+	fmt.Printf("Value: %s\n", v.String())
+	fmt.Printf("Value >= r or negative (non-canonical): %v\n", v.Sign() < 0 || v.Cmp(r) >= 0)
+	aHex := printScalarEncodings("Value mod r", a)
+	recordVerdictInput(aHex)
+	reportInput("value", v.String())
+
+	result := a
+	switch *op {
+	case "neg":
+		result.Neg(&a)
+		var sum fr.Element
+		sum.Add(&a, &result)
+		if !sum.IsZero() {
+			fmt.Println("❌ a + (-a) is not 0")
+			return errChecksFailed
+		}
+		fmt.Println()
+		printScalarEncodings("r - a", result)
+		fmt.Println("✅ a + (r - a) = 0 mod r")
+	case "inv":
+		if a.IsZero() {
+			return fmt.Errorf("--value: zero mod r has no inverse")
+		}
+		result.Inverse(&a)
+		var check fr.Element
+		check.Mul(&a, &result)
+		if !check.IsOne() {
+			fmt.Println("❌ a * a^-1 is not 1")
+			return errChecksFailed
+		}
+		fmt.Println()
+		printScalarEncodings("a^-1", result)
+		fmt.Println("✅ a * a^-1 = 1 mod r")
+	}
+	be := result.Bytes()
+	recordVerdictResult(hex.EncodeToString(be[:]))
+	reportOutput("decimal", result.BigInt(new(big.Int)).String())
+	reportOutput("be", hex.EncodeToString(be[:]))
+	reportOutput("le", hex.EncodeToString(reverseBytes(be[:])))
+	reportOutput("csharp", hex.EncodeToString(csharpBigIntegerBytes(result.BigInt(new(big.Int)))))
+	return nil
+}
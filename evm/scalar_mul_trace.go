@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"evm/serialization"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The --trace of g1mul / g2mul replays k * P as a left-to-right double-and-add over the
+// 32-byte scalar as given, the loop Neo's C# port runs, and prints R after every double
+// and every add. Affine coordinates are comparable across implementations; the Jacobian
+// (X, Y, Z) are gnark-crypto's (x = X/Z^2, y = Y/Z^3) and agree with another
+// implementation only up to that scaling. gnark itself multiplies with the GLV
+// decomposition k = k1 + k2 * lambda mod r, which is printed and checked as well.
+
+var (
+	// lambda = x^2 - 1 with the curve parameter x, the eigenvalue of phi(x, y) = (w x, y)
+	glvLambda, _ = new(big.Int).SetString("228988810152649578064853576960394133503", 10)
+	// w, a primitive cube root of unity in Fp; G2 uses w^2
+	glvOmega = func() fp.Element {
+		var w fp.Element
+		w.SetString("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436")
+		return w
+	}()
+)
+
+// traceLadder runs double-and-add over the bits of k from the top set bit. R starts at P
+// for that bit; show is called after every step and may be nil.
+func traceLadder(k *big.Int, set, double, add func(), show func(label string)) {
+	if k.Sign() == 0 {
+		if show != nil {
+			show("k = 0: R = infinity")
+		}
+		return
+	}
+	top := k.BitLen() - 1
+	set()
+	if show != nil {
+		show(fmt.Sprintf("bit %3d = 1: R = P", top))
+	}
+	for i := top - 1; i >= 0; i-- {
+		double()
+		if show != nil {
+			show(fmt.Sprintf("bit %3d = %d: R = 2R", i, k.Bit(i)))
+		}
+		if k.Bit(i) == 1 {
+			add()
+			if show != nil {
+				show("            R = R + P")
+			}
+		}
+	}
+}
+
+// glvSplit is the GLV decomposition of k mod r, as gnark-crypto computes it
+func glvSplit(k *big.Int) (*big.Int, [2]big.Int) {
+	var lattice ecc.Lattice
+	ecc.PrecomputeLattice(fr.Modulus(), glvLambda, &lattice)
+	reduced := new(big.Int).Mod(k, fr.Modulus())
+	return reduced, ecc.SplitScalar(reduced, &lattice)
+}
+
+// traceScalarMul prints the trace of the g1mul / g2mul input and checks the ladder, the
+// GLV decomposition and the endomorphism against the precompile result
+func traceScalarMul(mode, input, result string) error {
+	data, err := hex.DecodeString(input)
+	if err != nil {
+		return err
+	}
+	g2 := mode == "g2mul"
+	pointSize := 128
+	if g2 {
+		pointSize = 256
+	}
+	if len(data) != pointSize+32 {
+		return fmt.Errorf("%s input must be %d bytes", mode, pointSize+32)
+	}
+	k := new(big.Int).SetBytes(data[pointSize:])
+	reduced, split := glvSplit(k)
+	failed := 0
+	check := func(ok bool, what string) {
+		if !ok {
+			fmt.Printf("❌ %s\n", what)
+			failed++
+			return
+		}
+		fmt.Printf("✅ %s\n", what)
+	}
+
+	fmt.Println()
+	fmt.Println("=== Double-and-add Trace (most significant bit first) ===")
+	fmt.Printf("Scalar: %s (0x%x, %d bits)\n", k.String(), k, k.BitLen())
+	var got []byte
+	// mulBy is a quiet ladder for the GLV checks; it returns Ethereum encodings
+	var mulBy func(s *big.Int, phi bool) []byte
+	if g2 {
+		p, err := serialization.ParseEthereumG2PointFromBytes(data[:pointSize])
+		if err != nil {
+			return err
+		}
+		feLine := func(label string, e *bls.E2) {
+			fmt.Printf("    %s: c0=%x c1=%x\n", label, e.A0.Bytes(), e.A1.Bytes())
+		}
+		fmt.Println("P:")
+		feLine("x", &p.X)
+		feLine("y", &p.Y)
+		ladder := func(s *big.Int, base bls.G2Affine, show bool) bls.G2Affine {
+			var r bls.G2Jac
+			var out bls.G2Affine
+			display := func(label string) {
+				out.FromJacobian(&r)
+				fmt.Println(label)
+				feLine("x", &out.X)
+				feLine("y", &out.Y)
+				feLine("X", &r.X)
+				feLine("Y", &r.Y)
+				feLine("Z", &r.Z)
+			}
+			var shown func(string)
+			if show {
+				shown = display
+			}
+			r.X.SetOne()
+			r.Y.SetOne()
+			traceLadder(s, func() { r.FromAffine(&base) }, func() { r.DoubleAssign() }, func() { r.AddMixed(&base) }, shown)
+			return *out.FromJacobian(&r)
+		}
+		res := ladder(k, p, true)
+		got = serialization.EncodeEthereumG2Point(res)
+		mulBy = func(s *big.Int, phi bool) []byte {
+			base := p
+			if phi {
+				var w2 fp.Element
+				w2.Square(&glvOmega)
+				base.X.MulByElement(&base.X, &w2)
+			}
+			abs := new(big.Int).Abs(s)
+			q := ladder(abs, base, false)
+			if s.Sign() < 0 {
+				q.Neg(&q)
+			}
+			return serialization.EncodeEthereumG2Point(q)
+		}
+	} else {
+		p, err := serialization.ParseEthereumG1PointFromBytes(data[:pointSize])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("P:\n    x: %x\n    y: %x\n", p.X.Bytes(), p.Y.Bytes())
+		ladder := func(s *big.Int, base bls.G1Affine, show bool) bls.G1Affine {
+			var r bls.G1Jac
+			var out bls.G1Affine
+			display := func(label string) {
+				out.FromJacobian(&r)
+				fmt.Println(label)
+				fmt.Printf("    x: %x\n    y: %x\n", out.X.Bytes(), out.Y.Bytes())
+				fmt.Printf("    X: %x\n    Y: %x\n    Z: %x\n", r.X.Bytes(), r.Y.Bytes(), r.Z.Bytes())
+			}
+			var shown func(string)
+			if show {
+				shown = display
+			}
+			r.X.SetOne()
+			r.Y.SetOne()
+			traceLadder(s, func() { r.FromAffine(&base) }, func() { r.DoubleAssign() }, func() { r.AddMixed(&base) }, shown)
+			return *out.FromJacobian(&r)
+		}
+		res := ladder(k, p, true)
+		got = serialization.EncodeEthereumG1Point(res)
+		mulBy = func(s *big.Int, phi bool) []byte {
+			base := p
+			if phi {
+				base.X.Mul(&base.X, &glvOmega)
+			}
+			abs := new(big.Int).Abs(s)
+			q := ladder(abs, base, false)
+			if s.Sign() < 0 {
+				q.Neg(&q)
+			}
+			return serialization.EncodeEthereumG1Point(q)
+		}
+	}
+	fmt.Printf("Double-and-add result: %x\n", got)
+	check(hex.EncodeToString(got) == result, "double-and-add result matches the precompile result")
+
+	fmt.Println()
+	fmt.Println("=== GLV Decomposition ===")
+	fmt.Printf("k mod r: %s\n", reduced.String())
+	fmt.Printf("lambda:  %s\n", glvLambda.String())
+	fmt.Printf("k1: %s\n", split[0].String())
+	fmt.Printf("k2: %s\n", split[1].String())
+	sum := new(big.Int).Mul(&split[1], glvLambda)
+	sum.Add(sum, &split[0]).Mod(sum, fr.Modulus())
+	check(sum.Cmp(reduced) == 0, "k1 + k2 * lambda = k mod r")
+	phiP, lambdaP := mulBy(big.NewInt(1), true), mulBy(glvLambda, false)
+	fmt.Printf("phi(P): %x\n", phiP)
+	check(hex.EncodeToString(phiP) == hex.EncodeToString(lambdaP), "phi(P) = lambda * P (P has order r)")
+	k1P, k2Phi := mulBy(&split[0], false), mulBy(&split[1], true)
+	fmt.Printf("k1 * P:      %x\n", k1P)
+	fmt.Printf("k2 * phi(P): %x\n", k2Phi)
+	combined, err := eip2537Add(g2)(append(k1P, k2Phi...))
+	if err != nil {
+		return err
+	}
+	check(hex.EncodeToString(combined) == result, "k1 * P + k2 * phi(P) matches the precompile result")
+	if failed > 0 {
+		return errChecksFailed
+	}
+	return nil
+}
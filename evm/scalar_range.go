@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// scalarRange is the policy random MultiExp scalars are drawn with. The default keeps
+// them in [1, int.MaxValue] so they fit a C# int; wider ranges exercise full 255-bit
+// scalars. A zero draw becomes 1 unless include-zero is given (MultiExp skips zero
+// scalars), and boundaries makes the first scalars the edges of the range.
+type scalarRange struct {
+	name        string
+	bound       *big.Int // scalars are drawn from [0, bound)
+	includeZero bool
+	boundaries  bool
+}
+
+var scalarRangeNames = []string{"csharp-int", "32-bit", "64-bit", "full-fr", "bits:<n>"}
+
+// defaultScalarRange is the C# int range random mode has always used
+func defaultScalarRange() scalarRange {
+	return scalarRange{name: "csharp-int", bound: big.NewInt(2147483648)} // int.MaxValue + 1
+}
+
+// registerScalarRangeFlag adds --scalar-range to a mode that generates random scalars
+func registerScalarRangeFlag(fs *flag.FlagSet) *string {
+	return fs.String("scalar-range", "csharp-int", "Scalar range: "+strings.Join(scalarRangeNames, ", ")+
+		" (n = 1..255, capped at r - 1), optionally followed by ,include-zero and ,boundaries")
+}
+
+// parseScalarRange parses a --scalar-range value: one range name and any modifiers
+func parseScalarRange(value string) (scalarRange, error) {
+	var sr scalarRange
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		var bits int
+		switch {
+		case part == "include-zero":
+			sr.includeZero = true
+			continue
+		case part == "boundaries":
+			sr.boundaries = true
+			continue
+		case part == "csharp-int":
+			bits = 31
+		case part == "32-bit":
+			bits = 32
+		case part == "64-bit":
+			bits = 64
+		case part == "full-fr":
+			bits = 255
+		case strings.HasPrefix(part, "bits:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(part, "bits:"))
+			if err != nil || n < 1 || n > 255 {
+				return sr, fmt.Errorf("invalid --scalar-range '%s': bits:<n> needs n in 1..255", part)
+			}
+			bits = n
+		default:
+			return sr, fmt.Errorf("invalid --scalar-range '%s' (valid: %s, include-zero, boundaries)", part, strings.Join(scalarRangeNames, ", "))
+		}
+		if sr.bound != nil {
+			return sr, fmt.Errorf("--scalar-range names two ranges (%s and %s)", sr.name, part)
+		}
+		sr.name = part
+		sr.bound = new(big.Int).Lsh(big.NewInt(1), uint(bits))
+		if sr.bound.Cmp(fr.Modulus()) > 0 {
+			sr.bound = fr.Modulus() // 2^255 > r: full-fr and bits:255 are [0, r - 1]
+		}
+	}
+	if sr.bound == nil {
+		def := defaultScalarRange()
+		sr.name, sr.bound = def.name, def.bound
+	}
+	return sr, nil
+}
+
+// describe prints the range as an interval, e.g. "[1, 2147483647] (csharp-int)"
+func (sr scalarRange) describe() string {
+	max := new(big.Int).Sub(sr.bound, big.NewInt(1))
+	low := "1"
+	if sr.includeZero {
+		low = "0"
+	}
+	out := fmt.Sprintf("[%s, %s] (%s", low, max, sr.name)
+	if sr.boundaries {
+		out += ", boundaries first"
+	}
+	return out + ")"
+}
+
+// flagValue is the --scalar-range value that selects this range again
+func (sr scalarRange) flagValue() string {
+	parts := []string{sr.name}
+	if sr.includeZero {
+		parts = append(parts, "include-zero")
+	}
+	if sr.boundaries {
+		parts = append(parts, "boundaries")
+	}
+	return strings.Join(parts, ",")
+}
+
+// boundaryValues are the edges of the range in ascending order: the two smallest and the
+// two largest allowed scalars, without duplicates for tiny ranges
+func (sr scalarRange) boundaryValues() []*big.Int {
+	low := int64(1)
+	if sr.includeZero {
+		low = 0
+	}
+	max := new(big.Int).Sub(sr.bound, big.NewInt(1))
+	var out []*big.Int
+	for _, v := range []*big.Int{big.NewInt(low), big.NewInt(low + 1), new(big.Int).Sub(max, big.NewInt(1)), max} {
+		if v.Sign() < 0 || v.Cmp(max) > 0 || (!sr.includeZero && v.Sign() == 0) {
+			continue
+		}
+		if len(out) > 0 && out[len(out)-1].Cmp(v) >= 0 {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// draw returns scalar i of a fixture. sample draws a value below a bound; callers pass
+// their own source so seeded streams stay reproducible.
+func (sr scalarRange) draw(i int, sample func(bound *big.Int) (*big.Int, error)) (*big.Int, error) {
+	if sr.boundaries {
+		if b := sr.boundaryValues(); i < len(b) {
+			return new(big.Int).Set(b[i]), nil
+		}
+	}
+	s, err := sample(sr.bound)
+	if err != nil {
+		return nil, err
+	}
+	if s.Sign() == 0 && !sr.includeZero {
+		s.SetInt64(1) // MultiExp skips zero scalars
+	}
+	return s, nil
+}
+
+// csharpScalarLiterals renders scalars as C# BigInteger initializers. Literals up to
+// ulong.MaxValue convert implicitly; wider ones do not compile and are parsed instead.
+func csharpScalarLiterals(scalars []*big.Int) []string {
+	maxUlong := new(big.Int).SetUint64(^uint64(0))
+	out := make([]string, len(scalars))
+	for i, s := range scalars {
+		if s.Cmp(maxUlong) > 0 {
+			out[i] = fmt.Sprintf("BigInteger.Parse(\"%s\")", s)
+		} else {
+			out[i] = s.String()
+		}
+	}
+	return out
+}
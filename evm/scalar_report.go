@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// parseScalarAnyForm parses a scalar given as decimal (optionally negative), 0x-prefixed
+// big-endian hex, or bare hex. format is "auto", "dec", "hex" (big-endian) or "le-hex"
+// (little-endian, as produced by C# BigInteger.ToByteArray()).
+func parseScalarAnyForm(s string, format string) (*big.Int, string, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	body := strings.TrimPrefix(s, "-")
+	if format == "auto" {
+		switch {
+		case strings.HasPrefix(body, "0x") || strings.HasPrefix(body, "0X"):
+			format = "hex"
+		case strings.ContainsAny(strings.ToLower(body), "abcdef"):
+			format = "hex"
+		default:
+			format = "dec"
+		}
+	}
+	body = strings.TrimPrefix(strings.TrimPrefix(body, "0x"), "0X")
+
+	v := new(big.Int)
+	switch format {
+	case "dec":
+		if _, ok := v.SetString(body, 10); !ok {
+			return nil, "", fmt.Errorf("invalid decimal scalar '%s'", s)
+		}
+	case "hex", "le-hex":
+		if len(body)%2 == 1 {
+			body = "0" + body
+		}
+		b, err := hex.DecodeString(body)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid hex scalar '%s': %v", s, err)
+		}
+		if format == "le-hex" {
+			b = reverseBytes(b)
+		}
+		v.SetBytes(b)
+	default:
+		return nil, "", fmt.Errorf("invalid scalar format '%s' (valid: auto, dec, hex, le-hex)", format)
+	}
+	if negative {
+		v.Neg(v)
+	}
+	return v, format, nil
+}
+
+// reverseBytes returns a reversed copy of b
+func reverseBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
+// csharpBigIntegerBytes returns what C# BigInteger.ToByteArray() produces for v:
+// minimal little-endian two's complement, so positive values whose top bit is set get
+// an extra 0x00 byte and negative values are sign-extended with 0xff
+func csharpBigIntegerBytes(v *big.Int) []byte {
+	if v.Sign() == 0 {
+		return []byte{0}
+	}
+	n := v.BitLen()/8 + 1
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(n*8))
+	twos := new(big.Int).Set(v)
+	if v.Sign() < 0 {
+		twos.Add(twos, mod)
+	}
+	be := twos.FillBytes(make([]byte, n))
+	// Drop redundant sign bytes, as .NET does
+	for len(be) > 1 && ((be[0] == 0x00 && be[1]&0x80 == 0) || (be[0] == 0xff && be[1]&0x80 != 0)) {
+		be = be[1:]
+	}
+	return reverseBytes(be)
+}
+
+// runScalarReportMode prints every representation of a scalar that commonly gets
+// mismatched between Go, C# BigInteger and the Ethereum/Neo byte formats
+func runScalarReportMode(args []string) error {
+	fs := newFlagSet("scalar-report")
+	scalarStr := fs.String("scalar", "", "Scalar: decimal (may be negative), 0x hex, or bare hex")
+	format := fs.String("format", "auto", "Input form: auto, dec, hex (big-endian), le-hex (little-endian)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *scalarStr == "" {
+		return fmt.Errorf("--scalar is required")
+	}
+	v, used, err := parseScalarAnyForm(*scalarStr, *format)
+	if err != nil {
+		return err
+	}
+
+	r := fr.Modulus()
+	reduced := new(big.Int).Mod(v, r) // Go's Mod is Euclidean, so the result is in [0, r)
+	magnitude := new(big.Int).Abs(v)
+
+	fmt.Println("=== Scalar Report ===")
+	fmt.Printf("Parsed as: %s\n", used)
+	fmt.Printf("Decimal: %s\n", v.String())
+	fmt.Printf("Bit length: %d\n", v.BitLen())
+	fmt.Printf("Sign: %s\n", map[int]string{-1: "negative", 0: "zero", 1: "positive"}[v.Sign()])
+	fmt.Println()
+
+	if magnitude.BitLen() <= 256 {
+		be := magnitude.FillBytes(make([]byte, 32))
+		fmt.Printf("32-byte big-endian (|value|):    %x\n", be)
+		fmt.Printf("32-byte little-endian (|value|): %x\n", reverseBytes(be))
+	} else {
+		fmt.Println("32-byte encodings: value does not fit in 32 bytes")
+	}
+	reducedBE := reduced.FillBytes(make([]byte, 32))
+	fmt.Printf("Value mod r (decimal): %s\n", reduced.String())
+	fmt.Printf("Value mod r (32-byte BE): %x\n", reducedBE)
+	fmt.Printf("Value mod r (32-byte LE): %x\n", reverseBytes(reducedBE))
+	fmt.Printf("Value >= r (non-canonical): %v\n", v.Cmp(r) >= 0)
+	if v.Sign() < 0 {
+		fmt.Println("Negative scalar: multiplication uses r - |value| mod r (the result is the negated point)")
+	}
+	fmt.Println()
+
+	fmt.Println("C# BigInteger:")
+	csBytes := csharpBigIntegerBytes(v)
+	fmt.Printf("  ToByteArray() (LE two's complement, %d bytes): %x\n", len(csBytes), csBytes)
+	if v.Sign() >= 0 && magnitude.BitLen() <= 256 {
+		le := reverseBytes(magnitude.FillBytes(make([]byte, 32)))
+		if le[31]&0x80 != 0 {
+			fmt.Println("  ⚠️  new BigInteger(32-byte LE) reads this value as NEGATIVE (top bit set);")
+			fmt.Println("      append a 0x00 byte or use new BigInteger(bytes, isUnsigned: true)")
+		} else {
+			fmt.Println("  new BigInteger(32-byte LE) reads this value correctly (top bit clear)")
+		}
+		fmt.Println("  Note: BigInteger(byte[]) is little-endian; pass the big-endian bytes with isBigEndian: true or reversed")
+	}
+	fmt.Printf("  Fits int (Int32.MaxValue %d):   %v\n", math.MaxInt32, v.IsInt64() && v.Int64() >= math.MinInt32 && v.Int64() <= math.MaxInt32)
+	fmt.Printf("  Fits long (Int64.MaxValue %d): %v\n", int64(math.MaxInt64), v.IsInt64())
+	fmt.Printf("  Fits ulong (UInt64.MaxValue %d): %v\n", uint64(math.MaxUint64), v.IsUint64())
+	return nil
+}
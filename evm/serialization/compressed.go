@@ -0,0 +1,106 @@
+package serialization
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// ErrBadFlags is returned (wrapped) when the flag bits of a compressed point are invalid
+var ErrBadFlags = errors.New("invalid compression flags")
+
+// Flag bits in the first byte of a compressed point
+const (
+	flagCompressed = 0x80
+	flagInfinity   = 0x40
+	flagSort       = 0x20
+	flagMask       = flagCompressed | flagInfinity | flagSort
+)
+
+// checkCompressedField verifies that a 48-byte big-endian field element is < p
+func checkCompressedField(data []byte, name string) error {
+	var e fp.Element
+	if err := e.SetBytesCanonical(data); err != nil {
+		return fmt.Errorf("%w: %s must be < p", ErrNonCanonical, name)
+	}
+	return nil
+}
+
+// checkCompressedFlags applies the flag rules shared by G1 and G2: the compression flag
+// must be set, and the infinity flag requires a clear sort flag and an all-zero x
+func checkCompressedFlags(data []byte) (infinity bool, err error) {
+	flags := data[0] & flagMask
+	if flags&flagCompressed == 0 {
+		return false, fmt.Errorf("%w: compression flag (0x80) not set", ErrBadFlags)
+	}
+	if flags&flagInfinity == 0 {
+		return false, nil
+	}
+	if flags&flagSort != 0 {
+		return false, fmt.Errorf("%w: sort flag set on infinity point", ErrBadFlags)
+	}
+	if data[0]&^flagMask != 0 || !bytes.Equal(data[1:], make([]byte, len(data)-1)) {
+		return false, fmt.Errorf("%w: infinity flag set with non-zero x", ErrBadFlags)
+	}
+	return true, nil
+}
+
+// DecodeCompressedG1Point decodes a 48-byte compressed G1 point, classifying the first failed
+// check in a fixed reference order: length, flags, canonical x, on curve, subgroup.
+// The errors wrap ErrInvalidLength, ErrBadFlags, ErrNonCanonical, ErrNotOnCurve and
+// ErrNotInSubgroup respectively.
+func DecodeCompressedG1Point(data []byte) (bls.G1Affine, error) {
+	var p bls.G1Affine
+	if len(data) != 48 {
+		return p, fmt.Errorf("%w: compressed G1 point must be 48 bytes, got %d", ErrInvalidLength, len(data))
+	}
+	infinity, err := checkCompressedFlags(data)
+	if err != nil || infinity {
+		return p, err
+	}
+	x := make([]byte, 48)
+	copy(x, data)
+	x[0] &^= flagMask
+	if err := checkCompressedField(x, "x"); err != nil {
+		return p, err
+	}
+	if err := bls.NewDecoder(bytes.NewReader(data), bls.NoSubgroupChecks()).Decode(&p); err != nil {
+		return bls.G1Affine{}, fmt.Errorf("G1: %w (%v)", ErrNotOnCurve, err)
+	}
+	if !p.IsInSubGroup() {
+		return bls.G1Affine{}, fmt.Errorf("G1: %w", ErrNotInSubgroup)
+	}
+	return p, nil
+}
+
+// DecodeCompressedG2Point decodes a 96-byte compressed G2 point ([x.C1, x.C0]) using the same
+// reference order as DecodeCompressedG1Point
+func DecodeCompressedG2Point(data []byte) (bls.G2Affine, error) {
+	var p bls.G2Affine
+	if len(data) != 96 {
+		return p, fmt.Errorf("%w: compressed G2 point must be 96 bytes, got %d", ErrInvalidLength, len(data))
+	}
+	infinity, err := checkCompressedFlags(data)
+	if err != nil || infinity {
+		return p, err
+	}
+	x := make([]byte, 96)
+	copy(x, data)
+	x[0] &^= flagMask
+	if err := checkCompressedField(x[0:48], "x.C1"); err != nil {
+		return p, err
+	}
+	if err := checkCompressedField(x[48:96], "x.C0"); err != nil {
+		return p, err
+	}
+	if err := bls.NewDecoder(bytes.NewReader(data), bls.NoSubgroupChecks()).Decode(&p); err != nil {
+		return bls.G2Affine{}, fmt.Errorf("G2: %w (%v)", ErrNotOnCurve, err)
+	}
+	if !p.IsInSubGroup() {
+		return bls.G2Affine{}, fmt.Errorf("G2: %w", ErrNotInSubgroup)
+	}
+	return p, nil
+}
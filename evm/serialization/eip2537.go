@@ -0,0 +1,97 @@
+package serialization
+
+import (
+	"errors"
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// Sentinel errors returned (wrapped) by the EIP-2537 decoders, so callers can classify
+// a rejection with errors.Is instead of matching messages
+var (
+	ErrInvalidLength  = errors.New("invalid length")
+	ErrNonZeroPadding = errors.New("non-zero padding")
+	ErrNonCanonical   = errors.New("non-canonical field element")
+	ErrNotOnCurve     = errors.New("point is not on the curve")
+	ErrNotInSubgroup  = errors.New("point is not in the correct subgroup")
+)
+
+// DecodeEIP2537Fp decodes one 64-byte EIP-2537 field element: 16 zero padding bytes
+// followed by a 48-byte big-endian value that must be strictly less than p. It is the
+// input of MAP_FP_TO_G1 and each half of MAP_FP2_TO_G2's.
+func DecodeEIP2537Fp(data []byte, name string) (fp.Element, error) {
+	var e fp.Element
+	if len(data) != 64 {
+		return e, fmt.Errorf("%w: %s must be 64 bytes, got %d", ErrInvalidLength, name, len(data))
+	}
+	for i := 0; i < 16; i++ {
+		if data[i] != 0 {
+			return e, fmt.Errorf("%w in %s at byte %d", ErrNonZeroPadding, name, i)
+		}
+	}
+	if err := e.SetBytesCanonical(data[16:64]); err != nil {
+		return e, fmt.Errorf("%w: %s must be < p: %v", ErrNonCanonical, name, err)
+	}
+	return e, nil
+}
+
+// DecodeEIP2537G1Point decodes a 128-byte G1 point exactly as the EIP-2537 precompiles do:
+// padding must be zero, both coordinates must be canonical, the point must be on the curve
+// and, when subgroupCheck is set, in the prime-order subgroup. All zeros decodes to infinity.
+// Unlike ParseEthereumG1PointFromBytes the given y coordinate is used as-is.
+func DecodeEIP2537G1Point(data []byte, subgroupCheck bool) (bls.G1Affine, error) {
+	var p bls.G1Affine
+	if len(data) != 128 {
+		return p, fmt.Errorf("%w: G1 point must be 128 bytes, got %d", ErrInvalidLength, len(data))
+	}
+	x, err := DecodeEIP2537Fp(data[0:64], "x")
+	if err != nil {
+		return p, err
+	}
+	y, err := DecodeEIP2537Fp(data[64:128], "y")
+	if err != nil {
+		return p, err
+	}
+	p.X, p.Y = x, y
+	if p.X.IsZero() && p.Y.IsZero() {
+		return p, nil
+	}
+	if !p.IsOnCurve() {
+		return bls.G1Affine{}, fmt.Errorf("G1: %w", ErrNotOnCurve)
+	}
+	if subgroupCheck && !p.IsInSubGroup() {
+		return bls.G1Affine{}, fmt.Errorf("G1: %w", ErrNotInSubgroup)
+	}
+	return p, nil
+}
+
+// DecodeEIP2537G2Point decodes a 256-byte G2 point ([x.C0, x.C1, y.C0, y.C1]) exactly as the
+// EIP-2537 precompiles do. See DecodeEIP2537G1Point for the rules applied.
+func DecodeEIP2537G2Point(data []byte, subgroupCheck bool) (bls.G2Affine, error) {
+	var p bls.G2Affine
+	if len(data) != 256 {
+		return p, fmt.Errorf("%w: G2 point must be 256 bytes, got %d", ErrInvalidLength, len(data))
+	}
+	names := []string{"x.C0", "x.C1", "y.C0", "y.C1"}
+	coords := make([]fp.Element, 4)
+	for i := range coords {
+		e, err := DecodeEIP2537Fp(data[i*64:(i+1)*64], names[i])
+		if err != nil {
+			return p, err
+		}
+		coords[i] = e
+	}
+	p.X.A0, p.X.A1, p.Y.A0, p.Y.A1 = coords[0], coords[1], coords[2], coords[3]
+	if p.X.IsZero() && p.Y.IsZero() {
+		return p, nil
+	}
+	if !p.IsOnCurve() {
+		return bls.G2Affine{}, fmt.Errorf("G2: %w", ErrNotOnCurve)
+	}
+	if subgroupCheck && !p.IsInSubGroup() {
+		return bls.G2Affine{}, fmt.Errorf("G2: %w", ErrNotInSubgroup)
+	}
+	return p, nil
+}
@@ -0,0 +1,59 @@
+package serialization
+
+import (
+	"fmt"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// GTSize is the size of a serialized GT element in both supported encodings
+const GTSize = 576
+
+// gtCoefficients returns pointers to the 12 Fp coefficients of z in tower order:
+// C0.B0.A0, C0.B0.A1, C0.B1.A0, ..., C1.B2.A1
+func gtCoefficients(z *bls.GT) [12]*fp.Element {
+	return [12]*fp.Element{
+		&z.C0.B0.A0, &z.C0.B0.A1, &z.C0.B1.A0, &z.C0.B1.A1, &z.C0.B2.A0, &z.C0.B2.A1,
+		&z.C1.B0.A0, &z.C1.B0.A1, &z.C1.B1.A0, &z.C1.B1.A1, &z.C1.B2.A0, &z.C1.B2.A1,
+	}
+}
+
+// EncodeNeoGT serializes a GT element the way Neo's Gt.ToArray() does: the 12 Fp coefficients
+// as 48-byte big-endian values in tower order (C0.B0.A0 first). gnark's Marshal() uses the
+// reverse order (C1.B2.A1 first), so the two 576-byte encodings differ for the same element.
+func EncodeNeoGT(z *bls.GT) []byte {
+	out := make([]byte, GTSize)
+	for i, c := range gtCoefficients(z) {
+		fp.BigEndian.PutElement((*[fp.Bytes]byte)(out[i*fp.Bytes:(i+1)*fp.Bytes]), *c)
+	}
+	return out
+}
+
+// DecodeNeoGT parses a 576-byte Neo-encoded GT element. Every coefficient must be canonical;
+// membership in the order-r subgroup is not checked (use IsInSubGroup).
+func DecodeNeoGT(data []byte) (bls.GT, error) {
+	var z bls.GT
+	if len(data) != GTSize {
+		return z, fmt.Errorf("%w: GT element must be %d bytes, got %d", ErrInvalidLength, GTSize, len(data))
+	}
+	for i, c := range gtCoefficients(&z) {
+		if err := c.SetBytesCanonical(data[i*fp.Bytes : (i+1)*fp.Bytes]); err != nil {
+			return bls.GT{}, fmt.Errorf("%w: GT coefficient %d must be < p", ErrNonCanonical, i)
+		}
+	}
+	return z, nil
+}
+
+// DecodeGnarkGT parses a 576-byte GT element in gnark's Marshal() order, with the same
+// rules as DecodeNeoGT
+func DecodeGnarkGT(data []byte) (bls.GT, error) {
+	var z bls.GT
+	if len(data) != GTSize {
+		return z, fmt.Errorf("%w: GT element must be %d bytes, got %d", ErrInvalidLength, GTSize, len(data))
+	}
+	if err := z.SetBytes(data); err != nil {
+		return bls.GT{}, fmt.Errorf("%w: %v", ErrNonCanonical, err)
+	}
+	return z, nil
+}
@@ -0,0 +1,98 @@
+package serialization
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Round-trip properties over random points, driven by testing/quick. Random points are
+// generator multiples with quick-generated 256-bit scalars.
+
+// quickScalar turns a quick-generated value into a scalar in [0, r-1]
+func quickScalar(limbs [4]uint64) *big.Int {
+	v := new(big.Int)
+	for _, l := range limbs {
+		v.Lsh(v, 64)
+		v.Or(v, new(big.Int).SetUint64(l))
+	}
+	return v.Mod(v, fr.Modulus())
+}
+
+func quickG1(limbs [4]uint64) bls.G1Affine {
+	_, _, g1Gen, _ := bls.Generators()
+	var p bls.G1Affine
+	p.ScalarMultiplication(&g1Gen, quickScalar(limbs))
+	return p
+}
+
+func quickG2(limbs [4]uint64) bls.G2Affine {
+	_, _, _, g2Gen := bls.Generators()
+	var p bls.G2Affine
+	p.ScalarMultiplication(&g2Gen, quickScalar(limbs))
+	return p
+}
+
+func TestRoundTripProperties(t *testing.T) {
+	tests := []struct {
+		name string
+		prop interface{}
+	}{
+		{"G1 decompress(compress(P)) = P", func(k [4]uint64) bool {
+			p := quickG1(k)
+			q, err := DecodeCompressedG1Point(ConvertG1AffineToCompressed(p))
+			return err == nil && q.Equal(&p)
+		}},
+		{"G2 decompress(compress(P)) = P", func(k [4]uint64) bool {
+			p := quickG2(k)
+			q, err := DecodeCompressedG2Point(ConvertG2AffineToCompressed(p))
+			return err == nil && q.Equal(&p)
+		}},
+		{"G1 manual compression = gnark Bytes()", func(k [4]uint64) bool {
+			p := quickG1(k)
+			b := p.Bytes()
+			return bytes.Equal(ConvertG1AffineToCompressed(p), b[:])
+		}},
+		{"G2 manual compression = gnark Bytes()", func(k [4]uint64) bool {
+			p := quickG2(k)
+			b := p.Bytes()
+			return bytes.Equal(ConvertG2AffineToCompressed(p), b[:])
+		}},
+		{"G1 parse(toEthereum(P)) = P", func(k [4]uint64) bool {
+			p := quickG1(k)
+			enc := EncodeEthereumG1Point(p)
+			q1, err1 := ParseEthereumG1PointFromBytes(enc)
+			q2, err2 := DecodeEIP2537G1Point(enc, true)
+			return err1 == nil && err2 == nil && q1.Equal(&p) && q2.Equal(&p)
+		}},
+		{"G2 parse(toEthereum(P)) = P", func(k [4]uint64) bool {
+			p := quickG2(k)
+			q, err := DecodeEIP2537G2Point(EncodeEthereumG2Point(p), true)
+			return err == nil && q.Equal(&p)
+		}},
+		{"G1 toEthereum(parse(E)) = E", func(k [4]uint64) bool {
+			enc := EncodeEthereumG1Point(quickG1(k))
+			q, err := DecodeEIP2537G1Point(enc, true)
+			return err == nil && bytes.Equal(EncodeEthereumG1Point(q), enc)
+		}},
+		{"GT DecodeNeoGT(EncodeNeoGT(z)) = z", func(a, b [4]uint64) bool {
+			z, err := bls.Pair([]bls.G1Affine{quickG1(a)}, []bls.G2Affine{quickG2(b)})
+			if err != nil {
+				return false
+			}
+			w, err := DecodeNeoGT(EncodeNeoGT(&z))
+			return err == nil && w.Equal(&z)
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := quick.Check(tc.prop, &quick.Config{MaxCount: 50}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,529 @@
+// Package serialization converts BLS12-381 G1/G2 points between gnark-crypto affine
+// points and the byte encodings used by Neo (ZCash-style compressed) and Ethereum
+// (EIP-2537 64-byte padded field elements).
+//
+// Invariants:
+//   - Compressed G1 is 48 bytes, compressed G2 is 96 bytes ([x.C1, x.C0]). The upper
+//     three bits of the first byte are flags: 0x80 compression (always set), 0x40
+//     infinity, 0x20 sort (y is lexicographically largest). The sort flag is never set
+//     on the point at infinity.
+//   - Ethereum G1 is 128 bytes ([x, y]), Ethereum G2 is 256 bytes ([x.C0, x.C1, y.C0,
+//     y.C1]). Every field element is 64 bytes: 16 zero padding bytes followed by the
+//     48-byte big-endian value. The point at infinity is all zeros.
+//   - For any point P: ParseEthereumG1PointFromBytes(EncodeEthereumG1Point(P)) == P and
+//     ParseEthereumG2PointFromBytes(EncodeEthereumG2Point(P)) == P. gnark-crypto's
+//     SetBytes on the compressed encoding also returns P.
+package serialization
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"evm/logging"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// BLS12-381 base field modulus p
+// p = 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab
+var bls12_381_p, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+// bls12_381_p_half = (p-1)/2
+var bls12_381_p_half = new(big.Int)
+
+func init() {
+	// Calculate (p-1)/2
+	bls12_381_p_half.Sub(bls12_381_p, big.NewInt(1))
+	bls12_381_p_half.Rsh(bls12_381_p_half, 1)
+}
+
+// IsLexicographicallyLargestFp checks if an Fp element (48 bytes, big-endian) is lexicographically largest
+// This matches Neo C# Fp.LexicographicallyLargest() implementation
+// An element is lexicographically largest if it is greater than (p-1)/2
+// Note: yBytes is in big-endian format (as returned by gnark-crypto Marshal())
+// Neo uses constant 0xdcff_7fff_ffff_d556 which is (p-1)/2 + 1, and checks t >= constant
+// This means t > (p-1)/2, which is equivalent to y > (p-1)/2
+func IsLexicographicallyLargestFp(yBytes []byte) bool {
+	if len(yBytes) != 48 {
+		return false
+	}
+	// gnark-crypto Marshal() returns big-endian format
+	// big.Int.SetBytes() interprets bytes as big-endian, so we can use directly
+	y := new(big.Int).SetBytes(yBytes)
+	// Compare with (p-1)/2
+	// Neo uses (p-1)/2 + 1 and checks t >= constant, which is equivalent to t > (p-1)/2
+	return y.Cmp(bls12_381_p_half) > 0
+}
+
+// IsLexicographicallyLargestFp2 checks if an Fp2 element (96 bytes, big-endian) is lexicographically largest
+// This matches Neo C# Fp2.LexicographicallyLargest() implementation
+// An Fp2 element is lexicographically largest if:
+//   - C1 is lexicographically largest, OR
+//   - C1 is zero AND C0 is lexicographically largest
+//
+// Note: yBytes format from gnark-crypto G2 Marshal() is [y.C1 (48 bytes) + y.C0 (48 bytes)] in big-endian
+func IsLexicographicallyLargestFp2(yBytes []byte) bool {
+	if len(yBytes) != 96 {
+		return false
+	}
+	// Extract C1 (first 48 bytes) and C0 (last 48 bytes)
+	// Format: [y.C1 (big-endian, 48 bytes) + y.C0 (big-endian, 48 bytes)]
+	c1Bytes := yBytes[0:48]
+	c0Bytes := yBytes[48:96]
+
+	// Check if C1 is lexicographically largest
+	c1IsLargest := IsLexicographicallyLargestFp(c1Bytes)
+	if c1IsLargest {
+		return true
+	}
+
+	// Check if C1 is zero
+	c1IsZero := true
+	for _, b := range c1Bytes {
+		if b != 0 {
+			c1IsZero = false
+			break
+		}
+	}
+
+	// If C1 is zero, check if C0 is lexicographically largest
+	if c1IsZero {
+		return IsLexicographicallyLargestFp(c0Bytes)
+	}
+
+	return false
+}
+
+// ParseEthereumG1PointFromBytes parses a G1 point from Ethereum format (128 bytes)
+// Ethereum format: 64 bytes x (first 16 bytes are 0, last 48 bytes are big-endian) +
+//
+//	64 bytes y (first 16 bytes are 0, last 48 bytes are big-endian)
+func ParseEthereumG1PointFromBytes(data []byte) (bls.G1Affine, error) {
+	if len(data) != 128 {
+		return bls.G1Affine{}, fmt.Errorf("ethereum G1 point must be 128 bytes, got %d", len(data))
+	}
+
+	// Check if this is an infinity point (all coordinates are zero)
+	// Infinity point in Ethereum format: all 128 bytes are zero
+	isInfinity := true
+	for i := 0; i < 128; i++ {
+		if data[i] != 0 {
+			isInfinity = false
+			break
+		}
+	}
+
+	if isInfinity {
+		// Return infinity point directly
+		var infinityPoint bls.G1Affine
+		// G1Affine zero value is infinity point
+		return infinityPoint, nil
+	}
+
+	// Check that first 16 bytes of each field element are zero
+	for i := 0; i < 16; i++ {
+		if data[i] != 0 || data[64+i] != 0 {
+			return bls.G1Affine{}, fmt.Errorf("non-zero padding bytes in Ethereum format at positions %d or %d", i, 64+i)
+		}
+	}
+
+	// Extract x and y (last 48 bytes of each 64-byte field element, big-endian)
+	xBytesBE := data[16:64]  // Last 48 bytes of x (big-endian)
+	yBytesBE := data[80:128] // Last 48 bytes of y (big-endian)
+
+	// gnark-crypto SetBytes accepts uncompressed format (96 bytes)
+	// Format: [x (48 bytes) + y (48 bytes)]
+	// Note: gnark-crypto's Marshal() actually returns big-endian format!
+	// So we can use Ethereum's big-endian bytes directly
+	// Copy into a fresh buffer: appending to the xBytesBE sub-slice would overwrite data[64:112]
+	uncompressedPoint := make([]byte, 0, 96)
+	uncompressedPoint = append(uncompressedPoint, xBytesBE...)
+	uncompressedPoint = append(uncompressedPoint, yBytesBE...)
+
+	var g1Point bls.G1Affine
+	bytesRead, err := g1Point.SetBytes(uncompressedPoint)
+	if err != nil {
+		return bls.G1Affine{}, fmt.Errorf("SetBytes failed: %v", err)
+	}
+	if bytesRead != 96 {
+		return bls.G1Affine{}, fmt.Errorf("SetBytes read %d bytes, expected 96", bytesRead)
+	}
+	return g1Point, nil
+}
+
+// EncodeEthereumG1Point encodes a G1 point to Ethereum format (128 bytes)
+// Format: 64 bytes x (first 16 bytes are 0, last 48 bytes are big-endian) +
+//
+//	64 bytes y (first 16 bytes are 0, last 48 bytes are big-endian)
+func EncodeEthereumG1Point(point bls.G1Affine) []byte {
+	if point.IsInfinity() {
+		return make([]byte, 128)
+	}
+
+	uncompressed := point.Marshal()
+	if len(uncompressed) != 96 {
+		panic(fmt.Sprintf("unexpected G1 uncompressed length: %d", len(uncompressed)))
+	}
+
+	// Extract x and y (48 bytes each, big-endian)
+	xBytes := uncompressed[0:48]
+	yBytes := uncompressed[48:96]
+
+	// Ethereum format: 64 bytes per field element (first 16 bytes are 0, last 48 bytes are the value)
+	output := make([]byte, 128)
+	// Explicitly zero out padding bytes to ensure they are zero
+	// x padding: bytes 0-15
+	// y padding: bytes 64-79
+	for i := 0; i < 16; i++ {
+		output[i] = 0    // x padding
+		output[64+i] = 0 // y padding
+	}
+	copy(output[16:64], xBytes)  // x: skip first 16 bytes, then 48 bytes
+	copy(output[80:128], yBytes) // y: skip first 16 bytes, then 48 bytes
+
+	return output
+}
+
+// EncodeEthereumG2Point encodes a G2 point to Ethereum format (256 bytes)
+// Format: 64 bytes x.C0 + 64 bytes x.C1 + 64 bytes y.C0 + 64 bytes y.C1
+// Each 64-byte field: first 16 bytes are 0, last 48 bytes are big-endian
+func EncodeEthereumG2Point(point bls.G2Affine) []byte {
+	if point.IsInfinity() {
+		return make([]byte, 256)
+	}
+
+	uncompressed := point.Marshal()
+	if len(uncompressed) != 192 {
+		panic(fmt.Sprintf("unexpected G2 uncompressed length: %d", len(uncompressed)))
+	}
+
+	// gnark-crypto format: [x.C1 (48 bytes) + x.C0 (48 bytes) + y.C1 (48 bytes) + y.C0 (48 bytes)]
+	// Ethereum format: [x.C0 (64 bytes) + x.C1 (64 bytes) + y.C0 (64 bytes) + y.C1 (64 bytes)]
+	xC1Bytes := uncompressed[0:48]
+	xC0Bytes := uncompressed[48:96]
+	yC1Bytes := uncompressed[96:144]
+	yC0Bytes := uncompressed[144:192]
+
+	output := make([]byte, 256)
+	// Explicitly zero out all padding bytes to ensure they are zero
+	// Each 64-byte field has 16 bytes of padding at the start
+	for i := 0; i < 16; i++ {
+		output[i] = 0     // x.C0 padding: bytes 0-15
+		output[64+i] = 0  // x.C1 padding: bytes 64-79
+		output[128+i] = 0 // y.C0 padding: bytes 128-143
+		output[192+i] = 0 // y.C1 padding: bytes 192-207
+	}
+	// x.C0: first 64 bytes, skip first 16, then 48 bytes
+	copy(output[16:64], xC0Bytes)
+	// x.C1: second 64 bytes, skip first 16, then 48 bytes
+	copy(output[80:128], xC1Bytes)
+	// y.C0: third 64 bytes, skip first 16, then 48 bytes
+	copy(output[144:192], yC0Bytes)
+	// y.C1: fourth 64 bytes, skip first 16, then 48 bytes
+	copy(output[208:256], yC1Bytes)
+
+	return output
+}
+
+// ConvertG1AffineToCompressed converts a G1Affine point to compressed format (48 bytes)
+func ConvertG1AffineToCompressed(point bls.G1Affine) []byte {
+	uncompressed := point.Marshal()
+	compressed := make([]byte, 48)
+	copy(compressed, uncompressed[:48])
+	compressed[0] |= 0x80 // Set compression flag
+	yBytes := uncompressed[48:96]
+	if IsLexicographicallyLargestFp(yBytes) {
+		compressed[0] |= 0x20 // Set y coordinate sort flag
+	}
+	return compressed
+}
+
+// ConvertG2AffineToCompressed converts a G2Affine point to compressed format (96 bytes)
+// Format matches Neo's G2Affine.ToCompressed():
+// - First 48 bytes: x.C1
+// - Next 48 bytes: x.C0
+// - First byte flags: 0x80 (compression), 0x40 (infinity), 0x20 (sort)
+// The flags are stored in the upper 3 bits of the first byte, while the lower 5 bits
+// are part of the x.C1 coordinate data.
+func ConvertG2AffineToCompressed(point bls.G2Affine) []byte {
+	uncompressed := point.Marshal()
+	compressed := make([]byte, 96)
+
+	// Extract x coordinate: gnark-crypto format is [x.C1 (48) + x.C0 (48) + y.C1 (48) + y.C0 (48)]
+	// Neo format is [x.C1 (48) + x.C0 (48)]
+	copy(compressed, uncompressed[:96]) // Extract x coordinate (x.C1 + x.C0)
+
+	// Clear only the flag bits (0x80, 0x40, 0x20) from the first byte before setting them
+	// The lower 5 bits (0x1F) are part of the x.C1 coordinate data and must be preserved
+	// Note: We use & 0x1F to clear the upper 3 bits (flags) while preserving the lower 5 bits (data)
+	compressed[0] &= 0x1F
+
+	// Set compression flag (MSB) - always set for compressed format
+	compressed[0] |= 0x80
+
+	// Check if point is at infinity
+	if point.IsInfinity() {
+		compressed[0] |= 0x40 // Set infinity flag
+		// For infinity point, Neo's validation requires: infinity -> !sort_flag & x.IsZero
+		// The sort flag should NOT be set for infinity points
+		return compressed
+	}
+
+	// Extract y coordinate to determine sort flag
+	yBytes := uncompressed[96:192] // y coordinate (y.C1 + y.C0)
+	if IsLexicographicallyLargestFp2(yBytes) {
+		compressed[0] |= 0x20 // Set y coordinate sort flag
+	}
+
+	return compressed
+}
+
+// lenientG2 makes ParseEthereumG2PointFromBytes accept non-zero padding and try the
+// alternative coordinate layouts instead of rejecting the input. It is atomic like the
+// other process-wide switches so it can be read from concurrent decoders.
+var lenientG2 atomic.Bool
+
+// SetLenientG2Parsing enables or disables the heuristic recovery of Ethereum G2 points
+// with non-zero padding. It is off by default: padding violations are rejected with
+// ErrNonZeroPadding, as EIP-2537 requires.
+func SetLenientG2Parsing(enabled bool) {
+	lenientG2.Store(enabled)
+}
+
+// LenientG2Parsing reports whether SetLenientG2Parsing(true) is in effect
+func LenientG2Parsing() bool {
+	return lenientG2.Load()
+}
+
+// ParseEthereumG2PointFromBytes parses a G2 point from Ethereum format (256 bytes)
+// Ethereum format: 64 bytes x.C0 (first 16 bytes are 0, last 48 bytes are big-endian) +
+//
+//	64 bytes x.C1 (first 16 bytes are 0, last 48 bytes are big-endian) +
+//	64 bytes y.C0 (first 16 bytes are 0, last 48 bytes are big-endian) +
+//	64 bytes y.C1 (first 16 bytes are 0, last 48 bytes are big-endian)
+//
+// This matches Neo's EncodeEthereumG2 format: [x.C0, x.C1, y.C0, y.C1]
+//
+// Non-zero padding is an ErrNonZeroPadding error unless SetLenientG2Parsing(true) is in
+// effect; only then are the padding bytes tolerated and the alternative layouts tried.
+func ParseEthereumG2PointFromBytes(data []byte) (bls.G2Affine, error) {
+	if len(data) != 256 {
+		return bls.G2Affine{}, fmt.Errorf("ethereum G2 point must be 256 bytes, got %d", len(data))
+	}
+
+	if logging.Enabled(logging.Debug) {
+		logging.Debugf("ParseEthereumG2PointFromBytes received data:")
+		for i, name := range []string{"x.C0", "x.C1", "y.C0", "y.C1"} {
+			logging.Debugf("  %s padding (bytes %d-%d): %x", name, i*64, i*64+16, data[i*64:i*64+16])
+			logging.Debugf("  %s data (bytes %d-%d): %x", name, i*64+16, i*64+64, data[i*64+16:i*64+64])
+		}
+	}
+
+	// Check that first 16 bytes of each field element are zero
+	// Ethereum format: each 64-byte field element has 16 bytes of padding (zeros) followed by 48 bytes of data
+	// In lenient mode we warn about non-zero padding and continue, as the actual data is
+	// normally in the last 48 bytes; strict mode (default) rejects it like EIP-2537
+	hasNonZeroPadding := false
+	var paddingErrors []string
+	for i := 0; i < 16; i++ {
+		if data[i] != 0 {
+			hasNonZeroPadding = true
+			paddingErrors = append(paddingErrors, fmt.Sprintf("x.C0[%d]=0x%02x", i, data[i]))
+		}
+		if data[64+i] != 0 {
+			hasNonZeroPadding = true
+			paddingErrors = append(paddingErrors, fmt.Sprintf("x.C1[%d]=0x%02x", 64+i, data[64+i]))
+		}
+		if data[128+i] != 0 {
+			hasNonZeroPadding = true
+			paddingErrors = append(paddingErrors, fmt.Sprintf("y.C0[%d]=0x%02x", 128+i, data[128+i]))
+		}
+		if data[192+i] != 0 {
+			hasNonZeroPadding = true
+			paddingErrors = append(paddingErrors, fmt.Sprintf("y.C1[%d]=0x%02x", 192+i, data[192+i]))
+		}
+	}
+	if hasNonZeroPadding && !lenientG2.Load() {
+		return bls.G2Affine{}, fmt.Errorf("%w in Ethereum format G2 point: %v (use lenient parsing to try the alternative layouts)", ErrNonZeroPadding, paddingErrors)
+	}
+	if hasNonZeroPadding {
+		// Log warning but continue - the actual coordinate data is in the last 48 bytes of each field
+		logging.Printf("Warning: non-zero padding bytes in Ethereum format G2 point: %v", paddingErrors)
+		logging.Printf("  Continuing anyway - coordinate data is in bytes [16:64], [80:128], [144:192], [208:256]")
+	}
+
+	// Extract coordinates (last 48 bytes of each 64-byte field element, big-endian)
+	// Ethereum/Neo format: [x.C0 (64 bytes), x.C1 (64 bytes), y.C0 (64 bytes), y.C1 (64 bytes)]
+	// Each 64-byte field: first 16 bytes are 0, last 48 bytes are the value
+	// However, if padding bytes are non-zero, the data might be in a different location
+	// Let's try both: standard location and alternative location (if padding is non-zero)
+
+	// Standard extraction (assuming padding is correct)
+	xC0Bytes := data[16:64]   // x.C0 (48 bytes, big-endian) - first 64 bytes, skip first 16
+	xC1Bytes := data[80:128]  // x.C1 (48 bytes, big-endian) - second 64 bytes, skip first 16
+	yC0Bytes := data[144:192] // y.C0 (48 bytes, big-endian) - third 64 bytes, skip first 16
+	yC1Bytes := data[208:256] // y.C1 (48 bytes, big-endian) - fourth 64 bytes, skip first 16
+
+	// Check if this is an infinity point (all coordinates are zero)
+	// Infinity point in Ethereum format: all 256 bytes are zero
+	isInfinity := true
+	for i := 0; i < 256; i++ {
+		if data[i] != 0 {
+			isInfinity = false
+			break
+		}
+	}
+
+	if isInfinity {
+		// Return infinity point directly
+		var infinityPoint bls.G2Affine
+		// G2Affine zero value is infinity point
+		return infinityPoint, nil
+	}
+
+	// If padding is non-zero, the data might actually be in the first 48 bytes of each field
+	// Let's check if the standard extraction produces valid data, and if not, try alternative
+	if hasNonZeroPadding {
+		logging.Verbosef("  Attempting to extract coordinates from standard location [16:64], [80:128], [144:192], [208:256]")
+		// If this fails, we might need to try alternative locations
+	}
+
+	// gnark-crypto's G2Affine.SetBytes only supports compressed format (96 bytes), not uncompressed (192 bytes)
+	// We need to convert Ethereum format to compressed format first
+	// Compressed format: [x.C1 (48 bytes) + x.C0 (48 bytes)] with flags in first byte
+	// This matches the approach used in bls12381vec.MultiExpFromCompressed for G2 points
+
+	// Construct compressed format from x coordinate
+	// Format: [xC1, xC0] (96 bytes total)
+	compressed := make([]byte, 96)
+	copy(compressed[0:48], xC1Bytes)  // x.C1 (first 48 bytes)
+	copy(compressed[48:96], xC0Bytes) // x.C0 (next 48 bytes)
+
+	// Clear flag bits (upper 3 bits) while preserving lower 5 bits of first byte
+	// The lower 5 bits are part of the x.C1 coordinate data
+	compressed[0] &= 0x1F
+
+	// Set compression flag (MSB) - always set for compressed format
+	compressed[0] |= 0x80
+
+	// Determine sort flag based on y coordinate
+	// y coordinate format: [y.C1, y.C0] (96 bytes, big-endian)
+	// Copy into a fresh buffer: appending to the yC1Bytes sub-slice would overwrite the
+	// bytes after the point (the MSM scalar and the next pair)
+	yBytes := append(append(make([]byte, 0, 96), yC1Bytes...), yC0Bytes...)
+	if IsLexicographicallyLargestFp2(yBytes) {
+		compressed[0] |= 0x20 // Set y coordinate sort flag
+	}
+
+	// Parse compressed format using SetBytes (same as bls12381vec.MultiExpFromCompressed)
+	logging.Debugf("Constructed compressed format (first 16 bytes): %x", compressed[0:16])
+	logging.Debugf("xC1Bytes (first 16 bytes): %x", xC1Bytes[0:16])
+	logging.Debugf("xC0Bytes (first 16 bytes): %x", xC0Bytes[0:16])
+
+	var g2Point bls.G2Affine
+	bytesRead, err := g2Point.SetBytes(compressed)
+	if err != nil {
+		// If padding was non-zero and parsing failed, try alternative location
+		// Data might be in compact format [0:48], [48:96], [96:144], [144:192] instead of Ethereum format [16:64], [80:128], [144:192], [208:256]
+		if hasNonZeroPadding {
+			logging.Verbosef("  Standard location failed, trying alternative location [0:48], [48:96], [96:144], [144:192] (compact format)")
+
+			// Try multiple alternative formats
+			// Format 1: Compact format [0:48], [48:96], [96:144], [144:192]
+			xC0BytesAlt1 := data[0:48]
+			xC1BytesAlt1 := data[48:96]
+			yC0BytesAlt1 := data[96:144]
+			yC1BytesAlt1 := data[144:192]
+
+			// Format 2: If padding bytes contain actual data, the format might be wrong
+			// Try using the padding bytes themselves as part of the coordinate data
+			// This is a last resort - if padding bytes are non-zero, maybe they ARE the data
+			// Format: Use first 16 bytes (padding) + next 32 bytes for x.C0, etc.
+			// Actually, let's try a different approach: maybe data is shifted
+			// Format 2: [16:64] for x.C0 (standard), but [0:48] for x.C1 (if padding is wrong)
+			// Or maybe the entire format is different - let's try using padding bytes as coordinate data
+			// Format 2: If padding bytes are non-zero, maybe data is shifted
+			// Try: x.C0 from [0:48] (including padding), x.C1 from [64:112], y.C0 from [128:176], y.C1 from [192:240]
+			// This assumes data might be in a mixed format where some fields use padding bytes
+			xC0BytesAlt2 := data[0:48]    // First 48 bytes (including padding)
+			xC1BytesAlt2 := data[64:112]  // Second field, first 48 bytes (skip padding)
+			yC0BytesAlt2 := data[128:176] // Third field, first 48 bytes (skip padding)
+			yC1BytesAlt2 := data[192:240] // Fourth field, first 48 bytes (skip padding)
+
+			// Try Format 1 first (compact)
+			xC0BytesAlt := xC0BytesAlt1
+			xC1BytesAlt := xC1BytesAlt1
+			yC0BytesAlt := yC0BytesAlt1
+			yC1BytesAlt := yC1BytesAlt1
+
+			// Construct compressed format from alternative location
+			compressedAlt := make([]byte, 96)
+			copy(compressedAlt[0:48], xC1BytesAlt)  // x.C1 (first 48 bytes)
+			copy(compressedAlt[48:96], xC0BytesAlt) // x.C0 (next 48 bytes)
+
+			// Clear flag bits and set compression flag
+			compressedAlt[0] &= 0x1F
+			compressedAlt[0] |= 0x80
+
+			// Determine sort flag based on y coordinate
+			yBytesAlt := append(append(make([]byte, 0, 96), yC1BytesAlt...), yC0BytesAlt...)
+			if IsLexicographicallyLargestFp2(yBytesAlt) {
+				compressedAlt[0] |= 0x20
+			}
+
+			// Try parsing with Format 1 (compact)
+			logging.Verbosef("    Trying Format 1 (compact): [0:48], [48:96], [96:144], [144:192]")
+			bytesReadAlt, errAlt := g2Point.SetBytes(compressedAlt)
+			if errAlt != nil {
+				// Try Format 2
+				logging.Verbosef("    Format 1 failed (%v), trying Format 2 (padding bytes included)", errAlt)
+				compressedAlt2 := make([]byte, 96)
+				copy(compressedAlt2[0:48], xC1BytesAlt2[0:48])
+				copy(compressedAlt2[48:96], xC0BytesAlt2[0:48])
+				compressedAlt2[0] &= 0x1F
+				compressedAlt2[0] |= 0x80
+				yBytesAlt2 := append(append(make([]byte, 0, 96), yC1BytesAlt2[0:48]...), yC0BytesAlt2[0:48]...)
+				if IsLexicographicallyLargestFp2(yBytesAlt2) {
+					compressedAlt2[0] |= 0x20
+				}
+
+				bytesReadAlt2, errAlt2 := g2Point.SetBytes(compressedAlt2)
+				if errAlt2 != nil {
+					return bls.G2Affine{}, fmt.Errorf("failed to parse G2 point from compressed format (tried standard and 2 alternative formats): "+
+						"standard=%v, alt1(compact)=%v, alt2(mixed)=%v. "+
+						"Input: [x.C1(%d), x.C0(%d), y.C1(%d), y.C0(%d)] = %d bytes. "+
+						"Standard compressed: %x (first 16 bytes), "+
+						"Alt1 compressed: %x (first 16 bytes), "+
+						"Alt2 compressed: %x (first 16 bytes)",
+						err, errAlt, errAlt2, len(xC1Bytes), len(xC0Bytes), len(yC1Bytes), len(yC0Bytes), 256,
+						compressed[:16], compressedAlt[:16], compressedAlt2[:16])
+				}
+				bytesReadAlt = bytesReadAlt2
+				errAlt = nil
+				logging.Verbosef("    Format 2 succeeded")
+			} else {
+				logging.Verbosef("    Format 1 (compact) succeeded")
+			}
+			if bytesReadAlt != 96 {
+				return bls.G2Affine{}, fmt.Errorf("SetBytes(alternative) read %d bytes, expected 96", bytesReadAlt)
+			}
+			logging.Printf("  Successfully parsed using alternative location")
+		} else {
+			return bls.G2Affine{}, fmt.Errorf("failed to parse G2 point from compressed format: %v. "+
+				"Input: [x.C1(%d), x.C0(%d), y.C1(%d), y.C0(%d)] = %d bytes. "+
+				"Compressed format: %x (first 16 bytes)",
+				err, len(xC1Bytes), len(xC0Bytes), len(yC1Bytes), len(yC0Bytes), 256, compressed[:16])
+		}
+	}
+	if bytesRead != 96 {
+		return bls.G2Affine{}, fmt.Errorf("SetBytes read %d bytes, expected 96", bytesRead)
+	}
+
+	// Verify the point is on the curve
+	if !g2Point.IsOnCurve() {
+		return bls.G2Affine{}, fmt.Errorf("point is not on the curve")
+	}
+
+	return g2Point, nil
+}
@@ -0,0 +1,219 @@
+package serialization
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Well-known compressed generators (ZCash / IETF encoding)
+const (
+	g1GenCompressed = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	g2GenCompressed = "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+)
+
+// testPoints returns the generators, their negations, infinity and a few random
+// multiples of the generators
+func testPoints(t *testing.T) ([]bls.G1Affine, []bls.G2Affine) {
+	t.Helper()
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var g1Neg bls.G1Affine
+	g1Neg.Neg(&g1Gen)
+	var g2Neg bls.G2Affine
+	g2Neg.Neg(&g2Gen)
+	g1Points := []bls.G1Affine{g1Gen, g1Neg, {}}
+	g2Points := []bls.G2Affine{g2Gen, g2Neg, {}}
+	for i := 0; i < 3; i++ {
+		var k fr.Element
+		if _, err := k.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		kb := k.BigInt(new(big.Int))
+		var p bls.G1Affine
+		var q bls.G2Affine
+		g1Points = append(g1Points, *p.ScalarMultiplication(&g1Gen, kb))
+		g2Points = append(g2Points, *q.ScalarMultiplication(&g2Gen, kb))
+	}
+	return g1Points, g2Points
+}
+
+func TestCompressedEncoding(t *testing.T) {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var g1Inf bls.G1Affine
+	var g2Inf bls.G2Affine
+	g1InfBytes, g2InfBytes := g1Inf.Bytes(), g2Inf.Bytes()
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"G1 generator compressed", ConvertG1AffineToCompressed(g1Gen), g1GenCompressed},
+		{"G2 generator compressed", ConvertG2AffineToCompressed(g2Gen), g2GenCompressed},
+		{"G1 infinity compressed", ConvertG1AffineToCompressed(g1Inf), hex.EncodeToString(g1InfBytes[:])},
+		{"G2 infinity compressed", ConvertG2AffineToCompressed(g2Inf), hex.EncodeToString(g2InfBytes[:])},
+		{"G1 infinity Ethereum encoding is all zeros", EncodeEthereumG1Point(g1Inf), hex.EncodeToString(make([]byte, 128))},
+		{"G2 infinity Ethereum encoding is all zeros", EncodeEthereumG2Point(g2Inf), hex.EncodeToString(make([]byte, 256))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := hex.EncodeToString(tc.got); got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSortFlag(t *testing.T) {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	var g1Neg bls.G1Affine
+	g1Neg.Neg(&g1Gen)
+	var g2Neg bls.G2Affine
+	g2Neg.Neg(&g2Gen)
+	tests := []struct {
+		name   string
+		p, neg []byte
+	}{
+		{"G1", ConvertG1AffineToCompressed(g1Gen), ConvertG1AffineToCompressed(g1Neg)},
+		{"G2", ConvertG2AffineToCompressed(g2Gen), ConvertG2AffineToCompressed(g2Neg)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if (tc.p[0]^tc.neg[0])&0x20 == 0 {
+				t.Errorf("sort flag equal for P and -P: %x, %x", tc.p[0], tc.neg[0])
+			}
+		})
+	}
+}
+
+func TestEthereumParseRejects(t *testing.T) {
+	_, _, g1Gen, _ := bls.Generators()
+	g1Padded := EncodeEthereumG1Point(g1Gen)
+	g1Padded[0] = 0x01
+	tests := []struct {
+		name  string
+		parse func() error
+	}{
+		{"G1 wrong length", func() error { _, err := ParseEthereumG1PointFromBytes(make([]byte, 127)); return err }},
+		{"G2 wrong length", func() error { _, err := ParseEthereumG2PointFromBytes(make([]byte, 255)); return err }},
+		{"G1 non-zero padding", func() error { _, err := ParseEthereumG1PointFromBytes(g1Padded); return err }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.parse(); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestG2PaddingLenient(t *testing.T) {
+	_, _, _, g2Gen := bls.Generators()
+	data := EncodeEthereumG2Point(g2Gen)
+	data[192] = 0x01
+	defer SetLenientG2Parsing(LenientG2Parsing())
+	tests := []struct {
+		name    string
+		lenient bool
+		wantErr error
+	}{
+		{"strict", false, ErrNonZeroPadding},
+		{"lenient", true, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetLenientG2Parsing(tc.lenient)
+			p, err := ParseEthereumG2PointFromBytes(data)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && !p.Equal(&g2Gen) {
+				t.Error("parsed point is not the generator")
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	g1Points, g2Points := testPoints(t)
+	type roundTrip struct {
+		name  string
+		check func() error
+	}
+	var tests []roundTrip
+	for i, p := range g1Points {
+		tests = append(tests,
+			roundTrip{fmt.Sprintf("G1 point %d Ethereum", i), func() error {
+				parsed, err := ParseEthereumG1PointFromBytes(EncodeEthereumG1Point(p))
+				if err != nil {
+					return err
+				}
+				if !parsed.Equal(&p) {
+					return fmt.Errorf("parsed point differs")
+				}
+				return nil
+			}},
+			roundTrip{fmt.Sprintf("G1 point %d compressed matches gnark Bytes()", i), func() error {
+				if b := p.Bytes(); !bytes.Equal(ConvertG1AffineToCompressed(p), b[:]) {
+					return fmt.Errorf("got %x, gnark %x", ConvertG1AffineToCompressed(p), b)
+				}
+				return nil
+			}},
+		)
+	}
+	for i, q := range g2Points {
+		tests = append(tests,
+			roundTrip{fmt.Sprintf("G2 point %d Ethereum", i), func() error {
+				parsed, err := ParseEthereumG2PointFromBytes(EncodeEthereumG2Point(q))
+				if err != nil {
+					return err
+				}
+				if !parsed.Equal(&q) {
+					return fmt.Errorf("parsed point differs")
+				}
+				return nil
+			}},
+			roundTrip{fmt.Sprintf("G2 point %d compressed matches gnark Bytes()", i), func() error {
+				if b := q.Bytes(); !bytes.Equal(ConvertG2AffineToCompressed(q), b[:]) {
+					return fmt.Errorf("got %x, gnark %x", ConvertG2AffineToCompressed(q), b)
+				}
+				return nil
+			}},
+		)
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.check(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestNeoGT(t *testing.T) {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	z, err := bls.Pair([]bls.G1Affine{g1Gen}, []bls.G2Affine{g2Gen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	neo, gnark := EncodeNeoGT(&z), z.Marshal()
+	for i := 0; i < 12; i++ {
+		t.Run(fmt.Sprintf("coefficient %d is gnark's %d", i, 11-i), func(t *testing.T) {
+			if !bytes.Equal(neo[i*48:(i+1)*48], gnark[(11-i)*48:(12-i)*48]) {
+				t.Errorf("got %x, want %x", neo[i*48:(i+1)*48], gnark[(11-i)*48:(12-i)*48])
+			}
+		})
+	}
+	parsed, err := DecodeNeoGT(neo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(&z) {
+		t.Error("Neo GT round-trip differs")
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// campaignShard selects a deterministic slice of a campaign: fixture k (numbered across all
+// entries in config order) belongs to shard k % Count. The zero value means "unsharded".
+type campaignShard struct {
+	Index int
+	Count int
+}
+
+// parseShard parses "--shard i/n" (0-based i)
+func parseShard(s string) (campaignShard, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return campaignShard{}, fmt.Errorf("invalid shard '%s' (expected i/n, e.g. 0/4)", s)
+	}
+	i, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	n, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || n < 1 || i < 0 || i >= n {
+		return campaignShard{}, fmt.Errorf("invalid shard '%s' (expected 0 <= i < n)", s)
+	}
+	return campaignShard{Index: i, Count: n}, nil
+}
+
+func (s campaignShard) sharded() bool {
+	return s.Count > 1
+}
+
+// includes reports whether global fixture index k belongs to this shard
+func (s campaignShard) includes(k int) bool {
+	if !s.sharded() {
+		return true
+	}
+	return k%s.Count == s.Index
+}
+
+// String is also the label the shard's sub-seed is derived from
+func (s campaignShard) String() string {
+	if !s.sharded() {
+		return "0/1"
+	}
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// campaignManifest lists the fixtures one campaign run (or shard) generated. Shard
+// manifests are merged into manifest.json with "campaign --merge".
+type campaignManifest struct {
+	Campaign string                  `json:"campaign"`
+	Seed     string                  `json:"seed,omitempty"`
+	Shard    int                     `json:"shard"`
+	Shards   int                     `json:"shards"`
+	Fixtures []campaignManifestEntry `json:"fixtures"`
+}
+
+type campaignManifestEntry struct {
+	Index     int    `json:"index"`
+	Entry     int    `json:"entry"`
+	Name      string `json:"name"`
+	Group     string `json:"group"`
+	Pairs     int    `json:"pairs"`
+	Expected  string `json:"expected"`
+	Dir       string `json:"dir,omitempty"`
+	Reproduce string `json:"reproduce,omitempty"`
+}
+
+func newManifestEntry(index, entry int, f multiExpFixture, dir, reproduce string) campaignManifestEntry {
+	return campaignManifestEntry{Index: index, Entry: entry, Name: f.Name, Group: f.groupName(),
+		Pairs: len(f.Points), Expected: hex.EncodeToString(f.Expected), Dir: dir, Reproduce: reproduce}
+}
+
+// manifestPath is manifest.json for unsharded runs and manifest-shard-i-of-n.json otherwise
+func manifestPath(emitDir string, shard campaignShard) string {
+	if !shard.sharded() {
+		return filepath.Join(emitDir, "manifest.json")
+	}
+	return filepath.Join(emitDir, fmt.Sprintf("manifest-shard-%d-of-%d.json", shard.Index, shard.Count))
+}
+
+// writeCampaignManifest writes a manifest through the --sink, like the fixtures it lists
+func writeCampaignManifest(path string, m campaignManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := outputSink.write(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+	fmt.Printf("Wrote manifest: %s (%d fixtures)\n", outputSink.describe(path), len(m.Fixtures))
+	return nil
+}
+
+// mergeShardManifests combines every shard manifest in emitDir into manifest.json. All
+// shards of one split must be present and agree on campaign and seed, and together they
+// must cover each fixture index exactly once.
+func mergeShardManifests(cfg campaignConfig) error {
+	paths, err := filepath.Glob(filepath.Join(cfg.EmitDir, "manifest-shard-*-of-*.json"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no shard manifests found in %s", cfg.EmitDir)
+	}
+	sort.Strings(paths)
+
+	merged := campaignManifest{Campaign: cfg.Name, Shards: -1}
+	seen := map[int]bool{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var m campaignManifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("invalid manifest %s: %v", path, err)
+		}
+		if m.Campaign != cfg.Name {
+			return fmt.Errorf("%s belongs to campaign '%s', not '%s'", path, m.Campaign, cfg.Name)
+		}
+		if merged.Shards == -1 {
+			merged.Shards, merged.Seed = m.Shards, m.Seed
+		} else if m.Shards != merged.Shards || m.Seed != merged.Seed {
+			return fmt.Errorf("%s comes from a different split (%d shards, seed %s)", path, m.Shards, m.Seed)
+		}
+		if seen[m.Shard] {
+			return fmt.Errorf("shard %d/%d appears twice", m.Shard, m.Shards)
+		}
+		seen[m.Shard] = true
+		merged.Fixtures = append(merged.Fixtures, m.Fixtures...)
+	}
+	var missing []string
+	for i := 0; i < merged.Shards; i++ {
+		if !seen[i] {
+			missing = append(missing, fmt.Sprintf("%d/%d", i, merged.Shards))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing shard manifests: %s", strings.Join(missing, ", "))
+	}
+
+	sort.Slice(merged.Fixtures, func(a, b int) bool { return merged.Fixtures[a].Index < merged.Fixtures[b].Index })
+	var total campaignEstimate
+	for _, e := range cfg.Entries {
+		total.add(estimateCampaignEntry(e))
+	}
+	for k, f := range merged.Fixtures {
+		if f.Index != k {
+			return fmt.Errorf("fixture index %d is missing or duplicated", k)
+		}
+	}
+	if len(merged.Fixtures) != total.Fixtures {
+		return fmt.Errorf("shards contain %d fixtures, campaign defines %d", len(merged.Fixtures), total.Fixtures)
+	}
+
+	fmt.Printf("=== Campaign %s: merging %d shard manifests ===\n", cfg.Name, len(paths))
+	return writeCampaignManifest(filepath.Join(cfg.EmitDir, "manifest.json"), merged)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"evm/logging"
+)
+
+// fixtureSink receives every file the fixture emitters and campaign manifests produce
+// (global --sink). path is where the directory sink writes the file, e.g.
+// fixtures/neo-edge/g1_zero_scalar/Bls12381MultiExpFixture.cs; the other sinks use it
+// as the file's name.
+type fixtureSink interface {
+	write(path string, data []byte) error
+	// describe names where write(path) went, for the "Wrote" lines
+	describe(path string) string
+	close() error
+}
+
+// outputSink is the sink selected with --sink
+var outputSink fixtureSink = dirSink{}
+
+// parseSink parses a --sink value: dir (default), stdout, file:<path.jsonl> or an
+// http(s):// collector URL
+func parseSink(value string) (fixtureSink, error) {
+	switch {
+	case value == "dir":
+		return dirSink{}, nil
+	case value == "stdout":
+		return stdoutSink{w: os.Stdout}, nil
+	case strings.HasPrefix(value, "file:"):
+		path := strings.TrimPrefix(value, "file:")
+		if path == "" {
+			return nil, fmt.Errorf("--sink file: needs a path (e.g. file:fixtures.jsonl)")
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("--sink %s: %v", value, err)
+		}
+		return &fileSink{path: path, f: f}, nil
+	case strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://"):
+		return &httpSink{url: value, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	}
+	return nil, fmt.Errorf("invalid --sink '%s' (valid: dir, stdout, file:<path.jsonl>, http(s)://<collector>)", value)
+}
+
+// dirSink writes each file at its path, creating the directory tree
+type dirSink struct{}
+
+func (dirSink) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (dirSink) describe(path string) string { return path }
+func (dirSink) close() error                { return nil }
+
+// stdoutSink prints each file under a "--- path ---" header
+type stdoutSink struct{ w io.Writer }
+
+func (s stdoutSink) write(path string, data []byte) error {
+	if _, err := fmt.Fprintf(s.w, "--- %s ---\n", path); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		_, err := fmt.Fprintln(s.w)
+		return err
+	}
+	return nil
+}
+
+func (stdoutSink) describe(path string) string { return "stdout (" + path + ")" }
+func (stdoutSink) close() error                { return nil }
+
+// sinkRecord is one line of a file: sink
+type sinkRecord struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// fileSink writes every file as one JSON line into a single file
+type fileSink struct {
+	path string
+	f    *os.File
+}
+
+func (s *fileSink) write(path string, data []byte) error {
+	line, err := json.Marshal(sinkRecord{Path: path, Content: string(data)})
+	if err != nil {
+		return err
+	}
+	_, err = s.f.Write(append(line, '\n'))
+	return err
+}
+
+func (s *fileSink) describe(path string) string { return s.path + " (" + path + ")" }
+func (s *fileSink) close() error                { return s.f.Close() }
+
+// httpSink POSTs every file to a collector, with its path in the X-Fixture-Path header.
+// Network errors and 5xx responses are retried twice; any other non-2xx status fails.
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+// httpSinkAttempts is the number of tries per file
+const httpSinkAttempts = 3
+
+func (s *httpSink) write(path string, data []byte) error {
+	contentType := "text/plain; charset=utf-8"
+	if strings.HasSuffix(path, ".json") {
+		contentType = "application/json"
+	}
+	var lastErr error
+	for attempt := 1; attempt <= httpSinkAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * time.Second)
+		}
+		req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Fixture-Path", filepath.ToSlash(path))
+		resp, err := s.client.Do(req)
+		if err != nil {
+			lastErr = err
+			logging.Verbosef("POST %s: attempt %d of %d failed: %v", path, attempt, httpSinkAttempts, err)
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode/100 == 2:
+			return nil
+		case resp.StatusCode >= 500:
+			lastErr = fmt.Errorf("collector returned %s", resp.Status)
+			logging.Verbosef("POST %s: attempt %d of %d failed: %v", path, attempt, httpSinkAttempts, lastErr)
+		default:
+			return fmt.Errorf("POST %s: collector returned %s", path, resp.Status)
+		}
+	}
+	return fmt.Errorf("POST %s: %v (after %d attempts)", path, lastErr, httpSinkAttempts)
+}
+
+func (s *httpSink) describe(path string) string { return "POST " + s.url + " (" + path + ")" }
+func (s *httpSink) close() error                { return nil }
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The curve groups have order r * h: E(Fp) for G1 and the twist E'(Fp2) for G2. The
+// cofactors, with multiplicity:
+//
+//	h1 = 0x396c8c005555e1568c00aaab0000aaab = 3 * 11^2 * 10177^2 * 859267^2 * 52437899^2
+//	h2 = 13^2 * 23^2 * 2713 * 11953 * 262069 * (a 448-bit prime)
+//
+// A point on the curve is in the prime-order subgroup iff [r]P = O. Its cofactor part
+// [r]P has an order dividing h, which gives points of order l for every prime l of h.
+var (
+	g1CofactorPrimes = bigInts("3", "11", "11", "10177", "10177", "859267", "859267", "52437899", "52437899")
+	g2CofactorPrimes = bigInts("13", "13", "23", "23", "2713", "11953", "262069",
+		"402096035359507321594726366720466575392706800671181159425656785868777272553337714697862511267018014931937703598282857976535744623203249")
+)
+
+func bigInts(values ...string) []*big.Int {
+	out := make([]*big.Int, len(values))
+	for i, v := range values {
+		out[i], _ = new(big.Int).SetString(v, 10)
+	}
+	return out
+}
+
+// curvePoint is a G1 or G2 point that need not be in the subgroup
+type curvePoint struct {
+	g2     bool
+	p1     bls.G1Affine
+	p2     bls.G2Affine
+	orderH []*big.Int // the cofactor primes of the group
+}
+
+func newCurvePoint(g2 bool) curvePoint {
+	if g2 {
+		return curvePoint{g2: true, orderH: g2CofactorPrimes}
+	}
+	return curvePoint{orderH: g1CofactorPrimes}
+}
+
+func (c curvePoint) group() string {
+	if c.g2 {
+		return "G2"
+	}
+	return "G1"
+}
+
+func (c curvePoint) mul(k *big.Int) curvePoint {
+	if c.g2 {
+		c.p2 = g2MulAnyOrder(&c.p2, k)
+	} else {
+		c.p1 = g1MulAnyOrder(&c.p1, k)
+	}
+	return c
+}
+
+func (c curvePoint) add(o curvePoint) curvePoint {
+	if c.g2 {
+		var a, b bls.G2Jac
+		a.FromAffine(&c.p2)
+		b.FromAffine(&o.p2)
+		c.p2.FromJacobian(a.AddAssign(&b))
+	} else {
+		var a, b bls.G1Jac
+		a.FromAffine(&c.p1)
+		b.FromAffine(&o.p1)
+		c.p1.FromJacobian(a.AddAssign(&b))
+	}
+	return c
+}
+
+func (c curvePoint) isInfinity() bool {
+	if c.g2 {
+		return c.p2.IsInfinity()
+	}
+	return c.p1.IsInfinity()
+}
+
+func (c curvePoint) inSubgroup() bool {
+	if c.g2 {
+		return c.p2.IsInSubGroup()
+	}
+	return c.p1.IsInSubGroup()
+}
+
+// clearCofactor maps the point into the subgroup (gnark's efficient cofactor clearing)
+func (c curvePoint) clearCofactor() curvePoint {
+	if c.g2 {
+		c.p2.ClearCofactor(&c.p2)
+	} else {
+		c.p1.ClearCofactor(&c.p1)
+	}
+	return c
+}
+
+func (c curvePoint) compressed() []byte {
+	if c.g2 {
+		return serialization.ConvertG2AffineToCompressed(c.p2)
+	}
+	return serialization.ConvertG1AffineToCompressed(c.p1)
+}
+
+func (c curvePoint) ethereum() []byte {
+	if c.g2 {
+		return serialization.EncodeEthereumG2Point(c.p2)
+	}
+	return serialization.EncodeEthereumG1Point(c.p1)
+}
+
+// cofactor is the product of the group's cofactor primes
+func (c curvePoint) cofactor() *big.Int {
+	h := big.NewInt(1)
+	for _, l := range c.orderH {
+		h.Mul(h, l)
+	}
+	return h
+}
+
+// torsionOrder returns the order m of the cofactor part [r]P, a divisor of h, by removing
+// every prime of h that [m/l][r]P = O allows
+func (c curvePoint) torsionOrder() *big.Int {
+	torsion := c.mul(fr.Modulus())
+	m := c.cofactor()
+	for _, l := range c.orderH {
+		var q big.Int
+		q.Quo(m, l)
+		if torsion.mul(&q).isInfinity() {
+			m = &q
+		}
+	}
+	return m
+}
+
+// order returns the order of the point as "r", "r * m" or "m", with m factored into the
+// cofactor primes
+func (c curvePoint) order() string {
+	if c.isInfinity() {
+		return "1"
+	}
+	m := c.torsionOrder()
+	var factors []string
+	for i, l := range c.orderH {
+		if i > 0 && c.orderH[i-1].Cmp(l) == 0 {
+			continue // repeated primes are adjacent
+		}
+		e := 0
+		for t := new(big.Int).Set(m); new(big.Int).Mod(t, l).Sign() == 0; t.Quo(t, l) {
+			e++
+		}
+		if e == 0 {
+			continue
+		}
+		f := l.String()
+		if l.BitLen() > 64 {
+			f = fmt.Sprintf("p%d", l.BitLen()) // the large prime factor of h2
+		}
+		if e > 1 {
+			f += fmt.Sprintf("^%d", e)
+		}
+		factors = append(factors, f)
+	}
+	if !c.mul(m).isInfinity() {
+		factors = append([]string{"r"}, factors...)
+	}
+	return strings.Join(factors, " * ")
+}
+
+// randomCurvePoint draws a point on the whole curve (not only the subgroup) from vectorRand
+// by sampling x until x^3 + b is a square
+func randomCurvePoint(g2 bool) (curvePoint, error) {
+	c, _, err := sampleCurvePoint(g2)
+	return c, err
+}
+
+// sampleCurvePoint is randomCurvePoint that also returns the number of x values drawn.
+// About half of them are on the curve, and y is gnark's square root of x^3 + b.
+func sampleCurvePoint(g2 bool) (curvePoint, int, error) {
+	c := newCurvePoint(g2)
+	for tries := 1; ; tries++ {
+		a0, err := uniformBelow(vectorRand, fp.Modulus())
+		if err != nil {
+			return c, tries, err
+		}
+		if !g2 {
+			c.p1.X.SetBigInt(a0)
+			rhs := g1CurveRHS(&c.p1.X)
+			if rhs.Legendre() == 1 {
+				c.p1.Y.Sqrt(&rhs)
+				return c, tries, nil
+			}
+			continue
+		}
+		a1, err := uniformBelow(vectorRand, fp.Modulus())
+		if err != nil {
+			return c, tries, err
+		}
+		c.p2.X.A0.SetBigInt(a0)
+		c.p2.X.A1.SetBigInt(a1)
+		rhs := g2CurveRHS(&c.p2.X)
+		if rhs.Legendre() == 1 {
+			c.p2.Y.Sqrt(&rhs)
+			return c, tries, nil
+		}
+	}
+}
+
+// lowOrderPoint returns a point of prime order l, a cofactor prime: [m/l][r]R for a random
+// curve point R whose cofactor part has an order m divisible by l. The group need not be
+// cyclic, so [r*h/l]R itself can be O for every R.
+func lowOrderPoint(g2 bool, l *big.Int) (curvePoint, error) {
+	for {
+		r, err := randomCurvePoint(g2)
+		if err != nil {
+			return r, err
+		}
+		m := r.torsionOrder()
+		if new(big.Int).Mod(m, l).Sign() == 0 {
+			return r.mul(fr.Modulus()).mul(new(big.Int).Quo(m, l)), nil
+		}
+	}
+}
+
+// runSubgroupCheckMode reports whether a point on the curve is in the prime-order subgroup,
+// with two independent tests and the order of its cofactor part
+func runSubgroupCheckMode(args []string) (int, error) {
+	fs := newFlagSet("subgroup-check")
+	pointHex := fs.String("point", "", "Point hex: 48/96-byte compressed, 96/192-byte gnark uncompressed or 128/256-byte Ethereum")
+	format := fs.String("format", "auto", "Encoding: auto (by length and flags), "+strings.Join(validateKinds, ", "))
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *pointHex == "" {
+		return 0, usageErrorf("--point is required")
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*pointHex), "0x"))
+	if err != nil {
+		return 0, usageErrorf("--point: invalid hex: %v", err)
+	}
+	kind := *format
+	if kind == "auto" {
+		var ok bool
+		if kind, ok = detectPointKind(data); !ok {
+			return 0, fmt.Errorf("%d bytes matches no encoding (48, 96, 128, 192, 256)", len(data))
+		}
+	}
+	if _, ok := pointKindInfo[kind]; !ok {
+		return 0, usageErrorf("invalid --format '%s' (valid: auto, %s)", kind, strings.Join(validateKinds, ", "))
+	}
+	g1, g2, err := decodePointKind(kind, data, false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", pointKindInfo[kind].Description, err)
+	}
+	c := newCurvePoint(kind[1] == '2')
+	c.p1, c.p2 = g1, g2
+
+	fmt.Printf("=== Subgroup Check (%s) ===\n", c.group())
+	fmt.Printf("Encoding: %s\n", pointKindInfo[kind].Description)
+	endomorphism := c.inSubgroup()
+	scalarMul := c.mul(fr.Modulus()).isInfinity()
+	fmt.Printf("Endomorphism test (gnark IsInSubGroup): %v\n", endomorphism)
+	fmt.Printf("[r]P = O (double-and-add):              %v\n", scalarMul)
+	if endomorphism != scalarMul {
+		fmt.Println("❌ The two subgroup tests disagree")
+		return 1, nil
+	}
+	order := c.order()
+	fmt.Printf("Order: %s\n", order)
+	if endomorphism {
+		fmt.Println("✅ In the prime-order subgroup")
+	} else {
+		cleared := c.clearCofactor()
+		fmt.Println("Not in the prime-order subgroup: strict decoders must reject it")
+		fmt.Printf("Cofactor cleared (in subgroup): %x\n", cleared.compressed())
+		reportOutput("cleared", hex.EncodeToString(cleared.compressed()))
+	}
+
+	recordVerdictInput(hex.EncodeToString(data))
+	reportInput("point", hex.EncodeToString(data))
+	reportOutput("group", strings.ToLower(c.group()))
+	reportOutput("in_subgroup", endomorphism)
+	reportOutput("order", order)
+	return 0, nil
+}
+
+// runSampleCurvePointMode samples random points on the whole curve, which are outside the
+// subgroup with overwhelming probability, and with --clear also their cofactor-cleared
+// counterparts, checking that those are in the subgroup
+func runSampleCurvePointMode(args []string) (int, error) {
+	fs := newFlagSet("sample-curve-point")
+	group := fs.String("group", "both", "Group: g1, g2 or both")
+	count := fs.Int("count", 1, "Points per group")
+	withCleared := fs.Bool("clear", false, "Also print each point with the cofactor cleared")
+	seed := registerSeedFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	groups := map[string][]bool{"g1": {false}, "g2": {true}, "both": {false, true}}[*group]
+	if groups == nil {
+		return 0, usageErrorf("invalid --group '%s' (valid: g1, g2, both)", *group)
+	}
+	if *count < 1 {
+		return 0, usageErrorf("--count must be at least 1")
+	}
+	if err := applySeed(*seed); err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	check := func(ok bool, what string) {
+		if !ok {
+			fmt.Printf("  ❌ %s\n", what)
+			failed++
+		}
+	}
+	var points []map[string]any
+	for _, g2 := range groups {
+		fmt.Printf("=== Random Curve Points (%s) ===\n", newCurvePoint(g2).group())
+		for i := 0; i < *count; i++ {
+			c, tries, err := sampleCurvePoint(g2)
+			if err != nil {
+				return failed, err
+			}
+			fmt.Printf("[%d] x values drawn: %d\n", i, tries)
+			if g2 {
+				fmt.Printf("  x: c0=%x c1=%x\n", c.p2.X.A0.Bytes(), c.p2.X.A1.Bytes())
+				fmt.Printf("  y: c0=%x c1=%x\n", c.p2.Y.A0.Bytes(), c.p2.Y.A1.Bytes())
+				check(c.p2.IsOnCurve(), "the point is not on the curve")
+			} else {
+				fmt.Printf("  x: %x\n  y: %x\n", c.p1.X.Bytes(), c.p1.Y.Bytes())
+				check(c.p1.IsOnCurve(), "the point is not on the curve")
+			}
+			order := c.order()
+			fmt.Printf("  Order: %s\n", order)
+			fmt.Printf("  In subgroup: %v\n", c.inSubgroup())
+			fmt.Printf("  Compressed: %x\n", c.compressed())
+			fmt.Printf("  Ethereum: %x\n", c.ethereum())
+			point := map[string]any{
+				"group":       strings.ToLower(c.group()),
+				"compressed":  hex.EncodeToString(c.compressed()),
+				"ethereum":    hex.EncodeToString(c.ethereum()),
+				"order":       order,
+				"in_subgroup": c.inSubgroup(),
+			}
+			if *withCleared {
+				cleared := c.clearCofactor()
+				fmt.Printf("  Cleared compressed: %x\n", cleared.compressed())
+				fmt.Printf("  Cleared Ethereum: %x\n", cleared.ethereum())
+				check(cleared.inSubgroup() && cleared.mul(fr.Modulus()).isInfinity(), "the cleared point is not in the subgroup")
+				point["cleared_compressed"] = hex.EncodeToString(cleared.compressed())
+				point["cleared_ethereum"] = hex.EncodeToString(cleared.ethereum())
+			}
+			points = append(points, point)
+		}
+		fmt.Println()
+	}
+	if failed == 0 && *withCleared {
+		fmt.Printf("✅ %d points on the curve, every cleared point is in the subgroup\n", len(points))
+	} else if failed == 0 {
+		fmt.Printf("✅ %d points on the curve\n", len(points))
+	}
+	if len(points) == 1 {
+		recordVerdictResult(points[0]["ethereum"].(string))
+	}
+	reportOutput("points", points)
+	return failed, nil
+}
+
+// subgroupVector is one generated point with the verdict strict decoders must return
+type subgroupVector struct {
+	Name        string
+	Description string
+	Point       curvePoint
+	Accept      bool
+}
+
+// subgroupVectors builds the vectors of one group: points of every small prime order of the
+// cofactor, the same added to a subgroup point (order r * l), a general cofactor-part point,
+// a raw curve point and, as the positive control, that point with the cofactor cleared
+func subgroupVectors(g2 bool) ([]subgroupVector, error) {
+	prefix := strings.ToLower(newCurvePoint(g2).group())
+	var out []subgroupVector
+	seen := map[string]bool{}
+	subgroup, err := randomSubgroupPoint(g2)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range newCurvePoint(g2).orderH {
+		if seen[l.String()] || l.BitLen() > 64 {
+			continue
+		}
+		seen[l.String()] = true
+		t, err := lowOrderPoint(g2, l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out,
+			subgroupVector{fmt.Sprintf("%s_order_%s", prefix, l), fmt.Sprintf("point of order %s", l), t, false},
+			subgroupVector{fmt.Sprintf("%s_order_r_times_%s", prefix, l), fmt.Sprintf("subgroup point plus a point of order %s (order r * %s)", l, l), subgroup.add(t), false})
+	}
+	raw, err := randomCurvePoint(g2)
+	if err != nil {
+		return nil, err
+	}
+	out = append(out,
+		subgroupVector{prefix + "_cofactor_part", "[r]R for a random curve point R (order divides h)", raw.mul(fr.Modulus()), false},
+		subgroupVector{prefix + "_uncleared", "random curve point, cofactor not cleared", raw, false},
+		subgroupVector{prefix + "_cleared", "the uncleared point with the cofactor cleared (positive control)", raw.clearCofactor(), true})
+	return out, nil
+}
+
+// randomSubgroupPoint is [k]G for a random k
+func randomSubgroupPoint(g2 bool) (curvePoint, error) {
+	c := newCurvePoint(g2)
+	var err error
+	if g2 {
+		c.p2, err = randomOnG2()
+	} else {
+		c.p1, err = randomOnG1()
+	}
+	return c, err
+}
+
+// runSubgroupVectorsMode generates on-curve points outside the subgroup and checks that the
+// strict decoders reject every one of them with NOT_IN_SUBGROUP
+func runSubgroupVectorsMode(args []string) (int, error) {
+	fs := newFlagSet("subgroup-vectors")
+	group := fs.String("group", "both", "Group: g1, g2 or both")
+	seed := registerSeedFlag(fs)
+	outPath := fs.String("out", "", "Also write the vectors as a fixture file (JSON, the corrupt-nearby/verify-errors format)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	groups := map[string][]bool{"g1": {false}, "g2": {true}, "both": {false, true}}[*group]
+	if groups == nil {
+		return 0, usageErrorf("invalid --group '%s' (valid: g1, g2, both)", *group)
+	}
+	if err := applySeed(*seed); err != nil {
+		return 0, err
+	}
+
+	var fixtures []negativeFixture
+	failed := 0
+	for _, g2 := range groups {
+		vectors, err := subgroupVectors(g2)
+		if err != nil {
+			return failed, err
+		}
+		group := newCurvePoint(g2).group()
+		fmt.Printf("=== Subgroup Vectors (%s) ===\n", group)
+		for _, v := range vectors {
+			compressed, ethereum := v.Point.compressed(), v.Point.ethereum()
+			fmt.Printf("%s: %s\n", v.Name, v.Description)
+			fmt.Printf("  Order: %s\n", v.Point.order())
+			fmt.Printf("  Compressed: %x\n", compressed)
+			fmt.Printf("  Ethereum: %x\n", ethereum)
+			lower := strings.ToLower(group)
+			msmInput := append(append([]byte{}, ethereum...), scalarTo32Bytes(big.NewInt(1))...)
+			_, compressedErr := validatePoint(lower+"c", compressed)
+			_, ethereumErr := validatePoint(lower+"e", ethereum)
+			msmCode, _, _ := firstValidationError("eip2537", lower+"msm", msmInput)
+			compressedCode, _ := classifyError(compressedErr)
+			ethereumCode, _ := classifyError(ethereumErr)
+			checks := []struct {
+				fx   negativeFixture
+				code errorCode
+			}{
+				{negativeFixture{Name: v.Name + "_compressed", Op: "deserialize-" + lower, Input: hex.EncodeToString(compressed)}, compressedCode},
+				{negativeFixture{Name: v.Name + "_eip2537", Op: "eip2537-" + lower, Input: hex.EncodeToString(ethereum)}, ethereumCode},
+				{negativeFixture{Name: v.Name + "_msm", Op: lower + "msm", Input: hex.EncodeToString(msmInput)}, msmCode},
+			}
+			for _, c := range checks {
+				fx := c.fx
+				fx.Expected, fx.Reason = "accept", v.Description
+				if !v.Accept {
+					fx.Expected, fx.ErrorCode = "reject", errNotInSubgroup
+				}
+				if c.code != fx.ErrorCode {
+					fmt.Printf("  ❌ %s: got %q, want %q\n", fx.Op, c.code, fx.ErrorCode)
+					failed++
+				}
+				fixtures = append(fixtures, fx)
+			}
+		}
+		fmt.Println()
+	}
+	if failed == 0 {
+		fmt.Printf("✅ %d fixtures: the strict decoders reject every point outside the subgroup\n", len(fixtures))
+	}
+	reportOutput("fixtures", len(fixtures))
+	recordQuietFixtures(fixtures)
+	if *outPath != "" {
+		if err := writeNegativeFixtures(*outPath, "subgroup-vectors --group "+*group, fixtures); err != nil {
+			return failed, err
+		}
+		fmt.Printf("Wrote %s\n", *outPath)
+	}
+	return failed, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// defaultSlowThreshold marks results that took long enough to be worth a performance test
+const defaultSlowThreshold = 100 * time.Millisecond
+
+// timingOptions controls per-operation timing annotations (--timing, --slow-threshold).
+// Slow results are collected so generation runs can list them as performance test
+// candidates at the end.
+type timingOptions struct {
+	Enabled       bool
+	SlowThreshold time.Duration
+	slow          []string
+}
+
+// registerTimingFlags adds --timing and --slow-threshold to a flag set
+func registerTimingFlags(fs *flag.FlagSet) *timingOptions {
+	t := &timingOptions{}
+	fs.BoolVar(&t.Enabled, "timing", false, "Annotate each computed result with its execution time")
+	fs.DurationVar(&t.SlowThreshold, "slow-threshold", defaultSlowThreshold, "Flag results slower than this as performance test candidates (with --timing)")
+	return t
+}
+
+// report prints the execution time of one result and records it if it is slow
+func (t *timingOptions) report(name string, elapsed time.Duration) {
+	if t == nil || !t.Enabled {
+		return
+	}
+	fmt.Printf("Execution time: %v\n", elapsed.Round(time.Microsecond))
+	if elapsed >= t.SlowThreshold {
+		fmt.Printf("⚠️  Slow result (>= %v): performance test candidate\n", t.SlowThreshold)
+		t.slow = append(t.slow, fmt.Sprintf("%s (%v)", name, elapsed.Round(time.Microsecond)))
+	}
+}
+
+// printSummary lists the slow results seen so far
+func (t *timingOptions) printSummary() {
+	if t == nil || !t.Enabled {
+		return
+	}
+	fmt.Println("=== Timing Summary ===")
+	if len(t.slow) == 0 {
+		fmt.Printf("No result exceeded %v\n", t.SlowThreshold)
+		return
+	}
+	fmt.Printf("Performance test candidates (>= %v):\n", t.SlowThreshold)
+	for _, s := range t.slow {
+		fmt.Printf("  %s\n", s)
+	}
+}
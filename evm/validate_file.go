@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// validateKinds are the encodings validate-file recognizes, in summary order. The fuzz
+// kind names are reused; g1u/g2u are gnark's uncompressed encodings (Marshal).
+var validateKinds = []string{"g1c", "g2c", "g1u", "g2u", "g1e", "g2e"}
+
+// detectPointKind guesses the encoding from the length; 96 bytes is a compressed G2 point
+// when the compression flag is set and an uncompressed G1 point otherwise
+func detectPointKind(data []byte) (string, bool) {
+	switch len(data) {
+	case 48:
+		return "g1c", true
+	case 96:
+		if data[0]&0x80 != 0 {
+			return "g2c", true
+		}
+		return "g1u", true
+	case 192:
+		return "g2u", true
+	case 128:
+		return "g1e", true
+	case 256:
+		return "g2e", true
+	}
+	return "", false
+}
+
+// validatePoint strictly decodes one encoding and reports whether it is the point at infinity
+func validatePoint(kind string, data []byte) (infinity bool, err error) {
+	switch kind {
+	case "g1c":
+		var p bls.G1Affine
+		p, err = serialization.DecodeCompressedG1Point(data)
+		return p.IsInfinity(), err
+	case "g2c":
+		var p bls.G2Affine
+		p, err = serialization.DecodeCompressedG2Point(data)
+		return p.IsInfinity(), err
+	case "g1e":
+		var p bls.G1Affine
+		p, err = serialization.DecodeEIP2537G1Point(data, true)
+		return p.IsInfinity(), err
+	case "g2e":
+		var p bls.G2Affine
+		p, err = serialization.DecodeEIP2537G2Point(data, true)
+		return p.IsInfinity(), err
+	case "g1u", "g2u":
+		eth, infinity, err := uncompressedToEIP2537(data, kind == "g2u")
+		if err != nil || infinity {
+			return infinity, err
+		}
+		if kind == "g1u" {
+			_, err = serialization.DecodeEIP2537G1Point(eth, true)
+		} else {
+			_, err = serialization.DecodeEIP2537G2Point(eth, true)
+		}
+		return false, err
+	}
+	return false, fmt.Errorf("unknown format '%s'", kind)
+}
+
+// uncompressedToEIP2537 checks the flags of gnark's uncompressed encoding (x || y, G2
+// coordinates as C1 || C0) and re-lays the coordinates out in the EIP-2537 format, so the
+// strict decoder reports canonicity, curve and subgroup errors in its usual order
+func uncompressedToEIP2537(data []byte, g2 bool) (eth []byte, infinity bool, err error) {
+	size := bls.SizeOfG1AffineUncompressed
+	if g2 {
+		size = bls.SizeOfG2AffineUncompressed
+	}
+	if len(data) != size {
+		return nil, false, fmt.Errorf("%w: uncompressed point must be %d bytes, got %d", serialization.ErrInvalidLength, size, len(data))
+	}
+	flags := data[0] & 0xe0
+	switch {
+	case flags&0x80 != 0:
+		return nil, false, fmt.Errorf("%w: compression flag set on an uncompressed point", serialization.ErrBadFlags)
+	case flags&0x20 != 0:
+		return nil, false, fmt.Errorf("%w: sort flag set on an uncompressed point", serialization.ErrBadFlags)
+	case flags&0x40 != 0:
+		rest := append([]byte{data[0] &^ 0xe0}, data[1:]...)
+		if !bytes.Equal(rest, make([]byte, len(rest))) {
+			return nil, false, fmt.Errorf("%w: infinity flag set with non-zero coordinates", serialization.ErrBadFlags)
+		}
+		return nil, true, nil
+	}
+	fields := make([][]byte, size/48)
+	for i := range fields {
+		fields[i] = data[i*48 : (i+1)*48]
+	}
+	if g2 {
+		// C1 || C0 per coordinate -> C0, C1
+		fields[0], fields[1], fields[2], fields[3] = fields[1], fields[0], fields[3], fields[2]
+	}
+	for _, f := range fields {
+		eth = append(eth, make([]byte, 16)...)
+		eth = append(eth, f...)
+	}
+	return eth, false, nil
+}
+
+// validationCode classifies a decoder error
+func validationCode(err error) errorCode {
+	if code, ok := classifyError(err); ok {
+		return code
+	}
+	return "UNKNOWN"
+}
+
+// splitDumpLine returns the label and the hex field of a dump line: the last comma- or
+// whitespace-separated field is the encoding, anything before it is kept as the label
+func splitDumpLine(line string) (label, encoding string) {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == ';'
+	})
+	if len(fields) == 0 {
+		return "", ""
+	}
+	encoding = fields[len(fields)-1]
+	label = strings.Join(fields[:len(fields)-1], " ")
+	return label, strings.TrimPrefix(strings.TrimPrefix(encoding, "0x"), "0X")
+}
+
+// validateFileStats collects the summary of a run
+type validateFileStats struct {
+	lines, skipped, valid, invalid, infinity, duplicates int
+	byKind                                               map[string][2]int // kind -> valid, invalid
+	byCode                                               map[errorCode]int
+	seen                                                 map[string]int // valid encoding -> first line
+}
+
+// runValidateFileMode validates every point encoding of a file (or stdin with "-")
+func runValidateFileMode(args []string) (int, error) {
+	fs := newFlagSet("validate-file")
+	path := fs.String("file", "", "File with one point encoding per line (\"-\" for stdin); blank lines and # comments are skipped")
+	format := fs.String("format", "auto", "Encoding of every line: auto (by length and flags), "+strings.Join(validateKinds, ", "))
+	invalidOnly := fs.Bool("invalid-only", false, "Print only the invalid lines (the summary is always printed)")
+	outPath := fs.String("out", "", "Also write the verdicts as CSV (line,label,format,verdict,error_code,reason)")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *path == "" {
+		return 0, usageErrorf("--file is required")
+	}
+	if *format != "auto" {
+		valid := false
+		for _, k := range validateKinds {
+			valid = valid || *format == k
+		}
+		if !valid {
+			return 0, usageErrorf("invalid --format '%s' (valid: auto, %s)", *format, strings.Join(validateKinds, ", "))
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if *path != "-" {
+		f, err := os.Open(*path)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		in = f
+	}
+	var csvOut *csv.Writer
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		csvOut = csv.NewWriter(f)
+		csvOut.Write([]string{"line", "label", "format", "verdict", "error_code", "reason"})
+	}
+
+	stats := validateFileStats{byKind: map[string][2]int{}, byCode: map[errorCode]int{}, seen: map[string]int{}}
+	scanner := bufio.NewScanner(in)
+	// Ethereum G2 points are 512 hex chars; allow long label columns too
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	fmt.Printf("=== Validate File: %s ===\n", *path)
+	for n := 1; scanner.Scan(); n++ {
+		stats.lines++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			stats.skipped++
+			continue
+		}
+		label, encoding := splitDumpLine(line)
+
+		kind := *format
+		var code errorCode
+		var reason string
+		infinity := false
+		data, err := hex.DecodeString(encoding)
+		if err != nil {
+			kind, code, reason = "?", "BAD_HEX", err.Error()
+		} else if kind == "auto" {
+			var ok bool
+			if kind, ok = detectPointKind(data); !ok {
+				kind = "?"
+				code, reason = errBadLength, fmt.Sprintf("%d bytes matches no encoding (48, 96, 128, 192, 256)", len(data))
+			}
+		}
+		if code == "" {
+			if infinity, err = validatePoint(kind, data); err != nil {
+				code, reason = validationCode(err), err.Error()
+			}
+		}
+
+		counts := stats.byKind[kind]
+		verdict := "valid"
+		if code == "" {
+			stats.valid++
+			counts[0]++
+			if infinity {
+				stats.infinity++
+				reason = "point at infinity"
+			}
+			if first, dup := stats.seen[kind+encoding]; dup {
+				stats.duplicates++
+				reason = strings.TrimPrefix(reason+"; ", "; ") + fmt.Sprintf("duplicate of line %d", first)
+			} else {
+				stats.seen[kind+encoding] = n
+			}
+		} else {
+			verdict = "invalid"
+			stats.invalid++
+			stats.byCode[code]++
+			counts[1]++
+		}
+		stats.byKind[kind] = counts
+
+		if code != "" || !*invalidOnly {
+			out := fmt.Sprintf("%6d  %-3s  %-7s", n, kind, verdict)
+			if code != "" {
+				out += " " + string(code)
+			}
+			if reason != "" {
+				out += "  # " + reason
+			}
+			if label != "" {
+				out += "  [" + label + "]"
+			}
+			fmt.Println(strings.TrimRight(out, " "))
+		}
+		if csvOut != nil {
+			csvOut.Write([]string{fmt.Sprint(n), label, kind, verdict, string(code), reason})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %v", *path, err)
+	}
+	if csvOut != nil {
+		csvOut.Flush()
+		if err := csvOut.Error(); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %v", *outPath, err)
+		}
+	}
+
+	printValidateFileSummary(stats)
+	if *outPath != "" {
+		fmt.Printf("Wrote %s\n", *outPath)
+	}
+	reportOutput("valid", stats.valid)
+	reportOutput("invalid", stats.invalid)
+	return stats.invalid, nil
+}
+
+func printValidateFileSummary(s validateFileStats) {
+	fmt.Println()
+	fmt.Println("=== Summary ===")
+	fmt.Printf("Lines: %d (%d encodings, %d blank or comment)\n", s.lines, s.valid+s.invalid, s.skipped)
+	fmt.Printf("Valid: %d (%d at infinity, %d duplicates)\n", s.valid, s.infinity, s.duplicates)
+	fmt.Printf("Invalid: %d\n", s.invalid)
+	kinds := append(append([]string{}, validateKinds...), "?")
+	for _, k := range kinds {
+		if c, ok := s.byKind[k]; ok {
+			fmt.Printf("  %-3s %6d valid, %6d invalid\n", k, c[0], c[1])
+		}
+	}
+	codes := []errorCode{}
+	for _, t := range errorTaxonomy {
+		codes = append(codes, t.Code)
+	}
+	for _, code := range append(codes, "BAD_HEX", "UNKNOWN") {
+		if n := s.byCode[code]; n > 0 {
+			fmt.Printf("  %-16s %6d\n", code, n)
+		}
+	}
+	if s.invalid == 0 {
+		fmt.Println("✅ Every encoding is valid")
+	} else {
+		fmt.Printf("❌ %d invalid encoding(s)\n", s.invalid)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm/bls12381vec"
+	"evm/serialization"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// validationOrderVector is an input with several simultaneous defects. Profiles disagree
+// (or implementations drift) on which defect is reported, so each vector pins the first
+// error a profile must surface.
+type validationOrderVector struct {
+	Name    string
+	Op      string // g1msm, g2msm, pairing, deserialize-g1, deserialize-g2
+	Defects []errorCode
+	Input   []byte
+}
+
+// validationOrderProfiles documents the validation order of each profile
+var validationOrderProfiles = map[string]string{
+	"eip2537": "Whole-input length first, then pairs in order; within a point each 64-byte field element " +
+		"(x, then y; G2: x.C0, x.C1, y.C0, y.C1) is checked for padding then canonicity, " +
+		"then the point is checked on curve, then in subgroup.",
+	"neo": "Ethereum alias methods follow the EIP-2537 order. Compressed deserialization checks " +
+		"length, flags, canonical x, on curve, then subgroup; Neo throws a single FormatException type, " +
+		"so the category is what a diagnostic implementation should report.",
+}
+
+// pairSizeForOp returns the pair size for an Ethereum-format operation (0 if not applicable)
+func pairSizeForOp(op string) int {
+	switch op {
+	case "g1msm":
+		return bls12381vec.G1MSMPairLength
+	case "g2msm":
+		return bls12381vec.G2MSMPairLength
+	case "pairing":
+		return 384
+	}
+	return 0
+}
+
+// firstValidationError evaluates an input under a profile and returns the first error code
+// and the pair index it occurred at (-1 for whole-input errors). applicable is false when
+// the profile does not define the operation.
+func firstValidationError(profile, op string, input []byte) (code errorCode, pair int, applicable bool) {
+	switch op {
+	case "deserialize-g1", "deserialize-g2":
+		if profile != "neo" {
+			return "", 0, false
+		}
+		var err error
+		if op == "deserialize-g1" {
+			_, err = serialization.DecodeCompressedG1Point(input)
+		} else {
+			_, err = serialization.DecodeCompressedG2Point(input)
+		}
+		code, _ := classifyError(err)
+		return code, -1, true
+	}
+
+	pairSize := pairSizeForOp(op)
+	if len(input) == 0 || len(input)%pairSize != 0 {
+		return errBadLength, -1, true
+	}
+	for i := 0; i*pairSize < len(input); i++ {
+		pairBytes := input[i*pairSize : (i+1)*pairSize]
+		var err error
+		switch op {
+		case "g1msm":
+			_, err = serialization.DecodeEIP2537G1Point(pairBytes[0:128], true)
+		case "g2msm":
+			_, err = serialization.DecodeEIP2537G2Point(pairBytes[0:256], true)
+		case "pairing":
+			if _, err = serialization.DecodeEIP2537G1Point(pairBytes[0:128], true); err == nil {
+				_, err = serialization.DecodeEIP2537G2Point(pairBytes[128:384], true)
+			}
+		}
+		if code, ok := classifyError(err); ok {
+			return code, i, true
+		}
+	}
+	return "", 0, true
+}
+
+// validationOrderVectors builds the multi-defect inputs
+func validationOrderVectors() []validationOrderVector {
+	_, _, g1Gen, g2Gen := bls.Generators()
+	scalar := make([]byte, 32)
+	scalar[31] = 7
+
+	g1Valid := serialization.EncodeEthereumG1Point(g1Gen)
+	g1OffCurve := serialization.EncodeEthereumG1Point(g1PointNotOnCurve(g1Gen))
+	g1NoSubgroup := serialization.EncodeEthereumG1Point(g1PointOnCurveNotInSubgroup(1))
+	g2Valid := serialization.EncodeEthereumG2Point(g2Gen)
+
+	// p's 48-byte big-endian encoding; any value >= p is non-canonical
+	pBytes := make([]byte, 48)
+	fp.Modulus().FillBytes(pBytes)
+
+	withPadding := func(point []byte, offset int) []byte {
+		out := append([]byte{}, point...)
+		out[offset] = 0x01
+		return out
+	}
+	withNonCanonical := func(point []byte, fieldOffset int) []byte {
+		out := append([]byte{}, point...)
+		copy(out[fieldOffset+16:fieldOffset+64], pBytes)
+		return out
+	}
+	concat := func(parts ...[]byte) []byte {
+		var out []byte
+		for _, p := range parts {
+			out = append(out, p...)
+		}
+		return out
+	}
+
+	g1Compressed := serialization.ConvertG1AffineToCompressed(g1Gen)
+	noCompressionFlag := append([]byte{}, g1Compressed...)
+	noCompressionFlag[0] &^= 0x80
+	nonCanonicalCompressed := append([]byte{}, pBytes...)
+	nonCanonicalCompressed[0] |= 0x80
+	noFlagNonCanonical := append([]byte{}, pBytes...)
+	infinitySortNonZero := append([]byte{}, g1Compressed...)
+	infinitySortNonZero[0] |= 0xe0
+	notSubgroupCompressed := serialization.ConvertG1AffineToCompressed(g1PointOnCurveNotInSubgroup(1))
+
+	return []validationOrderVector{
+		{"g1msm_length_padding_offcurve", "g1msm", []errorCode{errBadLength, errBadPadding, errNotOnCurve},
+			concat(withPadding(g1OffCurve, 0), scalar, []byte{0x00})},
+		{"g1msm_padding_offcurve", "g1msm", []errorCode{errBadPadding, errNotOnCurve},
+			concat(withPadding(g1OffCurve, 0), scalar)},
+		{"g1msm_ypadding_offcurve", "g1msm", []errorCode{errBadPadding, errNotOnCurve},
+			concat(withPadding(g1OffCurve, 64), scalar)},
+		{"g1msm_x_noncanonical_y_padding", "g1msm", []errorCode{errNonCanonical, errBadPadding},
+			concat(withPadding(withNonCanonical(g1Valid, 0), 64), scalar)},
+		{"g1msm_x_padding_y_noncanonical", "g1msm", []errorCode{errBadPadding, errNonCanonical},
+			concat(withPadding(withNonCanonical(g1Valid, 64), 0), scalar)},
+		{"g1msm_noncanonical_offcurve", "g1msm", []errorCode{errNonCanonical, errNotOnCurve},
+			concat(withNonCanonical(g1OffCurve, 64), scalar)},
+		{"g1msm_pair0_subgroup_pair1_padding", "g1msm", []errorCode{errNotInSubgroup, errBadPadding},
+			concat(g1NoSubgroup, scalar, withPadding(g1Valid, 0), scalar)},
+		{"g1msm_pair1_offcurve_pair2_noncanonical", "g1msm", []errorCode{errNotOnCurve, errNonCanonical},
+			concat(g1Valid, scalar, g1OffCurve, scalar, withNonCanonical(g1Valid, 0), scalar)},
+		{"g2msm_xc0_noncanonical_xc1_padding", "g2msm", []errorCode{errNonCanonical, errBadPadding},
+			concat(withPadding(withNonCanonical(g2Valid, 0), 64), scalar)},
+		{"g2msm_yc1_padding_offcurve", "g2msm", []errorCode{errBadPadding, errNotOnCurve},
+			concat(withPadding(serialization.EncodeEthereumG2Point(g2PointNotOnCurve(g2Gen)), 192), scalar)},
+		{"pairing_g1_offcurve_g2_padding", "pairing", []errorCode{errNotOnCurve, errBadPadding},
+			concat(g1OffCurve, withPadding(g2Valid, 0))},
+		{"pairing_length_g1_subgroup", "pairing", []errorCode{errBadLength, errNotInSubgroup},
+			concat(g1NoSubgroup, g2Valid[:255])},
+		{"deserialize_g1_length_noflag", "deserialize-g1", []errorCode{errBadLength, errBadFlags},
+			append(append([]byte{}, noCompressionFlag...), 0x00)},
+		{"deserialize_g1_noflag_noncanonical", "deserialize-g1", []errorCode{errBadFlags, errNonCanonical},
+			noFlagNonCanonical},
+		{"deserialize_g1_infinity_sort_nonzero_x", "deserialize-g1", []errorCode{errBadFlags},
+			infinitySortNonZero},
+		{"deserialize_g1_noncanonical", "deserialize-g1", []errorCode{errNonCanonical, errNotOnCurve},
+			nonCanonicalCompressed},
+		{"deserialize_g1_not_in_subgroup", "deserialize-g1", []errorCode{errNotInSubgroup},
+			notSubgroupCompressed},
+	}
+}
+
+// runValidationOrderMode prints every multi-defect vector with the first error each profile must report
+func runValidationOrderMode(args []string) error {
+	fs := newFlagSet("validation-order")
+	profile := fs.String("profile", "", "Profile to evaluate (eip2537, neo; default: all)")
+	outPath := fs.String("out", "", "Also write the vectors as a self-describing fixture file (JSON), one fixture per vector and profile")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	profiles := []string{"eip2537", "neo"}
+	if *profile != "" {
+		if _, ok := validationOrderProfiles[*profile]; !ok {
+			return fmt.Errorf("unknown profile '%s' (valid: eip2537, neo)", *profile)
+		}
+		profiles = []string{*profile}
+	}
+
+	fmt.Println("=== Validation-Order Conformance Vectors ===")
+	for _, name := range profiles {
+		fmt.Printf("Profile %s: %s\n", name, validationOrderProfiles[name])
+	}
+	fmt.Println()
+
+	var fixtures []negativeFixture
+	for _, v := range validationOrderVectors() {
+		defects := make([]string, len(v.Defects))
+		for i, d := range v.Defects {
+			defects[i] = string(d)
+		}
+		fmt.Printf("Vector: %s\n", v.Name)
+		fmt.Printf("  Operation: %s\n", v.Op)
+		fmt.Printf("  Defects: %s\n", strings.Join(defects, ", "))
+		fmt.Printf("  Input (%d bytes): %x\n", len(v.Input), v.Input)
+		for _, name := range profiles {
+			code, pair, applicable := firstValidationError(name, v.Op, v.Input)
+			switch {
+			case !applicable:
+				fmt.Printf("  Expected first error (%s): n/a\n", name)
+			case code == "":
+				return fmt.Errorf("vector %s unexpectedly valid under profile %s", v.Name, name)
+			case pair < 0:
+				fmt.Printf("  Expected first error (%s): %s (whole input)\n", name, code)
+			default:
+				fmt.Printf("  Expected first error (%s): %s (pair %d)\n", name, code, pair)
+			}
+			if !applicable {
+				continue
+			}
+			fx := negativeFixture{Name: v.Name, Op: v.Op, Profile: name, Input: hex.EncodeToString(v.Input),
+				Expected: "reject", ErrorCode: code, Reason: "defects: " + strings.Join(defects, ", ")}
+			if pair >= 0 {
+				fx.Pair = &pair
+			}
+			fixtures = append(fixtures, fx)
+		}
+		fmt.Println()
+	}
+	recordQuietFixtures(fixtures)
+	if *outPath != "" {
+		if err := writeNegativeFixtures(*outPath, "validation-order", fixtures); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s (%d fixtures)\n", *outPath, len(fixtures))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"evm/logging"
+)
+
+// Every command ends with one machine-parsable line so logs of ad-hoc runs can be grepped
+// and correlated later:
+//
+//	VERDICT op=<mode> input=<kind>:<digest> result=<kind>:<digest> status=<ok|fail|panic> exit=<code>
+//
+// Digests are the first 16 hex digits of a SHA-256. The input digest covers the decoded
+// input bytes (kind "hex") for modes that take a hex input, otherwise the argument list
+// (kind "args"). The result digest covers the result bytes (kind "hex") for modes that
+// compute a single result, otherwise everything the command wrote to stdout ("stdout"). A
+// failed run that recorded no result reports result=none rather than a digest of its
+// error output.
+//
+// At --quiet the verdict line is dropped and so is the command's output. Modes that
+// compute a single result print it in hex. Generators print one line per vector (its name
+// and expected result) and decode its coordinates, through recordQuietResult; a failed
+// run adds its status. Self-tests and verifiers print only the status: ok, fail or panic.
+type commandVerdict struct {
+	op         string
+	input      string
+	result     string
+	resultHex  []byte   // the single result, printed at --quiet
+	quietLines []string // the result lines of modes without a single result
+
+	stdout *os.File // the real stdout; os.Stdout is a pipe teed into out while running
+	out    hash.Hash
+	done   chan struct{}
+	once   sync.Once
+}
+
+var verdict *commandVerdict
+
+func verdictDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// verdictOp names the command the way the dispatcher resolves it
+func verdictOp(args []string) string {
+	name, _ := resolveCommand(args[min(len(args), 1):])
+	return name
+}
+
+// startVerdict tees stdout so the final output can be digested. It must run before the
+// command prints anything.
+func startVerdict(args []string) {
+	v := &commandVerdict{
+		op:     verdictOp(args),
+		input:  "args:" + verdictDigest([]byte(strings.Join(args[min(len(args), 2):], "\x00"))),
+		stdout: os.Stdout,
+		out:    sha256.New(),
+		done:   make(chan struct{}),
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		// Without the tee the verdict still prints, with an empty stdout digest
+		close(v.done)
+		verdict = v
+		return
+	}
+	os.Stdout = w
+	var sink io.Writer = v.stdout
+	if report != nil {
+		// JSON mode: the output goes into the document instead of the terminal
+		sink = &report.stdout
+	} else if quietOutput() {
+		sink = io.Discard
+	}
+	var styled *hexStyleWriter
+	if !outputHexStyle.plain() {
+		styled = &hexStyleWriter{w: sink}
+		sink = styled
+	}
+	go func() {
+		io.Copy(io.MultiWriter(sink, v.out), r)
+		if styled != nil {
+			styled.flush()
+		}
+		r.Close()
+		close(v.done)
+	}()
+	verdict = v
+}
+
+// recordVerdictInput sets the input digest from the hex input of the operation
+func recordVerdictInput(inputHex string) {
+	if verdict == nil {
+		return
+	}
+	if data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(inputHex), "0x")); err == nil {
+		verdict.input = "hex:" + verdictDigest(data)
+	}
+}
+
+// recordVerdictResult sets the result digest from the hex result of the operation
+func recordVerdictResult(resultHex string) {
+	if verdict == nil {
+		return
+	}
+	if data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(resultHex), "0x")); err == nil {
+		verdict.result = "hex:" + verdictDigest(data)
+		verdict.resultHex = data
+	}
+}
+
+// recordQuietResult adds a line that --quiet prints for a mode without a single result,
+// e.g. a generated vector's name and expected value
+func recordQuietResult(fields ...string) {
+	if verdict == nil {
+		return
+	}
+	verdict.quietLines = append(verdict.quietLines, strings.Join(fields, " "))
+}
+
+// quietOutput reports whether only results are printed (--quiet, text output)
+func quietOutput() bool {
+	return logging.CurrentLevel() == logging.Quiet && report == nil
+}
+
+// finishVerdict restores stdout and prints the verdict line, or the JSON document with
+// --format json. Only the first call prints.
+func finishVerdict(status string, code int) {
+	v := verdict
+	if v == nil {
+		return
+	}
+	v.once.Do(func() {
+		if os.Stdout != v.stdout {
+			os.Stdout.Close()
+			os.Stdout = v.stdout
+		}
+		<-v.done
+		result := v.result
+		switch {
+		case result == "" && status != "ok":
+			result = "none"
+		case result == "":
+			result = "stdout:" + hex.EncodeToString(v.out.Sum(nil)[:8])
+		}
+		if report != nil {
+			printJSONReport(v.op, v.input, result, status, code)
+			return
+		}
+		if quietOutput() {
+			switch {
+			case v.resultHex != nil:
+				fmt.Println(styleHex(v.resultHex))
+			case len(v.quietLines) > 0:
+				for _, line := range v.quietLines {
+					fmt.Println(restyleHexLine(line))
+				}
+				if status != "ok" {
+					fmt.Println(status)
+				}
+			default:
+				fmt.Println(status)
+			}
+			return
+		}
+		fmt.Printf("VERDICT op=%s input=%s result=%s status=%s exit=%d\n", v.op, v.input, result, status, code)
+	})
+}
+
+// exit prints the verdict and exits; commands use it instead of os.Exit
+func exit(code int) {
+	status := "ok"
+	if code != 0 {
+		status = "fail"
+	}
+	finishVerdict(status, code)
+	os.Exit(code)
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// verify-file is the conformance runner: it recomputes every case of JSON corpora and
+// reports each mismatch. Two layouts are read:
+//   - precompile files in the ethereum/tests layout ({Input, Expected | ExpectedError, Name,
+//     Gas} entries), as eip2537-suite writes them; the operation comes from the file name
+//     (blsG1Add.json, fail-blsG1Add.json, ...) or --op
+//   - MultiExp fixtures: a --format json document of a generating command, an array of its
+//     fixtures or a single fixture
+
+// verifyFileResult is the outcome of one corpus file
+type verifyFileResult struct {
+	path       string
+	layout     string
+	cases      int
+	mismatches int
+}
+
+// detectVectorLayout tells the two corpus layouts apart by their keys
+func detectVectorLayout(data []byte) string {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var entries []map[string]json.RawMessage
+		if json.Unmarshal(data, &entries) == nil && len(entries) > 0 {
+			if _, ok := entries[0]["Input"]; ok {
+				return "ethereum"
+			}
+			if _, ok := entries[0]["points"]; ok {
+				return "fixtures"
+			}
+		}
+		return ""
+	}
+	var doc map[string]json.RawMessage
+	if json.Unmarshal(data, &doc) != nil {
+		return ""
+	}
+	if _, ok := doc["fixtures"]; ok {
+		return "fixtures"
+	}
+	if _, ok := doc["points"]; ok {
+		return "fixtures"
+	}
+	return ""
+}
+
+// verifyEthVectorFile recomputes a precompile file: the output or the rejection, the
+// rejection code (the entry's ErrorCode, or its error message when that maps onto the
+// taxonomy, as go-ethereum's messages do) and the gas when the file lists it
+func verifyEthVectorFile(path string, p eip2537Precompile, verbose bool) (verifyFileResult, error) {
+	res := verifyFileResult{path: path, layout: "ethereum/tests " + p.Name}
+	entries, err := readEthVectorFile(path)
+	if err != nil {
+		return res, err
+	}
+	for i, e := range entries {
+		v, err := e.vector(p, i)
+		if err != nil {
+			return res, err
+		}
+		res.cases++
+		m := e.mismatch(v)
+		if m == "" && e.ExpectedError != "" {
+			code, _ := classifyError(v.failure)
+			want, ok := e.ErrorCode, e.ErrorCode != ""
+			if !ok {
+				want, ok = mapError(builtinErrorMaps["geth"], e.ExpectedError)
+			}
+			if ok && want != code {
+				m = fmt.Sprintf("file expects error %q (%s), locally rejected with %s: %v", e.ExpectedError, want, code, v.failure)
+			}
+		}
+		if m == "" && e.ExpectedError == "" && e.Gas != nil {
+			if gas := p.gas(v.input); gas != *e.Gas {
+				m = fmt.Sprintf("file expects gas %d, locally %d", *e.Gas, gas)
+			}
+		}
+		if m != "" {
+			fmt.Printf("❌ %s: %s\n", v.name, m)
+			res.mismatches++
+		} else if verbose {
+			fmt.Printf("✅ %s\n", v.name)
+		}
+	}
+	return res, nil
+}
+
+// verifyFixtureFile recomputes MultiExp fixtures from their points and scalars and
+// compares every stored representation
+func verifyFixtureFile(path string, verbose bool) (verifyFileResult, error) {
+	res := verifyFileResult{path: path, layout: "MultiExp fixtures"}
+	fixtures, err := loadJSONFixtures(path)
+	if err != nil {
+		return res, err
+	}
+	for i, jf := range fixtures {
+		name := jf.Name
+		if name == "" {
+			name = fmt.Sprintf("fixture[%d]", i)
+		}
+		res.cases++
+		lines, err := checkJSONFixture(jf)
+		if err != nil {
+			lines = []string{err.Error()}
+		}
+		if len(lines) > 0 {
+			fmt.Printf("❌ %s:\n", name)
+			for _, line := range lines {
+				fmt.Printf("    %s\n", line)
+			}
+			res.mismatches++
+		} else if verbose {
+			fmt.Printf("✅ %s\n", name)
+		}
+	}
+	return res, nil
+}
+
+// checkJSONFixture recomputes a fixture and lists the fields that differ; fields missing
+// from the file are not compared
+func checkJSONFixture(jf jsonFixture) ([]string, error) {
+	if len(jf.Points) == 0 || len(jf.Points) != len(jf.Scalars) {
+		return nil, fmt.Errorf("needs the same, non-zero number of points and scalars (got %d and %d)", len(jf.Points), len(jf.Scalars))
+	}
+	useG2 := strings.EqualFold(jf.Group, "g2") || jf.Group == "" && len(jf.Points[0]) == 2*bls.SizeOfG2AffineCompressed
+	var g1Points []bls.G1Affine
+	var g2Points []bls.G2Affine
+	scalars := make([]*big.Int, len(jf.Scalars))
+	for i, h := range jf.Points {
+		data, err := hex.DecodeString(strings.TrimPrefix(h, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("points[%d]: invalid hex: %v", i, err)
+		}
+		if useG2 {
+			var p bls.G2Affine
+			if _, err := p.SetBytes(data); err != nil {
+				return nil, fmt.Errorf("points[%d]: %v", i, err)
+			}
+			g2Points = append(g2Points, p)
+		} else {
+			var p bls.G1Affine
+			if _, err := p.SetBytes(data); err != nil {
+				return nil, fmt.Errorf("points[%d]: %v", i, err)
+			}
+			g1Points = append(g1Points, p)
+		}
+		k, _, err := parseScalarAnyForm(jf.Scalars[i], "dec")
+		if err != nil {
+			return nil, fmt.Errorf("scalars[%d]: %v", i, err)
+		}
+		if k.Sign() < 0 {
+			return nil, fmt.Errorf("scalars[%d] is negative", i)
+		}
+		scalars[i] = k
+	}
+	f, err := computeMultiExpFixture(jf.Name, g1Points, g2Points, scalars, useG2)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, c := range []struct {
+		field, want string
+		got         []byte
+	}{
+		{"ethereum_input", jf.EthereumInput, f.EthereumInput},
+		{"expected", jf.Expected, f.Expected},
+		{"expected_ethereum", jf.ExpectedEthereum, f.ExpectedEthereum},
+	} {
+		if c.want == "" {
+			continue
+		}
+		if got := hex.EncodeToString(c.got); !strings.EqualFold(strings.TrimPrefix(c.want, "0x"), got) {
+			lines = append(lines, fmt.Sprintf("%s: file %s, recomputed %s", c.field, c.want, got))
+		}
+	}
+	return lines, nil
+}
+
+// collectVectorFiles expands directories into their *.json files
+func collectVectorFiles(paths []string) ([]string, map[string]bool, error) {
+	var files []string
+	fromDir := map[string]bool{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			files = append(files, m)
+			fromDir[m] = true
+		}
+	}
+	return files, fromDir, nil
+}
+
+// runVerifyFileMode recomputes every case of one or more corpus files or directories
+func runVerifyFileMode(args []string) (int, error) {
+	fs := newFlagSet("verify-file")
+	file := fs.String("file", "", "Corpus files or directories of *.json files (comma-separated)")
+	op := fs.String("op", "", "Operation of ethereum/tests files whose name does not tell: "+strings.Join(eip2537PrecompileNames(), ", "))
+	verbose := fs.Bool("verbose", false, "Also list the cases that match")
+	if err := parseFlags(fs, args); err != nil {
+		return 0, err
+	}
+	if *file == "" {
+		return 0, usageErrorf("--file is required")
+	}
+	var forced *eip2537Precompile
+	if *op != "" {
+		selected, err := selectEip2537Precompiles(*op)
+		if err != nil {
+			return 0, err
+		}
+		if len(selected) != 1 {
+			return 0, usageErrorf("--op takes a single operation")
+		}
+		forced = &selected[0]
+	}
+	files, fromDir, err := collectVectorFiles(splitList(*file))
+	if err != nil {
+		return 0, err
+	}
+	recordVerdictInput(*file)
+	reportInput("file", *file)
+
+	fmt.Println("=== Corpus Verification ===")
+	var results []verifyFileResult
+	cases, failed := 0, 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return failed, err
+		}
+		var res verifyFileResult
+		switch detectVectorLayout(data) {
+		case "ethereum":
+			p, ok := precompileForVectorFile(path)
+			if forced != nil {
+				p, ok = *forced, true
+			}
+			if !ok {
+				if fromDir[path] {
+					fmt.Printf("⚠️  %s: skipped, cannot infer the operation from the name (pass --op)\n", path)
+					continue
+				}
+				return failed, usageErrorf("cannot infer the operation from %s; pass --op", path)
+			}
+			fmt.Printf("--- %s (ethereum/tests, %s) ---\n", path, p.Name)
+			res, err = verifyEthVectorFile(path, p, *verbose)
+		case "fixtures":
+			fmt.Printf("--- %s (MultiExp fixtures) ---\n", path)
+			res, err = verifyFixtureFile(path, *verbose)
+		default:
+			if fromDir[path] {
+				fmt.Printf("⚠️  %s: skipped, not a vector file\n", path)
+				continue
+			}
+			return failed, fmt.Errorf("%s: neither an ethereum/tests precompile file nor MultiExp fixtures", path)
+		}
+		if err != nil {
+			return failed, err
+		}
+		results = append(results, res)
+		cases += res.cases
+		failed += res.mismatches
+	}
+	if len(results) == 0 {
+		return 0, fmt.Errorf("no vector files found in %s", *file)
+	}
+
+	fmt.Println("\n=== Summary ===")
+	for _, r := range results {
+		mark := "✅"
+		if r.mismatches > 0 {
+			mark = "❌"
+		}
+		fmt.Printf("%s %-48s %-29s %5d cases, %d mismatches\n", mark, r.path, r.layout, r.cases, r.mismatches)
+	}
+	fmt.Printf("Files: %d, cases: %d, mismatches: %d\n", len(results), cases, failed)
+	if failed == 0 {
+		fmt.Println("✅ Every case matches the recomputed result")
+	}
+	reportOutput("files", len(results))
+	reportOutput("cases", cases)
+	reportOutput("mismatches", failed)
+	return failed, nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// loadWeightsCSV reads integer weights (e.g. validator stakes) from one column of a CSV
+// file. column is a header name or a 0-based index; empty selects the last column.
+// A first row whose weight cell is not a number is treated as a header.
+func loadWeightsCSV(path, column string) ([]*big.Int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	var weights []*big.Int
+	col := -1
+	for row := 0; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if row == 0 {
+			col, err = weightColumnIndex(record, column)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+		}
+		idx := col
+		if idx < 0 {
+			idx = len(record) - 1
+		}
+		if idx >= len(record) {
+			return nil, fmt.Errorf("%s: row %d has no column %d", path, row+1, idx)
+		}
+		cell := strings.ReplaceAll(strings.TrimSpace(record[idx]), "_", "")
+		w, ok := new(big.Int).SetString(cell, 10)
+		if !ok {
+			if row == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("%s: row %d: invalid weight '%s' (integers only)", path, row+1, record[idx])
+		}
+		if w.Sign() < 0 || w.Cmp(fr.Modulus()) >= 0 {
+			return nil, fmt.Errorf("%s: row %d: weight %s is outside [0, r-1]", path, row+1, w.String())
+		}
+		weights = append(weights, w)
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("%s: no weights found", path)
+	}
+	return weights, nil
+}
+
+// weightColumnIndex resolves --column against the first row: -1 means the last column
+func weightColumnIndex(header []string, column string) (int, error) {
+	if column == "" {
+		return -1, nil
+	}
+	if n, err := strconv.Atoi(column); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("column index must be non-negative, got %d", n)
+		}
+		return n, nil
+	}
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), column) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("column '%s' not found in header", column)
+}
+
+// sampleWeights draws count weights uniformly from the supplied list (with replacement),
+// i.e. from the empirical stake distribution. count <= 0 uses every weight once, in order.
+func sampleWeights(weights []*big.Int, count int) ([]*big.Int, error) {
+	if count <= 0 {
+		return weights, nil
+	}
+	out := make([]*big.Int, count)
+	n := big.NewInt(int64(len(weights)))
+	for i := range out {
+		idx, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample weight: %v", err)
+		}
+		out[i] = weights[idx.Int64()]
+	}
+	return out, nil
+}
+
+// buildWeightedFixture pairs each scalar with a fresh random point
+func buildWeightedFixture(name string, scalars []*big.Int, useG2 bool) (multiExpFixture, error) {
+	g1Points := make([]bls.G1Affine, len(scalars))
+	g2Points := make([]bls.G2Affine, len(scalars))
+	for i := range scalars {
+		var err error
+		if useG2 {
+			g2Points[i], err = bls.RandomOnG2()
+		} else {
+			g1Points[i], err = randomOnG1()
+		}
+		if err != nil {
+			return multiExpFixture{}, fmt.Errorf("failed to generate random point %d: %v", i, err)
+		}
+	}
+	return newMultiExpFixture(name, g1Points, g2Points, scalars, useG2)
+}
+
+// runWeightedMode generates a committee-style MultiExp fixture whose scalars come from a
+// weight distribution instead of the uniform random scalars of random mode
+func runWeightedMode(args []string) error {
+	fs := newFlagSet("weighted")
+	weightsPath := fs.String("weights", "", "CSV file with integer weights (e.g. validator stakes)")
+	column := fs.String("column", "", "Weight column: header name or 0-based index (default: last column)")
+	count := fs.Int("count", 0, "Number of pairs, sampled from the weights with replacement (default: every weight once)")
+	useG2 := fs.Bool("use-g2", false, "Use G2 points (default: G1)")
+	emit := fs.String("emit", "", "Write fixtures for targets: csharp, go, go-bytes, rust, solidity, python, neo-alias, neo-debugger, gotest or all (comma-separated)")
+	registerCSharpTemplateFlag(fs)
+	emitDir := fs.String("emit-dir", "fixtures", "Output directory for --emit fixtures")
+	timing := registerTimingFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *weightsPath == "" {
+		return fmt.Errorf("--weights is required")
+	}
+	if *emit != "" {
+		if _, err := parseEmitTargets(*emit); err != nil {
+			return err
+		}
+	}
+	weights, err := loadWeightsCSV(*weightsPath, *column)
+	if err != nil {
+		return err
+	}
+	scalars, err := sampleWeights(weights, *count)
+	if err != nil {
+		return err
+	}
+
+	total, minW, maxW := new(big.Int), weights[0], weights[0]
+	zeros := 0
+	for _, w := range weights {
+		total.Add(total, w)
+		if w.Cmp(minW) < 0 {
+			minW = w
+		}
+		if w.Cmp(maxW) > 0 {
+			maxW = w
+		}
+	}
+	for _, s := range scalars {
+		if s.Sign() == 0 {
+			zeros++
+		}
+	}
+
+	fmt.Println("=== Weighted MultiExp Fixture ===")
+	fmt.Printf("Weights: %d from %s (min %s, max %s, total %s)\n", len(weights), *weightsPath, minW.String(), maxW.String(), total.String())
+	if *count > 0 {
+		fmt.Printf("Pairs: %d (sampled with replacement)\n", len(scalars))
+	} else {
+		fmt.Printf("Pairs: %d (one per weight)\n", len(scalars))
+	}
+	if zeros > 0 {
+		fmt.Printf("⚠️  %d zero weight(s): Neo MultiExp skips these pairs\n", zeros)
+	}
+
+	start := time.Now()
+	f, err := buildWeightedFixture("weighted", scalars, *useG2)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+	printMultiExpFixture(f)
+	timing.report(f.Name, elapsed)
+	if *emit != "" {
+		fmt.Println("\n=== Emitting Fixtures ===")
+		if err := emitFixtures(f, *emit, *emitDir); err != nil {
+			return err
+		}
+	}
+	timing.printSummary()
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// wellKnownPoint is one entry of the well-known catalogue. Every point is computed, not
+// stored, so the catalogue cannot drift from the curve; source names where integrators
+// meet the point.
+type wellKnownPoint struct {
+	Name        string
+	G2          bool
+	Description string
+	Source      string
+	point       func() (bls.G1Affine, bls.G2Affine, error)
+}
+
+// eth2SignSecretKey and eth2SignMessage are the first secret key and message of the
+// Ethereum consensus BLS sign tests (sign_case_*), signed with the POP ciphersuite
+const (
+	eth2SignSecretKey = "0x263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"
+	eth2SignMessage   = "0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// eth2SignCase signs the first eth2 sign test with the min-pk POP ciphersuite
+func eth2SignCase() (blsSignature, error) {
+	sk, err := parseSecretKey(eth2SignSecretKey)
+	if err != nil {
+		return blsSignature{}, err
+	}
+	msg, _ := hex.DecodeString(eth2SignMessage)
+	return blsSign(msg, sk, "min-pk", ciphersuiteDSTs["min-pk"])
+}
+
+// wellKnownPoints is the catalogue, G1 first
+var wellKnownPoints = []wellKnownPoint{
+	{"g1_generator", false, "G1 generator", "EIP-2537, eth2 spec, ZCash serialization",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			_, _, g1, _ = bls.Generators()
+			return g1, g2, nil
+		}},
+	{"g1_infinity", false, "G1 identity (point at infinity)", "EIP-2537 zero point; eth2 infinity public key, rejected by KeyValidate",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			g1.SetInfinity()
+			return g1, g2, nil
+		}},
+	{"g1_generator_neg", false, "-G1", "EIP-2537 pairing vectors e(G1, G2) * e(-G1, G2) == 1",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			_, _, gen, _ := bls.Generators()
+			g1.Neg(&gen)
+			return g1, g2, nil
+		}},
+	{"g1_generator_double", false, "[2]G1", "EIP-2537 G1ADD(G1, G1) and G1MSM(G1, 2)",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			_, _, gen, _ := bls.Generators()
+			g1.Double(&gen)
+			return g1, g2, nil
+		}},
+	{"g1_hash_empty", false, "hash_to_G1(\"\") with " + hashToCurveDSTs["hash-g1"], "RFC 9380 appendix J.9.1, first test vector",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			g1, err = bls.HashToG1(nil, []byte(hashToCurveDSTs["hash-g1"]))
+			return g1, g2, err
+		}},
+	{"g1_eth2_pubkey", false, "Public key of " + eth2SignSecretKey, "eth2 BLS sign tests, first secret key",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			s, err := eth2SignCase()
+			return s.PubG1, g2, err
+		}},
+	{"g1_order_3", false, "(0, 2), a point of order 3 outside the subgroup", "y^2 = x^3 + 4 at x = 0; the smallest cofactor torsion of G1",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			g1.Y.SetUint64(2)
+			return g1, g2, nil
+		}},
+	{"g2_generator", true, "G2 generator", "EIP-2537, eth2 spec, ZCash serialization",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			_, _, _, g2 = bls.Generators()
+			return g1, g2, nil
+		}},
+	{"g2_infinity", true, "G2 identity (point at infinity)", "EIP-2537 zero point; eth2 infinity signature",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			g2.SetInfinity()
+			return g1, g2, nil
+		}},
+	{"g2_generator_neg", true, "-G2", "EIP-2537 pairing vectors e(G1, G2) * e(G1, -G2) == 1; BLS min-sig verification",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			_, _, _, gen := bls.Generators()
+			g2.Neg(&gen)
+			return g1, g2, nil
+		}},
+	{"g2_generator_double", true, "[2]G2", "EIP-2537 G2ADD(G2, G2) and G2MSM(G2, 2)",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			_, _, _, gen := bls.Generators()
+			g2.Double(&gen)
+			return g1, g2, nil
+		}},
+	{"g2_hash_empty", true, "hash_to_G2(\"\") with " + hashToCurveDSTs["hash-g2"], "RFC 9380 appendix J.10.1, first test vector",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			g2, err = bls.HashToG2(nil, []byte(hashToCurveDSTs["hash-g2"]))
+			return g1, g2, err
+		}},
+	{"g2_eth2_signature", true, "Signature of 32 zero bytes by " + eth2SignSecretKey, "eth2 BLS sign tests, first case, POP ciphersuite",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			s, err := eth2SignCase()
+			return g1, s.SigG2, err
+		}},
+	{"g2_not_in_subgroup", true, "First point on the twist with x = n + 0*u outside the subgroup", "Out-of-subgroup point of validation-order and invalid-vectors",
+		func() (g1 bls.G1Affine, g2 bls.G2Affine, err error) {
+			return g1, g2PointOnCurveNotInSubgroup(1), nil
+		}},
+}
+
+// runWellKnownMode prints the catalogue of canonical points in every encoding
+func runWellKnownMode(args []string) error {
+	fs := newFlagSet("well-known")
+	name := fs.String("name", "", "Print only this entry (e.g. g2_generator)")
+	group := fs.String("group", "all", "Group: g1, g2 or all")
+	to := fs.String("to", "all", "Formats: "+strings.Join(pointFormats, ", ")+" or all (comma-separated)")
+	list := fs.Bool("list", false, "List the entry names and sources only")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *group != "all" && *group != "g1" && *group != "g2" {
+		return usageErrorf("invalid --group '%s' (valid: g1, g2, all)", *group)
+	}
+	formats, err := parsePointFormats(*to)
+	if err != nil {
+		return usageError{err}
+	}
+
+	var entries []wellKnownPoint
+	for _, p := range wellKnownPoints {
+		if (*name == "" || p.Name == *name) && (*group == "all" || p.G2 == (*group == "g2")) {
+			entries = append(entries, p)
+		}
+	}
+	if len(entries) == 0 {
+		names := make([]string, len(wellKnownPoints))
+		for i, p := range wellKnownPoints {
+			names[i] = p.Name
+		}
+		if *group != "all" {
+			return usageErrorf("no well-known point '%s' in %s", *name, strings.ToUpper(*group))
+		}
+		return usageErrorf("no well-known point '%s' (valid: %s)", *name, strings.Join(names, ", "))
+	}
+
+	fmt.Println("=== Well-Known Points ===")
+	if *list {
+		for _, p := range entries {
+			fmt.Printf("%-20s %s (%s)\n", p.Name, p.Description, p.Source)
+		}
+		return nil
+	}
+	var last string
+	for _, p := range entries {
+		g1, g2, err := p.point()
+		if err != nil {
+			return fmt.Errorf("%s: %v", p.Name, err)
+		}
+		inSubgroup := g1.IsInSubGroup()
+		if p.G2 {
+			inSubgroup = g2.IsInSubGroup()
+		}
+		fmt.Println()
+		fmt.Printf("--- %s ---\n", p.Name)
+		fmt.Printf("Point: %s\n", p.Description)
+		fmt.Printf("Source: %s\n", p.Source)
+		fmt.Printf("In subgroup: %v\n", inSubgroup)
+		outputs := map[string]any{"description": p.Description, "source": p.Source, "in_subgroup": inSubgroup}
+		for _, f := range formats {
+			out := hex.EncodeToString(encodePointFormat(f, p.G2, g1, g2))
+			fmt.Printf("%s (%d bytes): %s\n", f, len(out)/2, out)
+			outputs[f] = out
+			last = out
+		}
+		if !p.G2 && !g1.IsInfinity() {
+			fmt.Printf("x (decimal): %s\n", new(big.Int).SetBytes(g1.X.Marshal()))
+			fmt.Printf("y (decimal): %s\n", new(big.Int).SetBytes(g1.Y.Marshal()))
+		} else if p.G2 && !g2.IsInfinity() {
+			fmt.Printf("x.c0 (decimal): %s\n", new(big.Int).SetBytes(g2.X.A0.Marshal()))
+			fmt.Printf("x.c1 (decimal): %s\n", new(big.Int).SetBytes(g2.X.A1.Marshal()))
+			fmt.Printf("y.c0 (decimal): %s\n", new(big.Int).SetBytes(g2.Y.A0.Marshal()))
+			fmt.Printf("y.c1 (decimal): %s\n", new(big.Int).SetBytes(g2.Y.A1.Marshal()))
+		}
+		reportOutput(p.Name, outputs)
+	}
+	if len(entries) == 1 && len(formats) == 1 {
+		recordVerdictResult(last)
+	}
+	return nil
+}
@@ -373,7 +373,7 @@ run_boundary_tests() {
         case "$case_operation" in
             multiexp)
                 if [ "$case_use_g2" = true ]; then
-                    if ! go run pairing_gen.go manual --g2 "$case_point" --scalars "$case_scalars" --use-g2 > "$TEMP_OUTPUT" 2>&1; then
+                    if ! go run . manual --g2 "$case_point" --scalars "$case_scalars" --use-g2 > "$TEMP_OUTPUT" 2>&1; then
                         echo -e "${RED}Error: pairing_gen.go manual (G2) failed for $case_name${NC}"
                         cat "$TEMP_OUTPUT"
                         mismatch_count=$((mismatch_count + 1))
@@ -381,7 +381,7 @@ run_boundary_tests() {
                         continue
                     fi
                 else
-                    if ! go run pairing_gen.go manual --g1 "$case_point" --scalars "$case_scalars" > "$TEMP_OUTPUT" 2>&1; then
+                    if ! go run . manual --g1 "$case_point" --scalars "$case_scalars" > "$TEMP_OUTPUT" 2>&1; then
                         echo -e "${RED}Error: pairing_gen.go manual (G1) failed for $case_name${NC}"
                         cat "$TEMP_OUTPUT"
                         mismatch_count=$((mismatch_count + 1))
@@ -605,7 +605,7 @@ PY_SCRIPT
             
             # Calculate expected result using pairing_gen.go
             cd "$EVM_DIR"
-            if ! go run pairing_gen.go "$case_operation" --input "$ETHEREUM_INPUT_HEX" > "$TEMP_OUTPUT" 2>&1; then
+            if ! go run . "$case_operation" --input "$ETHEREUM_INPUT_HEX" > "$TEMP_OUTPUT" 2>&1; then
                 echo -e "${RED}Error: pairing_gen.go $case_operation failed for $case_name${NC}"
                 cat "$TEMP_OUTPUT"
                 mismatch_count=$((mismatch_count + 1))
@@ -775,7 +775,7 @@ for ((test_num=1; test_num<=NUM_TESTS; test_num++)); do
         # Ethereum format test
         echo "Step 1: Running pairing_gen.go (ethereum mode)..."
         cd "$EVM_DIR"
-        if ! go run pairing_gen.go ethereum --input "$INPUT_HEX" $([ "$USE_G2" = true ] && echo "--use-g2") > "$TEMP_OUTPUT" 2>&1; then
+        if ! go run . ethereum --input "$INPUT_HEX" $([ "$USE_G2" = true ] && echo "--use-g2") > "$TEMP_OUTPUT" 2>&1; then
             echo -e "${RED}Error: pairing_gen.go execution failed${NC}"
             cat "$TEMP_OUTPUT"
             mismatch_count=$((mismatch_count + 1))
@@ -812,7 +812,7 @@ for ((test_num=1; test_num<=NUM_TESTS; test_num++)); do
         if [ "$OPERATION_MODE" = "pairing" ]; then
             # For pairing, use pairing-random mode to generate test scenarios
             # This generates e(g1, g2) * e(-g1, g2) = 1 scenario (bilinearity test)
-            if ! go run pairing_gen.go pairing-random > "$TEMP_OUTPUT" 2>&1; then
+            if ! go run . pairing-random > "$TEMP_OUTPUT" 2>&1; then
                 echo -e "${RED}Error: pairing_gen.go pairing-random execution failed${NC}"
                 cat "$TEMP_OUTPUT"
                 mismatch_count=$((mismatch_count + 1))
@@ -859,7 +859,7 @@ for ((test_num=1; test_num<=NUM_TESTS; test_num++)); do
             if [ "$USE_G2" = true ]; then
                 USE_G2_FLAG="--use-g2"
             fi
-            if ! go run pairing_gen.go random 1 $USE_G2_FLAG > "$TEMP_OUTPUT" 2>&1; then
+            if ! go run . random 1 $USE_G2_FLAG > "$TEMP_OUTPUT" 2>&1; then
                 echo -e "${RED}Error: pairing_gen.go execution failed${NC}"
                 cat "$TEMP_OUTPUT"
                 mismatch_count=$((mismatch_count + 1))
@@ -884,7 +884,7 @@ for ((test_num=1; test_num<=NUM_TESTS; test_num++)); do
             # Generate second point for Add operations
             if [[ "$OPERATION_MODE" == *"add"* ]]; then
                 # Generate another random point
-                if ! go run pairing_gen.go random 1 $USE_G2_FLAG > "$TEMP_OUTPUT" 2>&1; then
+                if ! go run . random 1 $USE_G2_FLAG > "$TEMP_OUTPUT" 2>&1; then
                     echo -e "${RED}Error: Failed to generate second point${NC}"
                     mismatch_count=$((mismatch_count + 1))
                     continue
@@ -1178,7 +1178,7 @@ print(json.dumps(request))
         
         # Call pairing_gen.go to calculate expected result
         cd "$EVM_DIR"
-        if ! go run pairing_gen.go "$OPERATION_MODE" --input "$ETHEREUM_INPUT_HEX" > "$TEMP_OUTPUT" 2>&1; then
+        if ! go run . "$OPERATION_MODE" --input "$ETHEREUM_INPUT_HEX" > "$TEMP_OUTPUT" 2>&1; then
             echo -e "${RED}Error: pairing_gen.go execution failed${NC}"
             cat "$TEMP_OUTPUT"
             mismatch_count=$((mismatch_count + 1))
@@ -1250,7 +1250,7 @@ print(json.dumps(request))
     if [ "$USE_G2" = true ]; then
         USE_G2_FLAG="--use-g2"
     fi
-    if ! go run pairing_gen.go "$MAX_SCALARS" $USE_G2_FLAG > "$TEMP_OUTPUT" 2>&1; then
+    if ! go run . "$MAX_SCALARS" $USE_G2_FLAG > "$TEMP_OUTPUT" 2>&1; then
         echo -e "${RED}Error: pairing_gen.go execution failed${NC}"
         cat "$TEMP_OUTPUT"
         mismatch_count=$((mismatch_count + 1))